@@ -0,0 +1,44 @@
+// Command reconcile-inventory compares every inventory row with the sum of
+// its stock movements. It prints the rows that do not match and exits with
+// status 1 when there are any, so it can run as a scheduled check.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/database"
+	inventoryRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+)
+
+func main() {
+	db, err := database.NewDatabase()
+	if err != nil {
+		log.Fatal("Failed to start Database:", err)
+	}
+	service := inventoryServices.NewInventoryService(
+		inventoryRepositories.NewInventoryRepository(db),
+		inventoryRepositories.NewWarehouseRepository(db),
+		inventoryServices.PriorityOrderStrategy{},
+		transactors.NewTransactorRepo(db),
+		inventoryServices.DefaultInventoryServiceConfig,
+	)
+	discrepancies, err := service.Reconcile(context.Background())
+	if err != nil {
+		log.Fatal("Failed to reconcile inventory:", err)
+	}
+	if len(discrepancies) == 0 {
+		fmt.Println("inventory matches the stock movement ledger")
+		return
+	}
+	fmt.Printf("%-36s  %-36s  %10s  %10s  %10s  %10s\n", "sku_id", "warehouse_id", "quantity", "ledger", "reserved", "ledger")
+	for _, d := range discrepancies {
+		fmt.Printf("%-36s  %-36s  %10d  %10d  %10d  %10d\n", d.SKUID, d.WarehouseID, d.Quantity, d.LedgerQuantity, d.Reserved, d.LedgerReserved)
+	}
+	fmt.Printf("%d inventory rows do not match their ledger\n", len(discrepancies))
+	os.Exit(1)
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/address"
+	checkoutHandlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/checkout"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/address"
+	checkoutRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/checkout"
+	productRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/product"
+	userRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/user"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/address"
+	checkoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/checkout"
+)
+
+// AddressApp serves the address book and the checkout that copies its
+// entries into orders.
+func AddressApp(r routers.RouterImpl, deps AppDependencies) {
+	addressService := services.NewAddressService(
+		repositories.NewAddressRepository(deps.DB),
+		deps.Transactor,
+		services.DefaultAddressServiceConfig,
+	)
+	r.CreateAddressRoute(handlers.NewAddressHandler(addressService), deps.RequireAuth())
+
+	checkoutService := checkoutServices.NewCheckoutService(
+		checkoutRepositories.NewCartRepository(deps.DB),
+		checkoutRepositories.NewOrderRepository(deps.DB),
+		userRepositories.NewUserRepository(deps.DB),
+		productRepositories.NewProductRepository(deps.DB),
+		addressService,
+		deps.InventoryService,
+		deps.Transactor,
+	)
+	r.CreateCheckoutRoute(checkoutHandlers.NewCheckoutHandler(checkoutService), deps.RequireSession(), deps.PermissionGuard())
+}
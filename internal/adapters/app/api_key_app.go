@@ -0,0 +1,11 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/apikey"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+)
+
+func APIKeyApp(r routers.RouterImpl, deps AppDependencies) {
+	apiKeyHandler := handlers.NewAPIKeyHandler(deps.APIKeyService)
+	r.CreateAPIKeyRoute(apiKeyHandler, deps.RequireSession(), deps.PermissionGuard())
+}
@@ -0,0 +1,11 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/auth"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+)
+
+func AuthApp(r routers.RouterImpl, deps AppDependencies) {
+	authHandler := handlers.NewAuthHandler(deps.AuthService)
+	r.CreateAuthRoute(authHandler, deps.RequireSession())
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	apiKeyRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/apikey"
+	auditRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/audit"
+	authRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/auth"
+	impersonationRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/impersonation"
+	inventoryRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/inventory"
+	lockoutRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/lockout"
+	otpRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/otp"
+	roleRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/role"
+	userRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/user"
+	otpSenders "github.com/billowdev/go-fiber-e-commerce/internal/adapters/senders/otp"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/storage"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	apiKeyPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	authPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	impersonationPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	lockoutPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+	otpPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	storagePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	apiKeyServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/apikey"
+	authServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/auth"
+	impersonationServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/impersonation"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+	roleServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// AppDependencies holds the adapters and services shared by every feature app.
+type AppDependencies struct {
+	DB                   *gorm.DB
+	Transactor           transactors.IDatabaseTransactor
+	JWTManager           *tokens.JWTManager
+	Cipher               *encryption.Cipher
+	RoleService          rolePorts.IRoleService
+	OTPService           otpPorts.IOTPService
+	LockoutService       lockoutPorts.ILockoutService
+	AuthService          authPorts.IAuthService
+	APIKeyService        apiKeyPorts.IAPIKeyService
+	Storage              storagePorts.IFileStorage
+	ImpersonationService impersonationPorts.IImpersonationService
+	InventoryService     inventoryPorts.IInventoryService // Shared by the catalog, which edits stock, and checkout, which reserves it
+}
+
+func NewAppDependencies(db *gorm.DB) AppDependencies {
+	transactor := transactors.NewTransactorRepo(db)
+	userRepo := userRepositories.NewUserRepository(db)
+	roleRepo := roleRepositories.NewRoleRepository(db)
+	roleService := roleServices.NewRoleService(
+		roleRepo,
+		userRepo,
+		transactor,
+		roleServices.DefaultPermissionCacheTTL,
+	)
+	jwtManager := tokens.NewJWTManager(configs.JWT_SECRET, configs.APP_NAME, time.Duration(configs.ACCESS_TOKEN_EXP)*time.Minute)
+	cipher := encryption.NewCipher(configs.ENCRYPTION_KEY)
+	hashParams := configs.PasswordHashParams()
+	otpConfig := otpServices.DefaultOTPServiceConfig
+	otpConfig.HashParams = hashParams
+	otpService := otpServices.NewOTPService(
+		otpRepositories.NewOTPRepository(db),
+		newOTPSender(),
+		transactor,
+		otpConfig,
+	)
+	lockoutService := lockoutServices.NewLockoutService(
+		newLoginAttemptCounter(db),
+		lockoutServices.DefaultLockoutServiceConfig,
+	)
+	authService := authServices.NewAuthService(
+		userRepo,
+		roleRepo,
+		authRepositories.NewRefreshTokenRepository(db),
+		authRepositories.NewTwoFactorRecoveryCodeRepository(db),
+		otpService,
+		lockoutService,
+		transactor,
+		jwtManager,
+		cipher,
+		authServices.AuthServiceConfig{
+			RefreshTokenTTL:    time.Duration(configs.REFRESH_TOKEN_EXP) * time.Minute,
+			TwoFactorIssuer:    configs.APP_NAME,
+			PasswordHashParams: hashParams,
+		},
+	)
+	apiKeyService := apiKeyServices.NewAPIKeyService(
+		apiKeyRepositories.NewAPIKeyRepository(db),
+		userRepo,
+		roleRepo,
+		roleService,
+		transactor,
+		hashParams,
+		apiKeyServices.DefaultAPIKeyServiceConfig,
+	)
+	impersonationService := impersonationServices.NewImpersonationService(
+		impersonationRepositories.NewImpersonationRepository(db),
+		userRepo,
+		roleService,
+		auditRepositories.NewChangeLogRepository(db),
+		transactor,
+		jwtManager,
+		impersonationServices.ImpersonationServiceConfig{
+			TokenTTL: time.Duration(configs.IMPERSONATION_TOKEN_EXP) * time.Minute,
+		},
+	)
+	inventoryConfig := inventoryServices.DefaultInventoryServiceConfig
+	inventoryConfig.ReservationTTL = time.Duration(configs.INVENTORY_RESERVATION_TTL_MINUTES) * time.Minute
+	inventoryService := inventoryServices.NewInventoryService(
+		inventoryRepositories.NewInventoryRepository(db),
+		inventoryRepositories.NewWarehouseRepository(db),
+		newAllocationStrategy(),
+		transactor,
+		inventoryConfig,
+	)
+	return AppDependencies{
+		DB:                   db,
+		Transactor:           transactor,
+		JWTManager:           jwtManager,
+		Cipher:               cipher,
+		RoleService:          roleService,
+		OTPService:           otpService,
+		LockoutService:       lockoutService,
+		AuthService:          authService,
+		APIKeyService:        apiKeyService,
+		Storage:              newFileStorage(),
+		ImpersonationService: impersonationService,
+		InventoryService:     inventoryService,
+	}
+}
+
+// newOTPSender picks how codes are delivered. Anything but OTP_SENDER=live
+// only logs them, which keeps local development free of mail and SMS setup.
+func newOTPSender() otpPorts.IOTPSender {
+	if configs.OTP_SENDER != "live" {
+		return otpSenders.NewLogOTPSender()
+	}
+	return otpSenders.NewChannelOTPSender(map[messageDomain.OTP_CHANNEL]otpPorts.IOTPSender{
+		messageDomain.OTP_CHANNEL_EMAIL: otpSenders.NewSMTPOTPSender(otpSenders.SMTPConfig{
+			Host:     configs.SMTP_HOST,
+			Port:     configs.SMTP_PORT,
+			Username: configs.SMTP_USERNAME,
+			Password: configs.SMTP_PASSWORD,
+			From:     configs.SMTP_FROM,
+		}),
+		messageDomain.OTP_CHANNEL_SMS: otpSenders.NewSMSGatewayOTPSender(configs.SMS_GATEWAY_URL, configs.SMS_GATEWAY_TOKEN),
+	})
+}
+
+// newFileStorage picks where uploads are kept. Local storage only suits a
+// single instance or a shared volume.
+func newFileStorage() storagePorts.IFileStorage {
+	if configs.STORAGE_DRIVER == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        configs.S3_ENDPOINT,
+			Region:          configs.S3_REGION,
+			Bucket:          configs.S3_BUCKET,
+			AccessKeyID:     configs.S3_ACCESS_KEY_ID,
+			SecretAccessKey: configs.S3_SECRET_ACCESS_KEY,
+			PublicURL:       configs.S3_PUBLIC_URL,
+		}, nil)
+	}
+	return storage.NewLocalStorage(configs.STORAGE_LOCAL_DIR, configs.STORAGE_PUBLIC_URL)
+}
+
+// newPrivateFileStorage keeps files the API only hands out after checking who
+// asks, such as data exports. They go to S3_PRIVATE_BUCKET, which must not
+// allow public reads, or to PRIVATE_STORAGE_LOCAL_DIR, which is never served.
+// The directory only suits a single instance or a shared volume.
+func newPrivateFileStorage() storagePorts.IFileStorage {
+	if configs.STORAGE_DRIVER == "s3" {
+		if configs.S3_PRIVATE_BUCKET != "" {
+			return storage.NewS3Storage(storage.S3Config{
+				Endpoint:        configs.S3_ENDPOINT,
+				Region:          configs.S3_REGION,
+				Bucket:          configs.S3_PRIVATE_BUCKET,
+				AccessKeyID:     configs.S3_ACCESS_KEY_ID,
+				SecretAccessKey: configs.S3_SECRET_ACCESS_KEY,
+			}, nil)
+		}
+		log.Printf("storage: S3_PRIVATE_BUCKET is not set, private files are kept in %s", configs.PRIVATE_STORAGE_LOCAL_DIR)
+	}
+	if isWithinDir(configs.PRIVATE_STORAGE_LOCAL_DIR, configs.STORAGE_LOCAL_DIR) {
+		log.Fatalf("storage: PRIVATE_STORAGE_LOCAL_DIR %s is inside STORAGE_LOCAL_DIR, which is served publicly", configs.PRIVATE_STORAGE_LOCAL_DIR)
+	}
+	return storage.NewLocalStorage(configs.PRIVATE_STORAGE_LOCAL_DIR, "")
+}
+
+// isWithinDir reports whether path is dir or one of its descendants.
+func isWithinDir(path, dir string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// newAllocationStrategy picks how checkout chooses the warehouses an order
+// ships from, the priority order of the warehouses unless configured
+// otherwise.
+func newAllocationStrategy() inventoryPorts.IAllocationStrategy {
+	strategy, err := inventoryServices.NewAllocationStrategy(orderDomain.ALLOCATION_STRATEGY(configs.INVENTORY_ALLOCATION_STRATEGY))
+	if err != nil {
+		log.Printf("inventory: %v, using the priority order", err)
+		return inventoryServices.PriorityOrderStrategy{}
+	}
+	return strategy
+}
+
+// newLoginAttemptCounter picks where failed logins are counted. The in-memory
+// counter only suits a single instance.
+func newLoginAttemptCounter(db *gorm.DB) lockoutPorts.ILoginAttemptCounter {
+	if configs.LOCKOUT_STORE == "memory" {
+		return lockoutRepositories.NewMemoryLoginAttemptCounter()
+	}
+	return lockoutRepositories.NewLoginAttemptRepository(db)
+}
+
+// RequireAuth returns the middleware that authenticates bearer tokens and API
+// keys.
+func (d AppDependencies) RequireAuth() fiber.Handler {
+	return middlewares.RequireAuth(d.JWTManager, d.APIKeyService, d.ImpersonationService)
+}
+
+// RequireSession returns the middleware that only authenticates bearer
+// tokens, for routes an API key must not reach.
+func (d AppDependencies) RequireSession() fiber.Handler {
+	return middlewares.RequireSession(d.JWTManager, d.ImpersonationService)
+}
+
+// PermissionGuard returns the middleware factory that checks role permissions.
+func (d AppDependencies) PermissionGuard() middlewares.PermissionGuard {
+	return middlewares.NewPermissionGuard(d.RoleService)
+}
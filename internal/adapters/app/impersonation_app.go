@@ -0,0 +1,11 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/impersonation"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+)
+
+func ImpersonationApp(r routers.RouterImpl, deps AppDependencies) {
+	impersonationHandler := handlers.NewImpersonationHandler(deps.ImpersonationService)
+	r.CreateImpersonationRoute(impersonationHandler, deps.RequireSession(), deps.PermissionGuard())
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/inventory"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+)
+
+// InventoryApp serves the warehouses, the stock of SKUs and its history, and
+// starts the worker that releases the stock of abandoned checkouts.
+func InventoryApp(r routers.RouterImpl, deps AppDependencies) {
+	go deps.InventoryService.RunWorker(context.Background())
+
+	warehouseService := services.NewWarehouseService(repositories.NewWarehouseRepository(deps.DB), deps.Transactor)
+	r.CreateInventoryRoute(
+		handlers.NewInventoryHandler(deps.InventoryService),
+		handlers.NewWarehouseHandler(warehouseService),
+		deps.RequireAuth(),
+		deps.PermissionGuard(),
+	)
+}
@@ -0,0 +1,11 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+)
+
+func LockoutApp(r routers.RouterImpl, deps AppDependencies) {
+	lockoutHandler := handlers.NewLockoutHandler(deps.LockoutService)
+	r.CreateLockoutRoute(lockoutHandler, deps.RequireAuth(), deps.PermissionGuard())
+}
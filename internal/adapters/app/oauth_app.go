@@ -0,0 +1,28 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/oauth"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	oauthClients "github.com/billowdev/go-fiber-e-commerce/internal/adapters/oauth"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/oauth"
+	roleRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/role"
+	userRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/user"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/oauth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
+)
+
+func OAuthApp(r routers.RouterImpl, deps AppDependencies) {
+	oauthService := services.NewOAuthService(
+		repositories.NewOAuthProviderRepository(deps.DB),
+		repositories.NewOAuthRepository(deps.DB),
+		userRepositories.NewUserRepository(deps.DB),
+		roleRepositories.NewRoleRepository(deps.DB),
+		oauthClients.NewHTTPClient(nil),
+		deps.AuthService,
+		deps.Transactor,
+		deps.Cipher,
+		configs.PasswordHashParams(),
+	)
+	oauthHandler := handlers.NewOAuthHandler(oauthService)
+	r.CreateOAuthRoute(oauthHandler, deps.RequireSession(), deps.PermissionGuard())
+}
@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/privacy"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	auditRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/audit"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/privacy"
+	userRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/user"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/privacy"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
+	"time"
+)
+
+// PrivacyApp serves personal data exports and erasure, and starts the worker
+// that builds export archives in the background.
+func PrivacyApp(r routers.RouterImpl, deps AppDependencies) {
+	config := services.DefaultPrivacyServiceConfig
+	config.ExportTTL = time.Duration(configs.PRIVACY_EXPORT_TTL_HOURS) * time.Hour
+	privacyService := services.NewPrivacyService(
+		repositories.NewDataRequestRepository(deps.DB),
+		repositories.NewUserDataRepository(deps.DB),
+		userRepositories.NewUserRepository(deps.DB),
+		auditRepositories.NewChangeLogRepository(deps.DB),
+		newPrivateFileStorage(),
+		deps.Storage,
+		deps.Transactor,
+		config,
+	)
+	go privacyService.RunWorker(context.Background())
+
+	privacyHandler := handlers.NewPrivacyHandler(privacyService)
+	r.CreatePrivacyRoute(privacyHandler, deps.RequireSession(), deps.PermissionGuard())
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/product"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/product"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/product"
+)
+
+// ProductApp serves the catalog and starts the worker that processes bulk
+// catalog imports in the background.
+func ProductApp(r routers.RouterImpl, deps AppDependencies) {
+	productRepo := repositories.NewProductRepository(deps.DB)
+	categoryRepo := repositories.NewCategoryRepository(deps.DB)
+	skuRepo := repositories.NewProductSKURepository(deps.DB)
+	searchRepo := repositories.NewProductSearchRepository(deps.DB)
+	facetRepo := repositories.NewProductFacetRepository(deps.DB)
+
+	productService := services.NewProductService(productRepo, categoryRepo, deps.RoleService, deps.Transactor)
+	categoryService := services.NewCategoryService(categoryRepo, productRepo, deps.Transactor)
+	imageService := services.NewProductImageService(productRepo, deps.RoleService, deps.Storage, deps.Transactor, services.DefaultProductImageServiceConfig)
+	skuService := services.NewProductSKUService(skuRepo, productRepo, deps.RoleService, deps.InventoryService, deps.Transactor, services.DefaultProductSKUServiceConfig)
+	searchService := services.NewProductSearchService(searchRepo)
+	facetService := services.NewProductFacetService(facetRepo, categoryRepo, deps.Transactor)
+	importService := services.NewCatalogImportService(
+		repositories.NewCatalogImportRepository(deps.DB),
+		productRepo,
+		skuRepo,
+		categoryRepo,
+		newPrivateFileStorage(),
+		deps.InventoryService,
+		deps.Transactor,
+		services.DefaultCatalogImportServiceConfig,
+	)
+	go importService.RunWorker(context.Background())
+
+	r.CreateProductRoute(handlers.NewProductHandler(productService, facetService), deps.RequireAuth(), deps.PermissionGuard())
+	r.CreateProductImageRoute(handlers.NewProductImageHandler(imageService), deps.RequireAuth(), deps.PermissionGuard())
+	r.CreateProductSKURoute(handlers.NewProductSKUHandler(skuService), deps.RequireAuth(), deps.PermissionGuard())
+	r.CreateCategoryRoute(handlers.NewCategoryHandler(categoryService), deps.RequireAuth(), deps.PermissionGuard())
+	r.CreateCategoryFacetRoute(handlers.NewProductFacetHandler(facetService), deps.RequireAuth(), deps.PermissionGuard())
+	r.CreateSearchRoute(handlers.NewProductSearchHandler(searchService))
+	r.CreateCatalogImportRoute(handlers.NewCatalogImportHandler(importService), deps.RequireAuth(), deps.PermissionGuard())
+}
@@ -0,0 +1,19 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/profile"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/profile"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/profile"
+)
+
+func ProfileApp(r routers.RouterImpl, deps AppDependencies) {
+	profileService := services.NewProfileService(
+		repositories.NewProfileRepository(deps.DB),
+		deps.Storage,
+		deps.Transactor,
+		services.DefaultProfileServiceConfig,
+	)
+	profileHandler := handlers.NewProfileHandler(profileService)
+	r.CreateProfileRoute(profileHandler, deps.RequireAuth())
+}
@@ -1,15 +1,34 @@
 package app
 
 import (
+	"strings"
+
 	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 )
 
 func AppContainer(app *fiber.App, db *gorm.DB) *fiber.App {
+	// Files in local storage are served by the API unless they sit behind a
+	// CDN or another host.
+	if strings.HasPrefix(configs.STORAGE_PUBLIC_URL, "/") {
+		app.Static(configs.STORAGE_PUBLIC_URL, configs.STORAGE_LOCAL_DIR)
+	}
 	v1 := app.Group("/v1")
 	route := routers.NewRoute(v1)
-	_ = route
-	// SystemFieldApp(route, db)
+	deps := NewAppDependencies(db)
+	AuthApp(route, deps)
+	RoleApp(route, deps)
+	OAuthApp(route, deps)
+	LockoutApp(route, deps)
+	APIKeyApp(route, deps)
+	ProfileApp(route, deps)
+	AddressApp(route, deps)
+	PrivacyApp(route, deps)
+	UserAdminApp(route, deps)
+	ImpersonationApp(route, deps)
+	ProductApp(route, deps)
+	InventoryApp(route, deps)
 	return app
 }
@@ -0,0 +1,11 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/role"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+)
+
+func RoleApp(r routers.RouterImpl, deps AppDependencies) {
+	roleHandler := handlers.NewRoleHandler(deps.RoleService)
+	r.CreateRoleRoute(roleHandler, deps.RequireAuth(), deps.PermissionGuard())
+}
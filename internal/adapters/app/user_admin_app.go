@@ -0,0 +1,22 @@
+package app
+
+import (
+	handlers "github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/handlers/user"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/routers"
+	auditRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/audit"
+	authRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/auth"
+	userRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/user"
+	services "github.com/billowdev/go-fiber-e-commerce/internal/core/services/user"
+)
+
+func UserAdminApp(r routers.RouterImpl, deps AppDependencies) {
+	userAdminService := services.NewUserAdminService(
+		userRepositories.NewUserRepository(deps.DB),
+		deps.RoleService,
+		authRepositories.NewRefreshTokenRepository(deps.DB),
+		auditRepositories.NewChangeLogRepository(deps.DB),
+		deps.Transactor,
+	)
+	userAdminHandler := handlers.NewUserAdminHandler(userAdminService)
+	r.CreateUserAdminRoute(userAdminHandler, deps.RequireAuth(), deps.RequireSession(), deps.PermissionGuard())
+}
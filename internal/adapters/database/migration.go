@@ -1,7 +1,20 @@
 package database
 
 import (
+	"fmt"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
 	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	paymentDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/payment"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	reviewDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/reviews"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
 	"gorm.io/gorm"
 )
 
@@ -12,15 +25,294 @@ func RunMigrations(db *gorm.DB) error {
 			return err
 		}
 
+		// users.role_id used to hold free-text role names ("admin", "user");
+		// clear them so the column can become a uuid reference to user_roles.
+		if tx.Migrator().HasColumn(&userDomain.User{}, "role_id") {
+			err = tx.Exec(`UPDATE users SET role_id = NULL WHERE role_id IS NOT NULL AND role_id::text !~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'`).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// categories.slug is required and unique; rows created before it
+		// existed get their ID as a placeholder slug.
+		if tx.Migrator().HasTable(&productDomain.Category{}) && !tx.Migrator().HasColumn(&productDomain.Category{}, "slug") {
+			if err := tx.Exec("ALTER TABLE categories ADD COLUMN slug varchar(120)").Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE categories SET slug = id::text").Error; err != nil {
+				return err
+			}
+		}
+
+		// Images are ordered by position now; number the existing ones in
+		// their previous order, primary first.
+		if tx.Migrator().HasTable(&productDomain.ProductImage{}) && !tx.Migrator().HasColumn(&productDomain.ProductImage{}, "position") {
+			if err := tx.Exec("ALTER TABLE product_images ADD COLUMN position bigint NOT NULL DEFAULT 0").Error; err != nil {
+				return err
+			}
+			err = tx.Exec(`UPDATE product_images SET position = ranked.position FROM (
+				SELECT id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY is_primary DESC, created_at) - 1 AS position
+				FROM product_images) ranked
+				WHERE product_images.id = ranked.id`).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// Variants were replaced by SKUs. Their IDs mean nothing to
+		// product_skus, so cart lines keep the product and lose the variant.
+		if tx.Migrator().HasColumn(&cartDomain.CartItem{}, "variant_id") {
+			if err := tx.Migrator().RenameColumn(&cartDomain.CartItem{}, "variant_id", "sku_id"); err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE cart_items SET sku_id = NULL").Error; err != nil {
+				return err
+			}
+		}
+
+		// Stock is kept per warehouse now; what was counted before belongs to
+		// the default warehouse, which is created for it.
+		if tx.Migrator().HasTable(&orderDomain.Inventory{}) && !tx.Migrator().HasColumn(&orderDomain.Inventory{}, "warehouse_id") {
+			if err := migrateWarehouses(tx); err != nil {
+				return err
+			}
+		}
+
 		err = tx.AutoMigrate(
+			&userDomain.UserRole{},
+			&userDomain.UserPermission{},
 			&userDomain.User{},
+			&userDomain.UserProfile{},
+			&userDomain.UserAddress{},
+			&authDomain.RefreshToken{},
+			&authDomain.TwoFactorRecoveryCode{},
+			&messageDomain.OTP{},
+			&authDomain.OAuthProvider{},
+			&authDomain.OAuthAccessToken{},
+			&authDomain.OAuthRefreshToken{},
+			&authDomain.OAuthIdentity{},
+			&authDomain.OAuthState{},
+			&authDomain.LoginAttempt{},
+			&authDomain.APIKey{},
+			&authDomain.ImpersonationSession{},
+			&productDomain.Category{},
+			&productDomain.CategoryFacet{},
+			&productDomain.Product{},
+			&productDomain.ProductOption{},
+			&productDomain.ProductOptionValue{},
+			&productDomain.ProductSKU{},
+			&productDomain.ProductImage{},
+			&productDomain.ProductImageRendition{},
+			&productDomain.CatalogImportJob{},
+			&cartDomain.Cart{},
+			&cartDomain.CartItem{},
+			&orderDomain.Order{},
+			&orderDomain.OrderItem{},
+			&orderDomain.ShippingInfo{},
+			&orderDomain.BillingInfo{},
+			&orderDomain.Warehouse{},
+			&orderDomain.Inventory{},
+			&orderDomain.InventoryReservation{},
+			&orderDomain.StockMovement{},
+			&paymentDomain.Payment{},
+			&reviewDomain.Review{},
+			&reviewDomain.ShippingReview{},
+			&messageDomain.Email{},
+			&loggingDomain.ChangeLogs{},
+			&privacyDomain.DataRequest{},
 		)
 		if err != nil {
 			return err
 		}
 
+		// A product has at most one primary image.
+		err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_primary ON product_images (product_id) WHERE is_primary AND deleted_at IS NULL").Error
+		if err != nil {
+			return err
+		}
+
+		// Exactly one warehouse is the default.
+		err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_warehouses_default ON warehouses (is_default) WHERE is_default AND deleted_at IS NULL").Error
+		if err != nil {
+			return err
+		}
+		if _, err := ensureDefaultWarehouse(tx); err != nil {
+			return err
+		}
+
+		if err := migrateProductSearch(tx); err != nil {
+			return err
+		}
+
+		cipher := encryption.NewCipher(configs.ENCRYPTION_KEY)
+		if err := encryptPlaintextColumn(tx, cipher, userDomain.TNUser, "two_factor_secret"); err != nil {
+			return err
+		}
+		if err := encryptPlaintextColumn(tx, cipher, authDomain.TNOAuthProvider, "client_secret"); err != nil {
+			return err
+		}
+
 		return err
 	})
 
 	return err
 }
+
+// productSearchSQL keeps products.search_vector in step with the catalog.
+// A generated column may only read its own row, so the category name and the
+// option values are copied into search_category and search_variants by
+// triggers, and search_vector is generated from those and the product's own
+// columns. Weights: A name and SKU, B category, C variants, D description.
+const productSearchSQL = `
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_category text NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_variants text NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+	setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(sku, '')), 'A') ||
+	setweight(to_tsvector('english', search_category), 'B') ||
+	setweight(to_tsvector('english', search_variants), 'C') ||
+	setweight(to_tsvector('english', coalesce(description, '')), 'D')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_products_search_vector ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_products_name_trgm ON products USING GIN (name gin_trgm_ops);
+
+CREATE OR REPLACE FUNCTION product_search_variants(p_id uuid) RETURNS text AS $$
+	SELECT coalesce(string_agg(v.value, ' ' ORDER BY o.position, v.position), '')
+	FROM product_option_values v JOIN product_options o ON o.id = v.option_id
+	WHERE o.product_id = p_id AND o.deleted_at IS NULL AND v.deleted_at IS NULL
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION products_search_fields() RETURNS trigger AS $$
+BEGIN
+	NEW.search_category := coalesce((SELECT name FROM categories WHERE id = NEW.category_id), '');
+	NEW.search_variants := product_search_variants(NEW.id);
+	RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION categories_search_fields() RETURNS trigger AS $$
+BEGIN
+	UPDATE products SET search_category = NEW.name WHERE category_id = NEW.id;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION product_options_search_fields() RETURNS trigger AS $$
+DECLARE
+	row_product uuid;
+BEGIN
+	IF TG_TABLE_NAME = 'product_options' THEN
+		row_product := coalesce(NEW.product_id, OLD.product_id);
+	ELSE
+		SELECT product_id INTO row_product FROM product_options WHERE id = coalesce(NEW.option_id, OLD.option_id);
+	END IF;
+	UPDATE products SET search_variants = product_search_variants(row_product) WHERE id = row_product;
+	RETURN NULL;
+END
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS products_search_fields ON products;
+CREATE TRIGGER products_search_fields BEFORE INSERT OR UPDATE OF category_id ON products
+	FOR EACH ROW EXECUTE FUNCTION products_search_fields();
+DROP TRIGGER IF EXISTS categories_search_fields ON categories;
+CREATE TRIGGER categories_search_fields AFTER UPDATE OF name ON categories
+	FOR EACH ROW EXECUTE FUNCTION categories_search_fields();
+DROP TRIGGER IF EXISTS product_options_search_fields ON product_options;
+CREATE TRIGGER product_options_search_fields AFTER INSERT OR UPDATE OR DELETE ON product_options
+	FOR EACH ROW EXECUTE FUNCTION product_options_search_fields();
+DROP TRIGGER IF EXISTS product_option_values_search_fields ON product_option_values;
+CREATE TRIGGER product_option_values_search_fields AFTER INSERT OR UPDATE OR DELETE ON product_option_values
+	FOR EACH ROW EXECUTE FUNCTION product_options_search_fields();
+`
+
+// migrateProductSearch sets up full-text search on products and fills the
+// search columns of products created before it.
+func migrateProductSearch(tx *gorm.DB) error {
+	backfill := !tx.Migrator().HasColumn(&productDomain.Product{}, "search_category")
+	if err := tx.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(productSearchSQL).Error; err != nil {
+		return err
+	}
+	if !backfill {
+		return nil
+	}
+	// Setting category_id to itself fires products_search_fields.
+	return tx.Exec("UPDATE products SET category_id = category_id").Error
+}
+
+// ensureDefaultWarehouse creates the default warehouse when there is none
+// and returns its ID.
+func ensureDefaultWarehouse(tx *gorm.DB) (string, error) {
+	var ids []string
+	err := tx.Model(&orderDomain.Warehouse{}).Where("is_default").Limit(1).Pluck("id", &ids).Error
+	if err != nil {
+		return "", err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+	warehouse := orderDomain.Warehouse{Code: "MAIN", Name: "Main warehouse", IsDefault: true, Active: true}
+	if err := tx.Create(&warehouse).Error; err != nil {
+		return "", err
+	}
+	return warehouse.ID.String(), nil
+}
+
+// migrateWarehouses moves the stock counted before warehouses existed, its
+// reservations and its movements to the default warehouse.
+func migrateWarehouses(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&orderDomain.Warehouse{}); err != nil {
+		return err
+	}
+	warehouseID, err := ensureDefaultWarehouse(tx)
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{orderDomain.TNInventory, orderDomain.TNInventoryReservation, orderDomain.TNStockMovement} {
+		if !tx.Migrator().HasTable(table) {
+			continue
+		}
+		if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS warehouse_id uuid", table)).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET warehouse_id = ?", table), warehouseID).Error; err != nil {
+			return err
+		}
+	}
+	// A SKU had one inventory row; it has one per warehouse now.
+	return tx.Exec("DROP INDEX IF EXISTS idx_inventories_sku_id").Error
+}
+
+// encryptPlaintextColumn encrypts the values of a column that were stored in
+// plaintext before it was encrypted at rest (TOTP secrets, OAuth client
+// secrets).
+func encryptPlaintextColumn(tx *gorm.DB, cipher *encryption.Cipher, table, column string) error {
+	var rows []struct {
+		ID    string
+		Value string
+	}
+	err := tx.Table(table).
+		Select(fmt.Sprintf("id, %s AS value", column)).
+		Where(fmt.Sprintf("%s IS NOT NULL AND %s <> ''", column, column)).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if encryption.IsEncrypted(row.Value) {
+			continue
+		}
+		encrypted, err := cipher.Encrypt(row.Value)
+		if err != nil {
+			return err
+		}
+		if err := tx.Table(table).Where("id = ?", row.ID).Update(column, encrypted).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
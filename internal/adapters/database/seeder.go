@@ -4,7 +4,9 @@ import (
 	"fmt"
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/database/seeders"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
 	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
 	"gorm.io/gorm"
 )
 
@@ -18,13 +20,43 @@ func helperDeleteInfo(db *gorm.DB, table string) error {
 }
 
 func RunSeeds(db *gorm.DB) {
+	seeders.SeedUserRole(db)
 	seeders.SeedUser(db)
 
 }
 
 func resetSeeder(db *gorm.DB) error {
+	for _, table := range []string{
+		authDomain.TNOAuthState,
+		authDomain.TNOAuthIdentity,
+		authDomain.TNOAuthAccessToken,
+		authDomain.TNOAuthRefreshToken,
+		authDomain.TNLoginAttempt,
+		authDomain.TNAPIKey,
+		userDomain.TNUserProfile,
+		userDomain.TNUserAddress,
+	} {
+		if err := helperDeleteInfo(db, table); err != nil {
+			return err
+		}
+	}
+	if err := helperDeleteInfo(db, authDomain.TNRefreshToken); err != nil {
+		return err
+	}
+	if err := helperDeleteInfo(db, authDomain.TNTwoFactorRecoveryCode); err != nil {
+		return err
+	}
+	if err := helperDeleteInfo(db, messageDomain.TNOTP); err != nil {
+		return err
+	}
 	if err := helperDeleteInfo(db, userDomain.TNUser); err != nil {
 		return err
 	}
+	if err := helperDeleteInfo(db, userDomain.TNUserPermission); err != nil {
+		return err
+	}
+	if err := helperDeleteInfo(db, userDomain.TNUserRole); err != nil {
+		return err
+	}
 	return nil
 }
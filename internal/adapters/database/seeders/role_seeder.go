@@ -0,0 +1,78 @@
+package seeders
+
+import (
+	"time"
+
+	domain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	SEED_ROLE_ADMIN_ID           = uuidv7.MustParseToUUIDv7("01923800-0000-7000-8000-000000000001")
+	SEED_ROLE_SELLER_ID          = uuidv7.MustParseToUUIDv7("01923800-0000-7000-8000-000000000002")
+	SEED_ROLE_CUSTOMER_ID        = uuidv7.MustParseToUUIDv7("01923800-0000-7000-8000-000000000003")
+	SEED_ROLE_SERVICE_ACCOUNT_ID = uuidv7.MustParseToUUIDv7("01923800-0000-7000-8000-000000000004")
+)
+
+var SEED_USER_ROLE = []userDomain.UserRole{
+	{
+		BaseModel:   domain.BaseModel{ID: SEED_ROLE_ADMIN_ID},
+		RoleName:    userDomain.ROLE_ADMIN,
+		Description: "Full access to the back office",
+	},
+	{
+		BaseModel:   domain.BaseModel{ID: SEED_ROLE_SELLER_ID},
+		RoleName:    userDomain.ROLE_SELLER,
+		Description: "Manages the catalog and sees orders",
+	},
+	{
+		BaseModel:   domain.BaseModel{ID: SEED_ROLE_CUSTOMER_ID},
+		RoleName:    userDomain.ROLE_CUSTOMER,
+		Description: "Shops on the storefront",
+	},
+	{
+		BaseModel:   domain.BaseModel{ID: SEED_ROLE_SERVICE_ACCOUNT_ID},
+		RoleName:    userDomain.ROLE_SERVICE_ACCOUNT,
+		Description: "Machine clients using API keys; grant it what the integrations need",
+	},
+}
+
+func seedPermission(roleID uuid.UUID, permission, permissionType string) userDomain.UserPermission {
+	return userDomain.UserPermission{
+		RoleID:         roleID,
+		Permission:     permission,
+		PermissionType: permissionType,
+		EffectiveDate:  time.Now(),
+	}
+}
+
+var SEED_USER_PERMISSION = []userDomain.UserPermission{
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_ROLES, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_OAUTH_PROVIDERS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_LOCKOUTS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_SERVICE_ACCOUNTS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_PRIVACY_REQUESTS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_USERS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_IMPERSONATE_USERS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_CATALOG, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_CREATE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_UPDATE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_DELETE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_VIEW_ORDERS, "Read"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_ORDERS, "Admin"),
+	seedPermission(SEED_ROLE_ADMIN_ID, userDomain.PERMISSION_MANAGE_INVENTORY, "Admin"),
+	seedPermission(SEED_ROLE_SELLER_ID, userDomain.PERMISSION_CREATE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_SELLER_ID, userDomain.PERMISSION_UPDATE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_SELLER_ID, userDomain.PERMISSION_DELETE_PRODUCT, "Write"),
+	seedPermission(SEED_ROLE_SELLER_ID, userDomain.PERMISSION_VIEW_ORDERS, "Read"),
+}
+
+func SeedUserRole(db *gorm.DB) error {
+	if err := BaseStructSeeder(db, &SEED_USER_ROLE); err != nil {
+		return err
+	}
+	return BaseStructSeeder(db, &SEED_USER_PERMISSION)
+}
@@ -6,12 +6,13 @@ import (
 	domain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/configs"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
 	"gorm.io/gorm"
 )
 
 func SeedPassword(password string) string {
-	hash, _ := argon2id.CreateHash(password, argon2id.DefaultParams)
+	hash, _ := argon2id.CreateHash(password, configs.PasswordHashParams())
 	return hash
 }
 
@@ -23,7 +24,7 @@ var SEED_USER = []userDomain.User{
 		},
 		FirstName:        "John",
 		LastName:         "Doe",
-		RoleID:           "admin",
+		RoleID:           &SEED_ROLE_ADMIN_ID,
 		Address:          "123 Elm St, Springfield, IL",
 		PhoneNumber:      "+1234567890",
 		Email:            "john@example.com",
@@ -41,7 +42,7 @@ var SEED_USER = []userDomain.User{
 		},
 		FirstName:        "Jane",
 		LastName:         "Smith",
-		RoleID:           "user",
+		RoleID:           &SEED_ROLE_CUSTOMER_ID,
 		Address:          "456 Oak St, Springfield, IL",
 		PhoneNumber:      "+1987654321",
 		Email:            "jane@example.com",
@@ -49,8 +50,8 @@ var SEED_USER = []userDomain.User{
 		Password:         SeedPassword("@Test1234"),
 		Status:           "active",
 		LastLogin:        time.Now(),
-		TwoFactorEnabled: true,
-		TwoFactorSecret:  "abc123secret",
+		TwoFactorEnabled: false,
+		TwoFactorSecret:  "",
 		CreatedByID:      "",
 	},
 	{
@@ -59,7 +60,7 @@ var SEED_USER = []userDomain.User{
 		},
 		FirstName:        "Mike",
 		LastName:         "Johnson",
-		RoleID:           "user",
+		RoleID:           &SEED_ROLE_CUSTOMER_ID,
 		Address:          "789 Pine St, Springfield, IL",
 		PhoneNumber:      "+1472583690",
 		Email:            "mike@example.com",
@@ -77,7 +78,7 @@ var SEED_USER = []userDomain.User{
 		},
 		FirstName:        "Alice",
 		LastName:         "Brown",
-		RoleID:           "user",
+		RoleID:           &SEED_ROLE_CUSTOMER_ID,
 		Address:          "101 Maple St, Springfield, IL",
 		PhoneNumber:      "+3216549870",
 		Email:            "alice@example.com",
@@ -85,8 +86,8 @@ var SEED_USER = []userDomain.User{
 		Password:         SeedPassword("@Test1234"),
 		Status:           "active",
 		LastLogin:        time.Now(),
-		TwoFactorEnabled: true,
-		TwoFactorSecret:  "def456secret",
+		TwoFactorEnabled: false,
+		TwoFactorSecret:  "",
 		CreatedByID:      "",
 	},
 }
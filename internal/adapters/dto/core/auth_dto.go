@@ -0,0 +1,23 @@
+package dto
+
+import (
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+)
+
+// LoginResponse carries either the issued tokens or, for accounts with
+// two-factor authentication, the challenge to complete at /auth/2fa/verify.
+type LoginResponse struct {
+	User              UserResponse                   `json:"user"`
+	Tokens            *authDomain.TokenPair          `json:"tokens,omitempty"`
+	TwoFactorRequired bool                           `json:"two_factor_required"`
+	Challenge         *authDomain.TwoFactorChallenge `json:"challenge,omitempty"`
+}
+
+func ToLoginResponse(result *authDomain.LoginResult) LoginResponse {
+	return LoginResponse{
+		User:              ToUserResponse(result.User),
+		Tokens:            result.Tokens,
+		TwoFactorRequired: result.Challenge != nil,
+		Challenge:         result.Challenge,
+	}
+}
@@ -1 +1,66 @@
 package dto
+
+import (
+	"time"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+)
+
+// UserResponse is the public representation of a user. It never carries the
+// password hash or the two-factor secret.
+type UserResponse struct {
+	ID               uuid.UUID              `json:"id"`
+	FirstName        string                 `json:"first_name"`
+	LastName         string                 `json:"last_name"`
+	RoleID           *uuid.UUID             `json:"role_id"`
+	Address          string                 `json:"address"`
+	PhoneNumber      string                 `json:"phone_number"`
+	Email            string                 `json:"email"`
+	EmailVerified    bool                   `json:"email_verified"`
+	Username         string                 `json:"username"`
+	Status           userDomain.USER_STATUS `json:"status"`
+	LastLogin        time.Time              `json:"last_login"`
+	TwoFactorEnabled bool                   `json:"two_factor_enabled"`
+	CreatedAt        time.Time              `json:"created_at"`
+	UpdatedAt        time.Time              `json:"updated_at"`
+}
+
+func ToUserResponse(user *userDomain.User) UserResponse {
+	return UserResponse{
+		ID:               user.ID,
+		FirstName:        user.FirstName,
+		LastName:         user.LastName,
+		RoleID:           user.RoleID,
+		Address:          user.Address,
+		PhoneNumber:      user.PhoneNumber,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		Username:         user.Username,
+		Status:           user.Status,
+		LastLogin:        user.LastLogin,
+		TwoFactorEnabled: user.TwoFactorEnabled,
+		CreatedAt:        user.CreatedAt,
+		UpdatedAt:        user.UpdatedAt,
+	}
+}
+
+func ToUserResponses(users []userDomain.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, ToUserResponse(&users[i]))
+	}
+	return responses
+}
+
+func ToUserResponsePage(page pagination.Pagination[[]userDomain.User]) pagination.Pagination[[]UserResponse] {
+	return pagination.Pagination[[]UserResponse]{
+		Links:      page.Links,
+		Total:      page.Total,
+		Page:       page.Page,
+		PageSize:   page.PageSize,
+		TotalPages: page.TotalPages,
+		Rows:       ToUserResponses(page.Rows),
+	}
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type AddressHandlerImpl struct {
+	addressService ports.IAddressService
+}
+
+func NewAddressHandler(addressService ports.IAddressService) ports.IAddressHandler {
+	return &AddressHandlerImpl{addressService: addressService}
+}
+
+// HandleListAddresses implements ports.IAddressHandler.
+func (h *AddressHandlerImpl) HandleListAddresses(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	addresses, err := h.addressService.ListAddresses(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", addresses)
+}
+
+// HandleGetAddress implements ports.IAddressHandler.
+func (h *AddressHandlerImpl) HandleGetAddress(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid address id", nil)
+	}
+	address, err := h.addressService.GetAddress(c.Context(), userID, addressID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", address)
+}
+
+// HandleCreateAddress implements ports.IAddressHandler.
+func (h *AddressHandlerImpl) HandleCreateAddress(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload userDomain.UpsertUserAddressDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	address, err := h.addressService.CreateAddress(c.Context(), userID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Address added successfully", address)
+}
+
+// HandleUpdateAddress implements ports.IAddressHandler.
+func (h *AddressHandlerImpl) HandleUpdateAddress(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid address id", nil)
+	}
+	var payload userDomain.UpsertUserAddressDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	address, err := h.addressService.UpdateAddress(c.Context(), userID, addressID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Address updated successfully", address)
+}
+
+// HandleDeleteAddress implements ports.IAddressHandler.
+func (h *AddressHandlerImpl) HandleDeleteAddress(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	addressID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid address id", nil)
+	}
+	if err := h.addressService.DeleteAddress(c.Context(), userID, addressID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Address deleted successfully", nil)
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"errors"
+
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandlerImpl struct {
+	apiKeyService ports.IAPIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService ports.IAPIKeyService) ports.IAPIKeyHandler {
+	return &APIKeyHandlerImpl{apiKeyService: apiKeyService}
+}
+
+// HandleListMyAPIKeys implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleListMyAPIKeys(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	keys, err := h.apiKeyService.ListAPIKeys(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", keys)
+}
+
+// HandleCreateMyAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleCreateMyAPIKey(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.createAPIKey(c, userID, userID)
+}
+
+// HandleRotateMyAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleRotateMyAPIKey(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.rotateAPIKey(c, userID)
+}
+
+// HandleRevokeMyAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleRevokeMyAPIKey(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.revokeAPIKey(c, userID)
+}
+
+// HandleListServiceAccounts implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleListServiceAccounts(c *fiber.Ctx) error {
+	accounts, err := h.apiKeyService.ListServiceAccounts(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", dto.ToUserResponses(accounts))
+}
+
+// HandleCreateServiceAccount implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleCreateServiceAccount(c *fiber.Ctx) error {
+	adminID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload authDomain.CreateServiceAccountDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	account, err := h.apiKeyService.CreateServiceAccount(c.Context(), adminID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Service account created successfully", dto.ToUserResponse(account))
+}
+
+// HandleListServiceAccountAPIKeys implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleListServiceAccountAPIKeys(c *fiber.Ctx) error {
+	accountID, err := h.serviceAccountID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	keys, err := h.apiKeyService.ListAPIKeys(c.Context(), accountID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", keys)
+}
+
+// HandleCreateServiceAccountAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleCreateServiceAccountAPIKey(c *fiber.Ctx) error {
+	adminID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	accountID, err := h.serviceAccountID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.createAPIKey(c, accountID, adminID)
+}
+
+// HandleRotateServiceAccountAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleRotateServiceAccountAPIKey(c *fiber.Ctx) error {
+	accountID, err := h.serviceAccountID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.rotateAPIKey(c, accountID)
+}
+
+// HandleRevokeServiceAccountAPIKey implements ports.IAPIKeyHandler.
+func (h *APIKeyHandlerImpl) HandleRevokeServiceAccountAPIKey(c *fiber.Ctx) error {
+	accountID, err := h.serviceAccountID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.revokeAPIKey(c, accountID)
+}
+
+// serviceAccountID reads the :id parameter and makes sure it names a service
+// account, so the admin routes cannot manage the keys of regular users.
+func (h *APIKeyHandlerImpl) serviceAccountID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, errors.New("invalid service account id")
+	}
+	if _, err := h.apiKeyService.GetServiceAccount(c.Context(), id); err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+func (h *APIKeyHandlerImpl) createAPIKey(c *fiber.Ctx, ownerID, createdByID uuid.UUID) error {
+	var payload authDomain.CreateAPIKeyDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	key, err := h.apiKeyService.CreateAPIKey(c.Context(), ownerID, createdByID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "API key created, store it now as it will not be shown again", key)
+}
+
+func (h *APIKeyHandlerImpl) rotateAPIKey(c *fiber.Ctx, ownerID uuid.UUID) error {
+	keyID, err := uuid.Parse(c.Params("keyId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid api key id", nil)
+	}
+	key, err := h.apiKeyService.RotateAPIKey(c.Context(), ownerID, keyID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "API key rotated, the previous key no longer works", key)
+}
+
+func (h *APIKeyHandlerImpl) revokeAPIKey(c *fiber.Ctx, ownerID uuid.UUID) error {
+	keyID, err := uuid.Parse(c.Params("keyId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid api key id", nil)
+	}
+	if err := h.apiKeyService.RevokeAPIKey(c.Context(), ownerID, keyID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "API key revoked successfully", nil)
+}
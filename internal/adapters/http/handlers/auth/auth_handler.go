@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandlerImpl struct {
+	authService ports.IAuthService
+}
+
+func NewAuthHandler(authService ports.IAuthService) ports.IAuthHandler {
+	return &AuthHandlerImpl{authService: authService}
+}
+
+// HandleRegister implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleRegister(c *fiber.Ctx) error {
+	var payload userDomain.RegisterUserDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	user, err := h.authService.Register(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Registered successfully", dto.ToUserResponse(user))
+}
+
+// HandleLogin implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleLogin(c *fiber.Ctx) error {
+	var payload userDomain.LoginUserDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	result, err := h.authService.Login(c.Context(), payload, c.IP())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if result.Challenge != nil {
+		return utils.NewSuccessResponse(c, "Two-factor authentication required", dto.ToLoginResponse(result))
+	}
+	return utils.NewSuccessResponse(c, "Logged in successfully", dto.ToLoginResponse(result))
+}
+
+// HandleRefreshToken implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleRefreshToken(c *fiber.Ctx) error {
+	var payload authDomain.RefreshTokenDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	tokens, err := h.authService.RefreshToken(c.Context(), payload.RefreshToken)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Token refreshed successfully", tokens)
+}
+
+// HandleGetMe implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleGetMe(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	user, err := h.authService.GetCurrentUser(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, "User not found", nil)
+	}
+	return utils.NewSuccessResponse(c, "", dto.ToUserResponse(user))
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleResendEmailVerification implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleResendEmailVerification(c *fiber.Ctx) error {
+	var payload authDomain.EmailDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.ResendEmailVerification(c.Context(), payload.Email); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "If the address needs verification, a code has been sent", nil)
+}
+
+// HandleVerifyEmail implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleVerifyEmail(c *fiber.Ctx) error {
+	var payload authDomain.EmailCodeDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.VerifyEmail(c.Context(), payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Email verified successfully", nil)
+}
+
+// HandleRequestLoginOTP implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleRequestLoginOTP(c *fiber.Ctx) error {
+	var payload authDomain.EmailDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.RequestLoginOTP(c.Context(), payload.Email); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "If the account exists, a sign-in code has been sent", nil)
+}
+
+// HandleLoginWithOTP implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleLoginWithOTP(c *fiber.Ctx) error {
+	var payload authDomain.EmailCodeDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	result, err := h.authService.LoginWithOTP(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if result.Challenge != nil {
+		return utils.NewSuccessResponse(c, "Two-factor authentication required", dto.ToLoginResponse(result))
+	}
+	return utils.NewSuccessResponse(c, "Logged in successfully", dto.ToLoginResponse(result))
+}
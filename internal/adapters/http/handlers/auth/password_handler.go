@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleForgotPassword implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleForgotPassword(c *fiber.Ctx) error {
+	var payload authDomain.EmailDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.ForgotPassword(c.Context(), payload.Email); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "If the account exists, a reset code has been sent", nil)
+}
+
+// HandleResetPassword implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleResetPassword(c *fiber.Ctx) error {
+	var payload authDomain.ResetPasswordDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.ResetPassword(c.Context(), payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Password has been reset, please log in again", nil)
+}
+
+// HandleChangePassword implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleChangePassword(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload authDomain.ChangePasswordDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.ChangePassword(c.Context(), userID, payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Password changed, please log in again", nil)
+}
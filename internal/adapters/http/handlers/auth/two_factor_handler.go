@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleEnrollTwoFactor implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleEnrollTwoFactor(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	enrollment, err := h.authService.EnrollTwoFactor(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Scan the QR code and confirm with a code from your authenticator app", enrollment)
+}
+
+// HandleConfirmTwoFactor implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleConfirmTwoFactor(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload authDomain.TwoFactorCodeDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	codes, err := h.authService.ConfirmTwoFactor(c.Context(), userID, payload.Code)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Two-factor authentication enabled, store the recovery codes safely", codes)
+}
+
+// HandleDisableTwoFactor implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleDisableTwoFactor(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload authDomain.TwoFactorCodeDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.DisableTwoFactor(c.Context(), userID, payload.Code); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Two-factor authentication disabled", nil)
+}
+
+// HandleVerifyTwoFactorLogin implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleVerifyTwoFactorLogin(c *fiber.Ctx) error {
+	var payload authDomain.TwoFactorLoginDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	result, err := h.authService.VerifyTwoFactorLogin(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Logged in successfully", dto.ToLoginResponse(result))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HandleRequestAccountUnlock implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleRequestAccountUnlock(c *fiber.Ctx) error {
+	var payload authDomain.EmailDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.RequestAccountUnlock(c.Context(), payload.Email); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "If the account is locked, an unlock code has been sent", nil)
+}
+
+// HandleUnlockAccount implements ports.IAuthHandler.
+func (h *AuthHandlerImpl) HandleUnlockAccount(c *fiber.Ctx) error {
+	var payload authDomain.EmailCodeDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.authService.UnlockAccount(c.Context(), payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Account unlocked, you can log in again", nil)
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/checkout"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var errInvalidOrderID = errors.New("invalid order id")
+
+type CheckoutHandlerImpl struct {
+	checkoutService ports.ICheckoutService
+}
+
+func NewCheckoutHandler(checkoutService ports.ICheckoutService) ports.ICheckoutHandler {
+	return &CheckoutHandlerImpl{checkoutService: checkoutService}
+}
+
+// HandleCheckout implements ports.ICheckoutHandler.
+func (h *CheckoutHandlerImpl) HandleCheckout(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload orderDomain.CheckoutDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	order, err := h.checkoutService.Checkout(c.Context(), userID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Order placed successfully", order)
+}
+
+// HandleConfirmOrder implements ports.ICheckoutHandler.
+func (h *CheckoutHandlerImpl) HandleConfirmOrder(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOrderID.Error(), nil)
+	}
+	order, err := h.checkoutService.ConfirmOrder(c.Context(), actorID, orderID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Order confirmed", order)
+}
+
+// HandleCancelOrder implements ports.ICheckoutHandler.
+func (h *CheckoutHandlerImpl) HandleCancelOrder(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOrderID.Error(), nil)
+	}
+	order, err := h.checkoutService.CancelOrder(c.Context(), userID, orderID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Order cancelled", order)
+}
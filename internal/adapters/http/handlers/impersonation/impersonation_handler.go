@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ImpersonationHandlerImpl struct {
+	impersonationService ports.IImpersonationService
+}
+
+func NewImpersonationHandler(impersonationService ports.IImpersonationService) ports.IImpersonationHandler {
+	return &ImpersonationHandlerImpl{impersonationService: impersonationService}
+}
+
+// HandleStartImpersonation implements ports.IImpersonationHandler.
+func (h *ImpersonationHandlerImpl) HandleStartImpersonation(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid user id", nil)
+	}
+	var payload authDomain.StartImpersonationDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	token, err := h.impersonationService.StartImpersonation(c.Context(), actorID, userID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Impersonation started", token)
+}
+
+// HandleStopImpersonation implements ports.IImpersonationHandler.
+func (h *ImpersonationHandlerImpl) HandleStopImpersonation(c *fiber.Ctx) error {
+	actorID, err := utils.ParseActorUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	sessionID, err := utils.ParseImpersonationUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if err := h.impersonationService.StopImpersonation(c.Context(), sessionID, actorID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Impersonation stopped", nil)
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"errors"
+
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errInvalidSKUID       = errors.New("invalid sku id")
+	errInvalidWarehouseID = errors.New("invalid warehouse id")
+)
+
+type InventoryHandlerImpl struct {
+	inventoryService ports.IInventoryService
+}
+
+func NewInventoryHandler(inventoryService ports.IInventoryService) ports.IInventoryHandler {
+	return &InventoryHandlerImpl{inventoryService: inventoryService}
+}
+
+// HandleGetInventory implements ports.IInventoryHandler.
+func (h *InventoryHandlerImpl) HandleGetInventory(c *fiber.Ctx) error {
+	skuID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	inventory, err := h.inventoryService.GetInventory(c.Context(), skuID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", inventory)
+}
+
+// HandleListMovements implements ports.IInventoryHandler.
+func (h *InventoryHandlerImpl) HandleListMovements(c *fiber.Ctx) error {
+	skuID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	params := pagination.NewPaginationParams[orderDomain.StockMovementFilters](c)
+	warehouseID := c.Query("warehouse_id")
+	if warehouseID != "" {
+		if _, err := uuid.Parse(warehouseID); err != nil {
+			return utils.NewErrorResponse(c, errInvalidWarehouseID.Error(), nil)
+		}
+	}
+	params.Filters = orderDomain.StockMovementFilters{
+		Type:        c.Query("type"),
+		WarehouseID: warehouseID,
+		CommonTimeFilters: pagination.CommonTimeFilters{
+			DateField:     c.Query("date_field"),
+			CreatedAfter:  c.Query("created_after"),
+			CreatedBefore: c.Query("created_before"),
+			CreatedAt:     c.Query("created_at"),
+			StartDate:     c.Query("start_date"),
+			EndDate:       c.Query("end_date"),
+		},
+	}
+	movements, err := h.inventoryService.ListMovements(pagination.SetFilters(c.Context(), params), skuID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "Stock movements retrieved successfully", movements)
+}
+
+// HandleRecordMovement implements ports.IInventoryHandler.
+func (h *InventoryHandlerImpl) HandleRecordMovement(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	skuID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	var payload orderDomain.RecordStockMovementDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	movement, err := h.inventoryService.RecordMovement(c.Context(), actorID, skuID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Stock movement recorded", movement)
+}
+
+// HandleTransferStock implements ports.IInventoryHandler.
+func (h *InventoryHandlerImpl) HandleTransferStock(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	skuID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	var payload orderDomain.TransferStockDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	movements, err := h.inventoryService.Transfer(c.Context(), actorID, skuID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Stock transferred", movements)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type WarehouseHandlerImpl struct {
+	warehouseService ports.IWarehouseService
+}
+
+func NewWarehouseHandler(warehouseService ports.IWarehouseService) ports.IWarehouseHandler {
+	return &WarehouseHandlerImpl{warehouseService: warehouseService}
+}
+
+// HandleListWarehouses implements ports.IWarehouseHandler.
+func (h *WarehouseHandlerImpl) HandleListWarehouses(c *fiber.Ctx) error {
+	warehouses, err := h.warehouseService.ListWarehouses(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", warehouses)
+}
+
+// HandleGetWarehouse implements ports.IWarehouseHandler.
+func (h *WarehouseHandlerImpl) HandleGetWarehouse(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidWarehouseID.Error(), nil)
+	}
+	warehouse, err := h.warehouseService.GetWarehouse(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", warehouse)
+}
+
+// HandleCreateWarehouse implements ports.IWarehouseHandler.
+func (h *WarehouseHandlerImpl) HandleCreateWarehouse(c *fiber.Ctx) error {
+	var payload orderDomain.UpsertWarehouseDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	warehouse, err := h.warehouseService.CreateWarehouse(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Warehouse created successfully", warehouse)
+}
+
+// HandleUpdateWarehouse implements ports.IWarehouseHandler.
+func (h *WarehouseHandlerImpl) HandleUpdateWarehouse(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidWarehouseID.Error(), nil)
+	}
+	var payload orderDomain.UpsertWarehouseDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	warehouse, err := h.warehouseService.UpdateWarehouse(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Warehouse updated successfully", warehouse)
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type LockoutHandlerImpl struct {
+	lockoutService ports.ILockoutService
+}
+
+func NewLockoutHandler(lockoutService ports.ILockoutService) ports.ILockoutHandler {
+	return &LockoutHandlerImpl{lockoutService: lockoutService}
+}
+
+// HandleListLocks implements ports.ILockoutHandler.
+func (h *LockoutHandlerImpl) HandleListLocks(c *fiber.Ctx) error {
+	locks, err := h.lockoutService.ListLocks(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", locks)
+}
+
+// HandleClearLock implements ports.ILockoutHandler.
+func (h *LockoutHandlerImpl) HandleClearLock(c *fiber.Ctx) error {
+	var payload authDomain.ClearLoginLockDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	if err := h.lockoutService.ClearLock(c.Context(), payload.Scope, payload.Subject); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Lock cleared successfully", nil)
+}
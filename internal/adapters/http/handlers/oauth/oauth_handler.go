@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/oauth"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type OAuthHandlerImpl struct {
+	oauthService ports.IOAuthService
+}
+
+func NewOAuthHandler(oauthService ports.IOAuthService) ports.IOAuthHandler {
+	return &OAuthHandlerImpl{oauthService: oauthService}
+}
+
+// HandleListEnabledProviders implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleListEnabledProviders(c *fiber.Ctx) error {
+	providers, err := h.oauthService.ListEnabledProviders(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", providers)
+}
+
+// HandleAuthorize implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleAuthorize(c *fiber.Ctx) error {
+	authorization, err := h.oauthService.Authorize(c.Context(), c.Params("provider"), nil)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Redirect the user to the authorization URL", authorization)
+}
+
+// HandleLink implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleLink(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	authorization, err := h.oauthService.Authorize(c.Context(), c.Params("provider"), &userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Redirect the user to the authorization URL", authorization)
+}
+
+// HandleCallback implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleCallback(c *fiber.Ctx) error {
+	var payload authDomain.OAuthCallbackDomain
+	if err := c.QueryParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	result, err := h.oauthService.Callback(c.Context(), c.Params("provider"), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if result.Tokens == nil && result.Challenge == nil {
+		return utils.NewSuccessResponse(c, "Provider linked successfully", dto.ToLoginResponse(result))
+	}
+	return utils.NewSuccessResponse(c, "Logged in successfully", dto.ToLoginResponse(result))
+}
+
+// HandleRefreshProviderToken implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleRefreshProviderToken(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	token, err := h.oauthService.RefreshProviderToken(c.Context(), userID, c.Params("provider"))
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Provider token refreshed", token)
+}
+
+// HandleListProviders implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleListProviders(c *fiber.Ctx) error {
+	providers, err := h.oauthService.ListProviders(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", providers)
+}
+
+// HandleCreateProvider implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleCreateProvider(c *fiber.Ctx) error {
+	var payload authDomain.UpsertOAuthProviderDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	provider, err := h.oauthService.CreateProvider(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "OAuth provider created successfully", provider)
+}
+
+// HandleUpdateProvider implements ports.IOAuthHandler.
+func (h *OAuthHandlerImpl) HandleUpdateProvider(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid provider id", nil)
+	}
+	var payload authDomain.UpsertOAuthProviderDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	provider, err := h.oauthService.UpdateProvider(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "OAuth provider updated successfully", provider)
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"fmt"
+
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/privacy"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type PrivacyHandlerImpl struct {
+	privacyService ports.IPrivacyService
+}
+
+func NewPrivacyHandler(privacyService ports.IPrivacyService) ports.IPrivacyHandler {
+	return &PrivacyHandlerImpl{privacyService: privacyService}
+}
+
+// HandleRequestMyExport implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleRequestMyExport(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.requestExport(c, userID, userID)
+}
+
+// HandleListMyDataRequests implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleListMyDataRequests(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.listDataRequests(c, userID)
+}
+
+// HandleDownloadMyExport implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleDownloadMyExport(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.downloadExport(c, userID, userID)
+}
+
+// HandleEraseMe implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleEraseMe(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.eraseUser(c, userID, userID)
+}
+
+// HandleRequestUserExport implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleRequestUserExport(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.requestExport(c, userID, adminID)
+}
+
+// HandleListUserDataRequests implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleListUserDataRequests(c *fiber.Ctx) error {
+	_, userID, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.listDataRequests(c, userID)
+}
+
+// HandleDownloadUserExport implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleDownloadUserExport(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.downloadExport(c, userID, adminID)
+}
+
+// HandleEraseUser implements ports.IPrivacyHandler.
+func (h *PrivacyHandlerImpl) HandleEraseUser(c *fiber.Ctx) error {
+	adminID, userID, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return h.eraseUser(c, userID, adminID)
+}
+
+// adminAndUser returns the acting administrator and the :id user.
+func adminAndUser(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	adminID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fmt.Errorf("invalid user id")
+	}
+	return adminID, userID, nil
+}
+
+func (h *PrivacyHandlerImpl) requestExport(c *fiber.Ctx, userID, actorID uuid.UUID) error {
+	var payload privacyDomain.RequestDataExportDomain
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&payload); err != nil {
+			return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+		}
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	request, err := h.privacyService.RequestExport(c.Context(), userID, actorID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Export requested, it will be ready to download shortly", request)
+}
+
+func (h *PrivacyHandlerImpl) listDataRequests(c *fiber.Ctx, userID uuid.UUID) error {
+	requests, err := h.privacyService.ListDataRequests(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", requests)
+}
+
+func (h *PrivacyHandlerImpl) downloadExport(c *fiber.Ctx, userID, actorID uuid.UUID) error {
+	requestID, err := uuid.Parse(c.Params("requestId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid data request id", nil)
+	}
+	file, err := h.privacyService.DownloadExport(c.Context(), userID, requestID, actorID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Send(file.Data)
+}
+
+func (h *PrivacyHandlerImpl) eraseUser(c *fiber.Ctx, userID, actorID uuid.UUID) error {
+	var payload privacyDomain.RequestErasureDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	summary, err := h.privacyService.EraseUser(c.Context(), userID, actorID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Personal data erased", summary)
+}
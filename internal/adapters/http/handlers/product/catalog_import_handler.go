@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"time"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errInvalidImportID = errors.New("invalid import id")
+	errMissingFile     = errors.New("the catalog file is missing, send it in the \"file\" form field")
+	errCatalogFormat   = errors.New("format must be csv or ndjson")
+)
+
+type CatalogImportHandlerImpl struct {
+	importService ports.ICatalogImportService
+}
+
+func NewCatalogImportHandler(importService ports.ICatalogImportService) ports.ICatalogImportHandler {
+	return &CatalogImportHandlerImpl{importService: importService}
+}
+
+// HandleStartImport implements ports.ICatalogImportHandler.
+//
+// The file comes as the "file" field of a multipart form. The format is the
+// "format" field, or else the extension of the file: .csv, .ndjson or .jsonl.
+func (h *CatalogImportHandlerImpl) HandleStartImport(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		return utils.NewErrorResponse(c, errMissingFile.Error(), nil)
+	}
+	format := c.FormValue("format")
+	if format == "" {
+		format = filepath.Ext(header.Filename)
+		if len(format) > 0 {
+			format = format[1:]
+		}
+	}
+	parsed, ok := productDomain.ParseCatalogFormat(format)
+	if !ok {
+		return utils.NewErrorResponse(c, errCatalogFormat.Error(), nil)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	job, err := h.importService.StartImport(c.Context(), actorID, productDomain.StartCatalogImportDomain{
+		FileName: filepath.Base(header.Filename),
+		Format:   parsed,
+		Data:     data,
+	})
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Import queued, the rows will be processed shortly", job)
+}
+
+// HandleListImports implements ports.ICatalogImportHandler.
+func (h *CatalogImportHandlerImpl) HandleListImports(c *fiber.Ctx) error {
+	params := pagination.NewPaginationParams[productDomain.CatalogImportFilters](c)
+	params.Filters = productDomain.CatalogImportFilters{
+		Status:            c.Query("status"),
+		CommonTimeFilters: timeFilters(c),
+	}
+	jobs, err := h.importService.ListImports(pagination.SetFilters(c.Context(), params))
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "Imports retrieved successfully", jobs)
+}
+
+// HandleGetImport implements ports.ICatalogImportHandler.
+func (h *CatalogImportHandlerImpl) HandleGetImport(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidImportID.Error(), nil)
+	}
+	job, err := h.importService.GetImport(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", job)
+}
+
+// HandleDownloadImportReport implements ports.ICatalogImportHandler.
+func (h *CatalogImportHandlerImpl) HandleDownloadImportReport(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidImportID.Error(), nil)
+	}
+	file, err := h.importService.DownloadImportReport(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	c.Set(fiber.HeaderContentType, file.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Send(file.Data)
+}
+
+// HandleExportCatalog implements ports.ICatalogImportHandler.
+//
+// The body is streamed: rows go out batch by batch while the catalog is
+// still being read, so a failure midway can only cut the file short.
+func (h *CatalogImportHandlerImpl) HandleExportCatalog(c *fiber.Ctx) error {
+	format, ok := productDomain.ParseCatalogFormat(c.Query("format", string(productDomain.CATALOG_FORMAT_CSV)))
+	if !ok {
+		return utils.NewErrorResponse(c, errCatalogFormat.Error(), nil)
+	}
+	c.Set(fiber.HeaderContentType, format.ContentType())
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("catalog-%s.%s", time.Now().Format("20060102-150405"), format)))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	// The stream is written after the handler returns, when the request
+	// context is no longer usable.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.importService.ExportCatalog(context.Background(), format, w); err != nil {
+			log.Printf("catalog export: %v", err)
+		}
+	})
+	return nil
+}
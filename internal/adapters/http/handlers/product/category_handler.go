@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var errInvalidCategoryID = errors.New("invalid category id")
+
+type CategoryHandlerImpl struct {
+	categoryService ports.ICategoryService
+}
+
+func NewCategoryHandler(categoryService ports.ICategoryService) ports.ICategoryHandler {
+	return &CategoryHandlerImpl{categoryService: categoryService}
+}
+
+// HandleListCategories implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleListCategories(c *fiber.Ctx) error {
+	params := pagination.NewPaginationParams[productDomain.CategoryFilters](c)
+	params.Filters = productDomain.CategoryFilters{
+		Search:   c.Query("search"),
+		ParentID: c.Query("parent_id"),
+	}
+	ctx := pagination.SetFilters(c.Context(), params)
+
+	categories, err := h.categoryService.ListCategories(ctx)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "Categories retrieved successfully", categories)
+}
+
+// HandleGetCategory implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleGetCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	category, err := h.categoryService.GetCategory(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", category)
+}
+
+// HandleGetCategoryBySlug implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleGetCategoryBySlug(c *fiber.Ctx) error {
+	category, err := h.categoryService.GetCategoryBySlug(c.Context(), c.Params("slug"))
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", category)
+}
+
+// HandleGetCategoryTree implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleGetCategoryTree(c *fiber.Ctx) error {
+	tree, err := h.categoryService.GetCategoryTree(c.Context(), nil)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", tree)
+}
+
+// HandleGetCategorySubtree implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleGetCategorySubtree(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	tree, err := h.categoryService.GetCategoryTree(c.Context(), &id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", tree[0])
+}
+
+// HandleGetBreadcrumbs implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleGetBreadcrumbs(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	path, err := h.categoryService.GetBreadcrumbs(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", path)
+}
+
+// HandleCreateCategory implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleCreateCategory(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.CreateCategoryDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	category, err := h.categoryService.CreateCategory(c.Context(), actorID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Category created successfully", category)
+}
+
+// HandleUpdateCategory implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleUpdateCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	var payload productDomain.UpsertCategoryDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	category, err := h.categoryService.UpdateCategory(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Category updated successfully", category)
+}
+
+// HandleMoveCategory implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleMoveCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	var payload productDomain.MoveCategoryDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	category, err := h.categoryService.MoveCategory(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Category moved successfully", category)
+}
+
+// HandleDeleteCategory implements ports.ICategoryHandler.
+func (h *CategoryHandlerImpl) HandleDeleteCategory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	if err := h.categoryService.DeleteCategory(c.Context(), id); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Category deleted successfully", nil)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ProductFacetHandlerImpl struct {
+	facetService ports.IProductFacetService
+}
+
+func NewProductFacetHandler(facetService ports.IProductFacetService) ports.IProductFacetHandler {
+	return &ProductFacetHandlerImpl{facetService: facetService}
+}
+
+// HandleGetCategoryFacets implements ports.IProductFacetHandler.
+func (h *ProductFacetHandlerImpl) HandleGetCategoryFacets(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	facets, err := h.facetService.GetCategoryFacets(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", facets)
+}
+
+// HandleSetCategoryFacets implements ports.IProductFacetHandler.
+func (h *ProductFacetHandlerImpl) HandleSetCategoryFacets(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidCategoryID.Error(), nil)
+	}
+	var payload productDomain.SetCategoryFacetsDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	facets, err := h.facetService.SetCategoryFacets(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Category facets updated successfully", facets)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errInvalidImageID = errors.New("invalid image id")
+	errMissingImage   = errors.New("the image file is missing, send it in the \"image\" form field")
+)
+
+type ProductImageHandlerImpl struct {
+	imageService ports.IProductImageService
+}
+
+func NewProductImageHandler(imageService ports.IProductImageService) ports.IProductImageHandler {
+	return &ProductImageHandlerImpl{imageService: imageService}
+}
+
+// HandleListImages implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleListImages(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidProductID.Error(), nil)
+	}
+	images, err := h.imageService.ListImages(c.Context(), productID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", images)
+}
+
+// HandleAddImage implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleAddImage(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.AddProductImageDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	image, err := h.imageService.AddImage(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Image added successfully", image)
+}
+
+// HandleUploadImage implements ports.IProductImageHandler.
+//
+// The image comes as the "image" field of a multipart form; "is_primary" may
+// be set to true.
+func (h *ProductImageHandlerImpl) HandleUploadImage(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	header, err := c.FormFile("image")
+	if err != nil {
+		return utils.NewErrorResponse(c, errMissingImage.Error(), nil)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	image, err := h.imageService.UploadImage(c.Context(), actorID, productID, productDomain.UploadProductImageDomain{
+		Data:        data,
+		ContentType: header.Header.Get(fiber.HeaderContentType),
+		IsPrimary:   c.FormValue("is_primary") == "true",
+	})
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Image uploaded successfully", image)
+}
+
+// HandleUploadImageBase64 implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleUploadImageBase64(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.UploadProductImageBase64Domain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	image, err := h.imageService.UploadImageBase64(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Image uploaded successfully", image)
+}
+
+// HandleSetPrimaryImage implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleSetPrimaryImage(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	imageID, err := uuid.Parse(c.Params("imageId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidImageID.Error(), nil)
+	}
+	image, err := h.imageService.SetPrimaryImage(c.Context(), actorID, productID, imageID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Primary image updated successfully", image)
+}
+
+// HandleReorderImages implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleReorderImages(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.ReorderProductImagesDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	images, err := h.imageService.ReorderImages(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Images reordered successfully", images)
+}
+
+// HandleDeleteImage implements ports.IProductImageHandler.
+func (h *ProductImageHandlerImpl) HandleDeleteImage(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	imageID, err := uuid.Parse(c.Params("imageId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidImageID.Error(), nil)
+	}
+	if err := h.imageService.DeleteImage(c.Context(), actorID, productID, imageID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Image deleted successfully", nil)
+}
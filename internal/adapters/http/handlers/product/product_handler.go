@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errInvalidProductID = errors.New("invalid product id")
+	errInvalidPrice     = errors.New("min_price and max_price must be numbers")
+	errInvalidRating    = errors.New("min_rating must be a whole number from 1 to 5")
+)
+
+// optionQueryPrefix starts the query parameters that filter on an option,
+// e.g. "option.color=red,blue".
+const optionQueryPrefix = "option."
+
+type ProductHandlerImpl struct {
+	productService ports.IProductService
+	facetService   ports.IProductFacetService
+}
+
+func NewProductHandler(productService ports.IProductService, facetService ports.IProductFacetService) ports.IProductHandler {
+	return &ProductHandlerImpl{productService: productService, facetService: facetService}
+}
+
+// HandleListProducts implements ports.IProductHandler.
+func (h *ProductHandlerImpl) HandleListProducts(c *fiber.Ctx) error {
+	minPrice, err := priceQuery(c, "min_price")
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	maxPrice, err := priceQuery(c, "max_price")
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if _, _, err := productDomain.ParsePriceBand(c.Query("price")); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	minRating, err := ratingQuery(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	params := pagination.NewPaginationParams[productDomain.ProductFilters](c)
+	params.Filters = productDomain.ProductFilters{
+		CategoryID:        c.Query("category_id"),
+		SellerID:          c.Query("seller_id"),
+		InCategory:        c.Query("in_category"),
+		SKU:               c.Query("sku"),
+		Search:            c.Query("search"),
+		MinPrice:          minPrice,
+		MaxPrice:          maxPrice,
+		PriceBand:         c.Query("price"),
+		MinRating:         minRating,
+		Options:           optionQueries(c),
+		CommonTimeFilters: timeFilters(c),
+	}
+	ctx := pagination.SetFilters(c.Context(), params)
+
+	products, err := h.productService.ListProducts(ctx)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	facets, err := h.facetService.ListFacets(ctx)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewFacetedPaginationResponse(c, "Products retrieved successfully", products, facets)
+}
+
+// HandleGetProduct implements ports.IProductHandler.
+func (h *ProductHandlerImpl) HandleGetProduct(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidProductID.Error(), nil)
+	}
+	product, err := h.productService.GetProduct(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", product)
+}
+
+// HandleCreateProduct implements ports.IProductHandler.
+func (h *ProductHandlerImpl) HandleCreateProduct(c *fiber.Ctx) error {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.UpsertProductDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	product, err := h.productService.CreateProduct(c.Context(), actorID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Product created successfully", product)
+}
+
+// HandleUpdateProduct implements ports.IProductHandler.
+func (h *ProductHandlerImpl) HandleUpdateProduct(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.UpsertProductDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	product, err := h.productService.UpdateProduct(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Product updated successfully", product)
+}
+
+// HandleDeleteProduct implements ports.IProductHandler.
+func (h *ProductHandlerImpl) HandleDeleteProduct(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if err := h.productService.DeleteProduct(c.Context(), actorID, productID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Product deleted successfully", nil)
+}
+
+// actorAndProduct reads the authenticated user and the :id product parameter.
+func actorAndProduct(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	actorID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errInvalidProductID
+	}
+	return actorID, productID, nil
+}
+
+// priceQuery reads an optional price bound from the query string.
+func priceQuery(c *fiber.Ctx, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, errInvalidPrice
+	}
+	return &value, nil
+}
+
+// ratingQuery reads the optional min_rating filter.
+func ratingQuery(c *fiber.Ctx) (*int, error) {
+	raw := c.Query("min_rating")
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 1 || value > 5 {
+		return nil, errInvalidRating
+	}
+	return &value, nil
+}
+
+// optionQueries collects the "option.<name>=value,value" filters, keyed by
+// the lowercased option name.
+func optionQueries(c *fiber.Ctx) map[string][]string {
+	options := make(map[string][]string)
+	for key, raw := range c.Queries() {
+		name := strings.ToLower(strings.TrimPrefix(key, optionQueryPrefix))
+		if !strings.HasPrefix(key, optionQueryPrefix) || name == "" {
+			continue
+		}
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				options[name] = append(options[name], value)
+			}
+		}
+	}
+	return options
+}
+
+// timeFilters reads the created/updated date range of a listing.
+func timeFilters(c *fiber.Ctx) pagination.CommonTimeFilters {
+	return pagination.CommonTimeFilters{
+		DateField:     c.Query("date_field"),
+		CreatedAfter:  c.Query("created_after"),
+		UpdatedAfter:  c.Query("updated_after"),
+		CreatedBefore: c.Query("created_before"),
+		UpdatedBefore: c.Query("updated_before"),
+		CreatedAt:     c.Query("created_at"),
+		UpdatedAt:     c.Query("updated_at"),
+		StartDate:     c.Query("start_date"),
+		EndDate:       c.Query("end_date"),
+	}
+}
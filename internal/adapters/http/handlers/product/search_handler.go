@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductSearchHandlerImpl struct {
+	searchService ports.IProductSearchService
+}
+
+func NewProductSearchHandler(searchService ports.IProductSearchService) ports.IProductSearchHandler {
+	return &ProductSearchHandlerImpl{searchService: searchService}
+}
+
+// HandleSearchProducts implements ports.IProductSearchHandler.
+func (h *ProductSearchHandlerImpl) HandleSearchProducts(c *fiber.Ctx) error {
+	minPrice, err := priceQuery(c, "min_price")
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	maxPrice, err := priceQuery(c, "max_price")
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	params := pagination.NewPaginationParams[productDomain.ProductSearchFilters](c)
+	// Results are ranked unless a sort is asked for.
+	if c.Query("sort") == "" {
+		params.Sort = ""
+	}
+	params.Filters = productDomain.ProductSearchFilters{
+		Query:      c.Query("q"),
+		InCategory: c.Query("in_category"),
+		MinPrice:   minPrice,
+		MaxPrice:   maxPrice,
+	}
+	ctx := pagination.SetFilters(c.Context(), params)
+
+	hits, err := h.searchService.SearchProducts(ctx)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "Products found successfully", hits)
+}
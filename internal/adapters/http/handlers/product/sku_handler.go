@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var (
+	errInvalidOptionID = errors.New("invalid option id")
+	errInvalidValueID  = errors.New("invalid option value id")
+	errInvalidSKUID    = errors.New("invalid sku id")
+)
+
+type ProductSKUHandlerImpl struct {
+	skuService ports.IProductSKUService
+}
+
+func NewProductSKUHandler(skuService ports.IProductSKUService) ports.IProductSKUHandler {
+	return &ProductSKUHandlerImpl{skuService: skuService}
+}
+
+// HandleListOptions implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleListOptions(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidProductID.Error(), nil)
+	}
+	options, err := h.skuService.ListOptions(c.Context(), productID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", options)
+}
+
+// HandleCreateOption implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleCreateOption(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.CreateProductOptionDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	option, err := h.skuService.CreateOption(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Option created successfully", option)
+}
+
+// HandleUpdateOption implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleUpdateOption(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	optionID, err := uuid.Parse(c.Params("optionId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOptionID.Error(), nil)
+	}
+	var payload productDomain.UpdateProductOptionDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	option, err := h.skuService.UpdateOption(c.Context(), actorID, productID, optionID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Option updated successfully", option)
+}
+
+// HandleDeleteOption implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleDeleteOption(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	optionID, err := uuid.Parse(c.Params("optionId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOptionID.Error(), nil)
+	}
+	if err := h.skuService.DeleteOption(c.Context(), actorID, productID, optionID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Option deleted successfully", nil)
+}
+
+// HandleAddOptionValue implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleAddOptionValue(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	optionID, err := uuid.Parse(c.Params("optionId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOptionID.Error(), nil)
+	}
+	var payload productDomain.AddProductOptionValueDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	value, err := h.skuService.AddOptionValue(c.Context(), actorID, productID, optionID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Option value added successfully", value)
+}
+
+// HandleDeleteOptionValue implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleDeleteOptionValue(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	optionID, err := uuid.Parse(c.Params("optionId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidOptionID.Error(), nil)
+	}
+	valueID, err := uuid.Parse(c.Params("valueId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidValueID.Error(), nil)
+	}
+	if err := h.skuService.DeleteOptionValue(c.Context(), actorID, productID, optionID, valueID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Option value deleted successfully", nil)
+}
+
+// HandleListSKUs implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleListSKUs(c *fiber.Ctx) error {
+	productID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidProductID.Error(), nil)
+	}
+	skus, err := h.skuService.ListSKUs(c.Context(), productID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", skus)
+}
+
+// HandleCreateSKU implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleCreateSKU(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.UpsertProductSKUDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	sku, err := h.skuService.CreateSKU(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "SKU created successfully", sku)
+}
+
+// HandleUpdateSKU implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleUpdateSKU(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	skuID, err := uuid.Parse(c.Params("skuId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	var payload productDomain.UpsertProductSKUDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	sku, err := h.skuService.UpdateSKU(c.Context(), actorID, productID, skuID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "SKU updated successfully", sku)
+}
+
+// HandleDeleteSKU implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleDeleteSKU(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	skuID, err := uuid.Parse(c.Params("skuId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, errInvalidSKUID.Error(), nil)
+	}
+	if err := h.skuService.DeleteSKU(c.Context(), actorID, productID, skuID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "SKU deleted successfully", nil)
+}
+
+// HandleGenerateSKUs implements ports.IProductSKUHandler.
+func (h *ProductSKUHandlerImpl) HandleGenerateSKUs(c *fiber.Ctx) error {
+	actorID, productID, err := actorAndProduct(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload productDomain.GenerateProductSKUsDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	skus, err := h.skuService.GenerateSKUs(c.Context(), actorID, productID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "SKUs generated successfully", skus)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"strings"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/profile"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProfileHandlerImpl struct {
+	profileService ports.IProfileService
+}
+
+func NewProfileHandler(profileService ports.IProfileService) ports.IProfileHandler {
+	return &ProfileHandlerImpl{profileService: profileService}
+}
+
+// HandleGetProfile implements ports.IProfileHandler.
+func (h *ProfileHandlerImpl) HandleGetProfile(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	profile, err := h.profileService.GetProfile(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", profile)
+}
+
+// HandleUpdateProfile implements ports.IProfileHandler.
+//
+// The body is a JSON Merge Patch, preferably sent as
+// application/merge-patch+json; plain application/json is accepted too.
+func (h *ProfileHandlerImpl) HandleUpdateProfile(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if contentType := c.Get(fiber.HeaderContentType); contentType != "" && !strings.Contains(contentType, "json") {
+		return utils.NewErrorResponse(c, "Content-Type must be application/merge-patch+json", nil)
+	}
+
+	profile, err := h.profileService.UpdateProfile(c.Context(), userID, c.Body())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Profile updated successfully", profile)
+}
+
+// HandleUploadAvatar implements ports.IProfileHandler.
+func (h *ProfileHandlerImpl) HandleUploadAvatar(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload userDomain.UploadAvatarDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+
+	profile, err := h.profileService.UploadAvatar(c.Context(), userID, payload.Image)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Avatar uploaded successfully", profile)
+}
+
+// HandleDeleteAvatar implements ports.IProfileHandler.
+func (h *ProfileHandlerImpl) HandleDeleteAvatar(c *fiber.Ctx) error {
+	userID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	profile, err := h.profileService.DeleteAvatar(c.Context(), userID)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Avatar removed successfully", profile)
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type RoleHandlerImpl struct {
+	roleService ports.IRoleService
+}
+
+func NewRoleHandler(roleService ports.IRoleService) ports.IRoleHandler {
+	return &RoleHandlerImpl{roleService: roleService}
+}
+
+// HandleListRoles implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleListRoles(c *fiber.Ctx) error {
+	roles, err := h.roleService.ListRoles(c.Context())
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", roles)
+}
+
+// HandleGetRole implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleGetRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	role, err := h.roleService.GetRole(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", role)
+}
+
+// HandleCreateRole implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleCreateRole(c *fiber.Ctx) error {
+	var payload userDomain.UpsertRoleDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	role, err := h.roleService.CreateRole(c.Context(), payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Role created successfully", role)
+}
+
+// HandleUpdateRole implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleUpdateRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	var payload userDomain.UpsertRoleDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	role, err := h.roleService.UpdateRole(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Role updated successfully", role)
+}
+
+// HandleDeleteRole implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleDeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	if err := h.roleService.DeleteRole(c.Context(), id); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Role deleted successfully", nil)
+}
+
+// HandleListRolePermissions implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleListRolePermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	permissions, err := h.roleService.ListRolePermissions(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", permissions)
+}
+
+// HandleGrantPermission implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleGrantPermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	var payload userDomain.GrantPermissionDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	permission, err := h.roleService.GrantPermission(c.Context(), id, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Permission granted successfully", permission)
+}
+
+// HandleRevokePermission implements ports.IRoleHandler.
+func (h *RoleHandlerImpl) HandleRevokePermission(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid role id", nil)
+	}
+	permissionID, err := uuid.Parse(c.Params("permissionId"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid permission id", nil)
+	}
+	if err := h.roleService.RevokePermission(c.Context(), id, permissionID); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "Permission revoked successfully", nil)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"errors"
+
+	dto "github.com/billowdev/go-fiber-e-commerce/internal/adapters/dto/core"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+var errInvalidUserID = errors.New("invalid user id")
+
+type UserAdminHandlerImpl struct {
+	userAdminService ports.IUserAdminService
+}
+
+func NewUserAdminHandler(userAdminService ports.IUserAdminService) ports.IUserAdminHandler {
+	return &UserAdminHandlerImpl{userAdminService: userAdminService}
+}
+
+// HandleListUsers implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleListUsers(c *fiber.Ctx) error {
+	params := pagination.NewPaginationParams[userDomain.UserFilters](c)
+	params.Filters = userDomain.UserFilters{
+		Status:            c.Query("status"),
+		Role:              c.Query("role"),
+		Email:             c.Query("email"),
+		Search:            c.Query("search"),
+		CommonTimeFilters: timeFilters(c),
+	}
+	ctx := pagination.SetFilters(c.Context(), params)
+
+	users, err := h.userAdminService.ListUsers(ctx)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "Users retrieved successfully", dto.ToUserResponsePage(users))
+}
+
+// HandleGetUser implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleGetUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid user id", nil)
+	}
+	user, err := h.userAdminService.GetUser(c.Context(), id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "", dto.ToUserResponse(user))
+}
+
+// HandleChangeUserStatus implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleChangeUserStatus(c *fiber.Ctx) error {
+	adminID, id, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload userDomain.ChangeUserStatusDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	user, err := h.userAdminService.ChangeStatus(c.Context(), id, adminID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "User status updated successfully", dto.ToUserResponse(user))
+}
+
+// HandleAssignUserRole implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleAssignUserRole(c *fiber.Ctx) error {
+	adminID, id, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload userDomain.AssignUserRoleDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	user, err := h.userAdminService.AssignRole(c.Context(), id, adminID, payload)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "User role updated successfully", dto.ToUserResponse(user))
+}
+
+// HandleForceLogout implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleForceLogout(c *fiber.Ctx) error {
+	adminID, id, err := adminAndUser(c)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	var payload userDomain.ForceLogoutDomain
+	if err := c.BodyParser(&payload); err != nil {
+		return utils.NewErrorResponse(c, "Invalid request payload", err.Error())
+	}
+	if err := utils.ValidateStruct(payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	if err := h.userAdminService.ForceLogout(c.Context(), id, adminID, payload); err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return utils.NewSuccessResponse(c, "User sessions revoked, access tokens expire on their own", nil)
+}
+
+// HandleListUserHistory implements ports.IUserAdminHandler.
+func (h *UserAdminHandlerImpl) HandleListUserHistory(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return utils.NewErrorResponse(c, "Invalid user id", nil)
+	}
+	params := pagination.NewPaginationParams[loggingDomain.ChangeLogFilters](c)
+	params.Sort = c.Query("sort", "timestamp desc")
+	params.Filters = loggingDomain.ChangeLogFilters{
+		Action:            c.Query("action"),
+		CommonTimeFilters: timeFilters(c),
+	}
+	ctx := pagination.SetFilters(c.Context(), params)
+
+	history, err := h.userAdminService.ListUserHistory(ctx, id)
+	if err != nil {
+		return utils.NewErrorResponse(c, err.Error(), nil)
+	}
+	return pagination.NewPaginationResponse(c, "User history retrieved successfully", history)
+}
+
+// adminAndUser returns the acting administrator and the :id user.
+func adminAndUser(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	adminID, err := utils.ParseSubjectUUID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, errInvalidUserID
+	}
+	return adminID, id, nil
+}
+
+// timeFilters reads the created/updated date range of a listing.
+func timeFilters(c *fiber.Ctx) pagination.CommonTimeFilters {
+	return pagination.CommonTimeFilters{
+		DateField:     c.Query("date_field"),
+		CreatedAfter:  c.Query("created_after"),
+		UpdatedAfter:  c.Query("updated_after"),
+		CreatedBefore: c.Query("created_before"),
+		UpdatedBefore: c.Query("updated_before"),
+		CreatedAt:     c.Query("created_at"),
+		UpdatedAt:     c.Query("updated_at"),
+		StartDate:     c.Query("start_date"),
+		EndDate:       c.Query("end_date"),
+	}
+}
@@ -0,0 +1,128 @@
+package middlewares
+
+import (
+	"strings"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	apiKeyPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	impersonationPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	// LocalSubject holds the authenticated user ID, read by helpers.HelperSetSubject.
+	LocalSubject = "sub"
+	// LocalRole holds the role claim of the authenticated user. It is not set
+	// for API keys.
+	LocalRole = "role"
+	// LocalAPIKeyID holds the ID of the API key that authenticated the request.
+	LocalAPIKeyID = "api_key_id"
+	// LocalAPIKeyScopes holds the scopes of that key; RequirePermission refuses
+	// permissions outside them.
+	LocalAPIKeyScopes = "api_key_scopes"
+	// LocalActor holds the ID of the staff member impersonating the subject,
+	// read by utils.ParseActorUUID and by the response helpers for the banner.
+	LocalActor = "act"
+	// LocalImpersonationID holds the impersonation session of the token.
+	LocalImpersonationID = "jti"
+
+	// HeaderAPIKey carries an API key for clients that cannot use the
+	// Authorization header.
+	HeaderAPIKey = "X-API-Key"
+	// HeaderImpersonatedBy is set on every response to an impersonation
+	// token, so clients can show a banner.
+	HeaderImpersonatedBy = "X-Impersonated-By"
+)
+
+// RequireAuth authenticates the request with either a bearer access token or
+// an API key, sent as "Bearer sk_..." or in the X-API-Key header, and stores
+// the subject in the Fiber locals. Requests without valid credentials are
+// rejected with 401 Unauthorized.
+func RequireAuth(jwtManager *tokens.JWTManager, apiKeyService apiKeyPorts.IAPIKeyService, impersonationService impersonationPorts.IImpersonationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderAPIKey)
+		if token, ok := bearerToken(c); ok && strings.HasPrefix(token, authDomain.API_KEY_PREFIX) {
+			key = token
+		}
+		if key == "" {
+			return verifyAccessToken(c, jwtManager, impersonationService)
+		}
+
+		principal, err := apiKeyService.Authenticate(c.Context(), key, c.IP())
+		if err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error(), nil)
+		}
+		c.Locals(LocalSubject, principal.UserID.String())
+		c.Locals(LocalAPIKeyID, principal.KeyID.String())
+		c.Locals(LocalAPIKeyScopes, principal.Scopes)
+		return c.Next()
+	}
+}
+
+// RequireSession only accepts bearer access tokens. It guards interactive
+// account actions, such as changing the password or minting API keys, that a
+// leaked key must not be able to perform.
+func RequireSession(jwtManager *tokens.JWTManager, impersonationService impersonationPorts.IImpersonationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return verifyAccessToken(c, jwtManager, impersonationService)
+	}
+}
+
+// DenyImpersonation refuses the request with 403 Forbidden when it is made
+// with an impersonation token. It guards actions support staff must never
+// take on a customer's behalf, such as changing credentials or paying, and
+// must run after RequireAuth or RequireSession.
+func DenyImpersonation(c *fiber.Ctx) error {
+	if actor, _ := c.Locals(LocalActor).(string); actor != "" {
+		return utils.NewErrorResponseWithStatus(c, fiber.StatusForbidden, "This action is not allowed while impersonating a user", nil)
+	}
+	return c.Next()
+}
+
+func verifyAccessToken(c *fiber.Ctx, jwtManager *tokens.JWTManager, impersonationService impersonationPorts.IImpersonationService) error {
+	token, ok := bearerToken(c)
+	if !ok {
+		return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, "Missing bearer token", nil)
+	}
+
+	claims, err := jwtManager.VerifyAccessToken(token)
+	if err != nil {
+		return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, "Invalid or expired token", nil)
+	}
+
+	if claims.IsImpersonation() {
+		// Impersonation tokens can be stopped early, so unlike regular
+		// access tokens their session is looked up on every request.
+		sessionID, err := uuid.Parse(claims.ID)
+		if err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, "Invalid or expired token", nil)
+		}
+		actorID, err := uuid.Parse(claims.Actor.Subject)
+		if err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, "Invalid or expired token", nil)
+		}
+		if err := impersonationService.CheckImpersonation(c.Context(), sessionID, actorID); err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error(), nil)
+		}
+		c.Locals(LocalActor, claims.Actor.Subject)
+		c.Locals(LocalImpersonationID, claims.ID)
+		c.Set(HeaderImpersonatedBy, claims.Actor.Subject)
+	}
+
+	c.Locals(LocalSubject, claims.Subject)
+	c.Locals(LocalRole, claims.Role)
+	return c.Next()
+}
+
+func bearerToken(c *fiber.Ctx) (string, bool) {
+	header := c.Get(fiber.HeaderAuthorization)
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+// PermissionGuard builds a middleware that requires the given permission.
+type PermissionGuard func(permission string) fiber.Handler
+
+// NewPermissionGuard returns a PermissionGuard backed by the role service.
+// The returned middlewares must run after RequireAuth.
+//
+// Example usage:
+//
+//	can := NewPermissionGuard(roleService)
+//	route.Post("/products", requireAuth, can("CREATE_PRODUCT"), h.HandleCreateProduct)
+func NewPermissionGuard(roleService rolePorts.IRoleService) PermissionGuard {
+	return func(permission string) fiber.Handler {
+		return RequirePermission(roleService, permission)
+	}
+}
+
+// RequirePermission rejects the request with 403 Forbidden unless the
+// authenticated user's role holds an effective grant of the permission. For
+// API keys the permission must also be one of the key's scopes. Admin
+// permissions are refused to impersonation tokens, whatever the role of the
+// impersonated user.
+func RequirePermission(roleService rolePorts.IRoleService, permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := utils.ParseSubjectUUID(c)
+		if err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusUnauthorized, err.Error(), nil)
+		}
+		if actor, _ := c.Locals(LocalActor).(string); actor != "" && userDomain.IsAdminPermission(permission) {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusForbidden, "This action is not allowed while impersonating a user", nil)
+		}
+		if scopes, ok := c.Locals(LocalAPIKeyScopes).([]string); ok && !utils.StringContains(scopes, permission) {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusForbidden, "The API key is not scoped for this action", nil)
+		}
+
+		allowed, err := roleService.HasPermission(c.Context(), userID, permission)
+		if err != nil {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusInternalServerError, "Failed to check permissions", nil)
+		}
+		if !allowed {
+			return utils.NewErrorResponseWithStatus(c, fiber.StatusForbidden, "You do not have permission to perform this action", nil)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,15 @@
+package routers
+
+import (
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateAddressRoute(h ports.IAddressHandler, requireAuth fiber.Handler) {
+	addresses := r.route.Group("/me/addresses", requireAuth)
+	addresses.Get("/", h.HandleListAddresses)
+	addresses.Post("/", h.HandleCreateAddress)
+	addresses.Get("/:id", h.HandleGetAddress)
+	addresses.Put("/:id", h.HandleUpdateAddress)
+	addresses.Delete("/:id", h.HandleDeleteAddress)
+}
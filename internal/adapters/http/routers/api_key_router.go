@@ -0,0 +1,26 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateAPIKeyRoute registers the API key routes. Keys are minted with a
+// session only, so a leaked key cannot create further keys.
+func (r RouterImpl) CreateAPIKeyRoute(h ports.IAPIKeyHandler, requireSession fiber.Handler, can middlewares.PermissionGuard) {
+	keys := r.route.Group("/me/api-keys", requireSession, middlewares.DenyImpersonation)
+	keys.Get("/", h.HandleListMyAPIKeys)
+	keys.Post("/", h.HandleCreateMyAPIKey)
+	keys.Post("/:keyId/rotate", h.HandleRotateMyAPIKey)
+	keys.Delete("/:keyId", h.HandleRevokeMyAPIKey)
+
+	accounts := r.route.Group("/admin/service-accounts", requireSession, middlewares.DenyImpersonation, can(userDomain.PERMISSION_MANAGE_SERVICE_ACCOUNTS))
+	accounts.Get("/", h.HandleListServiceAccounts)
+	accounts.Post("/", h.HandleCreateServiceAccount)
+	accounts.Get("/:id/api-keys", h.HandleListServiceAccountAPIKeys)
+	accounts.Post("/:id/api-keys", h.HandleCreateServiceAccountAPIKey)
+	accounts.Post("/:id/api-keys/:keyId/rotate", h.HandleRotateServiceAccountAPIKey)
+	accounts.Delete("/:id/api-keys/:keyId", h.HandleRevokeServiceAccountAPIKey)
+}
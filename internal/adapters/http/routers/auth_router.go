@@ -0,0 +1,34 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateAuthRoute(h ports.IAuthHandler, requireAuth fiber.Handler) {
+	auth := r.route.Group("/auth")
+	auth.Post("/register", h.HandleRegister)
+	auth.Post("/login", h.HandleLogin)
+	auth.Post("/refresh", h.HandleRefreshToken)
+	auth.Get("/me", requireAuth, h.HandleGetMe)
+
+	auth.Post("/email/resend", h.HandleResendEmailVerification)
+	auth.Post("/email/verify", h.HandleVerifyEmail)
+	auth.Post("/otp/request", h.HandleRequestLoginOTP)
+	auth.Post("/otp/login", h.HandleLoginWithOTP)
+
+	password := auth.Group("/password")
+	password.Post("/forgot", h.HandleForgotPassword)
+	password.Post("/reset", h.HandleResetPassword)
+	password.Post("/change", requireAuth, middlewares.DenyImpersonation, h.HandleChangePassword)
+
+	auth.Post("/unlock/request", h.HandleRequestAccountUnlock)
+	auth.Post("/unlock", h.HandleUnlockAccount)
+
+	twoFactor := auth.Group("/2fa")
+	twoFactor.Post("/verify", h.HandleVerifyTwoFactorLogin)
+	twoFactor.Post("/enroll", requireAuth, middlewares.DenyImpersonation, h.HandleEnrollTwoFactor)
+	twoFactor.Post("/confirm", requireAuth, middlewares.DenyImpersonation, h.HandleConfirmTwoFactor)
+	twoFactor.Post("/disable", requireAuth, middlewares.DenyImpersonation, h.HandleDisableTwoFactor)
+}
@@ -0,0 +1,19 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/checkout"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateCheckoutRoute(h ports.ICheckoutHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	// Paying is the customer's own decision, never support's.
+	r.route.Post("/checkout", requireAuth, middlewares.DenyImpersonation, h.HandleCheckout)
+
+	orders := r.route.Group("/orders")
+	orders.Post("/:id/cancel", requireAuth, middlewares.DenyImpersonation, h.HandleCancelOrder)
+	// Confirmation records a payment, so it is for staff and payment
+	// integrations rather than the customer.
+	orders.Post("/:id/confirm", requireAuth, can(userDomain.PERMISSION_MANAGE_ORDERS), h.HandleConfirmOrder)
+}
@@ -0,0 +1,16 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateImpersonationRoute registers the impersonation routes. A session is
+// started with the staff member's own token and stopped with the
+// impersonation token it returned.
+func (r RouterImpl) CreateImpersonationRoute(h ports.IImpersonationHandler, requireSession fiber.Handler, can middlewares.PermissionGuard) {
+	r.route.Post("/admin/users/:id/impersonate", requireSession, middlewares.DenyImpersonation, can(userDomain.PERMISSION_IMPERSONATE_USERS), h.HandleStartImpersonation)
+	r.route.Post("/auth/impersonation/stop", requireSession, h.HandleStopImpersonation)
+}
@@ -0,0 +1,23 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateInventoryRoute registers the warehouses, the stock of SKUs at each
+// of them and its movement history, limited to PERMISSION_MANAGE_INVENTORY.
+func (r RouterImpl) CreateInventoryRoute(h ports.IInventoryHandler, warehouses ports.IWarehouseHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	inventory := r.route.Group("/inventory", requireAuth, can(userDomain.PERMISSION_MANAGE_INVENTORY))
+	inventory.Get("/skus/:id", h.HandleGetInventory)
+	inventory.Get("/skus/:id/movements", h.HandleListMovements)
+	inventory.Post("/skus/:id/movements", h.HandleRecordMovement)
+	inventory.Post("/skus/:id/transfers", h.HandleTransferStock)
+
+	inventory.Get("/warehouses", warehouses.HandleListWarehouses)
+	inventory.Post("/warehouses", warehouses.HandleCreateWarehouse)
+	inventory.Get("/warehouses/:id", warehouses.HandleGetWarehouse)
+	inventory.Put("/warehouses/:id", warehouses.HandleUpdateWarehouse)
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateLockoutRoute(h ports.ILockoutHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	lockouts := r.route.Group("/admin/lockouts", requireAuth, can(userDomain.PERMISSION_MANAGE_LOCKOUTS))
+	lockouts.Get("/", h.HandleListLocks)
+	lockouts.Post("/clear", h.HandleClearLock)
+}
@@ -0,0 +1,22 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/oauth"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateOAuthRoute(h ports.IOAuthHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	oauth := r.route.Group("/auth/oauth")
+	oauth.Get("/providers", h.HandleListEnabledProviders)
+	oauth.Get("/:provider/authorize", h.HandleAuthorize)
+	oauth.Get("/:provider/callback", h.HandleCallback)
+	oauth.Get("/:provider/link", requireAuth, h.HandleLink)
+	oauth.Post("/:provider/refresh", requireAuth, h.HandleRefreshProviderToken)
+
+	providers := r.route.Group("/admin/oauth-providers", requireAuth, can(userDomain.PERMISSION_MANAGE_OAUTH_PROVIDERS))
+	providers.Get("/", h.HandleListProviders)
+	providers.Post("/", h.HandleCreateProvider)
+	providers.Put("/:id", h.HandleUpdateProvider)
+}
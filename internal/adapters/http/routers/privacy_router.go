@@ -0,0 +1,27 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/privacy"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreatePrivacyRoute registers the data export and erasure routes. They need
+// a session: an API key must not be able to download or erase an account.
+func (r RouterImpl) CreatePrivacyRoute(h ports.IPrivacyHandler, requireSession fiber.Handler, can middlewares.PermissionGuard) {
+	// Fiber runs group middleware for every path under the prefix, so the
+	// groups are kept as narrow as the routes they guard.
+	requests := r.route.Group("/me/data-requests", requireSession, middlewares.DenyImpersonation)
+	requests.Get("/", h.HandleListMyDataRequests)
+	requests.Post("/export", h.HandleRequestMyExport)
+	requests.Get("/:requestId/download", h.HandleDownloadMyExport)
+	r.route.Post("/me/erasure", requireSession, middlewares.DenyImpersonation, h.HandleEraseMe)
+
+	manage := can(userDomain.PERMISSION_MANAGE_PRIVACY_REQUESTS)
+	userRequests := r.route.Group("/admin/users/:id/data-requests", requireSession, manage)
+	userRequests.Get("/", h.HandleListUserDataRequests)
+	userRequests.Post("/export", h.HandleRequestUserExport)
+	userRequests.Get("/:requestId/download", h.HandleDownloadUserExport)
+	r.route.Post("/admin/users/:id/erasure", requireSession, manage, h.HandleEraseUser)
+}
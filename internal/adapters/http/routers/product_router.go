@@ -0,0 +1,96 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateProductRoute registers the catalog. Reads are public; sellers write
+// their own products, PERMISSION_MANAGE_CATALOG reaches every product.
+func (r RouterImpl) CreateProductRoute(h ports.IProductHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	products := r.route.Group("/products")
+	products.Get("/", h.HandleListProducts)
+	products.Get("/:id", h.HandleGetProduct)
+
+	update := can(userDomain.PERMISSION_UPDATE_PRODUCT)
+	products.Post("/", requireAuth, can(userDomain.PERMISSION_CREATE_PRODUCT), h.HandleCreateProduct)
+	products.Put("/:id", requireAuth, update, h.HandleUpdateProduct)
+	products.Delete("/:id", requireAuth, can(userDomain.PERMISSION_DELETE_PRODUCT), h.HandleDeleteProduct)
+}
+
+// CreateProductImageRoute registers the images of a product. Uploads are
+// either a multipart form or a base64 data URI.
+func (r RouterImpl) CreateProductImageRoute(h ports.IProductImageHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	products := r.route.Group("/products")
+	products.Get("/:id/images", h.HandleListImages)
+
+	update := can(userDomain.PERMISSION_UPDATE_PRODUCT)
+	products.Post("/:id/images", requireAuth, update, h.HandleAddImage)
+	products.Post("/:id/images/upload", requireAuth, update, h.HandleUploadImage)
+	products.Post("/:id/images/base64", requireAuth, update, h.HandleUploadImageBase64)
+	products.Put("/:id/images/order", requireAuth, update, h.HandleReorderImages)
+	products.Put("/:id/images/:imageId/primary", requireAuth, update, h.HandleSetPrimaryImage)
+	products.Delete("/:id/images/:imageId", requireAuth, update, h.HandleDeleteImage)
+}
+
+// CreateProductSKURoute registers the option types and SKUs of a product.
+// "/skus/generate" comes before "/skus/:skuId" so it is not taken for an ID.
+func (r RouterImpl) CreateProductSKURoute(h ports.IProductSKUHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	products := r.route.Group("/products")
+	products.Get("/:id/options", h.HandleListOptions)
+	products.Get("/:id/skus", h.HandleListSKUs)
+
+	update := can(userDomain.PERMISSION_UPDATE_PRODUCT)
+	products.Post("/:id/options", requireAuth, update, h.HandleCreateOption)
+	products.Put("/:id/options/:optionId", requireAuth, update, h.HandleUpdateOption)
+	products.Delete("/:id/options/:optionId", requireAuth, update, h.HandleDeleteOption)
+	products.Post("/:id/options/:optionId/values", requireAuth, update, h.HandleAddOptionValue)
+	products.Delete("/:id/options/:optionId/values/:valueId", requireAuth, update, h.HandleDeleteOptionValue)
+	products.Post("/:id/skus/generate", requireAuth, update, h.HandleGenerateSKUs)
+	products.Post("/:id/skus", requireAuth, update, h.HandleCreateSKU)
+	products.Put("/:id/skus/:skuId", requireAuth, update, h.HandleUpdateSKU)
+	products.Delete("/:id/skus/:skuId", requireAuth, update, h.HandleDeleteSKU)
+}
+
+// CreateCategoryRoute registers the categories. Reads are public. The fixed
+// paths come before "/:id" so they are not taken for an ID.
+func (r RouterImpl) CreateCategoryRoute(h ports.ICategoryHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	categories := r.route.Group("/categories")
+	categories.Get("/", h.HandleListCategories)
+	categories.Get("/tree", h.HandleGetCategoryTree)
+	categories.Get("/slug/:slug", h.HandleGetCategoryBySlug)
+	categories.Get("/:id", h.HandleGetCategory)
+	categories.Get("/:id/tree", h.HandleGetCategorySubtree)
+	categories.Get("/:id/breadcrumbs", h.HandleGetBreadcrumbs)
+
+	manage := can(userDomain.PERMISSION_MANAGE_CATALOG)
+	categories.Post("/", requireAuth, manage, h.HandleCreateCategory)
+	categories.Put("/:id", requireAuth, manage, h.HandleUpdateCategory)
+	categories.Put("/:id/move", requireAuth, manage, h.HandleMoveCategory)
+	categories.Delete("/:id", requireAuth, manage, h.HandleDeleteCategory)
+}
+
+// CreateSearchRoute registers the public full-text product search.
+func (r RouterImpl) CreateSearchRoute(h ports.IProductSearchHandler) {
+	r.route.Get("/search", h.HandleSearchProducts)
+}
+
+// CreateCategoryFacetRoute registers the facet definitions of a category.
+func (r RouterImpl) CreateCategoryFacetRoute(h ports.IProductFacetHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	categories := r.route.Group("/categories")
+	categories.Get("/:id/facets", h.HandleGetCategoryFacets)
+	categories.Put("/:id/facets", requireAuth, can(userDomain.PERMISSION_MANAGE_CATALOG), h.HandleSetCategoryFacets)
+}
+
+// CreateCatalogImportRoute registers the bulk catalog import and export,
+// both limited to PERMISSION_MANAGE_CATALOG.
+func (r RouterImpl) CreateCatalogImportRoute(h ports.ICatalogImportHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	catalog := r.route.Group("/catalog", requireAuth, can(userDomain.PERMISSION_MANAGE_CATALOG))
+	catalog.Get("/export", h.HandleExportCatalog)
+	catalog.Post("/imports", h.HandleStartImport)
+	catalog.Get("/imports", h.HandleListImports)
+	catalog.Get("/imports/:id", h.HandleGetImport)
+	catalog.Get("/imports/:id/report", h.HandleDownloadImportReport)
+}
@@ -0,0 +1,14 @@
+package routers
+
+import (
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/profile"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateProfileRoute(h ports.IProfileHandler, requireAuth fiber.Handler) {
+	profile := r.route.Group("/me/profile", requireAuth)
+	profile.Get("/", h.HandleGetProfile)
+	profile.Patch("/", h.HandleUpdateProfile)
+	profile.Put("/avatar", h.HandleUploadAvatar)
+	profile.Delete("/avatar", h.HandleDeleteAvatar)
+}
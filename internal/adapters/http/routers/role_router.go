@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/gofiber/fiber/v2"
+)
+
+func (r RouterImpl) CreateRoleRoute(h ports.IRoleHandler, requireAuth fiber.Handler, can middlewares.PermissionGuard) {
+	roles := r.route.Group("/admin/roles", requireAuth, can(userDomain.PERMISSION_MANAGE_ROLES))
+	roles.Get("/", h.HandleListRoles)
+	roles.Post("/", h.HandleCreateRole)
+	roles.Get("/:id", h.HandleGetRole)
+	roles.Put("/:id", h.HandleUpdateRole)
+	roles.Delete("/:id", h.HandleDeleteRole)
+	roles.Get("/:id/permissions", h.HandleListRolePermissions)
+	roles.Post("/:id/permissions", h.HandleGrantPermission)
+	roles.Delete("/:id/permissions/:permissionId", h.HandleRevokePermission)
+}
@@ -0,0 +1,20 @@
+package routers
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/http/middlewares"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateUserAdminRoute registers the admin user console. Changes need a
+// session, the read-only routes also accept API keys scoped for MANAGE_USERS.
+func (r RouterImpl) CreateUserAdminRoute(h ports.IUserAdminHandler, requireAuth, requireSession fiber.Handler, can middlewares.PermissionGuard) {
+	manage := can(userDomain.PERMISSION_MANAGE_USERS)
+	r.route.Get("/admin/users", requireAuth, manage, h.HandleListUsers)
+	r.route.Get("/admin/users/:id", requireAuth, manage, h.HandleGetUser)
+	r.route.Get("/admin/users/:id/history", requireAuth, manage, h.HandleListUserHistory)
+	r.route.Put("/admin/users/:id/status", requireSession, manage, h.HandleChangeUserStatus)
+	r.route.Put("/admin/users/:id/role", requireSession, manage, h.HandleAssignUserRole)
+	r.route.Post("/admin/users/:id/logout", requireSession, manage, h.HandleForceLogout)
+}
@@ -0,0 +1,148 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/oauth"
+)
+
+// maxResponseBytes caps how much of a provider response is read.
+const maxResponseBytes = 1 << 20
+
+// HTTPClient implements the provider side of the authorization code flow
+// against the endpoints stored on each OAuthProvider row.
+type HTTPClient struct {
+	client *http.Client
+}
+
+func NewHTTPClient(client *http.Client) ports.IOAuthClient {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPClient{client: client}
+}
+
+// ExchangeCode implements ports.IOAuthClient.
+func (c *HTTPClient) ExchangeCode(ctx context.Context, provider *authDomain.OAuthProvider, clientSecret, code, codeVerifier string) (*authDomain.OAuthTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	return c.tokenRequest(ctx, provider, clientSecret, form)
+}
+
+// RefreshToken implements ports.IOAuthClient.
+func (c *HTTPClient) RefreshToken(ctx context.Context, provider *authDomain.OAuthProvider, clientSecret, refreshToken string) (*authDomain.OAuthTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	return c.tokenRequest(ctx, provider, clientSecret, form)
+}
+
+// UserInfo implements ports.IOAuthClient.
+func (c *HTTPClient) UserInfo(ctx context.Context, provider *authDomain.OAuthProvider, accessToken string) (*authDomain.OAuthUserInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.UserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var claims map[string]interface{}
+	if err := c.do(req, &claims); err != nil {
+		return nil, fmt.Errorf("oauth userinfo: %w", err)
+	}
+
+	info := &authDomain.OAuthUserInfo{
+		Subject:           claimString(claims, "sub"),
+		Email:             claimString(claims, "email"),
+		EmailVerified:     claimBool(claims, "email_verified"),
+		GivenName:         claimString(claims, "given_name"),
+		FamilyName:        claimString(claims, "family_name"),
+		Name:              claimString(claims, "name"),
+		PreferredUsername: claimString(claims, "preferred_username"),
+	}
+	if info.Subject == "" {
+		return nil, fmt.Errorf("oauth userinfo: response has no sub claim")
+	}
+	return info, nil
+}
+
+// tokenRequest posts to the token endpoint using client_secret_post, which
+// public clients without a secret can use as well.
+func (c *HTTPClient) tokenRequest(ctx context.Context, provider *authDomain.OAuthProvider, clientSecret string, form url.Values) (*authDomain.OAuthTokenResponse, error) {
+	form.Set("client_id", provider.ClientID)
+	if clientSecret != "" {
+		form.Set("client_secret", clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token authDomain.OAuthTokenResponse
+	if err := c.do(req, &token); err != nil {
+		return nil, fmt.Errorf("oauth token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("oauth token: response has no access_token")
+	}
+	return &token, nil
+}
+
+func (c *HTTPClient) do(req *http.Request, out interface{}) error {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var providerErr struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		if json.Unmarshal(body, &providerErr) == nil && providerErr.Error != "" {
+			return fmt.Errorf("provider returned %s: %s", providerErr.Error, providerErr.ErrorDescription)
+		}
+		return fmt.Errorf("provider responded with status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
+func claimString(claims map[string]interface{}, key string) string {
+	switch v := claims[key].(type) {
+	case string:
+		return v
+	case float64:
+		// Some providers use numeric user ids.
+		return fmt.Sprintf("%.0f", v)
+	}
+	return ""
+}
+
+func claimBool(claims map[string]interface{}, key string) bool {
+	switch v := claims[key].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
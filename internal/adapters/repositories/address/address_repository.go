@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AddressRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAddressRepository(db *gorm.DB) ports.IAddressRepository {
+	return &AddressRepositoryImpl{db: db}
+}
+
+func defaultColumn(kind userDomain.ADDRESS_KIND) (string, error) {
+	switch kind {
+	case userDomain.ADDRESS_KIND_BILLING:
+		return "is_default_billing", nil
+	case userDomain.ADDRESS_KIND_SHIPPING:
+		return "is_default_shipping", nil
+	}
+	return "", fmt.Errorf("unknown address kind %q", kind)
+}
+
+// ListUserAddresses implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) ListUserAddresses(ctx context.Context, userID uuid.UUID) ([]userDomain.UserAddress, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var addresses []userDomain.UserAddress
+	if err := tx.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default_shipping DESC, is_default_billing DESC, created_at DESC").
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+// GetUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) GetUserAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var address userDomain.UserAddress
+	if err := tx.WithContext(ctx).Where("id = ? AND user_id = ?", addressID, userID).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// GetDefaultUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) GetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error) {
+	column, err := defaultColumn(kind)
+	if err != nil {
+		return nil, err
+	}
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var address userDomain.UserAddress
+	if err := tx.WithContext(ctx).Where("user_id = ? AND "+column, userID).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// CountUserAddresses implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) CountUserAddresses(ctx context.Context, userID uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	err := tx.WithContext(ctx).Model(&userDomain.UserAddress{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// CreateUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) CreateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) UpdateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// DeleteUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) DeleteUserAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Where("id = ? AND user_id = ?", addressID, userID).Delete(&userDomain.UserAddress{}).Error
+}
+
+// UnsetDefaultUserAddress implements ports.IAddressRepository.
+func (r *AddressRepositoryImpl) UnsetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) error {
+	column, err := defaultColumn(kind)
+	if err != nil {
+		return err
+	}
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Model(&userDomain.UserAddress{}).
+		Where("user_id = ? AND "+column, userID).
+		Update(column, false).Error
+}
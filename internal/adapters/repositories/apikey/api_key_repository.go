@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) ports.IAPIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+// CreateAPIKey implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) CreateAPIKey(ctx context.Context, payload *authDomain.APIKey) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetAPIKeyByID implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*authDomain.APIKey, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var key authDomain.APIKey
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// GetAPIKeyByPrefix implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*authDomain.APIKey, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var key authDomain.APIKey
+	if err := tx.WithContext(ctx).Where("prefix = ?", prefix).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListUserAPIKeys implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]authDomain.APIKey, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var keys []authDomain.APIKey
+	if err := tx.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// UpdateAPIKey implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) UpdateAPIKey(ctx context.Context, payload *authDomain.APIKey) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// TouchAPIKey implements ports.IAPIKeyRepository.
+func (r *APIKeyRepositoryImpl) TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
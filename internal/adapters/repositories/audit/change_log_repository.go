@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxChangeLogPageSize = 100
+
+type ChangeLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewChangeLogRepository(db *gorm.DB) ports.IChangeLogRepository {
+	return &ChangeLogRepositoryImpl{db: db}
+}
+
+// CreateChangeLog implements ports.IChangeLogRepository.
+func (r *ChangeLogRepositoryImpl) CreateChangeLog(ctx context.Context, payload *loggingDomain.ChangeLogs) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// ListEntityChangeLogs implements ports.IChangeLogRepository.
+func (r *ChangeLogRepositoryImpl) ListEntityChangeLogs(ctx context.Context, entityType string, entityID uuid.UUID) (pagination.Pagination[[]loggingDomain.ChangeLogs], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	params := pagination.GetFilters[loggingDomain.ChangeLogFilters](ctx)
+	params.RestrictSort([]string{"timestamp", "action"}, "timestamp DESC")
+	params.LimitPageSize(maxChangeLogPageSize)
+
+	query := tx.WithContext(ctx).Model(&loggingDomain.ChangeLogs{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID)
+	query = pagination.ApplyCommaFilter(query, "action", params.Filters.Action)
+	query = pagination.ApplyDatetimeFilters(query, params.Filters.CommonTimeFilters)
+
+	return pagination.Paginate[loggingDomain.ChangeLogFilters, []loggingDomain.ChangeLogs](params, query)
+}
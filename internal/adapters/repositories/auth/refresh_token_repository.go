@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RefreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) ports.IRefreshTokenRepository {
+	return &RefreshTokenRepositoryImpl{db: db}
+}
+
+// CreateRefreshToken implements ports.IRefreshTokenRepository.
+func (r *RefreshTokenRepositoryImpl) CreateRefreshToken(ctx context.Context, payload *authDomain.RefreshToken) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetRefreshTokenByHash implements ports.IRefreshTokenRepository.
+func (r *RefreshTokenRepositoryImpl) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*authDomain.RefreshToken, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var token authDomain.RefreshToken
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token = ?", tokenHash).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed implements ports.IRefreshTokenRepository.
+func (r *RefreshTokenRepositoryImpl) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.RefreshToken{}).
+		Where("id = ?", id).
+		Update("used", true).Error
+}
+
+// RevokeRefreshTokenFamily implements ports.IRefreshTokenRepository.
+func (r *RefreshTokenRepositoryImpl) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.RefreshToken{}).
+		Where("family_id = ? AND revoked = ?", familyID, false).
+		Update("revoked", true).Error
+}
+
+// RevokeUserRefreshTokens implements ports.IRefreshTokenRepository.
+func (r *RefreshTokenRepositoryImpl) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.RefreshToken{}).
+		Where("user_id = ? AND revoked = ?", userID, false).
+		Update("revoked", true).Error
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRecoveryCodeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRecoveryCodeRepository(db *gorm.DB) ports.ITwoFactorRecoveryCodeRepository {
+	return &TwoFactorRecoveryCodeRepositoryImpl{db: db}
+}
+
+// CreateRecoveryCodes implements ports.ITwoFactorRecoveryCodeRepository.
+func (r *TwoFactorRecoveryCodeRepositoryImpl) CreateRecoveryCodes(ctx context.Context, payload []authDomain.TwoFactorRecoveryCode) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(&payload).Error
+}
+
+// ListUnusedRecoveryCodes implements ports.ITwoFactorRecoveryCodeRepository.
+func (r *TwoFactorRecoveryCodeRepositoryImpl) ListUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]authDomain.TwoFactorRecoveryCode, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var codes []authDomain.TwoFactorRecoveryCode
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Find(&codes).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// MarkRecoveryCodeUsed implements ports.ITwoFactorRecoveryCodeRepository.
+func (r *TwoFactorRecoveryCodeRepositoryImpl) MarkRecoveryCodeUsed(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.TwoFactorRecoveryCode{}).
+		Where("id = ?", id).
+		Update("used_at", time.Now()).Error
+}
+
+// DeleteUserRecoveryCodes implements ports.ITwoFactorRecoveryCodeRepository.
+func (r *TwoFactorRecoveryCodeRepositoryImpl) DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Unscoped().
+		Where("user_id = ?", userID).
+		Delete(&authDomain.TwoFactorRecoveryCode{}).Error
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/checkout"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) ports.ICartRepository {
+	return &CartRepositoryImpl{db: db}
+}
+
+// GetActiveCartForUpdate implements ports.ICartRepository.
+func (r *CartRepositoryImpl) GetActiveCartForUpdate(ctx context.Context, userID uuid.UUID) (*cartDomain.Cart, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var cart cartDomain.Cart
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND status = ?", userID, cartDomain.CART_STATUS_ACTIVE).
+		Order("created_at DESC").
+		First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// ListCartItems implements ports.ICartRepository.
+func (r *CartRepositoryImpl) ListCartItems(ctx context.Context, cartID uuid.UUID) ([]cartDomain.CartItem, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var items []cartDomain.CartItem
+	if err := tx.WithContext(ctx).Where("cart_id = ?", cartID).Order("created_at").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateCart implements ports.ICartRepository.
+func (r *CartRepositoryImpl) UpdateCart(ctx context.Context, payload *cartDomain.Cart) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
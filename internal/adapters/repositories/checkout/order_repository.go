@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/checkout"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOrderRepository(db *gorm.DB) ports.IOrderRepository {
+	return &OrderRepositoryImpl{db: db}
+}
+
+// CreateOrder implements ports.IOrderRepository.
+func (r *OrderRepositoryImpl) CreateOrder(ctx context.Context, payload *orderDomain.Order) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetOrderForUpdate implements ports.IOrderRepository.
+func (r *OrderRepositoryImpl) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*orderDomain.Order, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var order orderDomain.Order
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").
+		Where("id = ?", id).
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateOrderStatus implements ports.IOrderRepository.
+func (r *OrderRepositoryImpl) UpdateOrderStatus(ctx context.Context, payload *orderDomain.Order) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(payload).Update("status", payload.Status).Error
+}
+
+// UpdateOrderItemWarehouses implements ports.IOrderRepository.
+func (r *OrderRepositoryImpl) UpdateOrderItemWarehouses(ctx context.Context, items []orderDomain.OrderItem) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	for i := range items {
+		if err := tx.Model(&items[i]).Update("warehouse_id", items[i].WarehouseID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImpersonationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewImpersonationRepository(db *gorm.DB) ports.IImpersonationRepository {
+	return &ImpersonationRepositoryImpl{db: db}
+}
+
+// CreateImpersonationSession implements ports.IImpersonationRepository.
+func (r *ImpersonationRepositoryImpl) CreateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetImpersonationSession implements ports.IImpersonationRepository.
+func (r *ImpersonationRepositoryImpl) GetImpersonationSession(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var session authDomain.ImpersonationSession
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetImpersonationSessionForUpdate implements ports.IImpersonationRepository.
+func (r *ImpersonationRepositoryImpl) GetImpersonationSessionForUpdate(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var session authDomain.ImpersonationSession
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// UpdateImpersonationSession implements ports.IImpersonationRepository.
+func (r *ImpersonationRepositoryImpl) UpdateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
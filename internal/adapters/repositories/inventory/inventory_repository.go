@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stockMovementSortColumns are the columns the movement history can be
+// sorted by.
+var stockMovementSortColumns = []string{"created_at", "type", "quantity"}
+
+const maxMovementPageSize = 100
+
+type InventoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewInventoryRepository(db *gorm.DB) ports.IInventoryRepository {
+	return &InventoryRepositoryImpl{db: db}
+}
+
+// EnsureInventory implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) EnsureInventory(ctx context.Context, skuID, warehouseID uuid.UUID) (*orderDomain.Inventory, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	// product_skus.stock is the total over every warehouse, so what the
+	// other warehouses do not hold is at the default one. Concurrent first
+	// uses race on the unique (sku_id, warehouse_id); only the winner gets
+	// the row back.
+	var created []orderDomain.Inventory
+	if err := tx.WithContext(ctx).Raw(
+		fmt.Sprintf(`INSERT INTO %[1]s (id, product_id, sku_id, warehouse_id, quantity, reserved)
+			SELECT ?, s.product_id, s.id, w.id,
+				CASE WHEN w.is_default THEN GREATEST(s.stock - COALESCE(
+					(SELECT SUM(i.quantity) FROM %[1]s i WHERE i.sku_id = s.id AND i.deleted_at IS NULL), 0), 0)
+				ELSE 0 END,
+				0
+			FROM %[2]s s, %[3]s w
+			WHERE s.id = ? AND s.deleted_at IS NULL AND w.id = ? AND w.deleted_at IS NULL
+			ON CONFLICT (sku_id, warehouse_id) DO NOTHING
+			RETURNING *`, orderDomain.TNInventory, productDomain.TNProductSKU, orderDomain.TNWarehouse),
+		id, skuID, warehouseID,
+	).Scan(&created).Error; err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		return nil, nil
+	}
+	return &created[0], nil
+}
+
+// ReserveStock implements ports.IInventoryRepository.
+//
+// The availability check is part of the UPDATE, so the row lock the database
+// takes for it makes check and reservation one step.
+func (r *InventoryRepositoryImpl) ReserveStock(ctx context.Context, key orderDomain.StockKey, quantity int) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	result := tx.WithContext(ctx).
+		Model(&orderDomain.Inventory{}).
+		Where("sku_id = ? AND warehouse_id = ? AND quantity - reserved >= ?", key.SKUID, key.WarehouseID, quantity).
+		Updates(map[string]interface{}{
+			"reserved":   gorm.Expr("reserved + ?", quantity),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseStock implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ReleaseStock(ctx context.Context, key orderDomain.StockKey, quantity int) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Model(&orderDomain.Inventory{}).
+		Where("sku_id = ? AND warehouse_id = ?", key.SKUID, key.WarehouseID).
+		Updates(map[string]interface{}{
+			"reserved":   gorm.Expr("GREATEST(reserved - ?, 0)", quantity),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
+
+// CommitStock implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) CommitStock(ctx context.Context, key orderDomain.StockKey, quantity int) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	if err := tx.Model(&orderDomain.Inventory{}).
+		Where("sku_id = ? AND warehouse_id = ?", key.SKUID, key.WarehouseID).
+		Updates(map[string]interface{}{
+			"quantity":   gorm.Expr("quantity - ?", quantity),
+			"reserved":   gorm.Expr("GREATEST(reserved - ?, 0)", quantity),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+		return err
+	}
+	return r.syncSKUStock(tx, key.SKUID)
+}
+
+// AddStock implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) AddStock(ctx context.Context, key orderDomain.StockKey, delta int) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	if err := tx.Model(&orderDomain.Inventory{}).
+		Where("sku_id = ? AND warehouse_id = ?", key.SKUID, key.WarehouseID).
+		Updates(map[string]interface{}{
+			"quantity":   gorm.Expr("quantity + ?", delta),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error; err != nil {
+		return err
+	}
+	return r.syncSKUStock(tx, key.SKUID)
+}
+
+// syncSKUStock copies the units on hand at every warehouse into the stock
+// the catalog shows.
+func (r *InventoryRepositoryImpl) syncSKUStock(tx *gorm.DB, skuID uuid.UUID) error {
+	return tx.Model(&productDomain.ProductSKU{}).
+		Where("id = ?", skuID).
+		Update("stock", tx.Session(&gorm.Session{NewDB: true}).
+			Model(&orderDomain.Inventory{}).
+			Select("COALESCE(SUM(quantity), 0)").
+			Where("sku_id = ?", skuID)).Error
+}
+
+// ListInventoriesBySKU implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ListInventoriesBySKU(ctx context.Context, skuID uuid.UUID) ([]orderDomain.Inventory, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var inventories []orderDomain.Inventory
+	if err := tx.WithContext(ctx).Where("sku_id = ?", skuID).Order("warehouse_id").Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+	return inventories, nil
+}
+
+// ListInventoriesForUpdate implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ListInventoriesForUpdate(ctx context.Context, skuIDs []uuid.UUID) ([]orderDomain.Inventory, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var inventories []orderDomain.Inventory
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sku_id IN ?", skuIDs).
+		Order("sku_id, warehouse_id").
+		Find(&inventories).Error; err != nil {
+		return nil, err
+	}
+	return inventories, nil
+}
+
+// GetInventoryForUpdate implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) GetInventoryForUpdate(ctx context.Context, key orderDomain.StockKey) (*orderDomain.Inventory, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var inventory orderDomain.Inventory
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sku_id = ? AND warehouse_id = ?", key.SKUID, key.WarehouseID).
+		First(&inventory).Error; err != nil {
+		return nil, err
+	}
+	return &inventory, nil
+}
+
+// CreateReservation implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) CreateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// ListActiveReservationsForUpdate implements ports.IInventoryRepository.
+//
+// The reservations come in SKU then warehouse order, the order Reserve locks
+// inventory rows in.
+func (r *InventoryRepositoryImpl) ListActiveReservationsForUpdate(ctx context.Context, reference uuid.UUID) ([]orderDomain.InventoryReservation, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var reservations []orderDomain.InventoryReservation
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("reference = ? AND status = ?", reference, orderDomain.RESERVATION_STATUS_ACTIVE).
+		Order("sku_id, warehouse_id").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// ListExpiredReferences implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ListExpiredReferences(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var references []uuid.UUID
+	if err := tx.WithContext(ctx).
+		Model(&orderDomain.InventoryReservation{}).
+		Distinct("reference").
+		Where("status = ? AND expires_at <= ?", orderDomain.RESERVATION_STATUS_ACTIVE, now).
+		Limit(limit).
+		Pluck("reference", &references).Error; err != nil {
+		return nil, err
+	}
+	return references, nil
+}
+
+// UpdateReservation implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) UpdateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// CreateStockMovement implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) CreateStockMovement(ctx context.Context, payload *orderDomain.StockMovement) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// ListStockMovements implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ListStockMovements(ctx context.Context, skuID uuid.UUID) (pagination.Pagination[[]orderDomain.StockMovement], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	params := pagination.GetFilters[orderDomain.StockMovementFilters](ctx)
+	params.RestrictSort(stockMovementSortColumns, "created_at DESC")
+	params.LimitPageSize(maxMovementPageSize)
+
+	query := tx.WithContext(ctx).Model(&orderDomain.StockMovement{}).Where("sku_id = ?", skuID)
+	query = pagination.ApplyCommaFilter(query, "type", params.Filters.Type)
+	if params.Filters.WarehouseID != "" {
+		query = query.Where("warehouse_id = ?", params.Filters.WarehouseID)
+	}
+	query = pagination.ApplyDatetimeFilters(query, params.Filters.CommonTimeFilters)
+	return pagination.Paginate[orderDomain.StockMovementFilters, []orderDomain.StockMovement](params, query)
+}
+
+// ListInventoryDiscrepancies implements ports.IInventoryRepository.
+func (r *InventoryRepositoryImpl) ListInventoryDiscrepancies(ctx context.Context) ([]orderDomain.InventoryDiscrepancy, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var discrepancies []orderDomain.InventoryDiscrepancy
+	err := tx.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT i.sku_id, i.warehouse_id, i.quantity, i.reserved,
+			COALESCE(m.quantity, 0) AS ledger_quantity,
+			COALESCE(m.reserved, 0) AS ledger_reserved
+		FROM %s i
+		LEFT JOIN (
+			SELECT sku_id, warehouse_id, SUM(quantity) AS quantity, SUM(reserved) AS reserved
+			FROM %s GROUP BY sku_id, warehouse_id
+		) m ON m.sku_id = i.sku_id AND m.warehouse_id = i.warehouse_id
+		WHERE i.deleted_at IS NULL
+			AND (i.quantity <> COALESCE(m.quantity, 0) OR i.reserved <> COALESCE(m.reserved, 0))
+		ORDER BY i.sku_id, i.warehouse_id`, orderDomain.TNInventory, orderDomain.TNStockMovement),
+	).Scan(&discrepancies).Error
+	return discrepancies, err
+}
@@ -0,0 +1,110 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase connects to the Postgres database in TEST_DB_URL. The
+// tests write their own rows to it and delete them afterwards.
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	sqlDB.SetMaxOpenConns(32)
+	if err := db.AutoMigrate(&orderDomain.Inventory{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// TestConcurrentReserveStockNeverOversells races more buyers than there are
+// units on separate connections. Each reservation runs in its own
+// transaction that stays open a little after the UPDATE, so the others have
+// to wait for its row lock and check the availability again once it commits.
+func TestConcurrentReserveStockNeverOversells(t *testing.T) {
+	db := openTestDatabase(t)
+	repo := NewInventoryRepository(db)
+	transactor := transactors.NewTransactorRepo(db)
+	ctx := context.Background()
+
+	const stock, buyers = 10, 40
+	key := orderDomain.StockKey{SKUID: uuid.New(), WarehouseID: uuid.New()}
+	row := &orderDomain.Inventory{ProductID: uuid.New(), SKUID: key.SKUID, WarehouseID: key.WarehouseID, Quantity: stock}
+	if err := db.Create(row).Error; err != nil {
+		t.Fatalf("create inventory: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Unscoped().Where("sku_id = ?", key.SKUID).Delete(&orderDomain.Inventory{})
+	})
+
+	var (
+		reserved atomic.Int32
+		wg       sync.WaitGroup
+		start    = make(chan struct{})
+		errs     = make(chan error, buyers)
+	)
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				ok, err := repo.ReserveStock(ctx, key, 1)
+				if err != nil || !ok {
+					return err
+				}
+				reserved.Add(1)
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			})
+			if err != nil {
+				errs <- err
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("ReserveStock: %v", err)
+	}
+
+	if got := reserved.Load(); got != stock {
+		t.Fatalf("%d reservations succeeded, want %d", got, stock)
+	}
+	var stored orderDomain.Inventory
+	if err := db.Where("sku_id = ?", key.SKUID).First(&stored).Error; err != nil {
+		t.Fatalf("read inventory: %v", err)
+	}
+	if stored.Reserved != stock || stored.Quantity != stock {
+		t.Fatalf("quantity = %d, reserved = %d; want %d of %d reserved", stored.Quantity, stored.Reserved, stock, stock)
+	}
+	if ok, err := repo.ReserveStock(ctx, key, 1); err != nil || ok {
+		t.Fatalf("reserving a sold out SKU: ok = %v, err = %v", ok, err)
+	}
+}
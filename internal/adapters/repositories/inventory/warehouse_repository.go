@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WarehouseRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) ports.IWarehouseRepository {
+	return &WarehouseRepositoryImpl{db: db}
+}
+
+// CreateWarehouse implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) CreateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateWarehouse implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) UpdateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// GetWarehouse implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) GetWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var warehouse orderDomain.Warehouse
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&warehouse).Error; err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// GetDefaultWarehouse implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) GetDefaultWarehouse(ctx context.Context) (*orderDomain.Warehouse, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var warehouse orderDomain.Warehouse
+	if err := tx.WithContext(ctx).Where("is_default").First(&warehouse).Error; err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// ListWarehouses implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) ListWarehouses(ctx context.Context, activeOnly bool) ([]orderDomain.Warehouse, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	query := tx.WithContext(ctx).Order("priority, code")
+	if activeOnly {
+		query = query.Where("active")
+	}
+	var warehouses []orderDomain.Warehouse
+	if err := query.Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// ExistsWarehouseCode implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) ExistsWarehouseCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	// Soft deleted rows still hold their code in the unique index.
+	query := tx.WithContext(ctx).Unscoped().Model(&orderDomain.Warehouse{}).Where("code = ?", code)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ClearDefaultWarehouse implements ports.IWarehouseRepository.
+func (r *WarehouseRepositoryImpl) ClearDefaultWarehouse(ctx context.Context, exceptID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Model(&orderDomain.Warehouse{}).
+		Where("is_default AND id <> ?", exceptID).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepositoryImpl keeps the counters in Postgres so that every
+// instance of the API sees the same failures.
+type LoginAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) ports.ILoginAttemptCounter {
+	return &LoginAttemptRepositoryImpl{db: db}
+}
+
+// countFailureSQL counts a failure on an existing row. The right-hand side of
+// every assignment sees the row as it was before the update.
+const countFailureSQL = `
+	failures = CASE
+		WHEN login_attempts.last_failure_at < @window_start OR login_attempts.locked_until <= @now THEN 1
+		ELSE login_attempts.failures + 1 END,
+	first_failure_at = CASE
+		WHEN login_attempts.last_failure_at < @window_start OR login_attempts.locked_until <= @now THEN @now
+		ELSE login_attempts.first_failure_at END,
+	locked_until = CASE
+		WHEN login_attempts.locked_until <= @now THEN NULL
+		ELSE login_attempts.locked_until END,
+	last_failure_at = @now,
+	updated_at = @now`
+
+// registerFailureSQL counts a failure in a single statement so concurrent
+// attempts on different instances cannot lose increments.
+const registerFailureSQL = `
+INSERT INTO login_attempts (id, scope, subject, failures, first_failure_at, last_failure_at, created_at, updated_at)
+VALUES (@id, @scope, @subject, 1, @now, @now, @now, @now)
+ON CONFLICT (scope, subject) DO UPDATE SET` + countFailureSQL + `
+RETURNING *`
+
+// reserveFirstFailureSQL records the first failure of a subject unless another
+// attempt recorded one first.
+const reserveFirstFailureSQL = `
+INSERT INTO login_attempts (id, scope, subject, failures, first_failure_at, last_failure_at, created_at, updated_at)
+VALUES (@id, @scope, @subject, 1, @now, @now, @now, @now)
+ON CONFLICT (scope, subject) DO NOTHING
+RETURNING *`
+
+// reserveFailureSQL counts a failure only on the row as it was read. Every
+// write changes the failures, the last failure or the lock, so a row that
+// still matches has not been counted by anyone else.
+const reserveFailureSQL = `
+UPDATE login_attempts SET` + countFailureSQL + `
+WHERE scope = @scope AND subject = @subject
+	AND failures = @seen_failures
+	AND last_failure_at = @seen_last_failure_at
+	AND locked_until IS NOT DISTINCT FROM @seen_locked_until
+RETURNING *`
+
+// GetAttempt implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) GetAttempt(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) (*authDomain.LoginAttempt, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var attempt authDomain.LoginAttempt
+	err := tx.WithContext(ctx).
+		Where("scope = ? AND subject = ?", scope, subject).
+		First(&attempt).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// RegisterFailure implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) RegisterFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, now, windowStart time.Time) (*authDomain.LoginAttempt, error) {
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var attempt authDomain.LoginAttempt
+	err = tx.WithContext(ctx).Raw(registerFailureSQL, map[string]interface{}{
+		"id":           id,
+		"scope":        scope,
+		"subject":      subject,
+		"now":          now,
+		"window_start": windowStart,
+	}).Scan(&attempt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+// ReserveFailure implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) ReserveFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, seen *authDomain.LoginAttempt, now, windowStart time.Time) (*authDomain.LoginAttempt, error) {
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	query, args := reserveFirstFailureSQL, map[string]interface{}{
+		"id":           id,
+		"scope":        scope,
+		"subject":      subject,
+		"now":          now,
+		"window_start": windowStart,
+	}
+	if seen != nil {
+		query = reserveFailureSQL
+		args["seen_failures"] = seen.Failures
+		args["seen_last_failure_at"] = seen.LastFailureAt
+		args["seen_locked_until"] = seen.LockedUntil
+	}
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var attempt authDomain.LoginAttempt
+	result := tx.WithContext(ctx).Raw(query, args).Scan(&attempt)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+// ReleaseFailure implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) ReleaseFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.LoginAttempt{}).
+		Where("scope = ? AND subject = ? AND failures > 0", scope, subject).
+		Update("failures", gorm.Expr("failures - 1")).Error
+}
+
+// Lock implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) Lock(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, until time.Time) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.LoginAttempt{}).
+		Where("scope = ? AND subject = ?", scope, subject).
+		Update("locked_until", until).Error
+}
+
+// Reset implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) Reset(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Where("scope = ? AND subject = ?", scope, subject).
+		Delete(&authDomain.LoginAttempt{}).Error
+}
+
+// ListLocked implements ports.ILoginAttemptCounter.
+func (r *LoginAttemptRepositoryImpl) ListLocked(ctx context.Context, now time.Time) ([]authDomain.LoginAttempt, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var attempts []authDomain.LoginAttempt
+	err := tx.WithContext(ctx).
+		Where("locked_until > ?", now).
+		Order("locked_until DESC").
+		Find(&attempts).Error
+	if err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
@@ -0,0 +1,106 @@
+//go:build integration
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase connects to the Postgres database in TEST_DB_URL. The
+// tests write their own rows to it and delete them afterwards.
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DB_URL")
+	if dsn == "" {
+		t.Skip("TEST_DB_URL is not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("database handle: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	sqlDB.SetMaxOpenConns(32)
+	if err := db.AutoMigrate(&authDomain.LoginAttempt{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// TestConcurrentLoginAttemptsStopAtTheLimit races more login attempts than
+// the account allows on separate connections, as instances behind a load
+// balancer would. Every attempt is reserved before its password would be
+// checked, so only MaxFailures of them get that far.
+func TestConcurrentLoginAttemptsStopAtTheLimit(t *testing.T) {
+	db := openTestDatabase(t)
+	config := lockoutServices.LockoutServiceConfig{
+		Window:  time.Hour,
+		Account: lockoutServices.LockoutPolicy{FreeFailures: 3, MaxFailures: 5, LockDuration: time.Hour},
+		IP:      lockoutServices.LockoutPolicy{FreeFailures: 10, MaxFailures: 50, LockDuration: time.Hour},
+	}
+	lockout := lockoutServices.NewLockoutService(NewLoginAttemptRepository(db), config)
+	ctx := context.Background()
+
+	account := uuid.NewString()
+	t.Cleanup(func() {
+		db.Where("subject = ?", account).Delete(&authDomain.LoginAttempt{})
+	})
+
+	const attempts = 40
+	var (
+		reserved atomic.Int32
+		wg       sync.WaitGroup
+		start    = make(chan struct{})
+		errs     = make(chan error, attempts)
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			err := lockout.ReserveAttempt(ctx, account, "")
+			switch {
+			case err == nil:
+				reserved.Add(1)
+			case !errors.Is(err, lockoutServices.ErrAccountLocked) && !errors.Is(err, lockoutServices.ErrLoginThrottled):
+				errs <- err
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("ReserveAttempt: %v", err)
+	}
+
+	if got := reserved.Load(); got != int32(config.Account.MaxFailures) {
+		t.Fatalf("%d attempts reserved, want %d", got, config.Account.MaxFailures)
+	}
+	var stored authDomain.LoginAttempt
+	if err := db.Where("scope = ? AND subject = ?", authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account).First(&stored).Error; err != nil {
+		t.Fatalf("read counter: %v", err)
+	}
+	if stored.Failures != config.Account.MaxFailures {
+		t.Fatalf("failures = %d, want %d", stored.Failures, config.Account.MaxFailures)
+	}
+	if locked, err := lockout.RegisterFailure(ctx, account, ""); err != nil || !locked {
+		t.Fatalf("RegisterFailure = %v, %v; want the account locked", locked, err)
+	}
+}
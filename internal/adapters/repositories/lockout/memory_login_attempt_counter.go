@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+)
+
+type loginAttemptKey struct {
+	scope   authDomain.LOGIN_ATTEMPT_SCOPE
+	subject string
+}
+
+// MemoryLoginAttemptCounter keeps the counters in process. It only protects a
+// single instance; use NewLoginAttemptRepository when running several.
+type MemoryLoginAttemptCounter struct {
+	mu        sync.Mutex
+	attempts  map[loginAttemptKey]*authDomain.LoginAttempt
+	lastPrune time.Time
+}
+
+func NewMemoryLoginAttemptCounter() ports.ILoginAttemptCounter {
+	return &MemoryLoginAttemptCounter{attempts: map[loginAttemptKey]*authDomain.LoginAttempt{}}
+}
+
+// GetAttempt implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) GetAttempt(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) (*authDomain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	attempt, ok := m.attempts[loginAttemptKey{scope, subject}]
+	if !ok {
+		return nil, nil
+	}
+	clone := *attempt
+	return &clone, nil
+}
+
+// RegisterFailure implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) RegisterFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, now, windowStart time.Time) (*authDomain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.countFailure(scope, subject, now, windowStart), nil
+}
+
+// ReserveFailure implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) ReserveFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, seen *authDomain.LoginAttempt, now, windowStart time.Time) (*authDomain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.attempts[loginAttemptKey{scope, subject}]
+	if ok != (seen != nil) || ok && !sameCount(current, seen) {
+		return nil, nil
+	}
+	return m.countFailure(scope, subject, now, windowStart), nil
+}
+
+// ReleaseFailure implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) ReleaseFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempt, ok := m.attempts[loginAttemptKey{scope, subject}]; ok && attempt.Failures > 0 {
+		attempt.Failures--
+	}
+	return nil
+}
+
+// Lock implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) Lock(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if attempt, ok := m.attempts[loginAttemptKey{scope, subject}]; ok {
+		attempt.LockedUntil = &until
+	}
+	return nil
+}
+
+// Reset implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) Reset(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.attempts, loginAttemptKey{scope, subject})
+	return nil
+}
+
+// ListLocked implements ports.ILoginAttemptCounter.
+func (m *MemoryLoginAttemptCounter) ListLocked(ctx context.Context, now time.Time) ([]authDomain.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	locked := []authDomain.LoginAttempt{}
+	for _, attempt := range m.attempts {
+		if attempt.IsLocked(now) {
+			locked = append(locked, *attempt)
+		}
+	}
+	sort.Slice(locked, func(i, j int) bool {
+		return locked[i].LockedUntil.After(*locked[j].LockedUntil)
+	})
+	return locked, nil
+}
+
+// countFailure counts a failure at now and returns a copy of the counter. The
+// caller must hold m.mu.
+func (m *MemoryLoginAttemptCounter) countFailure(scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, now, windowStart time.Time) *authDomain.LoginAttempt {
+	m.prune(now, windowStart)
+
+	key := loginAttemptKey{scope, subject}
+	attempt, ok := m.attempts[key]
+	switch {
+	case !ok:
+		attempt = &authDomain.LoginAttempt{Scope: scope, Subject: subject, CreatedAt: now}
+		m.attempts[key] = attempt
+		fallthrough
+	case attempt.LastFailureAt.Before(windowStart), attempt.LockedUntil != nil && !now.Before(*attempt.LockedUntil):
+		attempt.Failures = 0
+		attempt.FirstFailureAt = now
+		attempt.LockedUntil = nil
+	}
+	attempt.Failures++
+	attempt.LastFailureAt = now
+	attempt.UpdatedAt = now
+
+	clone := *attempt
+	return &clone
+}
+
+// sameCount reports whether two reads of a counter saw the same failures and
+// lock.
+func sameCount(a, b *authDomain.LoginAttempt) bool {
+	if a.Failures != b.Failures || !a.LastFailureAt.Equal(b.LastFailureAt) {
+		return false
+	}
+	if a.LockedUntil == nil || b.LockedUntil == nil {
+		return a.LockedUntil == b.LockedUntil
+	}
+	return a.LockedUntil.Equal(*b.LockedUntil)
+}
+
+// prune drops idle counters at most once a minute so that attackers rotating
+// identifiers or addresses cannot grow the map without bound.
+func (m *MemoryLoginAttemptCounter) prune(now, windowStart time.Time) {
+	if now.Sub(m.lastPrune) < time.Minute {
+		return
+	}
+	m.lastPrune = now
+	for key, attempt := range m.attempts {
+		if attempt.LastFailureAt.Before(windowStart) && !attempt.IsLocked(now) {
+			delete(m.attempts, key)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/oauth"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OAuthProviderRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOAuthProviderRepository(db *gorm.DB) ports.IOAuthProviderRepository {
+	return &OAuthProviderRepositoryImpl{db: db}
+}
+
+// ListProviders implements ports.IOAuthProviderRepository.
+func (r *OAuthProviderRepositoryImpl) ListProviders(ctx context.Context) ([]authDomain.OAuthProvider, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var providers []authDomain.OAuthProvider
+	if err := tx.WithContext(ctx).Order("provider_name").Find(&providers).Error; err != nil {
+		return nil, err
+	}
+	return providers, nil
+}
+
+// GetProviderByID implements ports.IOAuthProviderRepository.
+func (r *OAuthProviderRepositoryImpl) GetProviderByID(ctx context.Context, id uuid.UUID) (*authDomain.OAuthProvider, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var provider authDomain.OAuthProvider
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&provider).Error; err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// GetProviderByName implements ports.IOAuthProviderRepository.
+func (r *OAuthProviderRepositoryImpl) GetProviderByName(ctx context.Context, name string) (*authDomain.OAuthProvider, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var provider authDomain.OAuthProvider
+	if err := tx.WithContext(ctx).Where("LOWER(provider_name) = LOWER(?)", name).First(&provider).Error; err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// CreateProvider implements ports.IOAuthProviderRepository.
+func (r *OAuthProviderRepositoryImpl) CreateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateProvider implements ports.IOAuthProviderRepository.
+func (r *OAuthProviderRepositoryImpl) UpdateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+type OAuthRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOAuthRepository(db *gorm.DB) ports.IOAuthRepository {
+	return &OAuthRepositoryImpl{db: db}
+}
+
+// CreateState implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) CreateState(ctx context.Context, payload *authDomain.OAuthState) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetStateByHash implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) GetStateByHash(ctx context.Context, stateHash string) (*authDomain.OAuthState, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var state authDomain.OAuthState
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("state_hash = ?", stateHash).
+		First(&state).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// MarkStateUsed implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) MarkStateUsed(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&authDomain.OAuthState{}).
+		Where("id = ?", id).
+		Update("used", true).Error
+}
+
+// GetIdentity implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) GetIdentity(ctx context.Context, providerID uuid.UUID, subject string) (*authDomain.OAuthIdentity, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var identity authDomain.OAuthIdentity
+	if err := tx.WithContext(ctx).
+		Where("provider_id = ? AND subject = ?", providerID, subject).
+		First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// GetUserIdentity implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) GetUserIdentity(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthIdentity, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var identity authDomain.OAuthIdentity
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? AND provider_id = ?", userID, providerID).
+		First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// CreateIdentity implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) CreateIdentity(ctx context.Context, payload *authDomain.OAuthIdentity) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpsertAccessToken implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) UpsertAccessToken(ctx context.Context, payload *authDomain.OAuthAccessToken) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"access_token", "token_type", "expires_in", "expires_at", "updated_at"}),
+		}).
+		Create(payload).Error
+}
+
+// UpsertRefreshToken implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) UpsertRefreshToken(ctx context.Context, payload *authDomain.OAuthRefreshToken) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "provider_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"refresh_token", "updated_at"}),
+		}).
+		Create(payload).Error
+}
+
+// GetRefreshToken implements ports.IOAuthRepository.
+func (r *OAuthRepositoryImpl) GetRefreshToken(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthRefreshToken, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var token authDomain.OAuthRefreshToken
+	if err := tx.WithContext(ctx).
+		Where("user_id = ? AND provider_id = ?", userID, providerID).
+		First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
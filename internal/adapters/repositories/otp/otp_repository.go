@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OTPRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewOTPRepository(db *gorm.DB) ports.IOTPRepository {
+	return &OTPRepositoryImpl{db: db}
+}
+
+// CreateOTP implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) CreateOTP(ctx context.Context, payload *messageDomain.OTP) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// CountOTPsSince implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) CountOTPsSince(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, since time.Time) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	if err := tx.WithContext(ctx).Model(&messageDomain.OTP{}).
+		Where("user_id = ? AND purpose = ? AND created_at >= ?", userID, purpose, since).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetLatestUnusedOTP implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) GetLatestUnusedOTP(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) (*messageDomain.OTP, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var otp messageDomain.OTP
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND purpose = ? AND used = ?", userID, purpose, false).
+		Order("created_at DESC").
+		First(&otp).Error; err != nil {
+		return nil, err
+	}
+	return &otp, nil
+}
+
+// InvalidateUnusedOTPs implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) InvalidateUnusedOTPs(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&messageDomain.OTP{}).
+		Where("user_id = ? AND purpose = ? AND used = ?", userID, purpose, false).
+		Update("used", true).Error
+}
+
+// IncrementOTPAttempts implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) IncrementOTPAttempts(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&messageDomain.OTP{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// MarkOTPUsed implements ports.IOTPRepository.
+func (r *OTPRepositoryImpl) MarkOTPUsed(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&messageDomain.OTP{}).
+		Where("id = ?", id).
+		Update("used", true).Error
+}
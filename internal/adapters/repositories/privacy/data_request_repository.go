@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/privacy"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DataRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewDataRequestRepository(db *gorm.DB) ports.IDataRequestRepository {
+	return &DataRequestRepositoryImpl{db: db}
+}
+
+// CreateDataRequest implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) CreateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetDataRequestForUpdate implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) GetDataRequestForUpdate(ctx context.Context, id uuid.UUID) (*privacyDomain.DataRequest, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var request privacyDomain.DataRequest
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ListUserDataRequests implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) ListUserDataRequests(ctx context.Context, userID uuid.UUID) ([]privacyDomain.DataRequest, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var requests []privacyDomain.DataRequest
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ListDataRequestsByStatus implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) ListDataRequestsByStatus(ctx context.Context, requestType privacyDomain.DATA_REQUEST_TYPE, status privacyDomain.DATA_REQUEST_STATUS, limit int) ([]privacyDomain.DataRequest, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var requests []privacyDomain.DataRequest
+	if err := tx.WithContext(ctx).
+		Where("type = ? AND status = ?", requestType, status).
+		Order("created_at").
+		Limit(limit).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// ListExpiredExports implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) ListExpiredExports(ctx context.Context, before time.Time, limit int) ([]privacyDomain.DataRequest, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var requests []privacyDomain.DataRequest
+	if err := tx.WithContext(ctx).
+		Where("type = ? AND status = ? AND expires_at < ?", privacyDomain.DATA_REQUEST_TYPE_EXPORT, privacyDomain.DATA_REQUEST_STATUS_COMPLETED, before).
+		Order("expires_at").
+		Limit(limit).
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// HasOpenDataRequest implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) HasOpenDataRequest(ctx context.Context, userID uuid.UUID, requestType privacyDomain.DATA_REQUEST_TYPE) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&privacyDomain.DataRequest{}).
+		Where("user_id = ? AND type = ? AND status IN ?", userID, requestType, []privacyDomain.DATA_REQUEST_STATUS{
+			privacyDomain.DATA_REQUEST_STATUS_PENDING,
+			privacyDomain.DATA_REQUEST_STATUS_PROCESSING,
+		}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// UpdateDataRequest implements ports.IDataRequestRepository.
+func (r *DataRequestRepositoryImpl) UpdateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
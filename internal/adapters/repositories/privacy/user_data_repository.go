@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/privacy"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// erasedShippingAddress replaces the shipping address of erased users' orders.
+const erasedShippingAddress = "[erased]"
+
+type UserDataRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewUserDataRepository(db *gorm.DB) ports.IUserDataRepository {
+	return &UserDataRepositoryImpl{db: db}
+}
+
+// CollectUserData implements ports.IUserDataRepository.
+//
+// Soft deleted rows are included, they are still data held about the user.
+func (r *UserDataRepositoryImpl) CollectUserData(ctx context.Context, userID uuid.UUID) (*privacyDomain.UserDataExport, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	export := &privacyDomain.UserDataExport{}
+
+	if err := tx.Where("id = ?", userID).First(&export.User).Error; err != nil {
+		return nil, err
+	}
+	var profile userDomain.UserProfile
+	switch err := tx.Where("user_id = ?", userID).First(&profile).Error; {
+	case err == nil:
+		export.Profile = &profile
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	var carts []cartDomain.Cart
+	queries := []struct {
+		dest  interface{}
+		query *gorm.DB
+	}{
+		{&export.Addresses, tx.Where("user_id = ?", userID).Order("created_at")},
+		{&carts, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.Orders, tx.Unscoped().
+			Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Preload("ShippingInfo", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Preload("BillingInfo", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+			Where("created_by = ?", userID).Order("order_date")},
+		{&export.Payments, tx.Unscoped().Where("order_id IN (?)", tx.Unscoped().Model(&orderDomain.Order{}).Select("id").Where("created_by = ?", userID)).Order("payment_date")},
+		{&export.Reviews, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.ShippingReviews, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.Emails, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.OTPs, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.RefreshTokens, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.APIKeys, tx.Where("user_id = ?", userID).Order("created_at")},
+		{&export.OAuthIdentities, tx.Unscoped().Where("user_id = ?", userID).Order("created_at")},
+		{&export.DataRequests, tx.Where("user_id = ?", userID).Order("created_at")},
+	}
+	for _, q := range queries {
+		if err := q.query.Find(q.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cart := range carts {
+		entry := privacyDomain.CartExport{Cart: cart}
+		if err := tx.Unscoped().Where("cart_id = ?", cart.ID).Order("created_at").Find(&entry.Items).Error; err != nil {
+			return nil, err
+		}
+		export.Carts = append(export.Carts, entry)
+	}
+	return export, nil
+}
+
+// EraseUserData implements ports.IUserDataRepository.
+//
+// Rows are removed with hard deletes so that soft deleted copies do not keep
+// the personal data around. Reviews stay, attributed to the anonymised user,
+// and billing addresses stay on the orders as invoices must be retained.
+func (r *UserDataRepositoryImpl) EraseUserData(ctx context.Context, anonymised *userDomain.User) (*privacyDomain.ErasureSummary, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	userID := anonymised.ID
+	summary := &privacyDomain.ErasureSummary{}
+
+	if err := tx.Save(anonymised).Error; err != nil {
+		return nil, err
+	}
+
+	userCarts := tx.Unscoped().Model(&cartDomain.Cart{}).Select("id").Where("user_id = ?", userID)
+	deletes := []struct {
+		model interface{}
+		query string
+		args  []interface{}
+	}{
+		{&cartDomain.CartItem{}, "cart_id IN (?)", []interface{}{userCarts}},
+		{&cartDomain.Cart{}, "user_id = ?", []interface{}{userID}},
+		{&userDomain.UserProfile{}, "user_id = ?", []interface{}{userID}},
+		{&userDomain.UserAddress{}, "user_id = ?", []interface{}{userID}},
+		{&messageDomain.Email{}, "user_id = ?", []interface{}{userID}},
+		{&messageDomain.OTP{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.RefreshToken{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.TwoFactorRecoveryCode{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.APIKey{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.OAuthIdentity{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.OAuthAccessToken{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.OAuthRefreshToken{}, "user_id = ?", []interface{}{userID}},
+		{&authDomain.LoginAttempt{}, "scope = ? AND subject = ?", []interface{}{authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, userID.String()}},
+	}
+	for _, d := range deletes {
+		result := tx.Unscoped().Where(d.query, d.args...).Delete(d.model)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		summary.DeletedRecords += result.RowsAffected
+	}
+
+	userOrders := tx.Unscoped().Model(&orderDomain.Order{}).Select("id").Where("created_by = ?", userID)
+	result := tx.Unscoped().Model(&orderDomain.ShippingInfo{}).
+		Where("order_id IN (?)", userOrders).
+		Updates(map[string]interface{}{
+			"address":             erasedShippingAddress,
+			"address_recipient":   "",
+			"address_line1":       "",
+			"address_line2":       "",
+			"address_city":        "",
+			"address_region":      "",
+			"address_postal_code": "",
+			"address_phone":       "",
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	summary.AnonymisedOrders = result.RowsAffected
+
+	if err := tx.Unscoped().Model(&orderDomain.BillingInfo{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"email":         anonymised.Email,
+			"phone":         "",
+			"address_phone": "",
+		}).Error; err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// catalogImportSortColumns are the columns the import list can be sorted by.
+var catalogImportSortColumns = []string{"created_at", "updated_at", "status"}
+
+type CatalogImportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCatalogImportRepository(db *gorm.DB) ports.ICatalogImportRepository {
+	return &CatalogImportRepositoryImpl{db: db}
+}
+
+// CreateCatalogImportJob implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) CreateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// GetCatalogImportJob implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) GetCatalogImportJob(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var job productDomain.CatalogImportJob
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetCatalogImportJobForUpdate implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) GetCatalogImportJobForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var job productDomain.CatalogImportJob
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// ListCatalogImportJobs implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) ListCatalogImportJobs(ctx context.Context) (pagination.Pagination[[]productDomain.CatalogImportJob], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	params := pagination.GetFilters[productDomain.CatalogImportFilters](ctx)
+	params.RestrictSort(catalogImportSortColumns, "created_at DESC")
+	params.LimitPageSize(maxProductPageSize)
+
+	query := tx.WithContext(ctx).Model(&productDomain.CatalogImportJob{})
+	query = pagination.ApplyCommaFilter(query, "status", params.Filters.Status)
+	query = pagination.ApplyDatetimeFilters(query, params.Filters.CommonTimeFilters)
+	return pagination.Paginate[productDomain.CatalogImportFilters, []productDomain.CatalogImportJob](params, query)
+}
+
+// ListCatalogImportJobsByStatus implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) ListCatalogImportJobsByStatus(ctx context.Context, status productDomain.CATALOG_IMPORT_STATUS, limit int) ([]productDomain.CatalogImportJob, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var jobs []productDomain.CatalogImportJob
+	if err := tx.WithContext(ctx).
+		Where("status = ?", status).
+		Order("created_at").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ListStaleCatalogImportJobs implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) ListStaleCatalogImportJobs(ctx context.Context, before time.Time, limit int) ([]productDomain.CatalogImportJob, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var jobs []productDomain.CatalogImportJob
+	if err := tx.WithContext(ctx).
+		Where("status = ? AND updated_at < ?", productDomain.CATALOG_IMPORT_STATUS_PROCESSING, before).
+		Order("updated_at").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// UpdateCatalogImportJob implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) UpdateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// GetProductBySKUForUpdate implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) GetProductBySKUForUpdate(ctx context.Context, sku string) (*productDomain.Product, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var product productDomain.Product
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sku = ?", sku).
+		First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetSKUByCodeForUpdate implements ports.ICatalogImportRepository.
+func (r *CatalogImportRepositoryImpl) GetSKUByCodeForUpdate(ctx context.Context, code string) (*productDomain.ProductSKU, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var sku productDomain.ProductSKU
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("OptionValues").
+		Where("code = ?", code).
+		First(&sku).Error; err != nil {
+		return nil, err
+	}
+	return &sku, nil
+}
+
+// EachCatalogBatch implements ports.ICatalogImportRepository.
+//
+// Batches are taken by keyset on the product ID rather than by offset, so
+// every batch costs the same however deep into the catalog it is.
+func (r *CatalogImportRepositoryImpl) EachCatalogBatch(ctx context.Context, batchSize int, fn func(rows []productDomain.CatalogRow) error) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	var lastID *uuid.UUID
+	for {
+		query := tx.Order("id").Limit(batchSize)
+		if lastID != nil {
+			query = query.Where("id > ?", *lastID)
+		}
+		var products []productDomain.Product
+		if err := query.Find(&products).Error; err != nil {
+			return err
+		}
+		if len(products) == 0 {
+			return nil
+		}
+		if err := attachProductRelations(tx, products); err != nil {
+			return err
+		}
+		slugs, err := r.categorySlugs(tx, products)
+		if err != nil {
+			return err
+		}
+
+		var rows []productDomain.CatalogRow
+		for i := range products {
+			rows = append(rows, catalogRows(&products[i], slugs[products[i].CategoryID])...)
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(products) < batchSize {
+			return nil
+		}
+		lastID = &products[len(products)-1].ID
+	}
+}
+
+// categorySlugs maps the category IDs of the products to their slugs. Soft
+// deleted categories are included so their products still name one.
+func (r *CatalogImportRepositoryImpl) categorySlugs(tx *gorm.DB, products []productDomain.Product) (map[uuid.UUID]string, error) {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.CategoryID)
+	}
+	var categories []productDomain.Category
+	if err := tx.Unscoped().Select("id", "slug").Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	slugs := make(map[uuid.UUID]string, len(categories))
+	for _, category := range categories {
+		slugs[category.ID] = category.Slug
+	}
+	return slugs, nil
+}
+
+// catalogRows flattens a product loaded with attachProductRelations.
+func catalogRows(product *productDomain.Product, categorySlug string) []productDomain.CatalogRow {
+	price := product.Price
+	base := productDomain.CatalogRow{
+		ProductSKU:   product.SKU,
+		Name:         product.Name,
+		Description:  product.Description,
+		Category:     categorySlug,
+		ProductPrice: &price,
+	}
+	if categorySlug == "" {
+		base.Category = product.CategoryID.String()
+	}
+	if len(product.SKUs) == 0 {
+		return []productDomain.CatalogRow{base}
+	}
+
+	optionNames := make(map[uuid.UUID]string, len(product.Options))
+	for _, option := range product.Options {
+		optionNames[option.ID] = option.Name
+	}
+	rows := make([]productDomain.CatalogRow, 0, len(product.SKUs))
+	for _, sku := range product.SKUs {
+		row := base
+		row.SKU = sku.Code
+		row.Barcode = sku.Barcode
+		row.Price = sku.Price
+		row.WeightGrams = sku.WeightGrams
+		row.Stock = sku.Stock
+		if len(sku.OptionValues) > 0 {
+			row.Options = make(map[string]string, len(sku.OptionValues))
+			for _, value := range sku.OptionValues {
+				row.Options[optionNames[value.OptionID]] = value.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
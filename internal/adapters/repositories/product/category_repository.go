@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// categorySortColumns are the columns the category list can be sorted by.
+var categorySortColumns = []string{"created_at", "updated_at", "name", "slug", "position"}
+
+// subtreeIDsSQL selects the category matched by id or slug and all of its
+// descendants. UNION rather than UNION ALL stops at a cycle should one ever
+// reach the table.
+const subtreeIDsSQL = `
+WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE (id::text = @root OR slug = @root) AND deleted_at IS NULL
+	UNION
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+)
+SELECT id FROM subtree`
+
+// ancestorsSQL walks parent_id up from a category; maxCategoryDepth bounds the
+// walk.
+const ancestorsSQL = `
+WITH RECURSIVE chain AS (
+	SELECT categories.*, 0 AS depth FROM categories WHERE id = @id AND deleted_at IS NULL
+	UNION ALL
+	SELECT c.*, chain.depth + 1 FROM categories c JOIN chain ON c.id = chain.parent_id
+	WHERE c.deleted_at IS NULL AND chain.depth < @max
+)
+SELECT * FROM chain ORDER BY depth DESC`
+
+const maxCategoryDepth = 64
+
+// categoryTreeLockKey is the advisory lock taken by LockCategoryTree.
+const categoryTreeLockKey = "categories.tree"
+
+// subtreeIDs builds the subquery behind ListCategorySubtreeIDs; root is a
+// category ID or slug.
+func subtreeIDs(tx *gorm.DB, root string) *gorm.DB {
+	return tx.Raw(subtreeIDsSQL, sql.Named("root", root))
+}
+
+type CategoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) ports.ICategoryRepository {
+	return &CategoryRepositoryImpl{db: db}
+}
+
+// ListCategories implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) ListCategories(ctx context.Context) (pagination.Pagination[[]productDomain.Category], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	params := pagination.GetFilters[productDomain.CategoryFilters](ctx)
+	params.RestrictSort(categorySortColumns, "position ASC, name ASC")
+	params.LimitPageSize(maxProductPageSize)
+
+	query := tx.WithContext(ctx).Model(&productDomain.Category{})
+	if params.Filters.Search != "" {
+		query = query.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(params.Filters.Search)+"%")
+	}
+	if params.Filters.ParentID != "" {
+		if params.Filters.ParentID == "root" {
+			query = query.Where("parent_id IS NULL")
+		} else {
+			query = query.Where("parent_id = ?", params.Filters.ParentID)
+		}
+	}
+	return pagination.Paginate[productDomain.CategoryFilters, []productDomain.Category](params, query)
+}
+
+// GetCategory implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) GetCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var category productDomain.Category
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListAllCategories implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) ListAllCategories(ctx context.Context) ([]productDomain.Category, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var categories []productDomain.Category
+	if err := tx.WithContext(ctx).Order("position, name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetCategoryForUpdate implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var category productDomain.Category
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetCategoryBySlug implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) GetCategoryBySlug(ctx context.Context, slug string) (*productDomain.Category, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var category productDomain.Category
+	if err := tx.WithContext(ctx).Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ExistsCategorySlug implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) ExistsCategorySlug(ctx context.Context, slug string, exceptID *uuid.UUID) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	// Soft deleted rows still hold their slug in the unique index.
+	query := tx.WithContext(ctx).Unscoped().Model(&productDomain.Category{}).Where("slug = ?", slug)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountCategoryChildren implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) CountCategoryChildren(ctx context.Context, parentID *uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	query := tx.WithContext(ctx).Model(&productDomain.Category{})
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListCategorySubtreeIDs implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) ListCategorySubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var ids []uuid.UUID
+	if err := subtreeIDs(tx.WithContext(ctx), id.String()).Scan(&ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListCategoryAncestors implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) ListCategoryAncestors(ctx context.Context, id uuid.UUID) ([]productDomain.Category, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var categories []productDomain.Category
+	if err := tx.WithContext(ctx).
+		Raw(ancestorsSQL, sql.Named("id", id), sql.Named("max", maxCategoryDepth)).
+		Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// LockCategoryTree implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) LockCategoryTree(ctx context.Context) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtext(?))", categoryTreeLockKey).Error
+}
+
+// CreateCategory implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) CreateCategory(ctx context.Context, payload *productDomain.Category) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateCategory implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) UpdateCategory(ctx context.Context, payload *productDomain.Category) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// DeleteCategory implements ports.ICategoryRepository.
+func (r *CategoryRepositoryImpl) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	result := tx.WithContext(ctx).Where("id = ?", id).Delete(&productDomain.Category{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
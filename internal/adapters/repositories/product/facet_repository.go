@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// facetOptionJoinSQL joins the option values of the listed products for an
+// option facet.
+const facetOptionJoinSQL = `JOIN product_options o ON o.product_id = base.id AND o.deleted_at IS NULL
+	JOIN product_option_values v ON v.option_id = o.id AND v.deleted_at IS NULL`
+
+type ProductFacetRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductFacetRepository(db *gorm.DB) ports.IProductFacetRepository {
+	return &ProductFacetRepositoryImpl{db: db}
+}
+
+// CountProductFacets implements ports.IProductFacetRepository.
+//
+// The listed products are selected once into a CTE together with one boolean
+// column per facet filter; each facet then counts its buckets over the rows
+// that pass every filter but its own, and the counts of all facets come back
+// from a single UNION ALL.
+func (r *ProductFacetRepositoryImpl) CountProductFacets(ctx context.Context, facets []productDomain.CategoryFacet) ([]productDomain.FacetCount, error) {
+	if len(facets) == 0 {
+		return nil, nil
+	}
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	filters := pagination.GetFilters[productDomain.ProductFilters](ctx).Filters
+	flags := facetFilters(filters)
+
+	columns := []string{"products.id", "products.price", "products.category_id", productRatingSQL + " AS rating"}
+	var columnArgs []interface{}
+	for i, flag := range flags {
+		columns = append(columns, fmt.Sprintf("(%s) AS f%d", flag.sql, i))
+		columnArgs = append(columnArgs, flag.args...)
+	}
+	base := applyProductFilters(tx, tx.Model(&productDomain.Product{}), filters).
+		Select(strings.Join(columns, ", "), columnArgs...)
+
+	args := []interface{}{base}
+	parts := make([]string, 0, len(facets))
+	for i, facet := range facets {
+		where := facetConditions(facet, flags)
+		switch facet.Kind {
+		case productDomain.FacetKindCategory:
+			parts = append(parts, fmt.Sprintf("SELECT %d AS facet, category_id::text AS bucket, '' AS label, COUNT(*) AS count FROM base WHERE %s GROUP BY 2", i, where))
+		case productDomain.FacetKindPrice:
+			bucket, bucketArgs := priceBandSQL(facet.Bands())
+			parts = append(parts, fmt.Sprintf("SELECT %d, %s, '', COUNT(*) FROM base WHERE %s GROUP BY 2", i, bucket, where))
+			args = append(args, bucketArgs...)
+		case productDomain.FacetKindRating:
+			parts = append(parts, fmt.Sprintf("SELECT %d, FLOOR(rating)::int::text, '', COUNT(*) FROM base WHERE rating IS NOT NULL AND %s GROUP BY 2", i, where))
+		case productDomain.FacetKindOption:
+			parts = append(parts, fmt.Sprintf("SELECT %d, LOWER(v.value), MIN(v.value), COUNT(DISTINCT base.id) FROM base %s WHERE LOWER(o.name) = ? AND %s GROUP BY 2", i, facetOptionJoinSQL, where))
+			args = append(args, strings.ToLower(facet.OptionName))
+		}
+	}
+	if len(parts) == 0 {
+		return nil, nil
+	}
+
+	var counts []productDomain.FacetCount
+	if err := tx.Raw("WITH base AS (?) "+strings.Join(parts, " UNION ALL "), args...).Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// facetConditions keeps the filter columns of the base CTE that the facet
+// does not ignore.
+func facetConditions(facet productDomain.CategoryFacet, flags []facetFilter) string {
+	conditions := []string{"TRUE"}
+	for i, flag := range flags {
+		if flag.kind == facet.Kind && (flag.kind != productDomain.FacetKindOption || flag.option == strings.ToLower(facet.OptionName)) {
+			continue
+		}
+		conditions = append(conditions, fmt.Sprintf("f%d", i))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// priceBandSQL numbers the price bands: 0 below the first limit, 1 between
+// the first two, and len(bands) from the last limit up.
+func priceBandSQL(bands []float64) (string, []interface{}) {
+	if len(bands) == 0 {
+		return "'0'", nil
+	}
+	var sql strings.Builder
+	args := make([]interface{}, len(bands))
+	sql.WriteString("CASE")
+	for i, limit := range bands {
+		fmt.Fprintf(&sql, " WHEN price < ? THEN '%d'", i)
+		args[i] = limit
+	}
+	fmt.Fprintf(&sql, " ELSE '%d' END", len(bands))
+	return sql.String(), args
+}
+
+// ListCategoryFacets implements ports.IProductFacetRepository.
+func (r *ProductFacetRepositoryImpl) ListCategoryFacets(ctx context.Context, categoryIDs []uuid.UUID) ([]productDomain.CategoryFacet, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var facets []productDomain.CategoryFacet
+	if len(categoryIDs) == 0 {
+		return facets, nil
+	}
+	if err := tx.WithContext(ctx).
+		Where("category_id IN ?", categoryIDs).
+		Order("position, created_at").
+		Find(&facets).Error; err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// ReplaceCategoryFacets implements ports.IProductFacetRepository.
+func (r *ProductFacetRepositoryImpl) ReplaceCategoryFacets(ctx context.Context, categoryID uuid.UUID, facets []productDomain.CategoryFacet) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	if err := tx.Where("category_id = ?", categoryID).Delete(&productDomain.CategoryFacet{}).Error; err != nil {
+		return err
+	}
+	if len(facets) == 0 {
+		return nil
+	}
+	return tx.Create(&facets).Error
+}
@@ -0,0 +1,332 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productSortColumns are the columns the product list can be sorted by.
+var productSortColumns = []string{"created_at", "updated_at", "name", "sku", "price"}
+
+const maxProductPageSize = 100
+
+type ProductRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductRepository(db *gorm.DB) ports.IProductRepository {
+	return &ProductRepositoryImpl{db: db}
+}
+
+// productRatingSQL is the average review rating of a product, NULL without
+// reviews.
+const productRatingSQL = "(SELECT AVG(reviews.rating) FROM reviews WHERE reviews.product_id = products.id AND reviews.deleted_at IS NULL)"
+
+// productOptionSQL matches products with one of the values of an option.
+const productOptionSQL = `EXISTS (SELECT 1 FROM product_options o JOIN product_option_values v ON v.option_id = o.id
+	WHERE o.product_id = products.id AND o.deleted_at IS NULL AND v.deleted_at IS NULL
+	AND LOWER(o.name) = ? AND LOWER(v.value) IN ?)`
+
+// facetFilter is a filter that a facet of the same kind ignores when counting
+// its buckets.
+type facetFilter struct {
+	kind   string // productDomain.FacetKindPrice, FacetKindRating or FacetKindOption
+	option string // Option filters: the lowercased option name
+	sql    string
+	args   []interface{}
+}
+
+// ListProducts implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) ListProducts(ctx context.Context) (pagination.Pagination[[]productDomain.Product], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	params := pagination.GetFilters[productDomain.ProductFilters](ctx)
+	params.RestrictSort(productSortColumns, "created_at DESC")
+	params.LimitPageSize(maxProductPageSize)
+
+	query := applyProductFilters(tx.WithContext(ctx), tx.WithContext(ctx).Model(&productDomain.Product{}), params.Filters)
+	for _, filter := range facetFilters(params.Filters) {
+		query = query.Where(filter.sql, filter.args...)
+	}
+
+	page, err := pagination.Paginate[productDomain.ProductFilters, []productDomain.Product](params, query)
+	if err != nil {
+		return page, err
+	}
+	// Preloading inside Paginate would also run on its COUNT query, so the
+	// relations of the page are loaded afterwards.
+	if err := attachProductRelations(tx.WithContext(ctx), page.Rows); err != nil {
+		return pagination.Pagination[[]productDomain.Product]{}, err
+	}
+	return page, nil
+}
+
+// applyProductFilters applies the filters of a product listing that no facet
+// covers; facetFilters returns the others.
+func applyProductFilters(tx, query *gorm.DB, filters productDomain.ProductFilters) *gorm.DB {
+	query = pagination.ApplyCommaFilter(query, "products.category_id", filters.CategoryID)
+	query = pagination.ApplyCommaFilter(query, "products.created_by", filters.SellerID)
+	query = pagination.ApplyCommaFilter(query, "products.sku", filters.SKU)
+	if filters.InCategory != "" {
+		query = query.Where("products.category_id IN (?)", subtreeIDs(tx, filters.InCategory))
+	}
+	if filters.Search != "" {
+		like := "%" + strings.ToLower(filters.Search) + "%"
+		query = query.Where("LOWER(products.name) LIKE ? OR LOWER(products.sku) LIKE ?", like, like)
+	}
+	return pagination.ApplyDatetimeFilters(query, filters.CommonTimeFilters)
+}
+
+// facetFilters returns the price, rating and option filters of a listing.
+func facetFilters(filters productDomain.ProductFilters) []facetFilter {
+	var result []facetFilter
+	if filters.MinPrice != nil {
+		result = append(result, facetFilter{kind: productDomain.FacetKindPrice, sql: "products.price >= ?", args: []interface{}{*filters.MinPrice}})
+	}
+	if filters.MaxPrice != nil {
+		result = append(result, facetFilter{kind: productDomain.FacetKindPrice, sql: "products.price <= ?", args: []interface{}{*filters.MaxPrice}})
+	}
+	if from, below, err := productDomain.ParsePriceBand(filters.PriceBand); err == nil {
+		if from != nil {
+			result = append(result, facetFilter{kind: productDomain.FacetKindPrice, sql: "products.price >= ?", args: []interface{}{*from}})
+		}
+		if below != nil {
+			result = append(result, facetFilter{kind: productDomain.FacetKindPrice, sql: "products.price < ?", args: []interface{}{*below}})
+		}
+	}
+	if filters.MinRating != nil {
+		result = append(result, facetFilter{kind: productDomain.FacetKindRating, sql: productRatingSQL + " >= ?", args: []interface{}{*filters.MinRating}})
+	}
+
+	// Sorted so that the same filters always build the same SQL.
+	names := make([]string, 0, len(filters.Options))
+	for name := range filters.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := make([]string, len(filters.Options[name]))
+		for i, value := range filters.Options[name] {
+			values[i] = strings.ToLower(value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		option := strings.ToLower(name)
+		result = append(result, facetFilter{kind: productDomain.FacetKindOption, option: option, sql: productOptionSQL, args: []interface{}{option, values}})
+	}
+	return result
+}
+
+// attachProductRelations fills Options, SKUs and PrimaryImage of the
+// products with one query per relation.
+func attachProductRelations(tx *gorm.DB, products []productDomain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(products))
+	for i := range products {
+		ids[i] = products[i].ID
+	}
+
+	var options []productDomain.ProductOption
+	if err := preloadOptionValues(tx).Where("product_id IN ?", ids).Order("position, created_at").Find(&options).Error; err != nil {
+		return err
+	}
+	var skus []productDomain.ProductSKU
+	if err := tx.Preload("OptionValues").Where("product_id IN ?", ids).Order("created_at").Find(&skus).Error; err != nil {
+		return err
+	}
+	var images []productDomain.ProductImage
+	if err := tx.Preload("Renditions").Where("product_id IN ? AND is_primary = ?", ids, true).Find(&images).Error; err != nil {
+		return err
+	}
+
+	index := make(map[uuid.UUID]*productDomain.Product, len(products))
+	for i := range products {
+		products[i].Options = []productDomain.ProductOption{}
+		products[i].SKUs = []productDomain.ProductSKU{}
+		index[products[i].ID] = &products[i]
+	}
+	for _, option := range options {
+		product := index[option.ProductID]
+		product.Options = append(product.Options, option)
+	}
+	for _, sku := range skus {
+		product := index[sku.ProductID]
+		product.SKUs = append(product.SKUs, sku)
+	}
+	for i := range images {
+		index[images[i].ProductID].PrimaryImage = &images[i]
+	}
+	return nil
+}
+
+// GetProduct implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var product productDomain.Product
+	if err := tx.WithContext(ctx).
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return preloadOptionValues(db).Order("position, created_at") }).
+		Preload("SKUs", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Preload("SKUs.OptionValues").
+		Preload("PrimaryImage", "is_primary = ?", true).
+		Preload("PrimaryImage.Renditions").
+		Where("id = ?", id).
+		First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductForUpdate implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) GetProductForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var product productDomain.Product
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ExistsProductSKU implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) ExistsProductSKU(ctx context.Context, sku string, exceptID *uuid.UUID) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	// Soft deleted rows still hold their SKU in the unique index.
+	query := tx.WithContext(ctx).Unscoped().Model(&productDomain.Product{}).Where("sku = ?", sku)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CountCategoryProducts implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) CountCategoryProducts(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	if err := tx.WithContext(ctx).Model(&productDomain.Product{}).Where("category_id = ?", categoryID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateProduct implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) CreateProduct(ctx context.Context, payload *productDomain.Product) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Omit(clause.Associations).Create(payload).Error
+}
+
+// UpdateProduct implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) UpdateProduct(ctx context.Context, payload *productDomain.Product) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(payload).Error
+}
+
+// DeleteProduct implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	options := tx.Model(&productDomain.ProductOption{}).Select("id").Where("product_id = ?", id)
+	if err := tx.Where("option_id IN (?)", options).Delete(&productDomain.ProductOptionValue{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id = ?", id).Delete(&productDomain.ProductOption{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id = ?", id).Delete(&productDomain.ProductSKU{}).Error; err != nil {
+		return err
+	}
+	images := tx.Model(&productDomain.ProductImage{}).Select("id").Where("product_id = ?", id)
+	if err := tx.Where("image_id IN (?)", images).Delete(&productDomain.ProductImageRendition{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id = ?", id).Delete(&productDomain.ProductImage{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id = ?", id).Delete(&productDomain.Product{}).Error
+}
+
+// ListProductImages implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) ListProductImages(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductImage, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var images []productDomain.ProductImage
+	if err := tx.WithContext(ctx).
+		Preload("Renditions").
+		Where("product_id = ?", productID).
+		Order("position, created_at").
+		Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// GetProductImage implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) GetProductImage(ctx context.Context, productID, imageID uuid.UUID) (*productDomain.ProductImage, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var image productDomain.ProductImage
+	if err := tx.WithContext(ctx).Preload("Renditions").Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateProductImage implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) CreateProductImage(ctx context.Context, payload *productDomain.ProductImage) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateProductImage implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) UpdateProductImage(ctx context.Context, payload *productDomain.ProductImage) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(payload).Error
+}
+
+// DeleteProductImage implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) DeleteProductImage(ctx context.Context, productID, imageID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	result := tx.Where("id = ? AND product_id = ?", imageID, productID).Delete(&productDomain.ProductImage{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return tx.Where("image_id = ?", imageID).Delete(&productDomain.ProductImageRendition{}).Error
+}
+
+// UnsetPrimaryProductImage implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) UnsetPrimaryProductImage(ctx context.Context, productID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).
+		Model(&productDomain.ProductImage{}).
+		Where("product_id = ? AND is_primary = ?", productID, true).
+		Update("is_primary", false).Error
+}
+
+// SetProductImagePositions implements ports.IProductRepository.
+func (r *ProductRepositoryImpl) SetProductImagePositions(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	for position, id := range imageIDs {
+		if err := tx.Model(&productDomain.ProductImage{}).
+			Where("id = ? AND product_id = ?", id, productID).
+			Update("position", position).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// searchSortColumns are the columns search results can be sorted by besides
+// their rank.
+var searchSortColumns = []string{"rank", "created_at", "name", "price"}
+
+// typoRankWeight scales the trigram similarity of the name into the rank, so
+// a product found only by typo tolerance ranks below full-text matches.
+const typoRankWeight = 0.5
+
+// highlightStart and highlightStop delimit matches in ts_headline output.
+// They are private use characters so that the text can be HTML escaped
+// before they are swapped for <mark> tags.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+const (
+	nameHeadlineOptions        = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", HighlightAll=true"
+	descriptionHeadlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop + ", MaxFragments=2, MaxWords=20, MinWords=5"
+)
+
+// searchHitSQL selects the rank and highlights of a match. The description
+// is only highlighted when it matches; ts_headline would otherwise return its
+// first words.
+const searchHitSQL = `products.id,
+	ts_rank_cd(products.search_vector, query, 32) + word_similarity(?, products.name) * ? AS rank,
+	ts_headline('english', products.name, query, ?) AS name_highlight,
+	CASE WHEN to_tsvector('english', products.description) @@ query
+		THEN ts_headline('english', products.description, query, ?) ELSE '' END AS description_highlight`
+
+type productSearchRow struct {
+	ID                   uuid.UUID
+	Rank                 float64
+	NameHighlight        string
+	DescriptionHighlight string
+}
+
+type ProductSearchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductSearchRepository(db *gorm.DB) ports.IProductSearchRepository {
+	return &ProductSearchRepositoryImpl{db: db}
+}
+
+// SearchProducts implements ports.IProductSearchRepository.
+func (r *ProductSearchRepositoryImpl) SearchProducts(ctx context.Context) (pagination.Pagination[[]productDomain.ProductSearchHit], error) {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	params := pagination.GetFilters[productDomain.ProductSearchFilters](ctx)
+	params.RestrictSort(searchSortColumns, "rank DESC, created_at DESC")
+	params.LimitPageSize(maxProductPageSize)
+	filters := params.Filters
+
+	// <% is the trigram word similarity operator; it lets "shrit" find
+	// "T-Shirt" and is served by the trigram index on name.
+	query := tx.Model(&productDomain.Product{}).
+		Joins("CROSS JOIN websearch_to_tsquery('english', ?) AS query", filters.Query).
+		Where("(products.search_vector @@ query OR ? <% products.name)", filters.Query)
+	if filters.InCategory != "" {
+		query = query.Where("products.category_id IN (?)", subtreeIDs(tx, filters.InCategory))
+	}
+	if filters.MinPrice != nil {
+		query = query.Where("products.price >= ?", *filters.MinPrice)
+	}
+	if filters.MaxPrice != nil {
+		query = query.Where("products.price <= ?", *filters.MaxPrice)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, err
+	}
+	var rows []productSearchRow
+	if err := query.
+		Select(searchHitSQL, filters.Query, typoRankWeight, nameHeadlineOptions, descriptionHeadlineOptions).
+		Order(params.GetSort()).
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Scan(&rows).Error; err != nil {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, err
+	}
+
+	hits, err := loadSearchHits(tx, rows)
+	if err != nil {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, err
+	}
+	return pagination.NewPage(params, total, hits), nil
+}
+
+// loadSearchHits loads the products of the rows with their relations and
+// keeps the rank order of the rows.
+func loadSearchHits(tx *gorm.DB, rows []productSearchRow) ([]productDomain.ProductSearchHit, error) {
+	hits := make([]productDomain.ProductSearchHit, 0, len(rows))
+	if len(rows) == 0 {
+		return hits, nil
+	}
+	ids := make([]uuid.UUID, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	var products []productDomain.Product
+	if err := tx.Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	if err := attachProductRelations(tx, products); err != nil {
+		return nil, err
+	}
+
+	index := make(map[uuid.UUID]productDomain.Product, len(products))
+	for _, product := range products {
+		index[product.ID] = product
+	}
+	for _, row := range rows {
+		product, ok := index[row.ID]
+		if !ok {
+			continue
+		}
+		hits = append(hits, productDomain.ProductSearchHit{
+			Product: product,
+			Rank:    row.Rank,
+			Highlight: productDomain.ProductSearchHighlight{
+				Name:        markHighlight(row.NameHighlight),
+				Description: markHighlight(row.DescriptionHighlight),
+			},
+		})
+	}
+	return hits, nil
+}
+
+// markHighlight escapes the headline and wraps its matches in <mark> tags.
+func markHighlight(headline string) string {
+	return strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>").Replace(html.EscapeString(headline))
+}
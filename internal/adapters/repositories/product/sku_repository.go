@@ -0,0 +1,186 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProductSKURepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProductSKURepository(db *gorm.DB) ports.IProductSKURepository {
+	return &ProductSKURepositoryImpl{db: db}
+}
+
+// preloadOptionValues loads the values of options in position order.
+func preloadOptionValues(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Values", func(db *gorm.DB) *gorm.DB { return db.Order("position, created_at") })
+}
+
+// ListProductOptions implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) ListProductOptions(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductOption, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var options []productDomain.ProductOption
+	if err := preloadOptionValues(tx.WithContext(ctx)).
+		Where("product_id = ?", productID).
+		Order("position, created_at").
+		Find(&options).Error; err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// GetProductOption implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) GetProductOption(ctx context.Context, productID, optionID uuid.UUID) (*productDomain.ProductOption, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var option productDomain.ProductOption
+	if err := preloadOptionValues(tx.WithContext(ctx)).
+		Where("id = ? AND product_id = ?", optionID, productID).
+		First(&option).Error; err != nil {
+		return nil, err
+	}
+	return &option, nil
+}
+
+// CreateProductOption implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) CreateProductOption(ctx context.Context, payload *productDomain.ProductOption) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateProductOption implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) UpdateProductOption(ctx context.Context, payload *productDomain.ProductOption) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(payload).Error
+}
+
+// DeleteProductOption implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) DeleteProductOption(ctx context.Context, productID, optionID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db).WithContext(ctx)
+	result := tx.Where("id = ? AND product_id = ?", optionID, productID).Delete(&productDomain.ProductOption{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return tx.Where("option_id = ?", optionID).Delete(&productDomain.ProductOptionValue{}).Error
+}
+
+// CreateProductOptionValue implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) CreateProductOptionValue(ctx context.Context, payload *productDomain.ProductOptionValue) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// DeleteProductOptionValue implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) DeleteProductOptionValue(ctx context.Context, optionID, valueID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	result := tx.WithContext(ctx).Where("id = ? AND option_id = ?", valueID, optionID).Delete(&productDomain.ProductOptionValue{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountProductSKUs implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) CountProductSKUs(ctx context.Context, productID uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	if err := tx.WithContext(ctx).Model(&productDomain.ProductSKU{}).Where("product_id = ?", productID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountSKUsWithOptionValue implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) CountSKUsWithOptionValue(ctx context.Context, valueID uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	if err := tx.WithContext(ctx).
+		Model(&productDomain.ProductSKU{}).
+		Joins("JOIN product_sku_option_values v ON v.sku_id = product_skus.id").
+		Where("v.option_value_id = ?", valueID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListProductSKUs implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) ListProductSKUs(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductSKU, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var skus []productDomain.ProductSKU
+	if err := tx.WithContext(ctx).
+		Preload("OptionValues").
+		Where("product_id = ?", productID).
+		Order("created_at").
+		Find(&skus).Error; err != nil {
+		return nil, err
+	}
+	return skus, nil
+}
+
+// GetProductSKU implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) GetProductSKU(ctx context.Context, productID, skuID uuid.UUID) (*productDomain.ProductSKU, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var sku productDomain.ProductSKU
+	if err := tx.WithContext(ctx).
+		Preload("OptionValues").
+		Where("id = ? AND product_id = ?", skuID, productID).
+		First(&sku).Error; err != nil {
+		return nil, err
+	}
+	return &sku, nil
+}
+
+// ExistsSKUCode implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) ExistsSKUCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	// Soft deleted rows still hold their code in the unique index.
+	query := tx.WithContext(ctx).Unscoped().Model(&productDomain.ProductSKU{}).Where("code = ?", code)
+	if exceptID != nil {
+		query = query.Where("id <> ?", *exceptID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// CreateProductSKU implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) CreateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	// The option values exist already; only the join rows are written.
+	return tx.WithContext(ctx).Omit("OptionValues.*").Create(payload).Error
+}
+
+// UpdateProductSKU implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) UpdateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(payload).Error
+}
+
+// DeleteProductSKU implements ports.IProductSKURepository.
+func (r *ProductSKURepositoryImpl) DeleteProductSKU(ctx context.Context, productID, skuID uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	result := tx.WithContext(ctx).Where("id = ? AND product_id = ?", skuID, productID).Delete(&productDomain.ProductSKU{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
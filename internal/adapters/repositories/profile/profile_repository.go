@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/profile"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ProfileRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) ports.IProfileRepository {
+	return &ProfileRepositoryImpl{db: db}
+}
+
+// GetProfileByUserID implements ports.IProfileRepository.
+func (r *ProfileRepositoryImpl) GetProfileByUserID(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var profile userDomain.UserProfile
+	if err := tx.WithContext(ctx).Where("user_id = ?", userID).First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// GetProfileByUserIDForUpdate implements ports.IProfileRepository.
+func (r *ProfileRepositoryImpl) GetProfileByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var profile userDomain.UserProfile
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&profile).Error; err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// CreateProfile implements ports.IProfileRepository.
+func (r *ProfileRepositoryImpl) CreateProfile(ctx context.Context, payload *userDomain.UserProfile) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateProfile implements ports.IProfileRepository.
+func (r *ProfileRepositoryImpl) UpdateProfile(ctx context.Context, payload *userDomain.UserProfile) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
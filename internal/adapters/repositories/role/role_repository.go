@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) ports.IRoleRepository {
+	return &RoleRepositoryImpl{db: db}
+}
+
+// ListRoles implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) ListRoles(ctx context.Context) ([]userDomain.UserRole, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var roles []userDomain.UserRole
+	if err := tx.WithContext(ctx).Order("role_name ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// GetRoleByID implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) GetRoleByID(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var role userDomain.UserRole
+	if err := tx.WithContext(ctx).Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRoleByName implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var role userDomain.UserRole
+	if err := tx.WithContext(ctx).Where("role_name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// CreateRole implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) CreateRole(ctx context.Context, payload *userDomain.UserRole) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateRole implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) UpdateRole(ctx context.Context, payload *userDomain.UserRole) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Model(&userDomain.UserRole{}).
+		Where("id = ?", payload.ID).
+		Updates(map[string]interface{}{
+			"role_name":   payload.RoleName,
+			"description": payload.Description,
+		}).Error
+}
+
+// DeleteRole implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	if err := tx.WithContext(ctx).Where("role_id = ?", id).Delete(&userDomain.UserPermission{}).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Where("id = ?", id).Delete(&userDomain.UserRole{}).Error
+}
+
+// CountUsersWithRole implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) CountUsersWithRole(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var count int64
+	if err := tx.WithContext(ctx).Model(&userDomain.User{}).Where("role_id = ?", roleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListRolePermissions implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var permissions []userDomain.UserPermission
+	if err := tx.WithContext(ctx).Where("role_id = ?", roleID).Order("permission ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetPermissionByID implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) GetPermissionByID(ctx context.Context, id uuid.UUID) (*userDomain.UserPermission, error) {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	var permission userDomain.UserPermission
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&permission).Error; err != nil {
+		return nil, err
+	}
+	return &permission, nil
+}
+
+// CreatePermission implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) CreatePermission(ctx context.Context, payload *userDomain.UserPermission) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// DeletePermission implements ports.IRoleRepository.
+func (r *RoleRepositoryImpl) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, r.db)
+	return tx.WithContext(ctx).Where("id = ?", id).Delete(&userDomain.UserPermission{}).Error
+}
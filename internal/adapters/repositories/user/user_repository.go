@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// userSortColumns are the columns the admin user list can be sorted by.
+var userSortColumns = []string{"created_at", "updated_at", "last_login", "email", "username", "first_name", "last_name", "status"}
+
+const maxUserPageSize = 100
+
+type UserRepositoryImpl struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) ports.IUserRepository {
+	return &UserRepositoryImpl{db: db}
+}
+
+// GetUserByID implements ports.IUserRepository.
+func (u *UserRepositoryImpl) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	var user userDomain.User
+	if err := tx.WithContext(ctx).Where("id = ?", id).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByIDForUpdate implements ports.IUserRepository.
+func (u *UserRepositoryImpl) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	var user userDomain.User
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByUsernameOrEmail implements ports.IUserRepository.
+func (u *UserRepositoryImpl) GetUserByUsernameOrEmail(ctx context.Context, identifier string) (*userDomain.User, error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	var user userDomain.User
+	if err := tx.WithContext(ctx).
+		Where("username = ? OR LOWER(email) = LOWER(?)", identifier, identifier).
+		First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ExistsByUsernameOrEmail implements ports.IUserRepository.
+//
+// Login looks identifiers up in both columns, so the username is also checked
+// against the emails and the email against the usernames.
+func (u *UserRepositoryImpl) ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	var count int64
+	if err := tx.WithContext(ctx).Model(&userDomain.User{}).
+		Where("username IN (?, ?) OR LOWER(email) IN (LOWER(?), LOWER(?))", username, email, email, username).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListUsersByRole implements ports.IUserRepository.
+func (u *UserRepositoryImpl) ListUsersByRole(ctx context.Context, roleID uuid.UUID) ([]userDomain.User, error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	var users []userDomain.User
+	if err := tx.WithContext(ctx).
+		Where("role_id = ?", roleID).
+		Order("created_at DESC").
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListUsers implements ports.IUserRepository.
+func (u *UserRepositoryImpl) ListUsers(ctx context.Context) (pagination.Pagination[[]userDomain.User], error) {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	params := pagination.GetFilters[userDomain.UserFilters](ctx)
+	params.RestrictSort(userSortColumns, "created_at DESC")
+	params.LimitPageSize(maxUserPageSize)
+	filters := params.Filters
+
+	query := tx.WithContext(ctx).Model(&userDomain.User{})
+	query = pagination.ApplyCommaFilter(query, "status", filters.Status)
+	if filters.Role != "" {
+		if roleID, err := uuid.Parse(filters.Role); err == nil {
+			query = query.Where("role_id = ?", roleID)
+		} else {
+			roles := tx.WithContext(ctx).Model(&userDomain.UserRole{}).Select("id").Where("LOWER(role_name) = LOWER(?)", filters.Role)
+			query = query.Where("role_id IN (?)", roles)
+		}
+	}
+	query = pagination.AddWhereClauseIfNotEmpty(query, "email", filters.Email, "like")
+	if filters.Search != "" {
+		like := "%" + strings.ToLower(filters.Search) + "%"
+		query = query.Where("LOWER(username) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?", like, like, like)
+	}
+	query = pagination.ApplyDatetimeFilters(query, filters.CommonTimeFilters)
+
+	return pagination.Paginate[userDomain.UserFilters, []userDomain.User](params, query)
+}
+
+// CreateUser implements ports.IUserRepository.
+func (u *UserRepositoryImpl) CreateUser(ctx context.Context, payload *userDomain.User) error {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	return tx.WithContext(ctx).Create(payload).Error
+}
+
+// UpdateUser implements ports.IUserRepository.
+func (u *UserRepositoryImpl) UpdateUser(ctx context.Context, payload *userDomain.User) error {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	return tx.WithContext(ctx).Save(payload).Error
+}
+
+// UpdateLastLogin implements ports.IUserRepository.
+func (u *UserRepositoryImpl) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	return tx.WithContext(ctx).Model(&userDomain.User{}).
+		Where("id = ?", id).
+		UpdateColumn("last_login", at).Error
+}
+
+// MarkEmailVerified implements ports.IUserRepository.
+func (u *UserRepositoryImpl) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	tx := transactors.HelperExtractTx(ctx, u.db)
+	return tx.WithContext(ctx).Model(&userDomain.User{}).
+		Where("id = ?", id).
+		UpdateColumn("email_verified", true).Error
+}
@@ -0,0 +1,64 @@
+package senders
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+)
+
+// ChannelOTPSender routes each message to the sender registered for its channel.
+type ChannelOTPSender struct {
+	senders map[messageDomain.OTP_CHANNEL]ports.IOTPSender
+}
+
+func NewChannelOTPSender(senders map[messageDomain.OTP_CHANNEL]ports.IOTPSender) ports.IOTPSender {
+	return &ChannelOTPSender{senders: senders}
+}
+
+// Send implements ports.IOTPSender.
+func (s *ChannelOTPSender) Send(ctx context.Context, message messageDomain.OTPMessage) error {
+	sender, ok := s.senders[message.Channel]
+	if !ok {
+		return fmt.Errorf("no sender configured for channel %q", message.Channel)
+	}
+	return sender.Send(ctx, message)
+}
+
+// LogOTPSender writes codes to the application log instead of delivering them.
+// It is meant for local development only.
+type LogOTPSender struct{}
+
+func NewLogOTPSender() ports.IOTPSender {
+	return &LogOTPSender{}
+}
+
+// Send implements ports.IOTPSender.
+func (s *LogOTPSender) Send(ctx context.Context, message messageDomain.OTPMessage) error {
+	log.Printf("otp: %s code for %s via %s: %s (expires %s)",
+		message.Purpose, message.Destination, message.Channel, message.Code, message.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+func otpSubject(purpose messageDomain.OTP_PURPOSE) string {
+	switch purpose {
+	case messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION:
+		return "Verify your email address"
+	case messageDomain.OTP_PURPOSE_PASSWORDLESS_LOGIN:
+		return "Your sign-in code"
+	case messageDomain.OTP_PURPOSE_PASSWORD_RESET:
+		return "Reset your password"
+	case messageDomain.OTP_PURPOSE_ACCOUNT_UNLOCK:
+		return "Unlock your account"
+	}
+	return "Your verification code"
+}
+
+func otpBody(message messageDomain.OTPMessage) string {
+	minutes := int(time.Until(message.ExpiresAt).Round(time.Minute).Minutes())
+	return fmt.Sprintf("Your code is %s. It expires in %d minutes. If you did not request it, you can ignore this message.",
+		message.Code, minutes)
+}
@@ -0,0 +1,59 @@
+package senders
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+)
+
+// SMSGatewayOTPSender delivers codes through an HTTP SMS gateway that accepts
+// a JSON body of the form {"to": "+66...", "message": "..."}.
+type SMSGatewayOTPSender struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewSMSGatewayOTPSender(url, token string) ports.IOTPSender {
+	return &SMSGatewayOTPSender{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Send implements ports.IOTPSender.
+func (s *SMSGatewayOTPSender) Send(ctx context.Context, message messageDomain.OTPMessage) error {
+	payload, err := json.Marshal(map[string]string{
+		"to":      message.Destination,
+		"message": otpBody(message),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
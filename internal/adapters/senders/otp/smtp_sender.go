@@ -0,0 +1,55 @@
+package senders
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+)
+
+// SMTPConfig describes the mail server used by SMTPOTPSender.
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPOTPSender delivers codes by email.
+type SMTPOTPSender struct {
+	config SMTPConfig
+}
+
+func NewSMTPOTPSender(config SMTPConfig) ports.IOTPSender {
+	return &SMTPOTPSender{config: config}
+}
+
+// Send implements ports.IOTPSender.
+func (s *SMTPOTPSender) Send(ctx context.Context, message messageDomain.OTPMessage) error {
+	if strings.ContainsAny(message.Destination, "\r\n") {
+		return fmt.Errorf("invalid email address %q", message.Destination)
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+
+	body := strings.Join([]string{
+		"From: " + s.config.From,
+		"To: " + message.Destination,
+		"Subject: " + otpSubject(message.Purpose),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		otpBody(message),
+	}, "\r\n")
+
+	addr := net.JoinHostPort(s.config.Host, s.config.Port)
+	return smtp.SendMail(addr, auth, s.config.From, []string{message.Destination}, []byte(body))
+}
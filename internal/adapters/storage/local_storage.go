@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+)
+
+var ErrInvalidStorageKey = errors.New("storage: invalid object key")
+
+// LocalStorage writes objects below a directory on the local filesystem. The
+// directory is served by the HTTP server at publicURL, so it only suits a
+// single instance or a shared volume. A LocalStorage without a publicURL
+// keeps private files that are only read back through Get.
+type LocalStorage struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStorage(root, publicURL string) ports.IFileStorage {
+	return &LocalStorage{root: root, publicURL: strings.TrimRight(publicURL, "/")}
+}
+
+// Put implements ports.IFileStorage.
+//
+// The file is written to a temporary name and renamed so readers never see a
+// partial object.
+func (s *LocalStorage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return "", err
+	}
+	return s.publicURL + "/" + key, nil
+}
+
+// Get implements ports.IFileStorage.
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(name)
+}
+
+// Delete implements ports.IFileStorage.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path maps a key to a file below root, refusing keys that would escape it.
+func (s *LocalStorage) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// validKey refuses keys that are absolute or climb out of their root.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("%w: %q", ErrInvalidStorageKey, key)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/sigv4"
+)
+
+// S3Config locates a bucket of an S3-compatible object store.
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.ap-southeast-1.amazonaws.com or http://localhost:9000 for MinIO
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PublicURL is the prefix objects are fetched from, e.g. a CDN. It
+	// defaults to the path-style object URL.
+	PublicURL string
+}
+
+// S3Storage keeps objects in an S3 bucket using path-style requests, which
+// every S3-compatible store understands.
+type S3Storage struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Storage(config S3Config, client *http.Client) ports.IFileStorage {
+	config.Endpoint = strings.TrimRight(config.Endpoint, "/")
+	if config.PublicURL == "" {
+		config.PublicURL = config.Endpoint + "/" + config.Bucket
+	}
+	config.PublicURL = strings.TrimRight(config.PublicURL, "/")
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &S3Storage{config: config, client: client, now: time.Now}
+}
+
+// Put implements ports.IFileStorage.
+func (s *S3Storage) Put(ctx context.Context, key, contentType string, data []byte) (string, error) {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", s3Error(resp)
+	}
+	return s.config.PublicURL + "/" + key, nil
+}
+
+// Get implements ports.IFileStorage.
+func (s *S3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3Error(resp)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Delete implements ports.IFileStorage.
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *S3Storage) do(ctx context.Context, method, key, contentType string, data []byte) (*http.Response, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	url := s.config.Endpoint + "/" + s.config.Bucket + "/" + key
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	sigv4.Sign(req, sigv4.PayloadHash(data), sigv4.Credentials{
+		AccessKeyID:     s.config.AccessKeyID,
+		SecretAccessKey: s.config.SecretAccessKey,
+	}, s.config.Region, "s3", s.now())
+	return s.client.Do(req)
+}
+
+// s3Error reports a failed request with the start of the XML error body.
+func s3Error(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("storage: s3 responded %s: %s", resp.Status, strings.TrimSpace(string(body)))
+}
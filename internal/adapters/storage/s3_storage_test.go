@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/pkg/sigv4"
+)
+
+// fakeS3 stands in for an S3-compatible store: a path-style bucket in memory
+// that refuses unsigned requests and payloads that do not match their hash.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+		http.Error(w, "<Error><Code>AccessDenied</Code></Error>", http.StatusForbidden)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if r.Header.Get(sigv4.HeaderContentSHA256) != sigv4.PayloadHash(body) {
+		http.Error(w, "<Error><Code>XAmzContentSHA256Mismatch</Code></Error>", http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/catalog/")
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Storage(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store := NewS3Storage(S3Config{
+		Endpoint:        server.URL,
+		Region:          "us-east-1",
+		Bucket:          "catalog",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio-secret",
+		PublicURL:       "https://cdn.example.com/",
+	}, server.Client())
+	ctx := context.Background()
+
+	url, err := store.Put(ctx, "products/p1/a.webp", "image/webp", []byte("RIFF"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if url != "https://cdn.example.com/products/p1/a.webp" || fake.types["products/p1/a.webp"] != "image/webp" {
+		t.Fatalf("url = %q, stored = %v", url, fake.types)
+	}
+	if data, err := store.Get(ctx, "products/p1/a.webp"); err != nil || string(data) != "RIFF" {
+		t.Fatalf("Get = %q, %v", data, err)
+	}
+	if err := store.Delete(ctx, "products/p1/a.webp"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "products/p1/a.webp"); err != nil {
+		t.Fatalf("deleting a missing object: %v", err)
+	}
+	if _, err := store.Get(ctx, "products/p1/a.webp"); err == nil {
+		t.Fatal("Get of a deleted object succeeded")
+	}
+	if _, err := store.Put(ctx, "../etc/passwd", "text/plain", nil); !errors.Is(err, ErrInvalidStorageKey) {
+		t.Fatalf("got %v, want ErrInvalidStorageKey", err)
+	}
+}
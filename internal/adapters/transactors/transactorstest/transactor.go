@@ -0,0 +1,19 @@
+// Package transactorstest provides a transactor for the tests of services
+// that run against in-memory fakes instead of a database.
+package transactorstest
+
+import (
+	"context"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+)
+
+// Transactor runs the function of WithinTransaction directly, without a
+// transaction. Any other method of transactors.IDatabaseTransactor panics.
+type Transactor struct {
+	transactors.IDatabaseTransactor
+}
+
+func (Transactor) WithinTransaction(ctx context.Context, tFunc func(ctx context.Context) error) error {
+	return tFunc(ctx)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "strings"
+
+// PostalAddress is a structured postal address. It is stored as columns of
+// the address book and copied into orders, so an order keeps the address it
+// was shipped to even if the customer edits or deletes the entry later.
+type PostalAddress struct {
+	Recipient   string `json:"recipient" gorm:"size:255"`  // Name of the person receiving the parcel or invoice
+	Line1       string `json:"line1" gorm:"size:255"`      // Street address, P.O. box, company name
+	Line2       string `json:"line2" gorm:"size:255"`      // Apartment, suite, unit, building, floor
+	City        string `json:"city" gorm:"size:100"`       // City, district or town
+	Region      string `json:"region" gorm:"size:100"`     // State, province or prefecture, when the country uses one
+	PostalCode  string `json:"postal_code" gorm:"size:20"` // Postal or ZIP code, normalised to upper case
+	CountryCode string `json:"country_code" gorm:"size:2"` // ISO 3166-1 alpha-2 country code
+	Phone       string `json:"phone" gorm:"size:30"`       // Contact phone number for the courier
+}
+
+// Format renders the address on a single line, used where a free-text
+// address is still expected.
+func (a PostalAddress) Format() string {
+	parts := make([]string, 0, 7)
+	for _, part := range []string{a.Recipient, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.CountryCode} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -11,10 +11,12 @@ import (
 
 type RefreshToken struct {
 	domain.BaseModel
-	UserID    uuid.UUID      `json:"user_id" gorm:"not null"`                     // References the User table to link the refresh token to a specific user
-	Token     string         `json:"token" gorm:"size:255;not null"`              // JWT refresh token string used to renew user authentication sessions
+	UserID    uuid.UUID      `json:"user_id" gorm:"not null;index"`               // References the User table to link the refresh token to a specific user
+	FamilyID  uuid.UUID      `json:"family_id" gorm:"type:uuid;not null;index"`   // Groups every token rotated from the same login so the chain can be revoked together
+	Token     string         `json:"-" gorm:"size:255;not null;uniqueIndex"`      // SHA-256 hash of the opaque refresh token handed to the client
 	ExpiresAt time.Time      `json:"expires_at"`                                  // Timestamp indicating when the refresh token will expire
 	Used      bool           `json:"used" gorm:"default:false"`                   // Indicates whether the refresh token has been used to issue a new token
+	Revoked   bool           `json:"revoked" gorm:"default:false"`                // Indicates whether the token was revoked (logout, reuse detection, password change)
 	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the refresh token record was created
 	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the refresh token record was last updated
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpersonationSession records a staff member acting as a customer. The
+// access token issued for it carries the session ID, and the token is
+// refused once the session has ended.
+type ImpersonationSession struct {
+	domain.BaseModel
+	ActorID   uuid.UUID  `json:"actor_id" gorm:"type:uuid;not null;index"`    // The staff member really making the requests
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`     // The customer being impersonated
+	Reason    string     `json:"reason" gorm:"type:text;not null"`            // Why support needed to see the account, e.g. a ticket number
+	ExpiresAt time.Time  `json:"expires_at"`                                  // Timestamp when the token stops working
+	EndedAt   *time.Time `json:"ended_at"`                                    // Set when the session was stopped before it expired
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the record was created
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the record was last updated
+}
+
+var TNImpersonationSession = "impersonation_sessions"
+
+// TableName sets the insert table name for ImpersonationSession struct
+func (ImpersonationSession) TableName() string {
+	return TNImpersonationSession
+}
+
+func (s *ImpersonationSession) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be used at the given time.
+func (s ImpersonationSession) IsActive(at time.Time) bool {
+	return s.EndedAt == nil && at.Before(s.ExpiresAt)
+}
+
+type StartImpersonationDomain struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// ImpersonationToken is returned when an impersonation starts. There is no
+// refresh token: when the access token expires the session is over.
+type ImpersonationToken struct {
+	AccessToken string                `json:"access_token"`
+	TokenType   string                `json:"token_type"`
+	ExpiresIn   int64                 `json:"expires_in"` // Access token lifetime in seconds
+	Session     *ImpersonationSession `json:"session"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"gorm.io/gorm"
+)
+
+type LOGIN_ATTEMPT_SCOPE string
+
+const (
+	LOGIN_ATTEMPT_SCOPE_ACCOUNT LOGIN_ATTEMPT_SCOPE = "account"
+	LOGIN_ATTEMPT_SCOPE_IP      LOGIN_ATTEMPT_SCOPE = "ip"
+	// LOGIN_ATTEMPT_SCOPE_CHALLENGE counts the wrong second factors entered
+	// for one two-factor challenge, by its token ID.
+	LOGIN_ATTEMPT_SCOPE_CHALLENGE LOGIN_ATTEMPT_SCOPE = "challenge"
+)
+
+// LoginAttempt counts recent failed logins for an account or a client IP.
+type LoginAttempt struct {
+	domain.BaseModel
+	Scope          LOGIN_ATTEMPT_SCOPE `json:"scope" gorm:"size:20;not null;uniqueIndex:idx_login_attempt_scope_subject"`    // Whether the counter tracks an account or a client IP
+	Subject        string              `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_login_attempt_scope_subject"` // User ID, or the identifier as typed for unknown accounts, or the client IP
+	Failures       int                 `json:"failures" gorm:"not null;default:0"`                                           // Failed attempts in the current window
+	FirstFailureAt time.Time           `json:"first_failure_at"`                                                             // Start of the current window
+	LastFailureAt  time.Time           `json:"last_failure_at"`                                                              // Most recent failed attempt, the base of the progressive delay
+	LockedUntil    *time.Time          `json:"locked_until" gorm:"index"`                                                    // Logins are refused until this time; nil when not locked
+	CreatedAt      time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                                  // Timestamp when the record was created
+	UpdatedAt      time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                                  // Timestamp when the record was last updated
+}
+
+var TNLoginAttempt = "login_attempts"
+
+// TableName sets the insert table name for LoginAttempt struct
+func (LoginAttempt) TableName() string {
+	return TNLoginAttempt
+}
+
+func (a *LoginAttempt) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsLocked reports whether the counter refuses logins at the given time.
+func (a LoginAttempt) IsLocked(at time.Time) bool {
+	return a.LockedUntil != nil && at.Before(*a.LockedUntil)
+}
+
+type ClearLoginLockDomain struct {
+	Scope   LOGIN_ATTEMPT_SCOPE `json:"scope" validate:"required,oneof=account ip"`
+	Subject string              `json:"subject" validate:"required,max=255"`
+}
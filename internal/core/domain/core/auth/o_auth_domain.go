@@ -11,12 +11,18 @@ import (
 
 type OAuthProvider struct {
 	domain.BaseModel
-	ProviderName string         `json:"provider_name" gorm:"size:100;not null"`      // Name of the OAuth provider (e.g., 'Google', 'Facebook')
-	ClientID     string         `json:"client_id" gorm:"size:255;not null"`          // Client ID provided by the OAuth provider
-	ClientSecret string         `json:"client_secret" gorm:"size:255;not null"`      // Client Secret provided by the OAuth provider
-	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the record was created
-	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the record was last updated
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	ProviderName string         `json:"provider_name" gorm:"size:100;not null;uniqueIndex"` // Name of the OAuth provider (e.g., 'google', 'facebook'), used in the login URLs
+	ClientID     string         `json:"client_id" gorm:"size:255;not null"`                 // Client ID provided by the OAuth provider
+	ClientSecret string         `json:"-" gorm:"size:512;not null"`                         // Client Secret provided by the OAuth provider, encrypted with encryption.Cipher
+	AuthorizeURL string         `json:"authorize_url" gorm:"size:512;not null"`             // Authorization endpoint the browser is sent to
+	TokenURL     string         `json:"token_url" gorm:"size:512;not null"`                 // Token endpoint used to redeem codes and refresh tokens
+	UserInfoURL  string         `json:"user_info_url" gorm:"size:512;not null"`             // OpenID Connect userinfo endpoint returning the identity
+	Scopes       string         `json:"scopes" gorm:"size:255"`                             // Space separated scopes to request (e.g., 'openid email profile')
+	RedirectURL  string         `json:"redirect_url" gorm:"size:512;not null"`              // Callback URL registered with the provider
+	Disabled     bool           `json:"disabled" gorm:"default:false"`                      // Disabled providers cannot be used to log in
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`        // Timestamp when the record was created
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`        // Timestamp when the record was last updated
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                            // Timestamp for soft deletes
 }
 
 var TNOAuthProvider = "oauth_providers"
@@ -35,14 +41,15 @@ func (o *OAuthProvider) BeforeCreate(tx *gorm.DB) (err error) {
 
 type OAuthAccessToken struct {
 	domain.BaseModel
-	UserID      uuid.UUID      `json:"user_id" gorm:"not null"`                     // References the User table to link the token to a specific user
-	ProviderID  uuid.UUID      `json:"provider_id" gorm:"not null"`                 // References the OAuthProvider table to identify the provider of the token
-	AccessToken string         `json:"access_token" gorm:"size:255;not null"`       // The OAuth access token
-	TokenType   string         `json:"token_type" gorm:"size:50"`                   // Type of token (e.g., 'Bearer')
-	ExpiresIn   int            `json:"expires_in"`                                  // Token expiration time in seconds
-	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the access token was created
-	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the access token was last updated
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	UserID      uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_oauth_access_token_user_provider"`     // References the User table to link the token to a specific user
+	ProviderID  uuid.UUID      `json:"provider_id" gorm:"type:uuid;not null;uniqueIndex:idx_oauth_access_token_user_provider"` // References the OAuthProvider table to identify the provider of the token
+	AccessToken string         `json:"-" gorm:"type:text;not null"`                                                            // The OAuth access token, encrypted with encryption.Cipher
+	TokenType   string         `json:"token_type" gorm:"size:50"`                                                              // Type of token (e.g., 'Bearer')
+	ExpiresIn   int            `json:"expires_in"`                                                                             // Token expiration time in seconds
+	ExpiresAt   *time.Time     `json:"expires_at"`                                                                             // When the token expires; nil if the provider did not say
+	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                                            // Timestamp when the access token was created
+	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                                            // Timestamp when the access token was last updated
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                                                // Timestamp for soft deletes
 }
 
 var TNOAuthAccessToken = "oauth_access_tokens"
@@ -61,12 +68,12 @@ func (o *OAuthAccessToken) BeforeCreate(tx *gorm.DB) (err error) {
 
 type OAuthRefreshToken struct {
 	domain.BaseModel
-	UserID       uuid.UUID      `json:"user_id" gorm:"not null"`                     // References the User table to link the token to a specific user
-	ProviderID   uuid.UUID      `json:"provider_id" gorm:"not null"`                 // References the OAuthProvider table to identify the provider of the token
-	RefreshToken string         `json:"refresh_token" gorm:"size:255;not null"`      // The OAuth refresh token
-	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the refresh token was created
-	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the refresh token was last updated
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_oauth_refresh_token_user_provider"`     // References the User table to link the token to a specific user
+	ProviderID   uuid.UUID      `json:"provider_id" gorm:"type:uuid;not null;uniqueIndex:idx_oauth_refresh_token_user_provider"` // References the OAuthProvider table to identify the provider of the token
+	RefreshToken string         `json:"-" gorm:"type:text;not null"`                                                             // The OAuth refresh token, encrypted with encryption.Cipher
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                                             // Timestamp when the refresh token was created
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                                             // Timestamp when the refresh token was last updated
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                                                 // Timestamp for soft deletes
 }
 
 var TNOAuthRefreshToken = "oauth_refresh_tokens"
@@ -82,3 +89,104 @@ func (o *OAuthRefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+type OAuthIdentity struct {
+	domain.BaseModel
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`                              // References the User table the external identity is linked to
+	ProviderID uuid.UUID      `json:"provider_id" gorm:"type:uuid;not null;uniqueIndex:idx_oauth_identity"` // References the OAuthProvider table
+	Subject    string         `json:"subject" gorm:"size:255;not null;uniqueIndex:idx_oauth_identity"`      // Stable user identifier at the provider (the OIDC `sub` claim)
+	Email      string         `json:"email" gorm:"size:255"`                                                // Email reported by the provider when the identity was linked
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                          // Timestamp when the identity was linked
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                          // Timestamp when the record was last updated
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                              // Timestamp for soft deletes
+}
+
+var TNOAuthIdentity = "oauth_identities"
+
+// TableName sets the insert table name for OAuthIdentity struct
+func (OAuthIdentity) TableName() string {
+	return TNOAuthIdentity
+}
+
+func (o *OAuthIdentity) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// OAuthState remembers an authorization request between the redirect to the
+// provider and the callback. It is single use and short lived.
+type OAuthState struct {
+	domain.BaseModel
+	StateHash    string         `json:"-" gorm:"size:255;not null;uniqueIndex"`      // SHA-256 hash of the `state` parameter sent to the provider
+	ProviderID   uuid.UUID      `json:"provider_id" gorm:"type:uuid;not null"`       // References the OAuthProvider table
+	CodeVerifier string         `json:"-" gorm:"size:512;not null"`                  // PKCE code verifier, encrypted with encryption.Cipher
+	LinkUserID   *uuid.UUID     `json:"link_user_id" gorm:"type:uuid"`               // Set when a logged in user links the provider to their account
+	ExpiresAt    time.Time      `json:"expires_at"`                                  // The callback must arrive before this time
+	Used         bool           `json:"used" gorm:"default:false"`                   // Indicates whether the callback already consumed the state
+	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the record was created
+	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the record was last updated
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNOAuthState = "oauth_states"
+
+// TableName sets the insert table name for OAuthState struct
+func (OAuthState) TableName() string {
+	return TNOAuthState
+}
+
+func (o *OAuthState) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type UpsertOAuthProviderDomain struct {
+	ProviderName string `json:"provider_name" validate:"required,max=100,alphanum"`
+	ClientID     string `json:"client_id" validate:"required,max=255"`
+	ClientSecret string `json:"client_secret" validate:"omitempty,max=255"` // Left empty on update to keep the stored secret
+	AuthorizeURL string `json:"authorize_url" validate:"required,url,max=512"`
+	TokenURL     string `json:"token_url" validate:"required,url,max=512"`
+	UserInfoURL  string `json:"user_info_url" validate:"required,url,max=512"`
+	Scopes       string `json:"scopes" validate:"max=255"`
+	RedirectURL  string `json:"redirect_url" validate:"required,url,max=512"`
+	Disabled     bool   `json:"disabled"`
+}
+
+// OAuthAuthorization tells the client where to send the browser.
+type OAuthAuthorization struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type OAuthCallbackDomain struct {
+	Code             string `query:"code" json:"code"`
+	State            string `query:"state" json:"state" validate:"required"`
+	Error            string `query:"error" json:"error"`
+	ErrorDescription string `query:"error_description" json:"error_description"`
+}
+
+// OAuthTokenResponse is the token endpoint response of RFC 6749 section 5.1.
+type OAuthTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+	IDToken      string `json:"id_token"`
+	Scope        string `json:"scope"`
+}
+
+// OAuthUserInfo holds the standard OpenID Connect claims we use.
+type OAuthUserInfo struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	GivenName         string
+	FamilyName        string
+	Name              string
+	PreferredUsername string
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// TokenPair is returned to clients after a successful login or refresh.
+type TokenPair struct {
+	AccessToken           string    `json:"access_token"`
+	TokenType             string    `json:"token_type"`
+	ExpiresIn             int64     `json:"expires_in"` // Access token lifetime in seconds
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+type RefreshTokenDomain struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRecoveryCode struct {
+	domain.BaseModel
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`     // References the User table to link the code to a specific user
+	CodeHash  string         `json:"-" gorm:"size:255;not null"`                  // argon2id hash of the single-use recovery code
+	UsedAt    *time.Time     `json:"used_at"`                                     // Timestamp when the code was redeemed; nil while it is still usable
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the record was created
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the record was last updated
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNTwoFactorRecoveryCode = "two_factor_recovery_codes"
+
+// TableName sets the insert table name for TwoFactorRecoveryCode struct
+func (TwoFactorRecoveryCode) TableName() string {
+	return TNTwoFactorRecoveryCode
+}
+
+func (r *TwoFactorRecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// TwoFactorEnrollment is returned when a user starts TOTP enrolment. The secret
+// only becomes active after it is confirmed with a valid code.
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauth_url"`
+	QRCode     string `json:"qr_code"` // PNG encoded as a data URI
+}
+
+// TwoFactorRecoveryCodes holds plaintext recovery codes. They are shown once and
+// only their hashes are stored.
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorChallenge is returned by the password step of a login when the
+// account has two-factor authentication enabled.
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challenge_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// LoginResult carries either a token pair or, for accounts with two-factor
+// authentication, a challenge that must be completed first.
+type LoginResult struct {
+	User      *userDomain.User
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+type TwoFactorCodeDomain struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type TwoFactorLoginDomain struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode   string `json:"recovery_code" validate:"required_without=Code,omitempty,max=64"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+// EmailDomain is used to request a code for an email address, e.g. to resend
+// the signup verification or to start a passwordless login.
+type EmailDomain struct {
+	Email string `json:"email" validate:"required,email,max=50"`
+}
+
+type EmailCodeDomain struct {
+	Email string `json:"email" validate:"required,email,max=50"`
+	Code  string `json:"code" validate:"required,numeric,max=10"`
+}
+
+type ResetPasswordDomain struct {
+	Email       string `json:"email" validate:"required,email,max=50"`
+	Code        string `json:"code" validate:"required,numeric,max=10"`
+	NewPassword string `json:"new_password" validate:"required,min=8,max=128"`
+}
+
+type ChangePasswordDomain struct {
+	CurrentPassword string `json:"current_password" validate:"required,max=128"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=128"`
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserAddress is an entry of a customer's address book. At most one address
+// per user is the default for billing and one for shipping; the partial
+// unique indexes enforce it.
+type UserAddress struct {
+	domain.BaseModel
+	UserID               uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index;uniqueIndex:idx_user_addresses_default_billing,where:is_default_billing;uniqueIndex:idx_user_addresses_default_shipping,where:is_default_shipping"` // References the User table, linking the address to its owner
+	Label                string    `json:"label" gorm:"size:50"`                                                                                                                                                                      // Name the customer gives the address, e.g. 'Home' or 'Office'
+	domain.PostalAddress `gorm:"embedded"`
+	IsDefaultBilling     bool      `json:"is_default_billing" gorm:"not null;default:false"`  // Preselected as the billing address at checkout
+	IsDefaultShipping    bool      `json:"is_default_shipping" gorm:"not null;default:false"` // Preselected as the shipping address at checkout
+	CreatedAt            time.Time `json:"created_at" gorm:"autoCreateTime"`                  // Timestamp when the address was added
+	UpdatedAt            time.Time `json:"updated_at" gorm:"autoUpdateTime"`                  // Timestamp when the address was last updated
+}
+
+var TNUserAddress = "user_addresses"
+
+// TableName sets the insert table name for UserAddress struct
+func (UserAddress) TableName() string {
+	return TNUserAddress
+}
+
+func (u *UserAddress) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpsertUserAddressDomain is the payload to add or replace an address book
+// entry. Country specific rules (postal code format, required region) are
+// checked by the address service.
+type UpsertUserAddressDomain struct {
+	Label             string `json:"label" validate:"omitempty,max=50"`
+	Recipient         string `json:"recipient" validate:"required,max=255"`
+	Line1             string `json:"line1" validate:"required,max=255"`
+	Line2             string `json:"line2" validate:"omitempty,max=255"`
+	City              string `json:"city" validate:"required,max=100"`
+	Region            string `json:"region" validate:"omitempty,max=100"`
+	PostalCode        string `json:"postal_code" validate:"omitempty,max=20"`
+	CountryCode       string `json:"country_code" validate:"required,len=2,alpha"`
+	Phone             string `json:"phone" validate:"required,max=30"`
+	IsDefaultBilling  bool   `json:"is_default_billing"`
+	IsDefaultShipping bool   `json:"is_default_shipping"`
+}
+
+type ADDRESS_KIND string
+
+const (
+	ADDRESS_KIND_BILLING  ADDRESS_KIND = "billing"
+	ADDRESS_KIND_SHIPPING ADDRESS_KIND = "shipping"
+)
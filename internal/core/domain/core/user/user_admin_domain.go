@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+)
+
+// UserFilters narrows the admin user list. Empty fields do not filter.
+type UserFilters struct {
+	Status string `json:"status"` // Comma-separated list of USER_STATUS values
+	Role   string `json:"role"`   // Role ID or role name
+	Email  string `json:"email"`  // Part of the email address, case-insensitive
+	Search string `json:"search"` // Part of the username, first or last name
+	pagination.CommonTimeFilters
+}
+
+type ChangeUserStatusDomain struct {
+	Status USER_STATUS `json:"status" validate:"required,oneof=active inactive banned"`
+	Reason string      `json:"reason" validate:"required,max=1000"`
+}
+
+type AssignUserRoleDomain struct {
+	RoleID uuid.UUID `json:"role_id" validate:"required"`
+	Reason string    `json:"reason" validate:"required,max=1000"`
+}
+
+type ForceLogoutDomain struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
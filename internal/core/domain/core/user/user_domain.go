@@ -5,6 +5,7 @@ import (
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -14,22 +15,26 @@ const (
 	USER_STATUS_ACTIVE   USER_STATUS = "active"
 	USER_STATUS_INACTIVE USER_STATUS = "inactive"
 	USER_STATUS_BANNED   USER_STATUS = "banned"
+	USER_STATUS_ERASED   USER_STATUS = "erased" // Personal data was anonymised on request, the account cannot be used again
 )
 
 type User struct {
 	domain.BaseModel
 	FirstName        string      `json:"first_name" validate:"required,max=255" gorm:"size:255"`
 	LastName         string      `json:"last_name" validate:"required,max=255"`
-	RoleID           string      `json:"role_id"`
+	RoleID           *uuid.UUID  `json:"role_id" gorm:"type:uuid;index"`
+	Role             *UserRole   `json:"role,omitempty" gorm:"foreignKey:RoleID"`
 	Address          string      `json:"address" validate:"required,max=255" gorm:"size:255"`
 	PhoneNumber      string      `json:"phone_number" validate:"required,max=50" gorm:"size:50"`
-	Email            string      `json:"email" validate:"required,max=150" gorm:"size:50"`
-	Username         string      `json:"username" validate:"required,max=255" gorm:"size:255"`
-	Password         string      `json:"password"`
+	Email            string      `json:"email" validate:"required,max=150" gorm:"size:50;uniqueIndex"`
+	EmailVerified    bool        `json:"email_verified" gorm:"default:false"`
+	Username         string      `json:"username" validate:"required,max=255" gorm:"size:255;uniqueIndex"`
+	Password         string      `json:"-"` // argon2id hash, never serialised
 	Status           USER_STATUS `json:"status" validate:"required,max=50" gorm:"size:50"`
 	LastLogin        time.Time   `json:"last_login"`
 	TwoFactorEnabled bool        `json:"two_factor_enabled"`
-	TwoFactorSecret  string      `json:"two_factor_secret"`
+	TwoFactorSecret  string      `json:"-" gorm:"size:255"` // TOTP secret encrypted with encryption.Cipher; set at enrolment, used once confirmed
+	TwoFactorStep    int64       `json:"-"`                 // Last accepted TOTP time step, so a code cannot be replayed
 	CreatedByID      string      `json:"created_by_id"`
 }
 
@@ -40,6 +45,9 @@ func (st *User) TableName() string {
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID != uuid.Nil {
+		return nil
+	}
 	if u.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
 		return err
 	}
@@ -48,17 +56,34 @@ func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 
 type UpdateUserDomain struct {
 	domain.BaseModel
-	FirstName        string    `json:"first_name" validate:"omitempty,max=255"`
-	LastName         string    `json:"last_name"`
-	RoleID           string    `json:"role_id"`
-	Address          string    `json:"address"`
-	PhoneNumber      string    `json:"phone_number"`
-	Username         string    `json:"username"`
-	Password         string    `json:"password"`
-	Status           string    `json:"status"`
-	LastLogin        time.Time `json:"last_login"`
-	TwoFactorEnabled bool      `json:"two_factor_enabled"`
-	TwoFactorSecret  string    `json:"two_factor_secret"`
-	Email            string    `json:"email"`
-	CreatedByID      string    `json:"created_by_id"`
+	FirstName        string     `json:"first_name" validate:"omitempty,max=255"`
+	LastName         string     `json:"last_name"`
+	RoleID           *uuid.UUID `json:"role_id"`
+	Address          string     `json:"address"`
+	PhoneNumber      string     `json:"phone_number"`
+	Username         string     `json:"username"`
+	Password         string     `json:"password"`
+	Status           string     `json:"status"`
+	LastLogin        time.Time  `json:"last_login"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	TwoFactorSecret  string     `json:"two_factor_secret"`
+	Email            string     `json:"email"`
+	CreatedByID      string     `json:"created_by_id"`
+}
+
+type RegisterUserDomain struct {
+	FirstName   string `json:"first_name" validate:"required,max=255"`
+	LastName    string `json:"last_name" validate:"required,max=255"`
+	Address     string `json:"address" validate:"omitempty,max=255"`
+	PhoneNumber string `json:"phone_number" validate:"required,max=50"`
+	Email       string `json:"email" validate:"required,email,max=50"`
+	// Login accepts a username or an email address, so usernames must not
+	// look like one.
+	Username string `json:"username" validate:"required,min=3,max=255,excludes=@"`
+	Password string `json:"password" validate:"required,min=8,max=128"`
+}
+
+type LoginUserDomain struct {
+	Username string `json:"username" validate:"required,max=255"` // Username or email address
+	Password string `json:"password" validate:"required,max=128"`
 }
@@ -5,16 +5,17 @@ import (
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserPermission struct {
 	domain.BaseModel
-	RoleID         uint      `json:"role_id" gorm:"not null"`                 // References the UserRole table to link permissions to specific roles
-	Permission     string    `json:"permission" gorm:"size:100;not null"`     // Description of the permission (e.g., 'CREATE_PRODUCT', 'VIEW_ORDERS')
-	PermissionType string    `json:"permission_type" gorm:"size:50;not null"` // Type of permission (e.g., 'Read', 'Write', 'Admin')
-	EffectiveDate  time.Time `json:"effective_date"`                          // Timestamp when the permission became effective
-	Description    string    `json:"description"`                             // Brief description of the permissions associated with this role
+	RoleID         uuid.UUID `json:"role_id" gorm:"type:uuid;not null;uniqueIndex:idx_role_permission"`   // References the UserRole table to link permissions to specific roles
+	Permission     string    `json:"permission" gorm:"size:100;not null;uniqueIndex:idx_role_permission"` // Description of the permission (e.g., 'CREATE_PRODUCT', 'VIEW_ORDERS')
+	PermissionType string    `json:"permission_type" gorm:"size:50;not null"`                             // Type of permission (e.g., 'Read', 'Write', 'Admin')
+	EffectiveDate  time.Time `json:"effective_date"`                                                      // Timestamp when the permission became effective
+	Description    string    `json:"description"`                                                         // Brief description of the permissions associated with this role
 }
 
 var TNUserPermission = "user_permissions"
@@ -24,9 +25,25 @@ func (UserPermission) TableName() string {
 	return TNUserPermission
 }
 
+// IsEffective reports whether the permission is in force at the given time.
+// A zero EffectiveDate means the permission applies immediately.
+func (u UserPermission) IsEffective(at time.Time) bool {
+	return u.EffectiveDate.IsZero() || !u.EffectiveDate.After(at)
+}
+
 func (u *UserPermission) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID != uuid.Nil {
+		return nil
+	}
 	if u.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
 		return err
 	}
 	return nil
 }
+
+type GrantPermissionDomain struct {
+	Permission     string     `json:"permission" validate:"required,max=100"`
+	PermissionType string     `json:"permission_type" validate:"required,max=50"`
+	EffectiveDate  *time.Time `json:"effective_date"`
+	Description    string     `json:"description"`
+}
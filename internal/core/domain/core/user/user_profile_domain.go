@@ -11,11 +11,12 @@ import (
 
 type UserProfile struct {
 	domain.BaseModel
-	UserID           uuid.UUID    `json:"user_id" gorm:"not null"`         // References the User table, linking the profile to a specific user
-	ProfilePicture   string       `json:"profile_picture" gorm:"size:255"` // URL or path to the user's profile picture
-	Preferences      domain.JSONB `json:"preferences"`                     // JSON object storing user-specific preferences and settings
-	DateOfBirth      *time.Time   `json:"date_of_birth"`                   // User's date of birth
-	SocialMediaLinks domain.JSONB `json:"social_media_links"`              // JSON object storing links to user's social media profiles
+	UserID            uuid.UUID    `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"` // References the User table, linking the profile to a specific user
+	ProfilePicture    string       `json:"profile_picture" gorm:"size:255"`               // URL or path to the user's profile picture
+	ProfilePictureKey string       `json:"-" gorm:"size:255"`                             // Storage key of the uploaded picture, used to delete it when replaced
+	Preferences       domain.JSONB `json:"preferences" gorm:"type:jsonb"`                 // JSON object storing user-specific preferences and settings, see PreferencesSchema
+	DateOfBirth       *time.Time   `json:"date_of_birth" gorm:"type:date"`                // User's date of birth
+	SocialMediaLinks  domain.JSONB `json:"social_media_links" gorm:"type:jsonb"`          // JSON object storing links to user's social media profiles, see SocialMediaLinksSchema
 }
 
 var TNUserProfile = "user_profiles"
@@ -30,3 +31,43 @@ func (u *UserProfile) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// DateOfBirthLayout is the format of date_of_birth in profile patches.
+const DateOfBirthLayout = "2006-01-02"
+
+// PreferencesSchema is the JSON Schema every stored preferences document must
+// satisfy. Unknown keys are rejected so that typos do not pile up silently.
+const PreferencesSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"additionalProperties": false,
+	"properties": {
+		"language": {"type": "string", "pattern": "^[a-z]{2}(-[A-Z]{2})?$"},
+		"currency": {"type": "string", "pattern": "^[A-Z]{3}$"},
+		"timezone": {"type": "string", "minLength": 1, "maxLength": 64},
+		"theme": {"enum": ["light", "dark", "system"]},
+		"newsletter": {"type": "boolean"},
+		"notifications": {
+			"type": "object",
+			"additionalProperties": false,
+			"properties": {
+				"email": {"type": "boolean"},
+				"sms": {"type": "boolean"},
+				"push": {"type": "boolean"}
+			}
+		}
+	}
+}`
+
+// SocialMediaLinksSchema maps a network name to a profile URL.
+const SocialMediaLinksSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"maxProperties": 10,
+	"propertyNames": {"pattern": "^[a-z0-9_]{1,32}$"},
+	"additionalProperties": {"type": "string", "maxLength": 255, "pattern": "^https?://[^\\s]+$"}
+}`
+
+type UploadAvatarDomain struct {
+	Image string `json:"image" validate:"required"` // Base64 data URI, e.g. data:image/png;base64,...
+}
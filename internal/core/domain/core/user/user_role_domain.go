@@ -1,26 +1,72 @@
 package domain
 
 import (
+	"strings"
+
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type UserRole struct {
 	domain.BaseModel
-	RoleName    string `json:"role_name" gorm:"size:50;not null"` // Name of the role (e.g., 'ADMIN', 'SELLER', 'CUSTOMER')
-	Description string `json:"description" gorm:"size:255"`       // Description of the role and its purpose within the system
+	RoleName    string           `json:"role_name" gorm:"size:50;not null;uniqueIndex"`                              // Name of the role (e.g., 'ADMIN', 'SELLER', 'CUSTOMER')
+	Description string           `json:"description" gorm:"size:255"`                                                // Description of the role and its purpose within the system
+	Permissions []UserPermission `json:"permissions,omitempty" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE"` // Permissions granted to the role
 }
 
 var TNUserRole = "user_roles"
 
+const (
+	ROLE_ADMIN    = "ADMIN"
+	ROLE_SELLER   = "SELLER"
+	ROLE_CUSTOMER = "CUSTOMER"
+	// ROLE_SERVICE_ACCOUNT is held by machine clients that authenticate with
+	// API keys only.
+	ROLE_SERVICE_ACCOUNT = "SERVICE_ACCOUNT"
+)
+
+const (
+	PERMISSION_MANAGE_ROLES            = "MANAGE_ROLES"
+	PERMISSION_MANAGE_OAUTH_PROVIDERS  = "MANAGE_OAUTH_PROVIDERS"
+	PERMISSION_MANAGE_LOCKOUTS         = "MANAGE_LOCKOUTS"
+	PERMISSION_MANAGE_SERVICE_ACCOUNTS = "MANAGE_SERVICE_ACCOUNTS"
+	PERMISSION_MANAGE_PRIVACY_REQUESTS = "MANAGE_PRIVACY_REQUESTS"
+	PERMISSION_MANAGE_USERS            = "MANAGE_USERS"
+	PERMISSION_IMPERSONATE_USERS       = "IMPERSONATE_USERS"
+	PERMISSION_MANAGE_CATALOG          = "MANAGE_CATALOG" // Categories, and products of every seller rather than only the holder's own
+	PERMISSION_CREATE_PRODUCT          = "CREATE_PRODUCT"
+	PERMISSION_UPDATE_PRODUCT          = "UPDATE_PRODUCT"
+	PERMISSION_DELETE_PRODUCT          = "DELETE_PRODUCT"
+	PERMISSION_VIEW_ORDERS             = "VIEW_ORDERS"
+	PERMISSION_MANAGE_ORDERS           = "MANAGE_ORDERS"    // Confirming the payment of orders
+	PERMISSION_MANAGE_INVENTORY        = "MANAGE_INVENTORY" // Recording stock movements and reading their history
+)
+
+// IsAdminPermission reports whether the permission administers the shop or
+// other accounts, as every MANAGE_* permission does, rather than letting its
+// holder act as one customer or seller. Admin permissions are never used on
+// someone else's behalf.
+func IsAdminPermission(permission string) bool {
+	return permission == PERMISSION_IMPERSONATE_USERS || strings.HasPrefix(permission, "MANAGE_")
+}
+
 // TableName sets the insert table name for UserRole struct
 func (UserRole) TableName() string {
 	return TNUserRole
 }
 func (u *UserRole) BeforeCreate(tx *gorm.DB) (err error) {
+	if u.ID != uuid.Nil {
+		return nil
+	}
 	if u.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
 		return err
 	}
 	return nil
 }
+
+type UpsertRoleDomain struct {
+	RoleName    string `json:"role_name" validate:"required,max=50"`
+	Description string `json:"description" validate:"omitempty,max=255"`
+}
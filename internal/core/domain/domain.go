@@ -41,11 +41,18 @@ func (j *JSONB) Scan(value interface{}) error {
 		}
 	}()
 
-	if data, ok := value.([]byte); ok {
+	switch data := value.(type) {
+	case nil:
+		*j = nil
+	case []byte:
 		if err := json.Unmarshal(data, j); err != nil {
 			return err
 		}
-	} else {
+	case string:
+		if err := json.Unmarshal([]byte(data), j); err != nil {
+			return err
+		}
+	default:
 		return fmt.Errorf("unexpected type for JSONB: %T", value)
 	}
 
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -21,18 +23,24 @@ type LogEntry struct {
 type ChangeLogs struct {
 	domain.BaseModel
 	EntityType  string    `json:"entity_type" gorm:"size:255;not null"`       // Type of entity changed (e.g., 'User', 'Order', etc.)
-	EntityID    uint      `json:"entity_id" gorm:"not null"`                  // ID of the modified record
+	EntityID    uuid.UUID `json:"entity_id" gorm:"type:uuid;not null;index"`  // ID of the modified record
 	FieldName   string    `json:"field_name" gorm:"size:255;not null"`        // Name of the changed field
 	OldValue    string    `json:"old_value" gorm:"type:text"`                 // Previous value of the field
 	NewValue    string    `json:"new_value" gorm:"type:text"`                 // New value of the field
 	Action      string    `json:"action" gorm:"size:50;not null"`             // Action performed (e.g., 'UPDATE', 'INSERT', 'DELETE')
 	Timestamp   time.Time `json:"timestamp" gorm:"default:CURRENT_TIMESTAMP"` // When the change was recorded
-	UserID      uint      `json:"user_id" gorm:"not null"`                    // User responsible for the change
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`    // User responsible for the change
 	Description string    `json:"description" gorm:"type:text"`               // Optional context about the change
 }
 
 var TNChangeLogs = "change_logs"
 
+// ChangeLogFilters narrows a change log listing. Empty fields do not filter.
+type ChangeLogFilters struct {
+	Action string `json:"action"` // Comma-separated list of actions
+	pagination.CommonTimeFilters
+}
+
 // TableName sets the insert table name for ChangeLogs struct
 func (ChangeLogs) TableName() string {
 	return TNChangeLogs
@@ -47,7 +55,7 @@ func (cl *ChangeLogs) BeforeCreate(tx *gorm.DB) (err error) {
 }
 
 // NewChangeLog creates a new ChangeLogs instance with the current timestamp.
-func NewChangeLog(entityType string, entityID uint, fieldName, oldValue, newValue, action string, userID uint, description string) *ChangeLogs {
+func NewChangeLog(entityType string, entityID uuid.UUID, fieldName, oldValue, newValue, action string, userID uuid.UUID, description string) *ChangeLogs {
 	return &ChangeLogs{
 		EntityType:  entityType,
 		EntityID:    entityID,
@@ -5,12 +5,13 @@ import (
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Email struct {
 	domain.BaseModel
-	UserID         uint           `json:"user_id" gorm:"not null"`                     // References the User table to link the email to a specific user
+	UserID         uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`     // References the User table to link the email to a specific user
 	TemporalID     string         `json:"temporal_id" gorm:"size:255"`                 // Unique identifier from Temporal services for tracking purposes
 	RecipientEmail string         `json:"recipient_email" gorm:"size:255;not null"`    // Email address of the recipient
 	Subject        string         `json:"subject" gorm:"size:255;not null"`            // Subject line of the email
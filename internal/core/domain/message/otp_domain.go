@@ -5,19 +5,39 @@ import (
 
 	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
 	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+type OTP_PURPOSE string
+
+const (
+	OTP_PURPOSE_EMAIL_VERIFICATION OTP_PURPOSE = "email_verification"
+	OTP_PURPOSE_PASSWORDLESS_LOGIN OTP_PURPOSE = "passwordless_login"
+	OTP_PURPOSE_PASSWORD_RESET     OTP_PURPOSE = "password_reset"
+	OTP_PURPOSE_ACCOUNT_UNLOCK     OTP_PURPOSE = "account_unlock"
+)
+
+type OTP_CHANNEL string
+
+const (
+	OTP_CHANNEL_EMAIL OTP_CHANNEL = "email"
+	OTP_CHANNEL_SMS   OTP_CHANNEL = "sms"
+)
+
 type OTP struct {
 	domain.BaseModel
-	UserID     uint           `json:"user_id" gorm:"not null"`                     // References the User table to link the OTP to a specific user
-	TemporalID string         `json:"temporal_id" gorm:"size:255"`                 // Unique identifier from Temporal services for tracking purposes
-	Otp        string         `json:"otp" gorm:"size:10;not null"`                 // The OTP value generated for authentication purposes
-	ExpiresAt  time.Time      `json:"expires_at"`                                  // Timestamp indicating when the OTP will expire
-	Used       bool           `json:"used" gorm:"default:false"`                   // Indicates whether the OTP has been used
-	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the OTP record was created
-	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the OTP record was last updated
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	UserID     uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index:idx_otp_user_purpose"` // References the User table to link the OTP to a specific user
+	TemporalID string         `json:"temporal_id" gorm:"size:255"`                                  // Unique identifier from Temporal services for tracking purposes
+	Purpose    OTP_PURPOSE    `json:"purpose" gorm:"size:50;not null;index:idx_otp_user_purpose"`   // What the code may be used for, a code for one purpose is never accepted for another
+	Channel    OTP_CHANNEL    `json:"channel" gorm:"size:20;not null"`                              // How the code was delivered (email, sms)
+	Otp        string         `json:"-" gorm:"size:255;not null"`                                   // argon2id hash of the OTP value; the plaintext is only sent to the user
+	ExpiresAt  time.Time      `json:"expires_at"`                                                   // Timestamp indicating when the OTP will expire
+	Used       bool           `json:"used" gorm:"default:false"`                                    // Indicates whether the OTP has been used (or superseded by a newer code)
+	Attempts   int            `json:"attempts" gorm:"default:0"`                                    // Number of failed verification attempts against this code
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                  // Timestamp when the OTP record was created
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                  // Timestamp when the OTP record was last updated
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                      // Timestamp for soft deletes
 }
 
 var TNOTP = "otps"
@@ -33,3 +53,19 @@ func (o *OTP) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// OTPMessage is what an OTP sender delivers to the user.
+type OTPMessage struct {
+	Channel     OTP_CHANNEL
+	Destination string // Email address or phone number
+	Purpose     OTP_PURPOSE
+	Code        string
+	ExpiresAt   time.Time
+}
+
+type IssueOTPDomain struct {
+	UserID      uuid.UUID
+	Purpose     OTP_PURPOSE
+	Channel     OTP_CHANNEL
+	Destination string
+}
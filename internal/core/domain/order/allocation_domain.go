@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/google/uuid"
+)
+
+type ALLOCATION_STRATEGY string
+
+const (
+	ALLOCATION_STRATEGY_NEAREST          ALLOCATION_STRATEGY = "nearest"          // Ship every line from the closest warehouse that has it
+	ALLOCATION_STRATEGY_FEWEST_SHIPMENTS ALLOCATION_STRATEGY = "fewest_shipments" // Ship the order in as few parcels as possible
+	ALLOCATION_STRATEGY_PRIORITY         ALLOCATION_STRATEGY = "priority"         // Ship every line from the first warehouse, by priority, that has it
+)
+
+// StockKey names the stock of one SKU at one warehouse.
+type StockKey struct {
+	SKUID       uuid.UUID
+	WarehouseID uuid.UUID
+}
+
+// AllocationRequest is what an allocation strategy chooses from: the lines
+// of an order, where it goes, and the units each active warehouse can still
+// promise.
+type AllocationRequest struct {
+	Destination domain.PostalAddress
+	Lines       []ReservationLine // One line per SKU
+	Warehouses  []Warehouse       // The active warehouses, by priority
+	Available   map[StockKey]int  // Units that can still be reserved, missing when none
+}
+
+// Allocation ships the units of one line from one warehouse.
+type Allocation struct {
+	SKUID       uuid.UUID `json:"sku_id"`
+	WarehouseID uuid.UUID `json:"warehouse_id"`
+	Quantity    int       `json:"quantity"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "github.com/google/uuid"
+
+// CheckoutDomain turns the active cart of the user into an order. Addresses
+// left out fall back to the default billing and shipping addresses of the
+// address book.
+type CheckoutDomain struct {
+	ShippingAddressID *uuid.UUID `json:"shipping_address_id"`
+	BillingAddressID  *uuid.UUID `json:"billing_address_id"`
+	ShippingMethod    string     `json:"shipping_method" validate:"required,max=50"` // e.g. 'Standard', 'Express'
+	PaymentMethod     string     `json:"payment_method" validate:"required,max=50"`  // e.g. 'Credit Card', 'Bank Transfer'
+}
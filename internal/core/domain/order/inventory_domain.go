@@ -9,13 +9,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// Inventory is the stock of one SKU at one warehouse. Quantity counts the
+// units on hand, Reserved the part of them held by checkouts that are not
+// paid yet, so Quantity - Reserved is what can still be sold.
 type Inventory struct {
 	domain.BaseModel
-	ProductID uuid.UUID      `json:"product_id" gorm:"not null"`                  // References the Product table to link the inventory record to a specific product
-	Quantity  int            `json:"quantity" gorm:"not null"`                    // Quantity of the product available in inventory
-	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the inventory item record was created
-	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the inventory record was last updated
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	ProductID   uuid.UUID      `json:"product_id" gorm:"type:uuid;not null;index"`                                       // References the Product table to link the inventory record to a specific product
+	SKUID       uuid.UUID      `json:"sku_id" gorm:"type:uuid;not null;uniqueIndex:idx_inventories_sku_warehouse"`       // References the ProductSKU the stock is counted for
+	WarehouseID uuid.UUID      `json:"warehouse_id" gorm:"type:uuid;not null;uniqueIndex:idx_inventories_sku_warehouse"` // References the Warehouse the stock is kept at
+	Quantity    int            `json:"quantity" gorm:"not null"`                                                         // Quantity of the product available in inventory
+	Reserved    int            `json:"reserved" gorm:"not null;default:0"`                                               // Units held by active reservations
+	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                                      // Timestamp when the inventory item record was created
+	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                                      // Timestamp when the inventory record was last updated
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`                                                          // Timestamp for soft deletes
 }
 
 var TNInventory = "inventories"
@@ -31,3 +37,26 @@ func (inv *Inventory) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// Available is the number of units that can still be reserved.
+func (inv Inventory) Available() int {
+	return inv.Quantity - inv.Reserved
+}
+
+// Key names the SKU and warehouse the row counts the stock of.
+func (inv Inventory) Key() StockKey {
+	return StockKey{SKUID: inv.SKUID, WarehouseID: inv.WarehouseID}
+}
+
+// SKUStock is the stock of one SKU over every warehouse.
+type SKUStock struct {
+	SKUID     uuid.UUID   `json:"sku_id"`
+	Quantity  int         `json:"quantity"`
+	Reserved  int         `json:"reserved"`
+	Locations []Inventory `json:"locations"`
+}
+
+// Available is the number of units that can still be reserved anywhere.
+func (s SKUStock) Available() int {
+	return s.Quantity - s.Reserved
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RESERVATION_STATUS string
+
+const (
+	RESERVATION_STATUS_ACTIVE    RESERVATION_STATUS = "active"    // The units are held and count against the available stock
+	RESERVATION_STATUS_COMMITTED RESERVATION_STATUS = "committed" // The units were sold and left the stock
+	RESERVATION_STATUS_RELEASED  RESERVATION_STATUS = "released"  // The units went back to the available stock
+)
+
+// InventoryReservation holds units of a SKU for a document, usually an order
+// waiting for payment. An active reservation past ExpiresAt is released by the
+// inventory worker, which is how an abandoned checkout gives its stock back.
+type InventoryReservation struct {
+	domain.BaseModel
+	SKUID       uuid.UUID          `json:"sku_id" gorm:"type:uuid;not null;index"`       // References the ProductSKU held
+	WarehouseID uuid.UUID          `json:"warehouse_id" gorm:"type:uuid;not null;index"` // References the Warehouse the units are held at
+	Reference   uuid.UUID          `json:"reference" gorm:"type:uuid;not null;index"`    // The document holding the units, e.g. the order ID
+	Quantity    int                `json:"quantity" gorm:"not null"`                     // Units held
+	Status      RESERVATION_STATUS `json:"status" gorm:"size:20;not null;index:idx_reservations_expiry"`
+	ExpiresAt   time.Time          `json:"expires_at" gorm:"not null;index:idx_reservations_expiry"` // When an active reservation is released automatically
+	CommittedAt *time.Time         `json:"committed_at"`
+	ReleasedAt  *time.Time         `json:"released_at"`
+	CreatedAt   time.Time          `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt   time.Time          `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+var TNInventoryReservation = "inventory_reservations"
+
+// TableName sets the insert table name for InventoryReservation struct
+func (InventoryReservation) TableName() string {
+	return TNInventoryReservation
+}
+
+func (r *InventoryReservation) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Key names the SKU and warehouse the units are held at.
+func (r InventoryReservation) Key() StockKey {
+	return StockKey{SKUID: r.SKUID, WarehouseID: r.WarehouseID}
+}
+
+// ReservationLine asks for units of one SKU.
+type ReservationLine struct {
+	SKUID    uuid.UUID `json:"sku_id"`
+	Quantity int       `json:"quantity"`
+}
+
+// ReserveDomain asks for the units of an order, which the allocation
+// strategy takes from the warehouses best placed to ship them to
+// Destination.
+type ReserveDomain struct {
+	Destination domain.PostalAddress
+	Lines       []ReservationLine
+}
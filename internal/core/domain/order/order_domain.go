@@ -15,12 +15,23 @@ type Order struct {
 	Status       string         `json:"status" gorm:"size:50;not null"`              // Current status of the order (e.g., 'pending', 'shipped', 'delivered')
 	OrderDate    time.Time      `json:"order_date" gorm:"default:CURRENT_TIMESTAMP"` // Timestamp when the order was placed
 	DeliveryDate *time.Time     `json:"delivery_date"`                               // Expected delivery date of the order
-	CreatedBy    uuid.UUID      `json:"created_by" gorm:"not null"`                  // References the User table to track who created the order
+	CreatedBy    uuid.UUID      `json:"created_by" gorm:"type:uuid;not null;index"`  // References the User table to track who created the order
 	CreatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the order record was created
 	UpdatedAt    time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the order record was last updated
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	Items        []OrderItem    `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+	ShippingInfo *ShippingInfo  `json:"shipping_info,omitempty" gorm:"foreignKey:OrderID"`
+	BillingInfo  *BillingInfo   `json:"billing_info,omitempty" gorm:"foreignKey:OrderID"`
 }
 
+const (
+	ORDER_STATUS_PENDING   = "pending"
+	ORDER_STATUS_PAID      = "paid"
+	ORDER_STATUS_SHIPPED   = "shipped"
+	ORDER_STATUS_DELIVERED = "delivered"
+	ORDER_STATUS_CANCELLED = "cancelled"
+)
+
 var TNOrder = "orders"
 
 // TableName sets the insert table name for Order struct
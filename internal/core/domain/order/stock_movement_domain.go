@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type STOCK_MOVEMENT_TYPE string
+
+const (
+	STOCK_MOVEMENT_RECEIPT     STOCK_MOVEMENT_TYPE = "receipt"     // Units delivered by a supplier
+	STOCK_MOVEMENT_SALE        STOCK_MOVEMENT_TYPE = "sale"        // Reserved units that were paid for and left the stock
+	STOCK_MOVEMENT_RETURN      STOCK_MOVEMENT_TYPE = "return"      // Units sent back by a customer
+	STOCK_MOVEMENT_ADJUSTMENT  STOCK_MOVEMENT_TYPE = "adjustment"  // Corrections after a count, damage or loss; also the opening balance
+	STOCK_MOVEMENT_TRANSFER    STOCK_MOVEMENT_TYPE = "transfer"    // Units moved between stock locations
+	STOCK_MOVEMENT_RESERVATION STOCK_MOVEMENT_TYPE = "reservation" // Units held for, or released by, a checkout
+)
+
+// STOCK_REFERENCE_TYPE names the kind of document a movement was made for.
+type STOCK_REFERENCE_TYPE string
+
+const (
+	STOCK_REFERENCE_ORDER          STOCK_REFERENCE_TYPE = "order"
+	STOCK_REFERENCE_SKU            STOCK_REFERENCE_TYPE = "sku"            // An edit of the SKU in the catalog
+	STOCK_REFERENCE_CATALOG_IMPORT STOCK_REFERENCE_TYPE = "catalog_import" // A bulk catalog import job
+	STOCK_REFERENCE_DOCUMENT       STOCK_REFERENCE_TYPE = "document"       // A document outside the system, e.g. a delivery note
+	STOCK_REFERENCE_TRANSFER       STOCK_REFERENCE_TYPE = "transfer"       // A transfer between warehouses, shared by its two movements
+)
+
+// StockMovement is one change to the stock of a SKU at a warehouse.
+// Movements are only ever appended: the on-hand and reserved units of an
+// inventory row are the sums of Quantity and Reserved over its movements.
+type StockMovement struct {
+	domain.BaseModel
+	SKUID         uuid.UUID            `json:"sku_id" gorm:"type:uuid;not null;index:idx_stock_movements_sku"`
+	WarehouseID   uuid.UUID            `json:"warehouse_id" gorm:"type:uuid;not null;index"`
+	Type          STOCK_MOVEMENT_TYPE  `json:"type" gorm:"size:20;not null"`
+	Quantity      int                  `json:"quantity" gorm:"not null"`           // Change of the units on hand, negative when they leave
+	Reserved      int                  `json:"reserved" gorm:"not null;default:0"` // Change of the reserved units
+	ActorID       *uuid.UUID           `json:"actor_id" gorm:"type:uuid"`          // Who made the change, nil for the system, e.g. expired reservations
+	Reason        string               `json:"reason" gorm:"size:255"`
+	ReferenceType STOCK_REFERENCE_TYPE `json:"reference_type,omitempty" gorm:"size:30;index:idx_stock_movements_reference"`
+	ReferenceID   *uuid.UUID           `json:"reference_id,omitempty" gorm:"type:uuid;index:idx_stock_movements_reference"`
+	CreatedAt     time.Time            `gorm:"default:CURRENT_TIMESTAMP;index:idx_stock_movements_sku" json:"created_at"`
+}
+
+var TNStockMovement = "stock_movements"
+
+// TableName sets the insert table name for StockMovement struct
+func (StockMovement) TableName() string {
+	return TNStockMovement
+}
+
+func (m *StockMovement) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BeforeUpdate refuses every update, the ledger is append-only.
+func (m *StockMovement) BeforeUpdate(tx *gorm.DB) error {
+	return gorm.ErrInvalidData
+}
+
+// BeforeDelete refuses every delete, the ledger is append-only.
+func (m *StockMovement) BeforeDelete(tx *gorm.DB) error {
+	return gorm.ErrInvalidData
+}
+
+// RecordStockMovementDomain is a movement entered by staff. Receipts and
+// returns add units; adjustments carry the signed change.
+type RecordStockMovementDomain struct {
+	Type        STOCK_MOVEMENT_TYPE `json:"type" validate:"required,oneof=receipt return adjustment"`
+	Quantity    int                 `json:"quantity" validate:"required"`
+	Reason      string              `json:"reason" validate:"required,max=255"`
+	ReferenceID *uuid.UUID          `json:"reference_id"` // The delivery note or return document, if any
+	WarehouseID *uuid.UUID          `json:"warehouse_id"` // Where the units are, the default warehouse when left out
+}
+
+// SetStockDomain is a count of the units on hand of a SKU, made by the
+// system editing it, e.g. the catalog. Without a warehouse, Quantity is the
+// total over every warehouse and the difference is counted at the default
+// one.
+type SetStockDomain struct {
+	WarehouseID   *uuid.UUID
+	Quantity      int
+	Reason        string
+	ReferenceType STOCK_REFERENCE_TYPE
+	ReferenceID   *uuid.UUID
+}
+
+type StockMovementFilters struct {
+	Type        string `json:"type"`         // Comma separated movement types
+	WarehouseID string `json:"warehouse_id"` // Only the movements at this warehouse
+	pagination.CommonTimeFilters
+}
+
+// InventoryDiscrepancy is an inventory row whose counters do not match the
+// sums of its movements.
+type InventoryDiscrepancy struct {
+	SKUID          uuid.UUID `json:"sku_id"`
+	WarehouseID    uuid.UUID `json:"warehouse_id"`
+	Quantity       int       `json:"quantity"`
+	LedgerQuantity int       `json:"ledger_quantity"`
+	Reserved       int       `json:"reserved"`
+	LedgerReserved int       `json:"ledger_reserved"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Warehouse is a location stock is kept in and shipped from. Exactly one
+// warehouse is the default: the stock of SKUs from before they were counted
+// per location starts there, and stock edits made from the catalog, which
+// only knows the total of a SKU, are counted there.
+type Warehouse struct {
+	domain.BaseModel
+	Code      string               `json:"code" gorm:"size:50;not null;uniqueIndex"`        // Short unique code, e.g. 'BKK-1'
+	Name      string               `json:"name" gorm:"size:255;not null"`                   // Display name of the warehouse
+	Address   domain.PostalAddress `json:"address" gorm:"embedded;embeddedPrefix:address_"` // Where parcels leave from, used to find the nearest warehouse
+	Priority  int                  `json:"priority" gorm:"not null;default:0"`              // Lower ships first under the priority strategy and breaks ties in the others
+	IsDefault bool                 `json:"is_default" gorm:"not null"`                      // Whether catalog stock edits are counted here
+	Active    bool                 `json:"active" gorm:"not null"`                          // Inactive warehouses keep their stock but ship nothing
+	CreatedAt time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`     // Timestamp when the warehouse record was created
+	UpdatedAt time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`     // Timestamp when the warehouse record was last updated
+	DeletedAt gorm.DeletedAt       `gorm:"index" json:"deleted_at"`                         // Timestamp for soft deletes
+}
+
+var TNWarehouse = "warehouses"
+
+// TableName sets the insert table name for Warehouse struct
+func (Warehouse) TableName() string {
+	return TNWarehouse
+}
+
+func (w *Warehouse) BeforeCreate(tx *gorm.DB) (err error) {
+	if w.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpsertWarehouseDomain creates or edits a warehouse. Marking a warehouse
+// as the default takes the mark off the previous one.
+type UpsertWarehouseDomain struct {
+	Code        string `json:"code" validate:"required,max=50"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Line1       string `json:"line1" validate:"omitempty,max=255"`
+	Line2       string `json:"line2" validate:"omitempty,max=255"`
+	City        string `json:"city" validate:"omitempty,max=100"`
+	Region      string `json:"region" validate:"omitempty,max=100"`
+	PostalCode  string `json:"postal_code" validate:"omitempty,max=20"`
+	CountryCode string `json:"country_code" validate:"required,len=2,alpha"`
+	Phone       string `json:"phone" validate:"omitempty,max=30"`
+	Priority    int    `json:"priority"`
+	IsDefault   bool   `json:"is_default"`
+	Active      bool   `json:"active"`
+}
+
+// TransferStockDomain moves units of a SKU from one warehouse to another.
+// Only units no checkout holds can be moved.
+type TransferStockDomain struct {
+	FromWarehouseID uuid.UUID `json:"from_warehouse_id" validate:"required"`
+	ToWarehouseID   uuid.UUID `json:"to_warehouse_id" validate:"required"`
+	Quantity        int       `json:"quantity" validate:"required,gt=0"`
+	Reason          string    `json:"reason" validate:"required,max=255"`
+}
@@ -10,7 +10,7 @@ import (
 
 type Payment struct {
 	ID            uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();" json:"id"` // Unique identifier for each payment record
-	OrderID       uuid.UUID      `json:"order_id" gorm:"not null"`                        // References the Order table to link the payment to a specific order
+	OrderID       uuid.UUID      `json:"order_id" gorm:"type:uuid;not null;index"`        // References the Order table to link the payment to a specific order
 	PaymentDate   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"payment_date"`   // Timestamp when the payment was made
 	PaymentMethod string         `json:"payment_method" gorm:"not null"`                  // Method used for payment (e.g., 'Credit Card', 'PayPal')
 	Amount        float64        `json:"amount" gorm:"not null"`                          // Amount paid for the order
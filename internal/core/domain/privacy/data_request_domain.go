@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	paymentDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/payment"
+	reviewDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/reviews"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type DATA_REQUEST_TYPE string
+
+const (
+	DATA_REQUEST_TYPE_EXPORT  DATA_REQUEST_TYPE = "export"
+	DATA_REQUEST_TYPE_ERASURE DATA_REQUEST_TYPE = "erasure"
+)
+
+type DATA_REQUEST_STATUS string
+
+const (
+	DATA_REQUEST_STATUS_PENDING    DATA_REQUEST_STATUS = "pending"
+	DATA_REQUEST_STATUS_PROCESSING DATA_REQUEST_STATUS = "processing"
+	DATA_REQUEST_STATUS_COMPLETED  DATA_REQUEST_STATUS = "completed"
+	DATA_REQUEST_STATUS_FAILED     DATA_REQUEST_STATUS = "failed"
+	DATA_REQUEST_STATUS_EXPIRED    DATA_REQUEST_STATUS = "expired" // The export archive was deleted after its retention period
+)
+
+type EXPORT_FORMAT string
+
+const (
+	EXPORT_FORMAT_JSON EXPORT_FORMAT = "json"
+	EXPORT_FORMAT_ZIP  EXPORT_FORMAT = "zip"
+)
+
+// DataRequest tracks a data-access (export) or erasure request about a user.
+type DataRequest struct {
+	domain.BaseModel
+	UserID        uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`     // The user the request is about
+	RequestedByID uuid.UUID           `json:"requested_by_id" gorm:"type:uuid;not null"`   // The user themselves, or the administrator handling the request
+	Type          DATA_REQUEST_TYPE   `json:"type" gorm:"size:20;not null"`                // export or erasure
+	Status        DATA_REQUEST_STATUS `json:"status" gorm:"size:20;not null;index"`        // Progress of the background job
+	Format        EXPORT_FORMAT       `json:"format,omitempty" gorm:"size:10"`             // Archive format of an export
+	FileKey       string              `json:"-" gorm:"size:255"`                           // Storage key of the export archive
+	FileSize      int64               `json:"file_size,omitempty"`                         // Size of the export archive in bytes
+	Reason        string              `json:"reason,omitempty" gorm:"type:text"`           // Why an administrator raised the request
+	Error         string              `json:"error,omitempty" gorm:"type:text"`            // Why the job failed
+	CompletedAt   *time.Time          `json:"completed_at"`                                // Timestamp when the job finished
+	ExpiresAt     *time.Time          `json:"expires_at"`                                  // Timestamp after which the export can no longer be downloaded
+	CreatedAt     time.Time           `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"` // Timestamp when the request was made
+	UpdatedAt     time.Time           `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"` // Timestamp when the request was last updated
+}
+
+var TNDataRequest = "data_requests"
+
+// TableName sets the insert table name for DataRequest struct
+func (DataRequest) TableName() string {
+	return TNDataRequest
+}
+
+func (d *DataRequest) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+type RequestDataExportDomain struct {
+	Format EXPORT_FORMAT `json:"format" validate:"omitempty,oneof=json zip"` // Defaults to zip
+	Reason string        `json:"reason" validate:"omitempty,max=1000"`
+}
+
+type RequestErasureDomain struct {
+	// Confirm must repeat the username of the account, so an erasure is
+	// never triggered by accident.
+	Confirm string `json:"confirm" validate:"required,max=255"`
+	Reason  string `json:"reason" validate:"omitempty,max=1000"`
+}
+
+// CartExport is a cart with its items.
+type CartExport struct {
+	cartDomain.Cart
+	Items []cartDomain.CartItem `json:"items"`
+}
+
+// UserDataExport is everything stored about a user, as handed out in an
+// export archive. Secrets (password hashes, token hashes, TOTP secrets) are
+// never part of it.
+type UserDataExport struct {
+	GeneratedAt     time.Time                     `json:"generated_at"`
+	User            userDomain.User               `json:"user"`
+	Profile         *userDomain.UserProfile       `json:"profile"`
+	Addresses       []userDomain.UserAddress      `json:"addresses"`
+	Carts           []CartExport                  `json:"carts"`
+	Orders          []orderDomain.Order           `json:"orders"`
+	Payments        []paymentDomain.Payment       `json:"payments"`
+	Reviews         []reviewDomain.Review         `json:"reviews"`
+	ShippingReviews []reviewDomain.ShippingReview `json:"shipping_reviews"`
+	Emails          []messageDomain.Email         `json:"emails"`
+	OTPs            []messageDomain.OTP           `json:"otps"`
+	RefreshTokens   []authDomain.RefreshToken     `json:"refresh_tokens"`
+	APIKeys         []authDomain.APIKey           `json:"api_keys"`
+	OAuthIdentities []authDomain.OAuthIdentity    `json:"oauth_identities"`
+	DataRequests    []DataRequest                 `json:"data_requests"`
+}
+
+// ErasureSummary counts what an erasure removed or anonymised.
+type ErasureSummary struct {
+	AnonymisedOrders int64 `json:"anonymised_orders"` // Orders kept for accounting with the shipping details scrubbed
+	DeletedRecords   int64 `json:"deleted_records"`   // Rows removed outright (profile, addresses, carts, emails, codes, tokens, keys)
+}
+
+// ExportFile is a downloadable export archive.
+type ExportFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+)
+
+// ProductFilters narrows the public product list. Empty fields do not filter.
+type ProductFilters struct {
+	CategoryID string   `json:"category_id"` // Comma-separated list of category IDs
+	InCategory string   `json:"in_category"` // Category ID or slug; matches its whole subtree
+	SellerID   string   `json:"seller_id"`   // Comma-separated list of creator IDs
+	SKU        string   `json:"sku"`         // Comma-separated list of SKUs
+	Search     string   `json:"search"`      // Part of the name or SKU, case-insensitive
+	MinPrice   *float64 `json:"min_price"`
+	MaxPrice   *float64 `json:"max_price"`
+	PriceBand  string   `json:"price"`      // Price facet bucket, "25-50", "-25" or "500-"; the upper limit is exclusive
+	MinRating  *int     `json:"min_rating"` // Lowest average review rating
+	// Options maps a lowercased option name to the accepted values; a product
+	// needs one of the values of every listed option.
+	Options map[string][]string `json:"options"`
+	pagination.CommonTimeFilters
+}
+
+// ErrInvalidPriceBand is returned by ParsePriceBand.
+var ErrInvalidPriceBand = errors.New(`price must be a band such as "25-50", "-25" or "500-"`)
+
+// ParsePriceBand reads a price facet bucket. from is inclusive and below
+// exclusive; an open end is nil. An empty band yields two nils.
+func ParsePriceBand(band string) (from, below *float64, err error) {
+	if band == "" {
+		return nil, nil, nil
+	}
+	low, high, found := strings.Cut(band, "-")
+	if !found || (low == "" && high == "") {
+		return nil, nil, ErrInvalidPriceBand
+	}
+	parse := func(raw string) (*float64, error) {
+		if raw == "" {
+			return nil, nil
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			return nil, ErrInvalidPriceBand
+		}
+		return &value, nil
+	}
+	if from, err = parse(low); err != nil {
+		return nil, nil, err
+	}
+	if below, err = parse(high); err != nil {
+		return nil, nil, err
+	}
+	if from != nil && below != nil && *from >= *below {
+		return nil, nil, ErrInvalidPriceBand
+	}
+	return from, below, nil
+}
+
+// CategoryFilters narrows the category list. Empty fields do not filter.
+type CategoryFilters struct {
+	Search   string `json:"search"`    // Part of the name, case-insensitive
+	ParentID string `json:"parent_id"` // Parent category ID, or "root" for the top level
+}
+
+// UpsertCategoryDomain is the payload to rename a category. An empty Slug
+// keeps the current one, or is derived from the name for a new category;
+// Position keeps its current value when nil.
+type UpsertCategoryDomain struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Slug     string `json:"slug" validate:"omitempty,max=120"`
+	Position *int   `json:"position" validate:"omitempty,gte=0"`
+}
+
+// CreateCategoryDomain adds a category under ParentID, or at the root when
+// ParentID is nil. Without a Position it goes after its siblings.
+type CreateCategoryDomain struct {
+	UpsertCategoryDomain
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+// MoveCategoryDomain reparents a category with its whole subtree.
+type MoveCategoryDomain struct {
+	ParentID *uuid.UUID `json:"parent_id"`
+	Position *int       `json:"position" validate:"omitempty,gte=0"`
+}
+
+type UpsertProductDomain struct {
+	Name        string    `json:"name" validate:"required,max=150"`
+	SKU         string    `json:"sku" validate:"required,max=64"`
+	Description string    `json:"description" validate:"omitempty,max=10000"`
+	Price       float64   `json:"price" validate:"gte=0"`
+	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
+}
+
+// CreateProductOptionDomain adds an option type with its initial values.
+type CreateProductOptionDomain struct {
+	Name   string   `json:"name" validate:"required,max=50"`
+	Values []string `json:"values" validate:"required,min=1,dive,required,max=50"`
+}
+
+type UpdateProductOptionDomain struct {
+	Name     string `json:"name" validate:"required,max=50"`
+	Position *int   `json:"position" validate:"omitempty,gte=0"`
+}
+
+type AddProductOptionValueDomain struct {
+	Value string `json:"value" validate:"required,max=50"`
+}
+
+// UpsertProductSKUDomain is the payload to add a SKU or change one.
+// OptionValueIDs picks one value of every option of the product; it is only
+// read when the SKU is created, a combination never changes afterwards.
+type UpsertProductSKUDomain struct {
+	Code           string      `json:"code" validate:"required,max=64"`
+	Barcode        string      `json:"barcode" validate:"omitempty,max=64"`
+	Price          float64     `json:"price" validate:"gte=0"`
+	WeightGrams    int         `json:"weight_grams" validate:"gte=0"`
+	Stock          int         `json:"stock" validate:"gte=0"`
+	OptionValueIDs []uuid.UUID `json:"option_value_ids"`
+}
+
+// GenerateProductSKUsDomain builds the cartesian product of option values.
+// Without OptionValueIDs every value of every option is used; otherwise each
+// option needs at least one of its values listed. Price defaults to the
+// product's price.
+type GenerateProductSKUsDomain struct {
+	OptionValueIDs []uuid.UUID `json:"option_value_ids"`
+	Price          *float64    `json:"price" validate:"omitempty,gte=0"`
+	WeightGrams    int         `json:"weight_grams" validate:"gte=0"`
+}
+
+// AddProductImageDomain attaches an image that is already hosted somewhere.
+type AddProductImageDomain struct {
+	ImageURL  string `json:"image_url" validate:"required,url,max=255"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// UploadProductImageDomain is an uploaded image file. ContentType is what the
+// client declared; the bytes must sniff as the same type.
+type UploadProductImageDomain struct {
+	Data        []byte
+	ContentType string
+	IsPrimary   bool
+}
+
+// UploadProductImageBase64Domain uploads an image as a data URI, e.g.
+// "data:image/png;base64,iVBORw0...".
+type UploadProductImageBase64Domain struct {
+	Image     string `json:"image" validate:"required"`
+	IsPrimary bool   `json:"is_primary"`
+}
+
+// ReorderProductImagesDomain lists every image of the product in the new order.
+type ReorderProductImagesDomain struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required,min=1"`
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CATALOG_FORMAT string
+
+const (
+	CATALOG_FORMAT_CSV    CATALOG_FORMAT = "csv"
+	CATALOG_FORMAT_NDJSON CATALOG_FORMAT = "ndjson" // One JSON object per line
+)
+
+type CATALOG_IMPORT_STATUS string
+
+const (
+	CATALOG_IMPORT_STATUS_PENDING    CATALOG_IMPORT_STATUS = "pending"
+	CATALOG_IMPORT_STATUS_PROCESSING CATALOG_IMPORT_STATUS = "processing"
+	CATALOG_IMPORT_STATUS_COMPLETED  CATALOG_IMPORT_STATUS = "completed" // Every row was read; FailedRows may still be above zero
+	CATALOG_IMPORT_STATUS_FAILED     CATALOG_IMPORT_STATUS = "failed"    // The file could not be read at all
+)
+
+// CatalogImportJob tracks a bulk catalog upload processed in the background.
+type CatalogImportJob struct {
+	domain.BaseModel
+	RequestedByID uuid.UUID             `json:"requested_by_id" gorm:"type:uuid;not null;index"` // References the User table; owner of the products created
+	Format        CATALOG_FORMAT        `json:"format" gorm:"size:10;not null"`                  // csv or ndjson
+	Status        CATALOG_IMPORT_STATUS `json:"status" gorm:"size:20;not null;index"`            // Progress of the background job
+	FileName      string                `json:"file_name" gorm:"size:255"`                       // Name of the uploaded file
+	FileKey       string                `json:"-" gorm:"size:255"`                               // Storage key of the uploaded file
+	ReportKey     string                `json:"-" gorm:"size:255"`                               // Storage key of the error report, empty when no row failed
+	TotalRows     int                   `json:"total_rows" gorm:"not null;default:0"`            // Data rows in the file
+	ImportedRows  int                   `json:"imported_rows" gorm:"not null;default:0"`         // Rows written to the catalog
+	FailedRows    int                   `json:"failed_rows" gorm:"not null;default:0"`           // Rows listed in the error report
+	Error         string                `json:"error,omitempty" gorm:"type:text"`                // Why the job failed
+	Attempts      int                   `json:"attempts" gorm:"not null;default:0"`              // Times a worker started the job; it is restarted when a worker stops mid-import
+	CompletedAt   *time.Time            `json:"completed_at"`                                    // Timestamp when the job finished
+	CreatedAt     time.Time             `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`     // Timestamp when the file was uploaded
+	UpdatedAt     time.Time             `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`     // Timestamp when the job was last updated
+}
+
+var TNCatalogImportJob = "catalog_import_jobs"
+
+// TableName sets the insert table name for CatalogImportJob struct
+func (CatalogImportJob) TableName() string {
+	return TNCatalogImportJob
+}
+
+func (j *CatalogImportJob) BeforeCreate(tx *gorm.DB) (err error) {
+	if j.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CatalogRow is one line of a catalog import or export: a product and one of
+// its SKUs. A row without SKU only upserts the product. The CSV header uses
+// the JSON names; Options is written there as a query string such as
+// "Color=Red&Size=L".
+type CatalogRow struct {
+	ProductSKU   string            `json:"product_sku"`             // Identifies the product
+	Name         string            `json:"name"`                    // Product name
+	Description  string            `json:"description"`             // Product description
+	Category     string            `json:"category"`                // Category slug or ID
+	ProductPrice *float64          `json:"product_price,omitempty"` // Base price of the product; empty keeps the current one
+	SKU          string            `json:"sku,omitempty"`           // Identifies the SKU
+	Barcode      string            `json:"barcode,omitempty"`
+	Price        float64           `json:"price"`
+	WeightGrams  int               `json:"weight_grams"`
+	Stock        int               `json:"stock"`
+	Options      map[string]string `json:"options,omitempty"` // Option name to value, one entry per option of the product
+}
+
+// CatalogRowError is one line of an import error report.
+type CatalogRowError struct {
+	Line       int    `json:"line"` // Line of the row in the file, the CSV header being line 1
+	ProductSKU string `json:"product_sku"`
+	SKU        string `json:"sku"`
+	Error      string `json:"error"`
+}
+
+// StartCatalogImportDomain is an uploaded catalog file.
+type StartCatalogImportDomain struct {
+	FileName string
+	Format   CATALOG_FORMAT
+	Data     []byte
+}
+
+// CatalogFile is a stored file handed back to the client.
+type CatalogFile struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// CatalogImportFilters narrows the list of import jobs.
+type CatalogImportFilters struct {
+	Status string `json:"status"` // Comma-separated list of statuses
+	pagination.CommonTimeFilters
+}
+
+// ParseCatalogFormat reads a format name; "jsonl" is accepted for ndjson.
+func ParseCatalogFormat(value string) (CATALOG_FORMAT, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "csv":
+		return CATALOG_FORMAT_CSV, true
+	case "ndjson", "jsonl":
+		return CATALOG_FORMAT_NDJSON, true
+	}
+	return "", false
+}
+
+// ContentType returns the media type of files in the format.
+func (f CATALOG_FORMAT) ContentType() string {
+	if f == CATALOG_FORMAT_NDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
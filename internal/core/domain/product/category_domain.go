@@ -12,6 +12,10 @@ import (
 type Category struct {
 	domain.BaseModel
 	Name      string         `json:"name" gorm:"size:100;not null"`               // Name of the category
+	Slug      string         `json:"slug" gorm:"size:120;not null;uniqueIndex"`   // URL segment of the category, unique across the tree
+	ParentID  *uuid.UUID     `json:"parent_id" gorm:"type:uuid;index"`            // References the parent Category, nil for a root category
+	Position  int            `json:"position" gorm:"not null;default:0"`          // Sort order among the siblings, lowest first
+	Children  []Category     `json:"children,omitempty" gorm:"-"`                 // Sub-categories, filled when the tree is returned
 	CreatedBy uuid.UUID      `json:"created_by" gorm:"not null"`                  // References the User table to track who created the category
 	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the category was created
 	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the category was last updated
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Kinds of catalog facets.
+const (
+	FacetKindCategory = "category" // Sub-categories of the listed category
+	FacetKindPrice    = "price"    // Price bands
+	FacetKindRating   = "rating"   // Average review rating, "4 & up"
+	FacetKindOption   = "option"   // Values of a product option such as Color
+)
+
+// CategoryFacet defines a facet shown when listing a category. A category
+// without definitions uses those of its nearest ancestor that has some, and
+// the top of the tree falls back to DefaultCategoryFacets.
+type CategoryFacet struct {
+	domain.BaseModel
+	CategoryID uuid.UUID      `json:"category_id" gorm:"type:uuid;not null;index"` // References the Category table
+	Kind       string         `json:"kind" gorm:"size:20;not null"`                // One of the FacetKind constants
+	Label      string         `json:"label" gorm:"size:50;not null"`               // Title shown above the buckets
+	OptionName string         `json:"option_name" gorm:"size:50"`                  // Option facets: name of the product option, case-insensitive
+	PriceBands string         `json:"price_bands" gorm:"size:255"`                 // Price facets: ascending band limits, comma-separated, e.g. "25,50,100"
+	Position   int            `json:"position" gorm:"not null;default:0"`          // Sort order among the category's facets
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the facet was created
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the facet was last updated
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNCategoryFacet = "category_facets"
+
+// TableName sets the insert table name for CategoryFacet struct
+func (CategoryFacet) TableName() string {
+	return TNCategoryFacet
+}
+
+func (f *CategoryFacet) BeforeCreate(tx *gorm.DB) (err error) {
+	if f.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Bands returns the band limits of a price facet. Entries that are not
+// numbers are skipped; the service only stores valid lists.
+func (f CategoryFacet) Bands() []float64 {
+	var bands []float64
+	for _, field := range strings.Split(f.PriceBands, ",") {
+		if value, err := strconv.ParseFloat(strings.TrimSpace(field), 64); err == nil {
+			bands = append(bands, value)
+		}
+	}
+	return bands
+}
+
+// DefaultCategoryFacets are used when no category on the path defines facets.
+var DefaultCategoryFacets = []CategoryFacet{
+	{Kind: FacetKindCategory, Label: "Category"},
+	{Kind: FacetKindPrice, Label: "Price", PriceBands: "25,50,100,250,500", Position: 1},
+	{Kind: FacetKindRating, Label: "Rating", Position: 2},
+}
+
+// UpsertCategoryFacetDomain is one facet of a SetCategoryFacetsDomain.
+type UpsertCategoryFacetDomain struct {
+	Kind       string    `json:"kind" validate:"required,oneof=category price rating option"`
+	Label      string    `json:"label" validate:"required,max=50"`
+	OptionName string    `json:"option_name" validate:"omitempty,max=50"`
+	PriceBands []float64 `json:"price_bands" validate:"omitempty,max=10,dive,gt=0"`
+}
+
+// SetCategoryFacetsDomain replaces the facets of a category, in display
+// order. An empty list makes the category inherit its parent's facets again.
+type SetCategoryFacetsDomain struct {
+	Facets []UpsertCategoryFacetDomain `json:"facets" validate:"max=12,dive"`
+}
+
+// CategoryFacetsDomain lists the facets in effect for a category and the
+// category that defines them, nil for DefaultCategoryFacets.
+type CategoryFacetsDomain struct {
+	Facets    []CategoryFacet `json:"facets"`
+	DefinedBy *uuid.UUID      `json:"defined_by"`
+}
+
+// FacetCount is one bucket counted by the repository. Facet is the index of
+// the facet in the definitions passed in.
+type FacetCount struct {
+	Facet  int
+	Bucket string // Category ID, price band index, rating floor or lowercased option value
+	Label  string // Option facets: the value as written on the product
+	Count  int64
+}
+
+// Facet is a facet of a product listing with its buckets.
+type Facet struct {
+	Kind    string        `json:"kind"`
+	Key     string        `json:"key"` // Query parameter that filters on the facet
+	Label   string        `json:"label"`
+	Buckets []FacetBucket `json:"buckets"`
+}
+
+type FacetBucket struct {
+	Value    string   `json:"value"` // What to send in Key to pick the bucket
+	Label    string   `json:"label"`
+	Count    int64    `json:"count"`
+	Min      *float64 `json:"min,omitempty"` // Price bands: lower limit, inclusive
+	Max      *float64 `json:"max,omitempty"` // Price bands: upper limit, exclusive
+	Selected bool     `json:"selected"`
+}
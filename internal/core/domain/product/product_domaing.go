@@ -11,12 +11,18 @@ import (
 
 type Product struct {
 	domain.BaseModel
-	Name       string         `json:"name" gorm:"size:150;not null"`               // Name of the product
-	CategoryID uuid.UUID      `json:"category_id" gorm:"not null"`                 // References the Category table to classify the product
-	CreatedBy  uuid.UUID      `json:"created_by" gorm:"not null"`                  // References the User table to track who created the product
-	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the product was created
-	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the product was last updated
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	Name         string          `json:"name" gorm:"size:150;not null"`                       // Name of the product
+	SKU          string          `json:"sku" gorm:"size:64;not null;uniqueIndex"`             // Stock keeping unit, unique across the catalog
+	Description  string          `json:"description" gorm:"type:text"`                        // Long description shown on the product page
+	Price        float64         `json:"price" gorm:"type:float;not null;default:0"`          // Base price; generated SKUs start from it
+	CategoryID   uuid.UUID       `json:"category_id" gorm:"not null"`                         // References the Category table to classify the product
+	CreatedBy    uuid.UUID       `json:"created_by" gorm:"not null"`                          // References the User table to track who created the product
+	Options      []ProductOption `json:"options,omitempty" gorm:"foreignKey:ProductID"`       // Option types with their values, filled on reads
+	SKUs         []ProductSKU    `json:"skus,omitempty" gorm:"foreignKey:ProductID"`          // Sellable combinations of option values, filled on reads
+	PrimaryImage *ProductImage   `json:"primary_image,omitempty" gorm:"foreignKey:ProductID"` // Image flagged IsPrimary, filled on reads
+	CreatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`         // Timestamp when the product was created
+	UpdatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`         // Timestamp when the product was last updated
+	DeletedAt    gorm.DeletedAt  `gorm:"index" json:"deleted_at"`                             // Timestamp for soft deletes
 }
 
 var TNProduct = "products"
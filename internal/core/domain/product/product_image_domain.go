@@ -11,12 +11,18 @@ import (
 
 type ProductImage struct {
 	domain.BaseModel
-	ProductID uuid.UUID      `json:"product_id" gorm:"not null"`                  // References the Product table to link the image to a specific product
-	ImageURL  string         `json:"image_url" gorm:"size:255;not null"`          // URL or path to the image file
-	IsPrimary bool           `json:"is_primary" gorm:"default:false"`             // Indicates whether this image is the primary image for the product
-	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the image record was created
-	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the image record was last updated
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+	ProductID   uuid.UUID               `json:"product_id" gorm:"not null"`                  // References the Product table to link the image to a specific product
+	ImageURL    string                  `json:"image_url" gorm:"size:255;not null"`          // URL or path to the image file
+	StorageKey  string                  `json:"-" gorm:"size:255"`                           // Storage key of an uploaded image, empty for images hosted elsewhere
+	ContentType string                  `json:"content_type" gorm:"size:50"`                 // MIME type of an uploaded image
+	Width       int                     `json:"width" gorm:"not null;default:0"`             // Pixel width of an uploaded image
+	Height      int                     `json:"height" gorm:"not null;default:0"`            // Pixel height of an uploaded image
+	Position    int                     `json:"position" gorm:"not null;default:0"`          // Sort order among the product's images
+	IsPrimary   bool                    `json:"is_primary" gorm:"default:false"`             // Indicates whether this image is the primary image for the product
+	Renditions  []ProductImageRendition `json:"renditions" gorm:"foreignKey:ImageID"`        // Thumbnails and WebP copies of an uploaded image
+	CreatedAt   time.Time               `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the image record was created
+	UpdatedAt   time.Time               `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the image record was last updated
+	DeletedAt   gorm.DeletedAt          `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
 }
 
 var TNProductImage = "product_images"
@@ -32,3 +38,34 @@ func (pi *ProductImage) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// ProductImageRendition is a resized or re-encoded copy of an uploaded
+// ProductImage, e.g. the 200px thumbnail in WebP.
+type ProductImageRendition struct {
+	domain.BaseModel
+	ImageID     uuid.UUID      `json:"image_id" gorm:"type:uuid;not null;index"`    // References the ProductImage table
+	Name        string         `json:"name" gorm:"size:20;not null"`                // Size name, e.g. 'thumb', 'medium' or 'full'
+	Format      string         `json:"format" gorm:"size:10;not null"`              // Encoding: 'jpeg', 'png' or 'webp'
+	ContentType string         `json:"content_type" gorm:"size:50;not null"`        // MIME type of the file
+	ImageURL    string         `json:"image_url" gorm:"size:255;not null"`          // URL the file is served from
+	StorageKey  string         `json:"-" gorm:"size:255;not null"`                  // Storage key, used to delete the file
+	Width       int            `json:"width" gorm:"not null"`                       // Pixel width
+	Height      int            `json:"height" gorm:"not null"`                      // Pixel height
+	CreatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the rendition was created
+	UpdatedAt   time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the rendition was last updated
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNProductImageRendition = "product_image_renditions"
+
+// TableName sets the insert table name for ProductImageRendition struct
+func (ProductImageRendition) TableName() string {
+	return TNProductImageRendition
+}
+
+func (r *ProductImageRendition) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
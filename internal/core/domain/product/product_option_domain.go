@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductOption is an option type a product varies by, such as Color or Size.
+type ProductOption struct {
+	domain.BaseModel
+	ProductID uuid.UUID            `json:"product_id" gorm:"type:uuid;not null;index"`  // References the Product table
+	Name      string               `json:"name" gorm:"size:50;not null"`                // Name of the option (e.g., 'Color', 'Size')
+	Position  int                  `json:"position" gorm:"not null;default:0"`          // Sort order among the product's options
+	Values    []ProductOptionValue `json:"values,omitempty" gorm:"foreignKey:OptionID"` // Values the option can take, filled on reads
+	CreatedAt time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the option was created
+	UpdatedAt time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the option was last updated
+	DeletedAt gorm.DeletedAt       `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNProductOption = "product_options"
+
+// TableName sets the insert table name for ProductOption struct
+func (ProductOption) TableName() string {
+	return TNProductOption
+}
+
+func (o *ProductOption) BeforeCreate(tx *gorm.DB) (err error) {
+	if o.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ProductOptionValue is one value of a ProductOption, such as Red or Large.
+type ProductOptionValue struct {
+	domain.BaseModel
+	OptionID  uuid.UUID      `json:"option_id" gorm:"type:uuid;not null;index"`   // References the ProductOption table
+	Value     string         `json:"value" gorm:"size:50;not null"`               // The value (e.g., 'Red', 'Large')
+	Position  int            `json:"position" gorm:"not null;default:0"`          // Sort order among the option's values
+	CreatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"` // Timestamp when the value was created
+	UpdatedAt time.Time      `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"` // Timestamp when the value was last updated
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`                     // Timestamp for soft deletes
+}
+
+var TNProductOptionValue = "product_option_values"
+
+// TableName sets the insert table name for ProductOptionValue struct
+func (ProductOptionValue) TableName() string {
+	return TNProductOptionValue
+}
+
+func (v *ProductOptionValue) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
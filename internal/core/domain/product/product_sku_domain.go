@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductSKU is a sellable unit of a product: one value of each of the
+// product's options, e.g. "Red / Large".
+type ProductSKU struct {
+	domain.BaseModel
+	ProductID    uuid.UUID            `json:"product_id" gorm:"type:uuid;not null;index:idx_product_skus_signature"`                                      // References the Product table
+	Code         string               `json:"code" gorm:"size:64;not null;uniqueIndex"`                                                                   // Stock keeping unit code, unique across the catalog
+	Barcode      string               `json:"barcode" gorm:"size:64;index"`                                                                               // EAN/UPC or any scannable code, optional
+	Price        float64              `json:"price" gorm:"type:float;not null"`                                                                           // Selling price of this SKU
+	WeightGrams  int                  `json:"weight_grams" gorm:"not null;default:0"`                                                                     // Shipping weight in grams
+	Stock        int                  `json:"stock" gorm:"not null;default:0"`                                                                            // Units on hand
+	Signature    string               `json:"-" gorm:"type:text;not null;index:idx_product_skus_signature"`                                               // Sorted option value IDs; identifies the combination within the product
+	OptionValues []ProductOptionValue `json:"option_values" gorm:"many2many:product_sku_option_values;joinForeignKey:SKUID;joinReferences:OptionValueID"` // The combination of option values
+	CreatedAt    time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`                                                                // Timestamp when the SKU was created
+	UpdatedAt    time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`                                                                // Timestamp when the SKU was last updated
+	DeletedAt    gorm.DeletedAt       `gorm:"index" json:"deleted_at"`                                                                                    // Timestamp for soft deletes
+}
+
+var TNProductSKU = "product_skus"
+
+// TableName sets the insert table name for ProductSKU struct
+func (ProductSKU) TableName() string {
+	return TNProductSKU
+}
+
+func (s *ProductSKU) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID, err = uuidv7.GenerateUUIDv7(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// SKUSignature identifies a combination of option values regardless of the
+// order they are given in.
+func SKUSignature(valueIDs []uuid.UUID) string {
+	ids := make([]string, len(valueIDs))
+	for i, id := range valueIDs {
+		ids[i] = id.String()
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
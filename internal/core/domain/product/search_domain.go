@@ -0,0 +1,23 @@
+package domain
+
+// ProductSearchFilters narrows a full-text product search. Query is parsed
+// with websearch_to_tsquery, so it accepts "quoted phrases", OR and -exclusions.
+type ProductSearchFilters struct {
+	Query      string   `json:"q"`
+	InCategory string   `json:"in_category"` // Category ID or slug; matches its whole subtree
+	MinPrice   *float64 `json:"min_price"`
+	MaxPrice   *float64 `json:"max_price"`
+}
+
+// ProductSearchHit is a product matched by a search, with its relevance and
+// the matched terms wrapped in <mark> tags.
+type ProductSearchHit struct {
+	Product
+	Rank      float64                `json:"rank"`
+	Highlight ProductSearchHighlight `json:"highlight"`
+}
+
+type ProductSearchHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"` // Matching fragments of the description, empty when it has none
+}
@@ -10,8 +10,8 @@ import (
 
 type Review struct {
 	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();" json:"id"` // Unique identifier for each review
-	ProductID uuid.UUID      `json:"product_id" gorm:"not null"`                      // References the Product table
-	UserID    uuid.UUID      `json:"user_id" gorm:"not null"`                         // References the User table
+	ProductID uuid.UUID      `json:"product_id" gorm:"type:uuid;not null;index"`      // References the Product table
+	UserID    uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`         // References the User table
 	Rating    int            `json:"rating" gorm:"not null"`                          // Rating given by the user
 	Comment   string         `json:"comment"`                                         // Comment left by the user
 	Status    string         `json:"status" gorm:"size:50"`                           // Status of the review
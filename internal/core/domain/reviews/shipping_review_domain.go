@@ -10,8 +10,8 @@ import (
 
 type ShippingReview struct {
 	ID           uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();" json:"id"` // Unique identifier for each shipping review
-	OrderID      uuid.UUID      `json:"order_id" gorm:"not null"`                        // References the Order table
-	UserID       uuid.UUID      `json:"user_id" gorm:"not null"`                         // References the User table
+	OrderID      uuid.UUID      `json:"order_id" gorm:"type:uuid;not null;index"`        // References the Order table
+	UserID       uuid.UUID      `json:"user_id" gorm:"type:uuid;not null;index"`         // References the User table
 	Rating       int            `json:"rating" gorm:"not null"`                          // Rating for the shipping experience
 	Comment      string         `json:"comment"`                                         // Comment about the shipping experience
 	DeliveryTime time.Time      `json:"delivery_time"`                                   // Timestamp when the product was delivered
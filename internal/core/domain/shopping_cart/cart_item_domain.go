@@ -10,9 +10,9 @@ import (
 
 type CartItem struct {
 	ID              uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();" json:"id"` // Unique identifier for each cart item
-	CartID          uuid.UUID      `json:"cart_id" gorm:"not null"`                         // References the Cart table to link the item to a specific cart
-	ProductID       uuid.UUID      `json:"product_id" gorm:"not null"`                      // References the Product table to link the item to a specific product
-	VariantID       uuid.UUID      `json:"variant_id"`                                      // References the ProductVariant table for product variations
+	CartID          uuid.UUID      `json:"cart_id" gorm:"type:uuid;not null;index"`         // References the Cart table to link the item to a specific cart
+	ProductID       uuid.UUID      `json:"product_id" gorm:"type:uuid;not null"`            // References the Product table to link the item to a specific product
+	SKUID           *uuid.UUID     `json:"sku_id" gorm:"type:uuid;index"`                   // References the ProductSKU chosen, nil for products without options
 	Quantity        int            `json:"quantity" gorm:"not null"`                        // Quantity of the product added to the cart
 	UnitPrice       float64        `json:"unit_price" gorm:"not null"`                      // Price per unit of the product at the time of addition to the cart
 	DiscountApplied float64        `json:"discount_applied"`                                // Discount amount applied to this item, if any
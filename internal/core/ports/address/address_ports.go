@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IAddressRepository interface {
+	// ListUserAddresses returns the defaults first, then the newest entries.
+	ListUserAddresses(ctx context.Context, userID uuid.UUID) ([]userDomain.UserAddress, error)
+	GetUserAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error)
+	GetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error)
+	CountUserAddresses(ctx context.Context, userID uuid.UUID) (int64, error)
+	CreateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error
+	UpdateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error
+	DeleteUserAddress(ctx context.Context, userID, addressID uuid.UUID) error
+	// UnsetDefaultUserAddress clears the default flag of the given kind on
+	// every address of the user.
+	UnsetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) error
+}
+
+type IAddressService interface {
+	ListAddresses(ctx context.Context, userID uuid.UUID) ([]userDomain.UserAddress, error)
+	GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error)
+	// GetDefaultAddress returns the default billing or shipping address.
+	GetDefaultAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error)
+	CreateAddress(ctx context.Context, userID uuid.UUID, payload userDomain.UpsertUserAddressDomain) (*userDomain.UserAddress, error)
+	UpdateAddress(ctx context.Context, userID, addressID uuid.UUID, payload userDomain.UpsertUserAddressDomain) (*userDomain.UserAddress, error)
+	DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error
+}
+
+type IAddressHandler interface {
+	HandleListAddresses(c *fiber.Ctx) error
+	HandleGetAddress(c *fiber.Ctx) error
+	HandleCreateAddress(c *fiber.Ctx) error
+	HandleUpdateAddress(c *fiber.Ctx) error
+	HandleDeleteAddress(c *fiber.Ctx) error
+}
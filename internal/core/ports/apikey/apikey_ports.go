@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, payload *authDomain.APIKey) error
+	// GetAPIKeyByID locks the row for update when called inside a transaction.
+	GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*authDomain.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*authDomain.APIKey, error)
+	ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]authDomain.APIKey, error)
+	UpdateAPIKey(ctx context.Context, payload *authDomain.APIKey) error
+	TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time, ip string) error
+}
+
+type IAPIKeyService interface {
+	ListAPIKeys(ctx context.Context, ownerID uuid.UUID) ([]authDomain.APIKey, error)
+	// CreateAPIKey issues a key for ownerID. createdByID is the owner itself, or
+	// the admin acting on a service account.
+	CreateAPIKey(ctx context.Context, ownerID, createdByID uuid.UUID, payload authDomain.CreateAPIKeyDomain) (*authDomain.APIKeySecret, error)
+	// RotateAPIKey replaces the key material; the old key stops working at once.
+	RotateAPIKey(ctx context.Context, ownerID, keyID uuid.UUID) (*authDomain.APIKeySecret, error)
+	RevokeAPIKey(ctx context.Context, ownerID, keyID uuid.UUID) error
+	// Authenticate resolves a full key to the identity it acts as. clientIP is
+	// recorded as the last use and may be empty.
+	Authenticate(ctx context.Context, key, clientIP string) (*authDomain.APIKeyPrincipal, error)
+
+	ListServiceAccounts(ctx context.Context) ([]userDomain.User, error)
+	GetServiceAccount(ctx context.Context, id uuid.UUID) (*userDomain.User, error)
+	CreateServiceAccount(ctx context.Context, createdByID uuid.UUID, payload authDomain.CreateServiceAccountDomain) (*userDomain.User, error)
+}
+
+type IAPIKeyHandler interface {
+	HandleListMyAPIKeys(c *fiber.Ctx) error
+	HandleCreateMyAPIKey(c *fiber.Ctx) error
+	HandleRotateMyAPIKey(c *fiber.Ctx) error
+	HandleRevokeMyAPIKey(c *fiber.Ctx) error
+
+	HandleListServiceAccounts(c *fiber.Ctx) error
+	HandleCreateServiceAccount(c *fiber.Ctx) error
+	HandleListServiceAccountAPIKeys(c *fiber.Ctx) error
+	HandleCreateServiceAccountAPIKey(c *fiber.Ctx) error
+	HandleRotateServiceAccountAPIKey(c *fiber.Ctx) error
+	HandleRevokeServiceAccountAPIKey(c *fiber.Ctx) error
+}
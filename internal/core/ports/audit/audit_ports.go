@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+)
+
+type IChangeLogRepository interface {
+	CreateChangeLog(ctx context.Context, payload *loggingDomain.ChangeLogs) error
+	// ListEntityChangeLogs pages through the entries about one entity, newest
+	// first, with the pagination.PaginationParams[loggingDomain.ChangeLogFilters]
+	// stored in the context by pagination.SetFilters.
+	ListEntityChangeLogs(ctx context.Context, entityType string, entityID uuid.UUID) (pagination.Pagination[[]loggingDomain.ChangeLogs], error)
+}
@@ -0,0 +1,80 @@
+package ports
+
+import (
+	"context"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IRefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, payload *authDomain.RefreshToken) error
+	// GetRefreshTokenByHash locks the row for update when called inside a transaction.
+	GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*authDomain.RefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+}
+
+type ITwoFactorRecoveryCodeRepository interface {
+	CreateRecoveryCodes(ctx context.Context, payload []authDomain.TwoFactorRecoveryCode) error
+	ListUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]authDomain.TwoFactorRecoveryCode, error)
+	MarkRecoveryCodeUsed(ctx context.Context, id uuid.UUID) error
+	DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error
+}
+
+type IAuthService interface {
+	Register(ctx context.Context, payload userDomain.RegisterUserDomain) (*userDomain.User, error)
+	// Login returns a token pair, or a two-factor challenge when the account has
+	// two-factor authentication enabled. clientIP feeds the per-IP lockout and
+	// may be empty.
+	Login(ctx context.Context, payload userDomain.LoginUserDomain, clientIP string) (*authDomain.LoginResult, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*authDomain.TokenPair, error)
+	GetCurrentUser(ctx context.Context, userID uuid.UUID) (*userDomain.User, error)
+	// IssueSession logs in a user who was authenticated by other means, such as
+	// an OAuth provider. Status checks and the two-factor challenge still apply.
+	IssueSession(ctx context.Context, user *userDomain.User) (*authDomain.LoginResult, error)
+
+	EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*authDomain.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) (*authDomain.TwoFactorRecoveryCodes, error)
+	DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error
+	VerifyTwoFactorLogin(ctx context.Context, payload authDomain.TwoFactorLoginDomain) (*authDomain.LoginResult, error)
+
+	ResendEmailVerification(ctx context.Context, email string) error
+	VerifyEmail(ctx context.Context, payload authDomain.EmailCodeDomain) error
+	RequestLoginOTP(ctx context.Context, email string) error
+	LoginWithOTP(ctx context.Context, payload authDomain.EmailCodeDomain) (*authDomain.LoginResult, error)
+
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, payload authDomain.ResetPasswordDomain) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, payload authDomain.ChangePasswordDomain) error
+
+	RequestAccountUnlock(ctx context.Context, email string) error
+	UnlockAccount(ctx context.Context, payload authDomain.EmailCodeDomain) error
+}
+
+type IAuthHandler interface {
+	HandleRegister(c *fiber.Ctx) error
+	HandleLogin(c *fiber.Ctx) error
+	HandleRefreshToken(c *fiber.Ctx) error
+	HandleGetMe(c *fiber.Ctx) error
+
+	HandleEnrollTwoFactor(c *fiber.Ctx) error
+	HandleConfirmTwoFactor(c *fiber.Ctx) error
+	HandleDisableTwoFactor(c *fiber.Ctx) error
+	HandleVerifyTwoFactorLogin(c *fiber.Ctx) error
+
+	HandleResendEmailVerification(c *fiber.Ctx) error
+	HandleVerifyEmail(c *fiber.Ctx) error
+	HandleRequestLoginOTP(c *fiber.Ctx) error
+	HandleLoginWithOTP(c *fiber.Ctx) error
+
+	HandleForgotPassword(c *fiber.Ctx) error
+	HandleResetPassword(c *fiber.Ctx) error
+	HandleChangePassword(c *fiber.Ctx) error
+
+	HandleRequestAccountUnlock(c *fiber.Ctx) error
+	HandleUnlockAccount(c *fiber.Ctx) error
+}
@@ -0,0 +1,49 @@
+package ports
+
+import (
+	"context"
+
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ICartRepository interface {
+	// GetActiveCartForUpdate locks the active cart of the user when called
+	// inside a transaction, so it cannot be checked out twice.
+	GetActiveCartForUpdate(ctx context.Context, userID uuid.UUID) (*cartDomain.Cart, error)
+	ListCartItems(ctx context.Context, cartID uuid.UUID) ([]cartDomain.CartItem, error)
+	UpdateCart(ctx context.Context, payload *cartDomain.Cart) error
+}
+
+type IOrderRepository interface {
+	// CreateOrder inserts the order together with its items, shipping and
+	// billing information.
+	CreateOrder(ctx context.Context, payload *orderDomain.Order) error
+	// GetOrderForUpdate locks the order when called inside a transaction and
+	// loads its items.
+	GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*orderDomain.Order, error)
+	// UpdateOrderStatus saves the status of the order only.
+	UpdateOrderStatus(ctx context.Context, payload *orderDomain.Order) error
+	// UpdateOrderItemWarehouses saves the warehouse of every item only.
+	UpdateOrderItemWarehouses(ctx context.Context, items []orderDomain.OrderItem) error
+}
+
+type ICheckoutService interface {
+	// Checkout places an order from the active cart and reserves its stock,
+	// at the warehouses each item ships from, until the order is paid or the
+	// reservation expires.
+	Checkout(ctx context.Context, userID uuid.UUID, payload orderDomain.CheckoutDomain) (*orderDomain.Order, error)
+	// ConfirmOrder records the payment of a pending order, which sells the
+	// reserved stock.
+	ConfirmOrder(ctx context.Context, actorID, orderID uuid.UUID) (*orderDomain.Order, error)
+	// CancelOrder cancels a pending order of the user and releases its stock.
+	CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*orderDomain.Order, error)
+}
+
+type ICheckoutHandler interface {
+	HandleCheckout(c *fiber.Ctx) error
+	HandleConfirmOrder(c *fiber.Ctx) error
+	HandleCancelOrder(c *fiber.Ctx) error
+}
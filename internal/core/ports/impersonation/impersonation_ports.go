@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IImpersonationRepository interface {
+	CreateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error
+	GetImpersonationSession(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error)
+	// GetImpersonationSessionForUpdate locks the row for update when called inside a transaction.
+	GetImpersonationSessionForUpdate(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error)
+	UpdateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error
+}
+
+type IImpersonationService interface {
+	// StartImpersonation issues a short-lived access token for the user that
+	// also names the actor.
+	StartImpersonation(ctx context.Context, actorID, userID uuid.UUID, payload authDomain.StartImpersonationDomain) (*authDomain.ImpersonationToken, error)
+	// StopImpersonation ends the session; its token is refused from then on.
+	StopImpersonation(ctx context.Context, sessionID, actorID uuid.UUID) error
+	// CheckImpersonation verifies that a token's session is still running and
+	// belongs to the actor named in the token.
+	CheckImpersonation(ctx context.Context, sessionID, actorID uuid.UUID) error
+}
+
+type IImpersonationHandler interface {
+	HandleStartImpersonation(c *fiber.Ctx) error
+	HandleStopImpersonation(c *fiber.Ctx) error
+}
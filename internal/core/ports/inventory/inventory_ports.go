@@ -0,0 +1,133 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IInventoryRepository interface {
+	// EnsureInventory creates the inventory row of the SKU at the warehouse
+	// when there is none yet. A row of the default warehouse starts with the
+	// stock of the SKU not counted elsewhere, any other row with nothing. It
+	// returns the row it created, or nil when there was one already.
+	EnsureInventory(ctx context.Context, skuID, warehouseID uuid.UUID) (*orderDomain.Inventory, error)
+	// ReserveStock adds quantity to the reserved units of the SKU at the
+	// warehouse only if that many are still available, in a single
+	// conditional update. It reports false, changing nothing, when they are
+	// not.
+	ReserveStock(ctx context.Context, key orderDomain.StockKey, quantity int) (bool, error)
+	// ReleaseStock returns reserved units to the available stock.
+	ReleaseStock(ctx context.Context, key orderDomain.StockKey, quantity int) error
+	// CommitStock takes reserved units out of the stock for good, and out of
+	// the stock of the SKU shown in the catalog.
+	CommitStock(ctx context.Context, key orderDomain.StockKey, quantity int) error
+	// AddStock changes the units on hand by delta, and the stock of the SKU
+	// shown in the catalog with them.
+	AddStock(ctx context.Context, key orderDomain.StockKey, delta int) error
+	// ListInventoriesBySKU returns the rows of the SKU at every warehouse.
+	ListInventoriesBySKU(ctx context.Context, skuID uuid.UUID) ([]orderDomain.Inventory, error)
+	// ListInventoriesForUpdate locks the rows of the SKUs at every warehouse
+	// when called inside a transaction, in SKU then warehouse order.
+	ListInventoriesForUpdate(ctx context.Context, skuIDs []uuid.UUID) ([]orderDomain.Inventory, error)
+	// GetInventoryForUpdate locks the inventory row when called inside a
+	// transaction.
+	GetInventoryForUpdate(ctx context.Context, key orderDomain.StockKey) (*orderDomain.Inventory, error)
+
+	CreateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error
+	// ListActiveReservationsForUpdate locks the active reservations of the
+	// reference when called inside a transaction.
+	ListActiveReservationsForUpdate(ctx context.Context, reference uuid.UUID) ([]orderDomain.InventoryReservation, error)
+	// ListExpiredReferences returns the references holding active
+	// reservations that expired before now.
+	ListExpiredReferences(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	UpdateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error
+
+	CreateStockMovement(ctx context.Context, payload *orderDomain.StockMovement) error
+	// ListStockMovements pages through the movements of the SKU, filtered by
+	// the orderDomain.StockMovementFilters of the context.
+	ListStockMovements(ctx context.Context, skuID uuid.UUID) (pagination.Pagination[[]orderDomain.StockMovement], error)
+	// ListInventoryDiscrepancies compares every inventory row with the sums
+	// of its movements and returns the rows that differ.
+	ListInventoryDiscrepancies(ctx context.Context) ([]orderDomain.InventoryDiscrepancy, error)
+}
+
+type IWarehouseRepository interface {
+	CreateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error
+	UpdateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error)
+	GetDefaultWarehouse(ctx context.Context) (*orderDomain.Warehouse, error)
+	// ListWarehouses returns the warehouses by priority, only the active
+	// ones when activeOnly is set.
+	ListWarehouses(ctx context.Context, activeOnly bool) ([]orderDomain.Warehouse, error)
+	// ExistsWarehouseCode reports whether another warehouse than exceptID
+	// uses the code.
+	ExistsWarehouseCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error)
+	// ClearDefaultWarehouse takes the default mark off every warehouse but
+	// exceptID.
+	ClearDefaultWarehouse(ctx context.Context, exceptID uuid.UUID) error
+}
+
+// IAllocationStrategy chooses the warehouses the lines of an order ship
+// from. Every line ships whole from one warehouse.
+type IAllocationStrategy interface {
+	Allocate(request orderDomain.AllocationRequest) ([]orderDomain.Allocation, error)
+}
+
+type IInventoryService interface {
+	// Reserve holds the units of every line for the order until the
+	// reservation TTL runs out, at the warehouses the allocation strategy
+	// chooses. Either every line is reserved or none is.
+	Reserve(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID, payload orderDomain.ReserveDomain) ([]orderDomain.InventoryReservation, error)
+	// Commit turns the active reservations of the order into sales.
+	Commit(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID) error
+	// Release gives the units of the active reservations of the order back
+	// to the available stock.
+	Release(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID) error
+	// ReleaseExpired releases reservations past their expiry and returns how
+	// many orders it released.
+	ReleaseExpired(ctx context.Context) (int, error)
+	// RunWorker releases expired reservations until the context is cancelled.
+	RunWorker(ctx context.Context)
+
+	// SetStock records a count of the units on hand of the SKU as an
+	// adjustment by the difference.
+	SetStock(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.SetStockDomain) error
+	// CheckStock returns the error SetStock would refuse a total of quantity
+	// units of the SKU with, nil when it would accept it.
+	CheckStock(ctx context.Context, skuID uuid.UUID, quantity int) error
+	// RecordMovement records a receipt, return or adjustment entered by staff.
+	RecordMovement(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.RecordStockMovementDomain) (*orderDomain.StockMovement, error)
+	// Transfer moves units of the SKU between warehouses, recorded as a pair
+	// of transfer movements.
+	Transfer(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.TransferStockDomain) ([]orderDomain.StockMovement, error)
+	GetInventory(ctx context.Context, skuID uuid.UUID) (*orderDomain.SKUStock, error)
+	ListMovements(ctx context.Context, skuID uuid.UUID) (pagination.Pagination[[]orderDomain.StockMovement], error)
+	// Reconcile returns the inventory rows that do not match their ledger.
+	Reconcile(ctx context.Context) ([]orderDomain.InventoryDiscrepancy, error)
+}
+
+type IWarehouseService interface {
+	ListWarehouses(ctx context.Context) ([]orderDomain.Warehouse, error)
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error)
+	CreateWarehouse(ctx context.Context, payload orderDomain.UpsertWarehouseDomain) (*orderDomain.Warehouse, error)
+	UpdateWarehouse(ctx context.Context, id uuid.UUID, payload orderDomain.UpsertWarehouseDomain) (*orderDomain.Warehouse, error)
+}
+
+type IInventoryHandler interface {
+	HandleGetInventory(c *fiber.Ctx) error
+	HandleListMovements(c *fiber.Ctx) error
+	HandleRecordMovement(c *fiber.Ctx) error
+	HandleTransferStock(c *fiber.Ctx) error
+}
+
+type IWarehouseHandler interface {
+	HandleListWarehouses(c *fiber.Ctx) error
+	HandleGetWarehouse(c *fiber.Ctx) error
+	HandleCreateWarehouse(c *fiber.Ctx) error
+	HandleUpdateWarehouse(c *fiber.Ctx) error
+}
@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ILoginAttemptCounter stores failed login counters. Use a shared store such as
+// Postgres when several instances of the API run behind a load balancer.
+type ILoginAttemptCounter interface {
+	// GetAttempt returns nil when nothing is recorded for the subject.
+	GetAttempt(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) (*authDomain.LoginAttempt, error)
+	// RegisterFailure atomically counts a failure at now. The count restarts at
+	// one when the previous failure was before windowStart or the lock expired.
+	RegisterFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, now, windowStart time.Time) (*authDomain.LoginAttempt, error)
+	// ReserveFailure counts a failure like RegisterFailure, but only while the
+	// counter is still as seen, nil meaning that nothing was recorded. It
+	// returns nil when another attempt changed the counter in the meantime.
+	ReserveFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, seen *authDomain.LoginAttempt, now, windowStart time.Time) (*authDomain.LoginAttempt, error)
+	// ReleaseFailure takes back one failure counted by ReserveFailure.
+	ReleaseFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error
+	Lock(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, until time.Time) error
+	Reset(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error
+	ListLocked(ctx context.Context, now time.Time) ([]authDomain.LoginAttempt, error)
+}
+
+type ILockoutService interface {
+	// Check refuses an attempt while the account or the IP is locked, or while
+	// the progressive delay after the last failure has not passed.
+	Check(ctx context.Context, account, ip string) error
+	// ReserveAttempt refuses an attempt like Check and otherwise counts it as
+	// a failure before the password is checked, so that concurrent attempts
+	// cannot all get past the limits.
+	ReserveAttempt(ctx context.Context, account, ip string) error
+	// ReleaseAttempt takes back the failure counted by ReserveAttempt once the
+	// password turned out to be right.
+	ReleaseAttempt(ctx context.Context, account, ip string) error
+	// RegisterFailure locks the account and the IP whose reserved attempt
+	// used up their failures, and reports whether it locked the account.
+	RegisterFailure(ctx context.Context, account, ip string) (bool, error)
+	RegisterSuccess(ctx context.Context, account string) error
+	// CheckChallenge refuses a two-factor challenge that has seen too many
+	// wrong codes, and the account like Check does.
+	CheckChallenge(ctx context.Context, account, challenge string) error
+	// RegisterChallengeFailure counts a wrong second factor against the
+	// challenge and the account, and reports whether it locked the account.
+	RegisterChallengeFailure(ctx context.Context, account, challenge string) (bool, error)
+
+	ListLocks(ctx context.Context) ([]authDomain.LoginAttempt, error)
+	ClearLock(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error
+}
+
+type ILockoutHandler interface {
+	HandleListLocks(c *fiber.Ctx) error
+	HandleClearLock(c *fiber.Ctx) error
+}
@@ -0,0 +1,67 @@
+package ports
+
+import (
+	"context"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IOAuthProviderRepository interface {
+	ListProviders(ctx context.Context) ([]authDomain.OAuthProvider, error)
+	GetProviderByID(ctx context.Context, id uuid.UUID) (*authDomain.OAuthProvider, error)
+	GetProviderByName(ctx context.Context, name string) (*authDomain.OAuthProvider, error)
+	CreateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error
+	UpdateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error
+}
+
+type IOAuthRepository interface {
+	CreateState(ctx context.Context, payload *authDomain.OAuthState) error
+	// GetStateByHash locks the row for update when called inside a transaction.
+	GetStateByHash(ctx context.Context, stateHash string) (*authDomain.OAuthState, error)
+	MarkStateUsed(ctx context.Context, id uuid.UUID) error
+
+	GetIdentity(ctx context.Context, providerID uuid.UUID, subject string) (*authDomain.OAuthIdentity, error)
+	GetUserIdentity(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthIdentity, error)
+	CreateIdentity(ctx context.Context, payload *authDomain.OAuthIdentity) error
+
+	// UpsertAccessToken and UpsertRefreshToken keep one token of each kind per
+	// user and provider.
+	UpsertAccessToken(ctx context.Context, payload *authDomain.OAuthAccessToken) error
+	UpsertRefreshToken(ctx context.Context, payload *authDomain.OAuthRefreshToken) error
+	GetRefreshToken(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthRefreshToken, error)
+}
+
+// IOAuthClient talks to the provider endpoints configured on an OAuthProvider.
+type IOAuthClient interface {
+	ExchangeCode(ctx context.Context, provider *authDomain.OAuthProvider, clientSecret, code, codeVerifier string) (*authDomain.OAuthTokenResponse, error)
+	RefreshToken(ctx context.Context, provider *authDomain.OAuthProvider, clientSecret, refreshToken string) (*authDomain.OAuthTokenResponse, error)
+	UserInfo(ctx context.Context, provider *authDomain.OAuthProvider, accessToken string) (*authDomain.OAuthUserInfo, error)
+}
+
+type IOAuthService interface {
+	ListEnabledProviders(ctx context.Context) ([]string, error)
+	// Authorize starts a login; with a non-nil linkUserID it links the provider
+	// to that user instead.
+	Authorize(ctx context.Context, providerName string, linkUserID *uuid.UUID) (*authDomain.OAuthAuthorization, error)
+	Callback(ctx context.Context, providerName string, payload authDomain.OAuthCallbackDomain) (*authDomain.LoginResult, error)
+	// RefreshProviderToken renews the stored provider access token of the user.
+	RefreshProviderToken(ctx context.Context, userID uuid.UUID, providerName string) (*authDomain.OAuthAccessToken, error)
+
+	ListProviders(ctx context.Context) ([]authDomain.OAuthProvider, error)
+	CreateProvider(ctx context.Context, payload authDomain.UpsertOAuthProviderDomain) (*authDomain.OAuthProvider, error)
+	UpdateProvider(ctx context.Context, id uuid.UUID, payload authDomain.UpsertOAuthProviderDomain) (*authDomain.OAuthProvider, error)
+}
+
+type IOAuthHandler interface {
+	HandleListEnabledProviders(c *fiber.Ctx) error
+	HandleAuthorize(c *fiber.Ctx) error
+	HandleLink(c *fiber.Ctx) error
+	HandleCallback(c *fiber.Ctx) error
+	HandleRefreshProviderToken(c *fiber.Ctx) error
+
+	HandleListProviders(c *fiber.Ctx) error
+	HandleCreateProvider(c *fiber.Ctx) error
+	HandleUpdateProvider(c *fiber.Ctx) error
+}
@@ -0,0 +1,32 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	"github.com/google/uuid"
+)
+
+type IOTPRepository interface {
+	CreateOTP(ctx context.Context, payload *messageDomain.OTP) error
+	CountOTPsSince(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, since time.Time) (int64, error)
+	// GetLatestUnusedOTP locks the row for update when called inside a transaction.
+	GetLatestUnusedOTP(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) (*messageDomain.OTP, error)
+	InvalidateUnusedOTPs(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) error
+	IncrementOTPAttempts(ctx context.Context, id uuid.UUID) error
+	MarkOTPUsed(ctx context.Context, id uuid.UUID) error
+}
+
+// IOTPSender delivers a code to the user, e.g. by email or SMS.
+type IOTPSender interface {
+	Send(ctx context.Context, message messageDomain.OTPMessage) error
+}
+
+type IOTPService interface {
+	// Issue creates a code for the purpose, supersedes any earlier unused code
+	// for it and sends the code. It returns when the code expires.
+	Issue(ctx context.Context, payload messageDomain.IssueOTPDomain) (time.Time, error)
+	// Verify consumes the current code for the purpose if it matches.
+	Verify(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, code string) error
+}
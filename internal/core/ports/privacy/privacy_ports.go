@@ -0,0 +1,59 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IDataRequestRepository interface {
+	CreateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error
+	// GetDataRequestForUpdate locks the row for update when called inside a transaction.
+	GetDataRequestForUpdate(ctx context.Context, id uuid.UUID) (*privacyDomain.DataRequest, error)
+	ListUserDataRequests(ctx context.Context, userID uuid.UUID) ([]privacyDomain.DataRequest, error)
+	// ListDataRequestsByStatus returns the oldest requests first.
+	ListDataRequestsByStatus(ctx context.Context, requestType privacyDomain.DATA_REQUEST_TYPE, status privacyDomain.DATA_REQUEST_STATUS, limit int) ([]privacyDomain.DataRequest, error)
+	// ListExpiredExports returns completed exports whose archive expired before the given time.
+	ListExpiredExports(ctx context.Context, before time.Time, limit int) ([]privacyDomain.DataRequest, error)
+	// HasOpenDataRequest reports whether a pending or processing request of the type exists.
+	HasOpenDataRequest(ctx context.Context, userID uuid.UUID, requestType privacyDomain.DATA_REQUEST_TYPE) (bool, error)
+	UpdateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error
+}
+
+type IUserDataRepository interface {
+	// CollectUserData gathers every record tied to the user.
+	CollectUserData(ctx context.Context, userID uuid.UUID) (*privacyDomain.UserDataExport, error)
+	// EraseUserData saves the anonymised user and removes or scrubs the
+	// personal data held in other tables. Orders, order items, billing and
+	// payment records are kept for accounting.
+	EraseUserData(ctx context.Context, anonymised *userDomain.User) (*privacyDomain.ErasureSummary, error)
+}
+
+type IPrivacyService interface {
+	// RequestExport queues an export job; the archive is built in the background.
+	RequestExport(ctx context.Context, userID, actorID uuid.UUID, payload privacyDomain.RequestDataExportDomain) (*privacyDomain.DataRequest, error)
+	ListDataRequests(ctx context.Context, userID uuid.UUID) ([]privacyDomain.DataRequest, error)
+	DownloadExport(ctx context.Context, userID, requestID, actorID uuid.UUID) (*privacyDomain.ExportFile, error)
+	// EraseUser anonymises the personal data of the user right away.
+	EraseUser(ctx context.Context, userID, actorID uuid.UUID, payload privacyDomain.RequestErasureDomain) (*privacyDomain.ErasureSummary, error)
+	// ProcessExport builds the archive of one export request.
+	ProcessExport(ctx context.Context, requestID uuid.UUID) error
+	// RunWorker processes queued exports and deletes expired archives until
+	// the context is cancelled.
+	RunWorker(ctx context.Context)
+}
+
+type IPrivacyHandler interface {
+	HandleRequestMyExport(c *fiber.Ctx) error
+	HandleListMyDataRequests(c *fiber.Ctx) error
+	HandleDownloadMyExport(c *fiber.Ctx) error
+	HandleEraseMe(c *fiber.Ctx) error
+	HandleRequestUserExport(c *fiber.Ctx) error
+	HandleListUserDataRequests(c *fiber.Ctx) error
+	HandleDownloadUserExport(c *fiber.Ctx) error
+	HandleEraseUser(c *fiber.Ctx) error
+}
@@ -0,0 +1,67 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ICatalogImportRepository interface {
+	CreateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error
+	GetCatalogImportJob(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error)
+	// GetCatalogImportJobForUpdate locks the row for update when called inside a transaction.
+	GetCatalogImportJobForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error)
+	// ListCatalogImportJobs pages through the jobs with the
+	// pagination.PaginationParams[productDomain.CatalogImportFilters] stored in ctx.
+	ListCatalogImportJobs(ctx context.Context) (pagination.Pagination[[]productDomain.CatalogImportJob], error)
+	// ListCatalogImportJobsByStatus returns the oldest jobs first.
+	ListCatalogImportJobsByStatus(ctx context.Context, status productDomain.CATALOG_IMPORT_STATUS, limit int) ([]productDomain.CatalogImportJob, error)
+	// ListStaleCatalogImportJobs returns the processing jobs last updated
+	// before the given time, the least recently updated first.
+	ListStaleCatalogImportJobs(ctx context.Context, before time.Time, limit int) ([]productDomain.CatalogImportJob, error)
+	UpdateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error
+
+	// GetProductBySKUForUpdate locks the product with the SKU when called
+	// inside a transaction.
+	GetProductBySKUForUpdate(ctx context.Context, sku string) (*productDomain.Product, error)
+	// GetSKUByCodeForUpdate returns the SKU with its option values, locked
+	// when called inside a transaction.
+	GetSKUByCodeForUpdate(ctx context.Context, code string) (*productDomain.ProductSKU, error)
+	// EachCatalogBatch reads the catalog batchSize products at a time and
+	// hands fn the rows of each batch: one per SKU, or a single row without
+	// SKU for a product that has none. It stops at the first error of fn.
+	EachCatalogBatch(ctx context.Context, batchSize int, fn func(rows []productDomain.CatalogRow) error) error
+}
+
+// ICatalogImportService loads the catalog from spreadsheets and writes it
+// back out in the same formats.
+type ICatalogImportService interface {
+	// StartImport stores the file and queues it; the rows are imported in
+	// the background.
+	StartImport(ctx context.Context, actorID uuid.UUID, payload productDomain.StartCatalogImportDomain) (*productDomain.CatalogImportJob, error)
+	ListImports(ctx context.Context) (pagination.Pagination[[]productDomain.CatalogImportJob], error)
+	GetImport(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error)
+	// DownloadImportReport returns the rows of a finished job that were not
+	// imported, with the reason, in the format of the uploaded file.
+	DownloadImportReport(ctx context.Context, id uuid.UUID) (*productDomain.CatalogFile, error)
+	// ProcessImport validates every row of the job and upserts the valid
+	// ones by SKU, a chunk of rows per transaction.
+	ProcessImport(ctx context.Context, id uuid.UUID) error
+	// RunWorker processes queued imports until the context is cancelled.
+	RunWorker(ctx context.Context)
+	// ExportCatalog streams the whole catalog to w, reading it in batches.
+	ExportCatalog(ctx context.Context, format productDomain.CATALOG_FORMAT, w io.Writer) error
+}
+
+type ICatalogImportHandler interface {
+	HandleStartImport(c *fiber.Ctx) error
+	HandleListImports(c *fiber.Ctx) error
+	HandleGetImport(c *fiber.Ctx) error
+	HandleDownloadImportReport(c *fiber.Ctx) error
+	HandleExportCatalog(c *fiber.Ctx) error
+}
@@ -0,0 +1,68 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ICategoryRepository interface {
+	// ListCategories pages through categories with the
+	// pagination.PaginationParams[productDomain.CategoryFilters] stored in ctx.
+	ListCategories(ctx context.Context) (pagination.Pagination[[]productDomain.Category], error)
+	// ListAllCategories returns every category ordered by position, then name.
+	ListAllCategories(ctx context.Context) ([]productDomain.Category, error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error)
+	// GetCategoryForUpdate locks the row for update when called inside a transaction.
+	GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*productDomain.Category, error)
+	ExistsCategorySlug(ctx context.Context, slug string, exceptID *uuid.UUID) (bool, error)
+	// CountCategoryChildren counts the direct children of parentID, or the
+	// root categories when parentID is nil.
+	CountCategoryChildren(ctx context.Context, parentID *uuid.UUID) (int64, error)
+	// ListCategorySubtreeIDs returns the category and all of its descendants.
+	ListCategorySubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	// ListCategoryAncestors returns the path from the root down to the
+	// category, the category included.
+	ListCategoryAncestors(ctx context.Context, id uuid.UUID) ([]productDomain.Category, error)
+	// LockCategoryTree serialises structural changes to the tree until the
+	// surrounding transaction ends.
+	LockCategoryTree(ctx context.Context) error
+	CreateCategory(ctx context.Context, payload *productDomain.Category) error
+	UpdateCategory(ctx context.Context, payload *productDomain.Category) error
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
+
+type ICategoryService interface {
+	ListCategories(ctx context.Context) (pagination.Pagination[[]productDomain.Category], error)
+	GetCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*productDomain.Category, error)
+	// GetCategoryTree returns the nested tree below rootID, or the whole
+	// forest when rootID is nil.
+	GetCategoryTree(ctx context.Context, rootID *uuid.UUID) ([]productDomain.Category, error)
+	// GetBreadcrumbs returns the path from the root down to the category.
+	GetBreadcrumbs(ctx context.Context, id uuid.UUID) ([]productDomain.Category, error)
+	CreateCategory(ctx context.Context, actorID uuid.UUID, payload productDomain.CreateCategoryDomain) (*productDomain.Category, error)
+	UpdateCategory(ctx context.Context, id uuid.UUID, payload productDomain.UpsertCategoryDomain) (*productDomain.Category, error)
+	// MoveCategory reparents the category with its subtree. Moving a category
+	// below itself or one of its descendants is refused.
+	MoveCategory(ctx context.Context, id uuid.UUID, payload productDomain.MoveCategoryDomain) (*productDomain.Category, error)
+	// DeleteCategory refuses categories that still hold products or children.
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+}
+
+type ICategoryHandler interface {
+	HandleListCategories(c *fiber.Ctx) error
+	HandleGetCategory(c *fiber.Ctx) error
+	HandleGetCategoryBySlug(c *fiber.Ctx) error
+	HandleGetCategoryTree(c *fiber.Ctx) error
+	HandleGetCategorySubtree(c *fiber.Ctx) error
+	HandleGetBreadcrumbs(c *fiber.Ctx) error
+	HandleCreateCategory(c *fiber.Ctx) error
+	HandleUpdateCategory(c *fiber.Ctx) error
+	HandleMoveCategory(c *fiber.Ctx) error
+	HandleDeleteCategory(c *fiber.Ctx) error
+}
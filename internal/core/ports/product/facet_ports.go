@@ -0,0 +1,38 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IProductFacetRepository interface {
+	// CountProductFacets counts the buckets of the facets over the products
+	// matching the pagination.PaginationParams[productDomain.ProductFilters]
+	// stored in ctx, in a single query. Each facet ignores its own filter so
+	// that its other buckets keep their counts once one is picked.
+	CountProductFacets(ctx context.Context, facets []productDomain.CategoryFacet) ([]productDomain.FacetCount, error)
+	// ListCategoryFacets returns the facets defined on any of the categories,
+	// ordered by position.
+	ListCategoryFacets(ctx context.Context, categoryIDs []uuid.UUID) ([]productDomain.CategoryFacet, error)
+	// ReplaceCategoryFacets deletes the facets of the category and creates
+	// the given ones.
+	ReplaceCategoryFacets(ctx context.Context, categoryID uuid.UUID, facets []productDomain.CategoryFacet) error
+}
+
+type IProductFacetService interface {
+	// ListFacets returns the facets of the product listing described by the
+	// filters stored in ctx, using the definitions of the listed category.
+	ListFacets(ctx context.Context) ([]productDomain.Facet, error)
+	// GetCategoryFacets returns the facet definitions in effect for the
+	// category, inherited ones included.
+	GetCategoryFacets(ctx context.Context, categoryID uuid.UUID) (*productDomain.CategoryFacetsDomain, error)
+	SetCategoryFacets(ctx context.Context, categoryID uuid.UUID, payload productDomain.SetCategoryFacetsDomain) (*productDomain.CategoryFacetsDomain, error)
+}
+
+type IProductFacetHandler interface {
+	HandleGetCategoryFacets(c *fiber.Ctx) error
+	HandleSetCategoryFacets(c *fiber.Ctx) error
+}
@@ -0,0 +1,41 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// IProductImageService manages product images. Writes follow the ownership
+// rules of IProductService.
+type IProductImageService interface {
+	ListImages(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductImage, error)
+	// AddImage attaches an image hosted elsewhere; the first image of a
+	// product becomes its primary image.
+	AddImage(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.AddProductImageDomain) (*productDomain.ProductImage, error)
+	// UploadImage checks the bytes are an image of the declared type, strips
+	// its metadata, stores it with its thumbnails and WebP copies and appends
+	// it to the product's images.
+	UploadImage(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UploadProductImageDomain) (*productDomain.ProductImage, error)
+	// UploadImageBase64 is UploadImage for an image sent as a data URI.
+	UploadImageBase64(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UploadProductImageBase64Domain) (*productDomain.ProductImage, error)
+	SetPrimaryImage(ctx context.Context, actorID, productID, imageID uuid.UUID) (*productDomain.ProductImage, error)
+	// ReorderImages puts the images in the given order, which must list each
+	// of them exactly once.
+	ReorderImages(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.ReorderProductImagesDomain) ([]productDomain.ProductImage, error)
+	// DeleteImage removes the image and its stored files; when it was the
+	// primary image the next one in line takes its place.
+	DeleteImage(ctx context.Context, actorID, productID, imageID uuid.UUID) error
+}
+
+type IProductImageHandler interface {
+	HandleListImages(c *fiber.Ctx) error
+	HandleAddImage(c *fiber.Ctx) error
+	HandleUploadImage(c *fiber.Ctx) error
+	HandleUploadImageBase64(c *fiber.Ctx) error
+	HandleSetPrimaryImage(c *fiber.Ctx) error
+	HandleReorderImages(c *fiber.Ctx) error
+	HandleDeleteImage(c *fiber.Ctx) error
+}
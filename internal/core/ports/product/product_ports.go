@@ -0,0 +1,61 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IProductRepository interface {
+	// ListProducts pages through products with the
+	// pagination.PaginationParams[productDomain.ProductFilters] stored in ctx.
+	// Each row carries its options, SKUs and primary image.
+	ListProducts(ctx context.Context) (pagination.Pagination[[]productDomain.Product], error)
+	// GetProduct returns the product with its options, SKUs and primary image.
+	GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error)
+	// GetProductForUpdate locks the row for update when called inside a transaction.
+	GetProductForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Product, error)
+	ExistsProductSKU(ctx context.Context, sku string, exceptID *uuid.UUID) (bool, error)
+	CountCategoryProducts(ctx context.Context, categoryID uuid.UUID) (int64, error)
+	CreateProduct(ctx context.Context, payload *productDomain.Product) error
+	UpdateProduct(ctx context.Context, payload *productDomain.Product) error
+	// DeleteProduct soft deletes the product along with its options, SKUs and
+	// images.
+	DeleteProduct(ctx context.Context, id uuid.UUID) error
+
+	// ListProductImages returns the images with their renditions in position
+	// order.
+	ListProductImages(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductImage, error)
+	GetProductImage(ctx context.Context, productID, imageID uuid.UUID) (*productDomain.ProductImage, error)
+	// CreateProductImage creates the image together with its Renditions.
+	CreateProductImage(ctx context.Context, payload *productDomain.ProductImage) error
+	UpdateProductImage(ctx context.Context, payload *productDomain.ProductImage) error
+	// DeleteProductImage soft deletes the image along with its renditions.
+	DeleteProductImage(ctx context.Context, productID, imageID uuid.UUID) error
+	// UnsetPrimaryProductImage clears IsPrimary on every image of the product.
+	UnsetPrimaryProductImage(ctx context.Context, productID uuid.UUID) error
+	// SetProductImagePositions numbers the images in the order of imageIDs.
+	SetProductImagePositions(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error
+}
+
+// IProductService manages the catalog. actorID is the authenticated user:
+// writes are limited to the actor's own products unless the actor holds
+// PERMISSION_MANAGE_CATALOG.
+type IProductService interface {
+	ListProducts(ctx context.Context) (pagination.Pagination[[]productDomain.Product], error)
+	GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error)
+	CreateProduct(ctx context.Context, actorID uuid.UUID, payload productDomain.UpsertProductDomain) (*productDomain.Product, error)
+	UpdateProduct(ctx context.Context, actorID, id uuid.UUID, payload productDomain.UpsertProductDomain) (*productDomain.Product, error)
+	DeleteProduct(ctx context.Context, actorID, id uuid.UUID) error
+}
+
+type IProductHandler interface {
+	HandleListProducts(c *fiber.Ctx) error
+	HandleGetProduct(c *fiber.Ctx) error
+	HandleCreateProduct(c *fiber.Ctx) error
+	HandleUpdateProduct(c *fiber.Ctx) error
+	HandleDeleteProduct(c *fiber.Ctx) error
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+)
+
+type IProductSearchRepository interface {
+	// SearchProducts pages through the products matching the
+	// pagination.PaginationParams[productDomain.ProductSearchFilters] stored
+	// in ctx, most relevant first. Names within a few typos of the query
+	// match as well. Each row carries its options, SKUs and primary image.
+	SearchProducts(ctx context.Context) (pagination.Pagination[[]productDomain.ProductSearchHit], error)
+}
+
+type IProductSearchService interface {
+	SearchProducts(ctx context.Context) (pagination.Pagination[[]productDomain.ProductSearchHit], error)
+}
+
+type IProductSearchHandler interface {
+	HandleSearchProducts(c *fiber.Ctx) error
+}
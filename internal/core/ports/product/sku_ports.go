@@ -0,0 +1,73 @@
+package ports
+
+import (
+	"context"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IProductSKURepository interface {
+	// ListProductOptions returns the options of the product with their values,
+	// both in position order.
+	ListProductOptions(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductOption, error)
+	GetProductOption(ctx context.Context, productID, optionID uuid.UUID) (*productDomain.ProductOption, error)
+	// CreateProductOption creates the option together with its Values.
+	CreateProductOption(ctx context.Context, payload *productDomain.ProductOption) error
+	UpdateProductOption(ctx context.Context, payload *productDomain.ProductOption) error
+	// DeleteProductOption soft deletes the option along with its values.
+	DeleteProductOption(ctx context.Context, productID, optionID uuid.UUID) error
+	CreateProductOptionValue(ctx context.Context, payload *productDomain.ProductOptionValue) error
+	DeleteProductOptionValue(ctx context.Context, optionID, valueID uuid.UUID) error
+
+	CountProductSKUs(ctx context.Context, productID uuid.UUID) (int64, error)
+	// CountSKUsWithOptionValue counts the live SKUs that use the value.
+	CountSKUsWithOptionValue(ctx context.Context, valueID uuid.UUID) (int64, error)
+	// ListProductSKUs returns the SKUs of the product with their option values.
+	ListProductSKUs(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductSKU, error)
+	GetProductSKU(ctx context.Context, productID, skuID uuid.UUID) (*productDomain.ProductSKU, error)
+	ExistsSKUCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error)
+	// CreateProductSKU creates the SKU and links its OptionValues.
+	CreateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error
+	// UpdateProductSKU saves the SKU's own columns; the combination is left as is.
+	UpdateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error
+	DeleteProductSKU(ctx context.Context, productID, skuID uuid.UUID) error
+}
+
+// IProductSKUService manages the option matrix of products. Writes follow the
+// ownership rules of IProductService.
+type IProductSKUService interface {
+	ListOptions(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductOption, error)
+	// CreateOption is refused once the product has SKUs, as they would lack
+	// a value for the new option.
+	CreateOption(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.CreateProductOptionDomain) (*productDomain.ProductOption, error)
+	UpdateOption(ctx context.Context, actorID, productID, optionID uuid.UUID, payload productDomain.UpdateProductOptionDomain) (*productDomain.ProductOption, error)
+	// DeleteOption is refused once the product has SKUs.
+	DeleteOption(ctx context.Context, actorID, productID, optionID uuid.UUID) error
+	AddOptionValue(ctx context.Context, actorID, productID, optionID uuid.UUID, payload productDomain.AddProductOptionValueDomain) (*productDomain.ProductOptionValue, error)
+	// DeleteOptionValue is refused while a SKU uses the value.
+	DeleteOptionValue(ctx context.Context, actorID, productID, optionID, valueID uuid.UUID) error
+
+	ListSKUs(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductSKU, error)
+	CreateSKU(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UpsertProductSKUDomain) (*productDomain.ProductSKU, error)
+	UpdateSKU(ctx context.Context, actorID, productID, skuID uuid.UUID, payload productDomain.UpsertProductSKUDomain) (*productDomain.ProductSKU, error)
+	DeleteSKU(ctx context.Context, actorID, productID, skuID uuid.UUID) error
+	// GenerateSKUs creates a SKU for every combination of the chosen option
+	// values that the product does not have yet, and returns the new ones.
+	GenerateSKUs(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.GenerateProductSKUsDomain) ([]productDomain.ProductSKU, error)
+}
+
+type IProductSKUHandler interface {
+	HandleListOptions(c *fiber.Ctx) error
+	HandleCreateOption(c *fiber.Ctx) error
+	HandleUpdateOption(c *fiber.Ctx) error
+	HandleDeleteOption(c *fiber.Ctx) error
+	HandleAddOptionValue(c *fiber.Ctx) error
+	HandleDeleteOptionValue(c *fiber.Ctx) error
+	HandleListSKUs(c *fiber.Ctx) error
+	HandleCreateSKU(c *fiber.Ctx) error
+	HandleUpdateSKU(c *fiber.Ctx) error
+	HandleDeleteSKU(c *fiber.Ctx) error
+	HandleGenerateSKUs(c *fiber.Ctx) error
+}
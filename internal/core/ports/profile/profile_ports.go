@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IProfileRepository interface {
+	GetProfileByUserID(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error)
+	// GetProfileByUserIDForUpdate locks the row for update when called inside a transaction.
+	GetProfileByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error)
+	CreateProfile(ctx context.Context, payload *userDomain.UserProfile) error
+	UpdateProfile(ctx context.Context, payload *userDomain.UserProfile) error
+}
+
+type IProfileService interface {
+	// GetProfile returns an empty profile when the user has not saved one yet.
+	GetProfile(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error)
+	// UpdateProfile applies a JSON Merge Patch (RFC 7396) to the preferences,
+	// social_media_links and date_of_birth of the profile.
+	UpdateProfile(ctx context.Context, userID uuid.UUID, patch []byte) (*userDomain.UserProfile, error)
+	// UploadAvatar stores a base64 data URI image as the profile picture.
+	UploadAvatar(ctx context.Context, userID uuid.UUID, dataURI string) (*userDomain.UserProfile, error)
+	DeleteAvatar(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error)
+}
+
+type IProfileHandler interface {
+	HandleGetProfile(c *fiber.Ctx) error
+	HandleUpdateProfile(c *fiber.Ctx) error
+	HandleUploadAvatar(c *fiber.Ctx) error
+	HandleDeleteAvatar(c *fiber.Ctx) error
+}
@@ -0,0 +1,52 @@
+package ports
+
+import (
+	"context"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IRoleRepository interface {
+	ListRoles(ctx context.Context) ([]userDomain.UserRole, error)
+	GetRoleByID(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error)
+	GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error)
+	CreateRole(ctx context.Context, payload *userDomain.UserRole) error
+	UpdateRole(ctx context.Context, payload *userDomain.UserRole) error
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	CountUsersWithRole(ctx context.Context, roleID uuid.UUID) (int64, error)
+
+	ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error)
+	GetPermissionByID(ctx context.Context, id uuid.UUID) (*userDomain.UserPermission, error)
+	CreatePermission(ctx context.Context, payload *userDomain.UserPermission) error
+	DeletePermission(ctx context.Context, id uuid.UUID) error
+}
+
+type IRoleService interface {
+	ListRoles(ctx context.Context) ([]userDomain.UserRole, error)
+	GetRole(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error)
+	CreateRole(ctx context.Context, payload userDomain.UpsertRoleDomain) (*userDomain.UserRole, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, payload userDomain.UpsertRoleDomain) (*userDomain.UserRole, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+
+	ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error)
+	GrantPermission(ctx context.Context, roleID uuid.UUID, payload userDomain.GrantPermissionDomain) (*userDomain.UserPermission, error)
+	RevokePermission(ctx context.Context, roleID, permissionID uuid.UUID) error
+
+	// HasPermission reports whether the user's role holds an effective grant of the permission.
+	HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error)
+	// InvalidateRole drops the cached permissions of a role.
+	InvalidateRole(roleID uuid.UUID)
+}
+
+type IRoleHandler interface {
+	HandleListRoles(c *fiber.Ctx) error
+	HandleGetRole(c *fiber.Ctx) error
+	HandleCreateRole(c *fiber.Ctx) error
+	HandleUpdateRole(c *fiber.Ctx) error
+	HandleDeleteRole(c *fiber.Ctx) error
+	HandleListRolePermissions(c *fiber.Ctx) error
+	HandleGrantPermission(c *fiber.Ctx) error
+	HandleRevokePermission(c *fiber.Ctx) error
+}
@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+// IFileStorage keeps uploaded files such as avatars and product images. Keys
+// are slash separated relative paths, e.g. "avatars/<user id>/<name>.png".
+type IFileStorage interface {
+	// Put stores the data under key, replacing any existing object, and
+	// returns the URL clients fetch it from.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Get reads the object back, for files that are not served publicly.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the object; a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+}
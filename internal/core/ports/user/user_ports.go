@@ -0,0 +1,56 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type IUserRepository interface {
+	GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error)
+	// GetUserByIDForUpdate locks the row for update when called inside a transaction.
+	GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error)
+	GetUserByUsernameOrEmail(ctx context.Context, identifier string) (*userDomain.User, error)
+	ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error)
+	ListUsersByRole(ctx context.Context, roleID uuid.UUID) ([]userDomain.User, error)
+	// ListUsers pages through users with the pagination.PaginationParams[userDomain.UserFilters]
+	// stored in the context by pagination.SetFilters.
+	ListUsers(ctx context.Context) (pagination.Pagination[[]userDomain.User], error)
+	CreateUser(ctx context.Context, payload *userDomain.User) error
+	UpdateUser(ctx context.Context, payload *userDomain.User) error
+	// UpdateLastLogin writes only the last_login column, so it cannot undo
+	// changes made to the user since it was read.
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkEmailVerified writes only the email_verified column.
+	MarkEmailVerified(ctx context.Context, id uuid.UUID) error
+}
+
+// IUserAdminService backs the admin user console. Every change takes the ID
+// of the acting administrator and is written to the change log.
+type IUserAdminService interface {
+	ListUsers(ctx context.Context) (pagination.Pagination[[]userDomain.User], error)
+	GetUser(ctx context.Context, id uuid.UUID) (*userDomain.User, error)
+	// ChangeStatus suspends (inactive), bans or reactivates a user. Leaving
+	// the active status also ends the sessions of the user.
+	ChangeStatus(ctx context.Context, id, actorID uuid.UUID, payload userDomain.ChangeUserStatusDomain) (*userDomain.User, error)
+	AssignRole(ctx context.Context, id, actorID uuid.UUID, payload userDomain.AssignUserRoleDomain) (*userDomain.User, error)
+	// ForceLogout revokes every refresh token of the user. Access tokens
+	// already issued stay valid until they expire.
+	ForceLogout(ctx context.Context, id, actorID uuid.UUID, payload userDomain.ForceLogoutDomain) error
+	// ListUserHistory pages through the change log entries about the user.
+	ListUserHistory(ctx context.Context, id uuid.UUID) (pagination.Pagination[[]loggingDomain.ChangeLogs], error)
+}
+
+type IUserAdminHandler interface {
+	HandleListUsers(c *fiber.Ctx) error
+	HandleGetUser(c *fiber.Ctx) error
+	HandleChangeUserStatus(c *fiber.Ctx) error
+	HandleAssignUserRole(c *fiber.Ctx) error
+	HandleForceLogout(c *fiber.Ctx) error
+	HandleListUserHistory(c *fiber.Ctx) error
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/postal"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAddressNotFound     = errors.New("address not found")
+	ErrNoDefaultAddress    = errors.New("no default address, add an address or choose one")
+	ErrAddressLimitReached = errors.New("address book is full")
+	ErrUnknownAddressKind  = errors.New("address kind must be billing or shipping")
+)
+
+// AddressServiceConfig holds the tunables of AddressServiceImpl.
+type AddressServiceConfig struct {
+	MaxAddressesPerUser int64
+}
+
+var DefaultAddressServiceConfig = AddressServiceConfig{
+	MaxAddressesPerUser: 20,
+}
+
+type AddressServiceImpl struct {
+	addressRepo ports.IAddressRepository
+	transactor  transactors.IDatabaseTransactor
+	config      AddressServiceConfig
+}
+
+func NewAddressService(
+	addressRepo ports.IAddressRepository,
+	transactor transactors.IDatabaseTransactor,
+	config AddressServiceConfig,
+) ports.IAddressService {
+	return &AddressServiceImpl{
+		addressRepo: addressRepo,
+		transactor:  transactor,
+		config:      config,
+	}
+}
+
+// ListAddresses implements ports.IAddressService.
+func (s *AddressServiceImpl) ListAddresses(ctx context.Context, userID uuid.UUID) ([]userDomain.UserAddress, error) {
+	return s.addressRepo.ListUserAddresses(ctx, userID)
+}
+
+// GetAddress implements ports.IAddressService.
+func (s *AddressServiceImpl) GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error) {
+	address, err := s.addressRepo.GetUserAddress(ctx, userID, addressID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAddressNotFound
+	}
+	return address, err
+}
+
+// GetDefaultAddress implements ports.IAddressService.
+func (s *AddressServiceImpl) GetDefaultAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error) {
+	if kind != userDomain.ADDRESS_KIND_BILLING && kind != userDomain.ADDRESS_KIND_SHIPPING {
+		return nil, ErrUnknownAddressKind
+	}
+	address, err := s.addressRepo.GetDefaultUserAddress(ctx, userID, kind)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoDefaultAddress
+	}
+	return address, err
+}
+
+// CreateAddress implements ports.IAddressService.
+//
+// The first address of a user becomes the default for both billing and
+// shipping.
+func (s *AddressServiceImpl) CreateAddress(ctx context.Context, userID uuid.UUID, payload userDomain.UpsertUserAddressDomain) (*userDomain.UserAddress, error) {
+	postalAddress, err := normalizeAddress(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	address := &userDomain.UserAddress{
+		UserID:            userID,
+		Label:             strings.TrimSpace(payload.Label),
+		PostalAddress:     postalAddress,
+		IsDefaultBilling:  payload.IsDefaultBilling,
+		IsDefaultShipping: payload.IsDefaultShipping,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		count, err := s.addressRepo.CountUserAddresses(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count >= s.config.MaxAddressesPerUser {
+			return ErrAddressLimitReached
+		}
+		if count == 0 {
+			address.IsDefaultBilling = true
+			address.IsDefaultShipping = true
+		}
+		if err := s.unsetDefaults(ctx, userID, address); err != nil {
+			return err
+		}
+		return s.addressRepo.CreateUserAddress(ctx, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// UpdateAddress implements ports.IAddressService.
+//
+// Clearing a default flag is ignored: a default is moved by marking another
+// address, so the user never ends up without one.
+func (s *AddressServiceImpl) UpdateAddress(ctx context.Context, userID, addressID uuid.UUID, payload userDomain.UpsertUserAddressDomain) (*userDomain.UserAddress, error) {
+	postalAddress, err := normalizeAddress(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	var address *userDomain.UserAddress
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		address, err = s.addressRepo.GetUserAddress(ctx, userID, addressID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAddressNotFound
+			}
+			return err
+		}
+		address.Label = strings.TrimSpace(payload.Label)
+		address.PostalAddress = postalAddress
+		marked := &userDomain.UserAddress{
+			IsDefaultBilling:  payload.IsDefaultBilling && !address.IsDefaultBilling,
+			IsDefaultShipping: payload.IsDefaultShipping && !address.IsDefaultShipping,
+		}
+		if err := s.unsetDefaults(ctx, userID, marked); err != nil {
+			return err
+		}
+		address.IsDefaultBilling = address.IsDefaultBilling || marked.IsDefaultBilling
+		address.IsDefaultShipping = address.IsDefaultShipping || marked.IsDefaultShipping
+		return s.addressRepo.UpdateUserAddress(ctx, address)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// DeleteAddress implements ports.IAddressService.
+//
+// When a default address is deleted, the most recent remaining address takes
+// over its default flags.
+func (s *AddressServiceImpl) DeleteAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		address, err := s.addressRepo.GetUserAddress(ctx, userID, addressID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAddressNotFound
+			}
+			return err
+		}
+		if err := s.addressRepo.DeleteUserAddress(ctx, userID, addressID); err != nil {
+			return err
+		}
+		if !address.IsDefaultBilling && !address.IsDefaultShipping {
+			return nil
+		}
+
+		remaining, err := s.addressRepo.ListUserAddresses(ctx, userID)
+		if err != nil || len(remaining) == 0 {
+			return err
+		}
+		successor := &remaining[0]
+		for i := range remaining {
+			if remaining[i].CreatedAt.After(successor.CreatedAt) {
+				successor = &remaining[i]
+			}
+		}
+		successor.IsDefaultBilling = successor.IsDefaultBilling || address.IsDefaultBilling
+		successor.IsDefaultShipping = successor.IsDefaultShipping || address.IsDefaultShipping
+		return s.addressRepo.UpdateUserAddress(ctx, successor)
+	})
+}
+
+// unsetDefaults clears the current defaults that address is about to take.
+func (s *AddressServiceImpl) unsetDefaults(ctx context.Context, userID uuid.UUID, address *userDomain.UserAddress) error {
+	if address.IsDefaultBilling {
+		if err := s.addressRepo.UnsetDefaultUserAddress(ctx, userID, userDomain.ADDRESS_KIND_BILLING); err != nil {
+			return err
+		}
+	}
+	if address.IsDefaultShipping {
+		if err := s.addressRepo.UnsetDefaultUserAddress(ctx, userID, userDomain.ADDRESS_KIND_SHIPPING); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeAddress trims the payload and validates it against the rules of
+// its country.
+func normalizeAddress(payload userDomain.UpsertUserAddressDomain) (domain.PostalAddress, error) {
+	codes := postal.Normalize(postal.Address{
+		CountryCode: payload.CountryCode,
+		Region:      payload.Region,
+		PostalCode:  payload.PostalCode,
+		Phone:       payload.Phone,
+	})
+	if err := postal.Validate(codes); err != nil {
+		return domain.PostalAddress{}, err
+	}
+	return domain.PostalAddress{
+		Recipient:   strings.TrimSpace(payload.Recipient),
+		Line1:       strings.TrimSpace(payload.Line1),
+		Line2:       strings.TrimSpace(payload.Line2),
+		City:        strings.TrimSpace(payload.City),
+		Region:      codes.Region,
+		PostalCode:  codes.PostalCode,
+		CountryCode: codes.CountryCode,
+		Phone:       codes.Phone,
+	}, nil
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/postal"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeAddressRepo struct {
+	addresses map[uuid.UUID]*userDomain.UserAddress
+	clock     time.Time
+}
+
+func (f *fakeAddressRepo) ListUserAddresses(ctx context.Context, userID uuid.UUID) ([]userDomain.UserAddress, error) {
+	var list []userDomain.UserAddress
+	for _, a := range f.addresses {
+		if a.UserID == userID {
+			list = append(list, *a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].CreatedAt.Before(list[j].CreatedAt) })
+	return list, nil
+}
+
+func (f *fakeAddressRepo) GetUserAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error) {
+	if a, ok := f.addresses[addressID]; ok && a.UserID == userID {
+		clone := *a
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAddressRepo) GetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error) {
+	for _, a := range f.addresses {
+		if a.UserID != userID {
+			continue
+		}
+		if (kind == userDomain.ADDRESS_KIND_BILLING && a.IsDefaultBilling) || (kind == userDomain.ADDRESS_KIND_SHIPPING && a.IsDefaultShipping) {
+			clone := *a
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAddressRepo) CountUserAddresses(ctx context.Context, userID uuid.UUID) (int64, error) {
+	list, _ := f.ListUserAddresses(ctx, userID)
+	return int64(len(list)), nil
+}
+
+func (f *fakeAddressRepo) CreateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error {
+	f.clock = f.clock.Add(time.Minute)
+	payload.ID = uuid.New()
+	payload.CreatedAt = f.clock
+	clone := *payload
+	f.addresses[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeAddressRepo) UpdateUserAddress(ctx context.Context, payload *userDomain.UserAddress) error {
+	clone := *payload
+	f.addresses[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeAddressRepo) DeleteUserAddress(ctx context.Context, userID, addressID uuid.UUID) error {
+	delete(f.addresses, addressID)
+	return nil
+}
+
+func (f *fakeAddressRepo) UnsetDefaultUserAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) error {
+	for _, a := range f.addresses {
+		if a.UserID != userID {
+			continue
+		}
+		if kind == userDomain.ADDRESS_KIND_BILLING {
+			a.IsDefaultBilling = false
+		} else {
+			a.IsDefaultShipping = false
+		}
+	}
+	return nil
+}
+
+// defaults returns the ids of the default billing and shipping addresses,
+// failing the test when a user has more than one of either.
+func (f *fakeAddressRepo) defaults(t *testing.T) (billing, shipping uuid.UUID) {
+	t.Helper()
+	for id, a := range f.addresses {
+		if a.IsDefaultBilling {
+			if billing != uuid.Nil {
+				t.Fatal("more than one default billing address")
+			}
+			billing = id
+		}
+		if a.IsDefaultShipping {
+			if shipping != uuid.Nil {
+				t.Fatal("more than one default shipping address")
+			}
+			shipping = id
+		}
+	}
+	return billing, shipping
+}
+
+func newTestAddressService() (*AddressServiceImpl, *fakeAddressRepo) {
+	repo := &fakeAddressRepo{addresses: map[uuid.UUID]*userDomain.UserAddress{}}
+	service := NewAddressService(repo, transactorstest.Transactor{}, DefaultAddressServiceConfig).(*AddressServiceImpl)
+	return service, repo
+}
+
+func bangkokAddress() userDomain.UpsertUserAddressDomain {
+	return userDomain.UpsertUserAddressDomain{
+		Label:       "Home",
+		Recipient:   "Somchai Jaidee",
+		Line1:       "99/1 Sukhumvit Road",
+		City:        "Khlong Toei",
+		Region:      "Bangkok",
+		PostalCode:  " 10110 ",
+		CountryCode: "th",
+		Phone:       "+66 81 234 5678",
+	}
+}
+
+func TestCreateAddressNormalizesAndDefaultsFirstEntry(t *testing.T) {
+	service, repo := newTestAddressService()
+	userID := uuid.New()
+	ctx := context.Background()
+
+	first, err := service.CreateAddress(ctx, userID, bangkokAddress())
+	if err != nil {
+		t.Fatalf("CreateAddress: %v", err)
+	}
+	if first.CountryCode != "TH" || first.PostalCode != "10110" {
+		t.Fatalf("address not normalised: %+v", first.PostalAddress)
+	}
+	if !first.IsDefaultBilling || !first.IsDefaultShipping {
+		t.Fatal("the first address should become the default for billing and shipping")
+	}
+
+	payload := bangkokAddress()
+	payload.Label = "Office"
+	payload.IsDefaultShipping = true
+	second, err := service.CreateAddress(ctx, userID, payload)
+	if err != nil {
+		t.Fatalf("CreateAddress: %v", err)
+	}
+	billing, shipping := repo.defaults(t)
+	if billing != first.ID || shipping != second.ID {
+		t.Fatalf("defaults = billing %v, shipping %v; want %v, %v", billing, shipping, first.ID, second.ID)
+	}
+}
+
+func TestCreateAddressAppliesCountryRules(t *testing.T) {
+	service, repo := newTestAddressService()
+
+	cases := []struct {
+		mutate func(*userDomain.UpsertUserAddressDomain)
+		want   error
+	}{
+		{func(p *userDomain.UpsertUserAddressDomain) { p.PostalCode = "1011" }, postal.ErrInvalidPostalCode},
+		{func(p *userDomain.UpsertUserAddressDomain) { p.Region = "" }, postal.ErrRegionRequired},
+		{func(p *userDomain.UpsertUserAddressDomain) { p.CountryCode = "ZZ" }, postal.ErrUnknownCountry},
+		{func(p *userDomain.UpsertUserAddressDomain) {
+			p.CountryCode, p.Region, p.PostalCode = "US", "TX", "7330"
+		}, postal.ErrInvalidPostalCode},
+		{func(p *userDomain.UpsertUserAddressDomain) { p.CountryCode, p.PostalCode = "US", "73301" }, postal.ErrInvalidRegion},
+	}
+	for i, tc := range cases {
+		payload := bangkokAddress()
+		tc.mutate(&payload)
+		if _, err := service.CreateAddress(context.Background(), uuid.New(), payload); !errors.Is(err, tc.want) {
+			t.Errorf("case %d: got %v, want %v", i, err, tc.want)
+		}
+	}
+	if len(repo.addresses) != 0 {
+		t.Fatal("invalid addresses must not be saved")
+	}
+}
+
+func TestCreateAddressEnforcesLimit(t *testing.T) {
+	service, _ := newTestAddressService()
+	service.config.MaxAddressesPerUser = 2
+	userID := uuid.New()
+
+	for i := 0; i < 2; i++ {
+		if _, err := service.CreateAddress(context.Background(), userID, bangkokAddress()); err != nil {
+			t.Fatalf("CreateAddress: %v", err)
+		}
+	}
+	if _, err := service.CreateAddress(context.Background(), userID, bangkokAddress()); !errors.Is(err, ErrAddressLimitReached) {
+		t.Fatalf("got %v, want ErrAddressLimitReached", err)
+	}
+}
+
+func TestUpdateAddressMovesDefaultsButNeverClearsThem(t *testing.T) {
+	service, repo := newTestAddressService()
+	userID := uuid.New()
+	ctx := context.Background()
+
+	first, _ := service.CreateAddress(ctx, userID, bangkokAddress())
+	second, _ := service.CreateAddress(ctx, userID, bangkokAddress())
+
+	payload := bangkokAddress()
+	payload.IsDefaultBilling = true
+	if _, err := service.UpdateAddress(ctx, userID, second.ID, payload); err != nil {
+		t.Fatalf("UpdateAddress: %v", err)
+	}
+	billing, shipping := repo.defaults(t)
+	if billing != second.ID || shipping != first.ID {
+		t.Fatalf("defaults = billing %v, shipping %v", billing, shipping)
+	}
+
+	// Sending false for the current default keeps it.
+	if _, err := service.UpdateAddress(ctx, userID, second.ID, bangkokAddress()); err != nil {
+		t.Fatalf("UpdateAddress: %v", err)
+	}
+	if billing, _ := repo.defaults(t); billing != second.ID {
+		t.Fatal("default billing address should be kept")
+	}
+
+	if _, err := service.UpdateAddress(ctx, uuid.New(), first.ID, bangkokAddress()); !errors.Is(err, ErrAddressNotFound) {
+		t.Fatalf("updating another user's address: got %v, want ErrAddressNotFound", err)
+	}
+}
+
+func TestDeleteDefaultAddressPromotesNewest(t *testing.T) {
+	service, repo := newTestAddressService()
+	userID := uuid.New()
+	ctx := context.Background()
+
+	first, _ := service.CreateAddress(ctx, userID, bangkokAddress())
+	_, _ = service.CreateAddress(ctx, userID, bangkokAddress())
+	third, _ := service.CreateAddress(ctx, userID, bangkokAddress())
+
+	if err := service.DeleteAddress(ctx, userID, first.ID); err != nil {
+		t.Fatalf("DeleteAddress: %v", err)
+	}
+	billing, shipping := repo.defaults(t)
+	if billing != third.ID || shipping != third.ID {
+		t.Fatalf("defaults = billing %v, shipping %v; want newest %v", billing, shipping, third.ID)
+	}
+	if _, err := service.GetDefaultAddress(ctx, userID, userDomain.ADDRESS_KIND_SHIPPING); err != nil {
+		t.Fatalf("GetDefaultAddress: %v", err)
+	}
+	if _, err := service.GetDefaultAddress(ctx, uuid.New(), userDomain.ADDRESS_KIND_BILLING); !errors.Is(err, ErrNoDefaultAddress) {
+		t.Fatalf("got %v, want ErrNoDefaultAddress", err)
+	}
+}
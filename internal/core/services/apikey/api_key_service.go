@@ -0,0 +1,405 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/apikey"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAPIKeyNotFound            = errors.New("api key not found")
+	ErrInvalidAPIKey             = errors.New("invalid api key")
+	ErrAPIKeyRevoked             = errors.New("api key has been revoked")
+	ErrAPIKeyExpired             = errors.New("api key has expired")
+	ErrAPIKeyOwnerDisabled       = errors.New("the owner of this api key is inactive or banned")
+	ErrAPIKeyOwnerNotFound       = errors.New("user not found")
+	ErrAPIKeyScopeNotHeld        = errors.New("scope is not granted to the role of the key owner")
+	ErrAPIKeyExpiryInPast        = errors.New("expires_at must be in the future")
+	ErrAPIKeyLimitReached        = errors.New("too many active api keys, revoke one first")
+	ErrServiceAccountNotFound    = errors.New("service account not found")
+	ErrServiceAccountExists      = errors.New("username is already in use")
+	ErrServiceAccountRoleMissing = errors.New("service account role does not exist, run the seeders")
+)
+
+const (
+	apiKeyPrefixBytes = 8
+	apiKeySecretBytes = 32
+
+	// Service accounts need a unique email; the reserved .invalid TLD makes
+	// sure no mail for them is ever delivered.
+	serviceAccountEmailDomain = "svc.invalid"
+)
+
+// APIKeyServiceConfig holds the tunables of APIKeyServiceImpl.
+type APIKeyServiceConfig struct {
+	MaxActiveKeysPerUser int
+	LastUsedResolution   time.Duration // Uses closer together than this from the same IP are not written back
+}
+
+var DefaultAPIKeyServiceConfig = APIKeyServiceConfig{
+	MaxActiveKeysPerUser: 20,
+	LastUsedResolution:   time.Minute,
+}
+
+type APIKeyServiceImpl struct {
+	apiKeyRepo  ports.IAPIKeyRepository
+	userRepo    userPorts.IUserRepository
+	roleRepo    rolePorts.IRoleRepository
+	roleService rolePorts.IRoleService
+	transactor  transactors.IDatabaseTransactor
+	hashParams  *argon2id.Params
+	config      APIKeyServiceConfig
+	now         func() time.Time
+}
+
+func NewAPIKeyService(
+	apiKeyRepo ports.IAPIKeyRepository,
+	userRepo userPorts.IUserRepository,
+	roleRepo rolePorts.IRoleRepository,
+	roleService rolePorts.IRoleService,
+	transactor transactors.IDatabaseTransactor,
+	hashParams *argon2id.Params,
+	config APIKeyServiceConfig,
+) ports.IAPIKeyService {
+	if hashParams == nil {
+		hashParams = argon2id.DefaultParams
+	}
+	return &APIKeyServiceImpl{
+		apiKeyRepo:  apiKeyRepo,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		roleService: roleService,
+		transactor:  transactor,
+		hashParams:  hashParams,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// ListAPIKeys implements ports.IAPIKeyService.
+func (s *APIKeyServiceImpl) ListAPIKeys(ctx context.Context, ownerID uuid.UUID) ([]authDomain.APIKey, error) {
+	return s.apiKeyRepo.ListUserAPIKeys(ctx, ownerID)
+}
+
+// CreateAPIKey implements ports.IAPIKeyService.
+func (s *APIKeyServiceImpl) CreateAPIKey(ctx context.Context, ownerID, createdByID uuid.UUID, payload authDomain.CreateAPIKeyDomain) (*authDomain.APIKeySecret, error) {
+	now := s.now()
+	if payload.ExpiresAt != nil && !payload.ExpiresAt.After(now) {
+		return nil, ErrAPIKeyExpiryInPast
+	}
+	scopes, err := s.checkScopes(ctx, ownerID, payload.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &authDomain.APIKey{
+		UserID:      ownerID,
+		Name:        strings.TrimSpace(payload.Name),
+		Scopes:      strings.Join(scopes, " "),
+		ExpiresAt:   payload.ExpiresAt,
+		CreatedByID: createdByID,
+	}
+	var secret string
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		// Locking the owner serialises concurrent creations so the limit holds.
+		if _, err := s.userRepo.GetUserByIDForUpdate(txCtx, ownerID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAPIKeyOwnerNotFound
+			}
+			return err
+		}
+		keys, err := s.apiKeyRepo.ListUserAPIKeys(txCtx, ownerID)
+		if err != nil {
+			return err
+		}
+		active := 0
+		for _, k := range keys {
+			if k.IsActive(now) {
+				active++
+			}
+		}
+		if active >= s.config.MaxActiveKeysPerUser {
+			return ErrAPIKeyLimitReached
+		}
+
+		if secret, err = assignKeyMaterial(key); err != nil {
+			return err
+		}
+		return s.apiKeyRepo.CreateAPIKey(txCtx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.APIKeySecret{APIKey: key, Key: secret}, nil
+}
+
+// RotateAPIKey implements ports.IAPIKeyService.
+//
+// The key keeps its ID, name, scopes and expiry; only the prefix and secret
+// change.
+func (s *APIKeyServiceImpl) RotateAPIKey(ctx context.Context, ownerID, keyID uuid.UUID) (*authDomain.APIKeySecret, error) {
+	var key *authDomain.APIKey
+	var secret string
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if key, err = s.ownedKey(txCtx, ownerID, keyID); err != nil {
+			return err
+		}
+		if err := checkKeyActive(key, s.now()); err != nil {
+			return err
+		}
+		if secret, err = assignKeyMaterial(key); err != nil {
+			return err
+		}
+		key.LastUsedAt = nil
+		key.LastUsedIP = ""
+		return s.apiKeyRepo.UpdateAPIKey(txCtx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.APIKeySecret{APIKey: key, Key: secret}, nil
+}
+
+// RevokeAPIKey implements ports.IAPIKeyService.
+func (s *APIKeyServiceImpl) RevokeAPIKey(ctx context.Context, ownerID, keyID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		key, err := s.ownedKey(txCtx, ownerID, keyID)
+		if err != nil {
+			return err
+		}
+		if key.RevokedAt != nil {
+			return nil
+		}
+		now := s.now()
+		key.RevokedAt = &now
+		return s.apiKeyRepo.UpdateAPIKey(txCtx, key)
+	})
+}
+
+// Authenticate implements ports.IAPIKeyService.
+//
+// The owner is loaded on every call so that banning a user disables their
+// keys at once. Scopes only narrow what the owner's role allows; the role is
+// still checked by the permission middleware.
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, rawKey, clientIP string) (*authDomain.APIKeyPrincipal, error) {
+	prefix, secret, ok := parseAPIKey(rawKey)
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(tokens.HashToken(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+	now := s.now()
+	if err := checkKeyActive(key, now); err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if owner.Status != userDomain.USER_STATUS_ACTIVE {
+		return nil, ErrAPIKeyOwnerDisabled
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= s.config.LastUsedResolution || key.LastUsedIP != clientIP {
+		if err := s.apiKeyRepo.TouchAPIKey(ctx, key.ID, now, clientIP); err != nil {
+			log.Printf("api key: record last use of %s: %v", key.Prefix, err)
+		}
+	}
+
+	return &authDomain.APIKeyPrincipal{
+		KeyID:  key.ID,
+		UserID: key.UserID,
+		Scopes: key.ScopeList(),
+	}, nil
+}
+
+// ListServiceAccounts implements ports.IAPIKeyService.
+func (s *APIKeyServiceImpl) ListServiceAccounts(ctx context.Context) ([]userDomain.User, error) {
+	role, err := s.serviceAccountRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.userRepo.ListUsersByRole(ctx, role.ID)
+}
+
+// GetServiceAccount implements ports.IAPIKeyService.
+func (s *APIKeyServiceImpl) GetServiceAccount(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	role, err := s.serviceAccountRole(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceAccountNotFound
+		}
+		return nil, err
+	}
+	if user.RoleID == nil || *user.RoleID != role.ID {
+		return nil, ErrServiceAccountNotFound
+	}
+	return user, nil
+}
+
+// CreateServiceAccount implements ports.IAPIKeyService.
+//
+// Service accounts get an unusable password, so they can only authenticate
+// with API keys. Their permissions come from the service account role.
+func (s *APIKeyServiceImpl) CreateServiceAccount(ctx context.Context, createdByID uuid.UUID, payload authDomain.CreateServiceAccountDomain) (*userDomain.User, error) {
+	unusable, err := tokens.NewOpaqueToken(apiKeySecretBytes)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := argon2id.CreateHash(unusable, s.hashParams)
+	if err != nil {
+		return nil, err
+	}
+
+	username := strings.ToLower(payload.Username)
+	user := &userDomain.User{
+		FirstName:   strings.TrimSpace(payload.Name),
+		Username:    username,
+		Email:       username + "@" + serviceAccountEmailDomain,
+		Password:    hash,
+		Status:      userDomain.USER_STATUS_ACTIVE,
+		CreatedByID: createdByID.String(),
+	}
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		role, err := s.serviceAccountRole(txCtx)
+		if err != nil {
+			return err
+		}
+		user.RoleID = &role.ID
+
+		exists, err := s.userRepo.ExistsByUsernameOrEmail(txCtx, user.Username, user.Email)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrServiceAccountExists
+		}
+		return s.userRepo.CreateUser(txCtx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *APIKeyServiceImpl) serviceAccountRole(ctx context.Context) (*userDomain.UserRole, error) {
+	role, err := s.roleRepo.GetRoleByName(ctx, userDomain.ROLE_SERVICE_ACCOUNT)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrServiceAccountRoleMissing
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *APIKeyServiceImpl) ownedKey(ctx context.Context, ownerID, keyID uuid.UUID) (*authDomain.APIKey, error) {
+	key, err := s.apiKeyRepo.GetAPIKeyByID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	if key.UserID != ownerID {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// checkScopes normalises the requested scopes and makes sure the owner's role
+// holds each of them, so a key can never do more than its owner.
+func (s *APIKeyServiceImpl) checkScopes(ctx context.Context, ownerID uuid.UUID, requested []string) ([]string, error) {
+	scopes := make([]string, 0, len(requested))
+	seen := map[string]bool{}
+	for _, scope := range requested {
+		scope = strings.ToUpper(strings.TrimSpace(scope))
+		if scope == "" || seen[scope] {
+			continue
+		}
+		seen[scope] = true
+
+		allowed, err := s.roleService.HasPermission(ctx, ownerID, scope)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: %s", ErrAPIKeyScopeNotHeld, scope)
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+func checkKeyActive(key *authDomain.APIKey, now time.Time) error {
+	if key.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	if !key.IsActive(now) {
+		return ErrAPIKeyExpired
+	}
+	return nil
+}
+
+// assignKeyMaterial gives the key a new prefix and secret and returns the full
+// key to hand to the client.
+func assignKeyMaterial(key *authDomain.APIKey) (string, error) {
+	b := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	secret, err := tokens.NewOpaqueToken(apiKeySecretBytes)
+	if err != nil {
+		return "", err
+	}
+	key.Prefix = authDomain.API_KEY_PREFIX + hex.EncodeToString(b)
+	key.SecretHash = tokens.HashToken(secret)
+	return key.Prefix + "." + secret, nil
+}
+
+// parseAPIKey splits "<prefix>.<secret>". The secret is base64url, which
+// never contains a dot.
+func parseAPIKey(key string) (string, string, bool) {
+	if !strings.HasPrefix(key, authDomain.API_KEY_PREFIX) {
+		return "", "", false
+	}
+	prefix, secret, found := strings.Cut(key, ".")
+	if !found || prefix == authDomain.API_KEY_PREFIX || secret == "" {
+		return "", "", false
+	}
+	return prefix, secret, true
+}
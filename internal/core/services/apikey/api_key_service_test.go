@@ -0,0 +1,371 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeAPIKeyRepo struct {
+	keys    map[uuid.UUID]*authDomain.APIKey
+	touches int
+}
+
+func (f *fakeAPIKeyRepo) CreateAPIKey(ctx context.Context, payload *authDomain.APIKey) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.keys[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) GetAPIKeyByID(ctx context.Context, id uuid.UUID) (*authDomain.APIKey, error) {
+	if k, ok := f.keys[id]; ok {
+		clone := *k
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAPIKeyRepo) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*authDomain.APIKey, error) {
+	for _, k := range f.keys {
+		if k.Prefix == prefix {
+			clone := *k
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeAPIKeyRepo) ListUserAPIKeys(ctx context.Context, userID uuid.UUID) ([]authDomain.APIKey, error) {
+	var keys []authDomain.APIKey
+	for _, k := range f.keys {
+		if k.UserID == userID {
+			keys = append(keys, *k)
+		}
+	}
+	return keys, nil
+}
+
+func (f *fakeAPIKeyRepo) UpdateAPIKey(ctx context.Context, payload *authDomain.APIKey) error {
+	clone := *payload
+	f.keys[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeAPIKeyRepo) TouchAPIKey(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	f.touches++
+	f.keys[id].LastUsedAt = &at
+	f.keys[id].LastUsedIP = ip
+	return nil
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if u, ok := f.users[id]; ok {
+		clone := *u
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	return f.GetUserByID(ctx, id)
+}
+
+func (f *fakeUserRepo) ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error) {
+	for _, u := range f.users {
+		if u.Username == username || u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserRepo) ListUsersByRole(ctx context.Context, roleID uuid.UUID) ([]userDomain.User, error) {
+	var users []userDomain.User
+	for _, u := range f.users {
+		if u.RoleID != nil && *u.RoleID == roleID {
+			users = append(users, *u)
+		}
+	}
+	return users, nil
+}
+
+func (f *fakeUserRepo) CreateUser(ctx context.Context, payload *userDomain.User) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.users[payload.ID] = &clone
+	return nil
+}
+
+type fakeRoleRepo struct {
+	rolePorts.IRoleRepository
+	serviceAccount userDomain.UserRole
+}
+
+func (f *fakeRoleRepo) GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error) {
+	if name == f.serviceAccount.RoleName {
+		clone := f.serviceAccount
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeRoleService grants permissions per user instead of per role.
+type fakeRoleService struct {
+	rolePorts.IRoleService
+	grants map[uuid.UUID][]string
+}
+
+func (f *fakeRoleService) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	for _, p := range f.grants[userID] {
+		if p == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type apiKeyTestEnv struct {
+	service *APIKeyServiceImpl
+	keys    *fakeAPIKeyRepo
+	users   *fakeUserRepo
+	roles   *fakeRoleService
+	now     time.Time
+}
+
+func newAPIKeyTestEnv(t *testing.T) *apiKeyTestEnv {
+	env := &apiKeyTestEnv{
+		keys:  &fakeAPIKeyRepo{keys: map[uuid.UUID]*authDomain.APIKey{}},
+		users: &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}},
+		roles: &fakeRoleService{grants: map[uuid.UUID][]string{}},
+		now:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	roleRepo := &fakeRoleRepo{serviceAccount: userDomain.UserRole{RoleName: userDomain.ROLE_SERVICE_ACCOUNT}}
+	roleRepo.serviceAccount.ID = uuid.New()
+	service := NewAPIKeyService(
+		env.keys,
+		env.users,
+		roleRepo,
+		env.roles,
+		transactorstest.Transactor{},
+		&argon2id.Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32},
+		DefaultAPIKeyServiceConfig,
+	).(*APIKeyServiceImpl)
+	service.now = func() time.Time { return env.now }
+	env.service = service
+	return env
+}
+
+func (env *apiKeyTestEnv) addUser(permissions ...string) uuid.UUID {
+	id := uuid.New()
+	env.users.users[id] = &userDomain.User{Username: id.String(), Status: userDomain.USER_STATUS_ACTIVE}
+	env.users.users[id].ID = id
+	env.roles.grants[id] = permissions
+	return id
+}
+
+func TestCreateAndAuthenticateAPIKey(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	userID := env.addUser(userDomain.PERMISSION_CREATE_PRODUCT, userDomain.PERMISSION_VIEW_ORDERS)
+
+	created, err := env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{
+		Name:   "ERP sync",
+		Scopes: []string{"view_orders", userDomain.PERMISSION_VIEW_ORDERS},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if !strings.HasPrefix(created.Key, created.APIKey.Prefix+".") {
+		t.Fatalf("key %q does not start with its prefix %q", created.Key, created.APIKey.Prefix)
+	}
+	if strings.Contains(created.APIKey.SecretHash, strings.TrimPrefix(created.Key, created.APIKey.Prefix+".")) {
+		t.Fatal("secret is stored in plaintext")
+	}
+	if created.APIKey.Scopes != userDomain.PERMISSION_VIEW_ORDERS {
+		t.Fatalf("scopes = %q, want them normalised and deduplicated", created.APIKey.Scopes)
+	}
+
+	principal, err := env.service.Authenticate(context.Background(), created.Key, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if principal.UserID != userID || principal.KeyID != created.APIKey.ID {
+		t.Fatalf("principal = %+v", principal)
+	}
+	if len(principal.Scopes) != 1 || principal.Scopes[0] != userDomain.PERMISSION_VIEW_ORDERS {
+		t.Fatalf("principal scopes = %v", principal.Scopes)
+	}
+
+	if _, err := env.service.Authenticate(context.Background(), created.APIKey.Prefix+".wrong", ""); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("wrong secret: got %v, want ErrInvalidAPIKey", err)
+	}
+	if _, err := env.service.Authenticate(context.Background(), "not-a-key", ""); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("malformed key: got %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestCreateAPIKeyRejectsScopesOutsideTheRole(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	userID := env.addUser(userDomain.PERMISSION_VIEW_ORDERS)
+
+	_, err := env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{
+		Name:   "too broad",
+		Scopes: []string{userDomain.PERMISSION_MANAGE_ROLES},
+	})
+	if !errors.Is(err, ErrAPIKeyScopeNotHeld) {
+		t.Fatalf("got %v, want ErrAPIKeyScopeNotHeld", err)
+	}
+
+	past := env.now.Add(-time.Hour)
+	_, err = env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{Name: "old", ExpiresAt: &past})
+	if !errors.Is(err, ErrAPIKeyExpiryInPast) {
+		t.Fatalf("got %v, want ErrAPIKeyExpiryInPast", err)
+	}
+}
+
+func TestRotateAndRevokeAPIKey(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	userID := env.addUser()
+	created, err := env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{Name: "ci"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	if _, err := env.service.RotateAPIKey(context.Background(), env.addUser(), created.APIKey.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("rotate by another user: got %v, want ErrAPIKeyNotFound", err)
+	}
+
+	rotated, err := env.service.RotateAPIKey(context.Background(), userID, created.APIKey.ID)
+	if err != nil {
+		t.Fatalf("RotateAPIKey: %v", err)
+	}
+	if rotated.APIKey.ID != created.APIKey.ID || rotated.Key == created.Key {
+		t.Fatal("rotation should keep the key record and change the key material")
+	}
+	if _, err := env.service.Authenticate(context.Background(), created.Key, ""); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("old key after rotation: got %v, want ErrInvalidAPIKey", err)
+	}
+	if _, err := env.service.Authenticate(context.Background(), rotated.Key, ""); err != nil {
+		t.Fatalf("new key after rotation: %v", err)
+	}
+
+	if err := env.service.RevokeAPIKey(context.Background(), userID, created.APIKey.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if _, err := env.service.Authenticate(context.Background(), rotated.Key, ""); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Fatalf("revoked key: got %v, want ErrAPIKeyRevoked", err)
+	}
+	if _, err := env.service.RotateAPIKey(context.Background(), userID, created.APIKey.ID); !errors.Is(err, ErrAPIKeyRevoked) {
+		t.Fatalf("rotate revoked key: got %v, want ErrAPIKeyRevoked", err)
+	}
+}
+
+func TestAuthenticateChecksExpiryAndOwner(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	userID := env.addUser()
+	expiresAt := env.now.Add(time.Hour)
+	created, err := env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{Name: "short", ExpiresAt: &expiresAt})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	env.users.users[userID].Status = userDomain.USER_STATUS_BANNED
+	if _, err := env.service.Authenticate(context.Background(), created.Key, ""); !errors.Is(err, ErrAPIKeyOwnerDisabled) {
+		t.Fatalf("banned owner: got %v, want ErrAPIKeyOwnerDisabled", err)
+	}
+
+	env.users.users[userID].Status = userDomain.USER_STATUS_ACTIVE
+	env.now = expiresAt
+	if _, err := env.service.Authenticate(context.Background(), created.Key, ""); !errors.Is(err, ErrAPIKeyExpired) {
+		t.Fatalf("expired key: got %v, want ErrAPIKeyExpired", err)
+	}
+}
+
+func TestAuthenticateThrottlesLastUsedWrites(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	userID := env.addUser()
+	created, err := env.service.CreateAPIKey(context.Background(), userID, userID, authDomain.CreateAPIKeyDomain{Name: "busy"})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := env.service.Authenticate(context.Background(), created.Key, "203.0.113.7"); err != nil {
+			t.Fatalf("Authenticate: %v", err)
+		}
+	}
+	if env.keys.touches != 1 {
+		t.Fatalf("touches = %d, want 1 within the resolution", env.keys.touches)
+	}
+
+	env.now = env.now.Add(DefaultAPIKeyServiceConfig.LastUsedResolution)
+	if _, err := env.service.Authenticate(context.Background(), created.Key, "203.0.113.7"); err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if env.keys.touches != 2 {
+		t.Fatalf("touches = %d, want 2 after the resolution", env.keys.touches)
+	}
+}
+
+func TestServiceAccounts(t *testing.T) {
+	env := newAPIKeyTestEnv(t)
+	adminID := env.addUser()
+
+	account, err := env.service.CreateServiceAccount(context.Background(), adminID, authDomain.CreateServiceAccountDomain{Username: "ErpSync", Name: "ERP sync"})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount: %v", err)
+	}
+	if account.Username != "erpsync" || account.Email != "erpsync@"+serviceAccountEmailDomain {
+		t.Fatalf("account = %s <%s>", account.Username, account.Email)
+	}
+	if account.Password == "" || account.CreatedByID != adminID.String() {
+		t.Fatal("service account should get an unusable password and record its creator")
+	}
+
+	if _, err := env.service.CreateServiceAccount(context.Background(), adminID, authDomain.CreateServiceAccountDomain{Username: "erpsync", Name: "again"}); !errors.Is(err, ErrServiceAccountExists) {
+		t.Fatalf("duplicate: got %v, want ErrServiceAccountExists", err)
+	}
+
+	if _, err := env.service.GetServiceAccount(context.Background(), account.ID); err != nil {
+		t.Fatalf("GetServiceAccount: %v", err)
+	}
+	if _, err := env.service.GetServiceAccount(context.Background(), adminID); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Fatalf("regular user: got %v, want ErrServiceAccountNotFound", err)
+	}
+
+	accounts, err := env.service.ListServiceAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("ListServiceAccounts: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].ID != account.ID {
+		t.Fatalf("accounts = %v", accounts)
+	}
+
+	env.roles.grants[account.ID] = []string{userDomain.PERMISSION_UPDATE_PRODUCT}
+	created, err := env.service.CreateAPIKey(context.Background(), account.ID, adminID, authDomain.CreateAPIKeyDomain{
+		Name:   "stock feed",
+		Scopes: []string{userDomain.PERMISSION_UPDATE_PRODUCT},
+	})
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if created.APIKey.UserID != account.ID || created.APIKey.CreatedByID != adminID {
+		t.Fatalf("key owner = %s, created by %s", created.APIKey.UserID, created.APIKey.CreatedByID)
+	}
+}
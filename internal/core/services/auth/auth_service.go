@@ -0,0 +1,355 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	lockoutPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+	otpPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserAlreadyExists  = errors.New("username or email is already registered")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrUserInactive       = errors.New("user account is inactive")
+	ErrUserBanned         = errors.New("user account is banned")
+	ErrUserNotFound       = errors.New("user not found")
+
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is
+	// presented again. Every token of its family is revoked when this happens.
+	ErrRefreshTokenReused = errors.New("refresh token has already been used, please log in again")
+)
+
+// refreshTokenBytes is the entropy of the opaque refresh tokens handed to clients.
+const refreshTokenBytes = 32
+
+// AuthServiceConfig holds the tunables of AuthServiceImpl.
+type AuthServiceConfig struct {
+	RefreshTokenTTL    time.Duration
+	TwoFactorIssuer    string           // Issuer shown by authenticator apps
+	PasswordHashParams *argon2id.Params // argon2id parameters for new hashes; argon2id.DefaultParams when nil
+}
+
+type AuthServiceImpl struct {
+	userRepo         userPorts.IUserRepository
+	roleRepo         rolePorts.IRoleRepository
+	refreshTokenRepo ports.IRefreshTokenRepository
+	recoveryCodeRepo ports.ITwoFactorRecoveryCodeRepository
+	otpService       otpPorts.IOTPService
+	lockoutService   lockoutPorts.ILockoutService
+	transactor       transactors.IDatabaseTransactor
+	jwtManager       *tokens.JWTManager
+	cipher           *encryption.Cipher
+	config           AuthServiceConfig
+}
+
+func NewAuthService(
+	userRepo userPorts.IUserRepository,
+	roleRepo rolePorts.IRoleRepository,
+	refreshTokenRepo ports.IRefreshTokenRepository,
+	recoveryCodeRepo ports.ITwoFactorRecoveryCodeRepository,
+	otpService otpPorts.IOTPService,
+	lockoutService lockoutPorts.ILockoutService,
+	transactor transactors.IDatabaseTransactor,
+	jwtManager *tokens.JWTManager,
+	cipher *encryption.Cipher,
+	config AuthServiceConfig,
+) ports.IAuthService {
+	if config.PasswordHashParams == nil {
+		config.PasswordHashParams = argon2id.DefaultParams
+	}
+	return &AuthServiceImpl{
+		userRepo:         userRepo,
+		roleRepo:         roleRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		recoveryCodeRepo: recoveryCodeRepo,
+		otpService:       otpService,
+		lockoutService:   lockoutService,
+		transactor:       transactor,
+		jwtManager:       jwtManager,
+		cipher:           cipher,
+		config:           config,
+	}
+}
+
+// Register implements ports.IAuthService.
+//
+// Self-registered users get the CUSTOMER role when it exists. A verification
+// code is emailed afterwards; failing to send it does not undo the signup since
+// the user can ask for a new code.
+func (a *AuthServiceImpl) Register(ctx context.Context, payload userDomain.RegisterUserDomain) (*userDomain.User, error) {
+	hash, err := argon2id.CreateHash(payload.Password, a.config.PasswordHashParams)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &userDomain.User{
+		FirstName:   payload.FirstName,
+		LastName:    payload.LastName,
+		Address:     payload.Address,
+		PhoneNumber: payload.PhoneNumber,
+		Email:       strings.ToLower(strings.TrimSpace(payload.Email)),
+		Username:    strings.TrimSpace(payload.Username),
+		Password:    hash,
+		Status:      userDomain.USER_STATUS_ACTIVE,
+	}
+
+	err = a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		exists, err := a.userRepo.ExistsByUsernameOrEmail(txCtx, user.Username, user.Email)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrUserAlreadyExists
+		}
+
+		role, err := a.roleRepo.GetRoleByName(txCtx, userDomain.ROLE_CUSTOMER)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if role != nil {
+			user.RoleID = &role.ID
+		}
+		return a.userRepo.CreateUser(txCtx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.issueEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION); err != nil {
+		log.Printf("failed to send email verification to user %s: %v", user.ID, err)
+	}
+	return user, nil
+}
+
+// Login implements ports.IAuthService.
+//
+// The password is verified before the account status so that the status of an
+// account is only revealed to someone who knows its password. A successful
+// login starts a new refresh token family; accounts with two-factor
+// authentication get a challenge instead, see VerifyTwoFactorLogin.
+//
+// Failures are counted per account and per client IP. Unknown identifiers are
+// counted too so that lockouts do not reveal which accounts exist.
+func (a *AuthServiceImpl) Login(ctx context.Context, payload userDomain.LoginUserDomain, clientIP string) (*authDomain.LoginResult, error) {
+	identifier := strings.TrimSpace(payload.Username)
+	user, err := a.userRepo.GetUserByUsernameOrEmail(ctx, identifier)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		user = nil
+	}
+
+	// The attempt counts as failed until the password is known to be right,
+	// so a burst of guesses cannot all pass the check before any is counted.
+	account := lockoutAccount(user, identifier)
+	if err := a.lockoutService.ReserveAttempt(ctx, account, clientIP); err != nil {
+		return nil, err
+	}
+
+	if user == nil {
+		return nil, a.registerLoginFailure(ctx, nil, account, clientIP)
+	}
+	match, params, err := argon2id.CheckHash(payload.Password, user.Password)
+	if err != nil || !match {
+		return nil, a.registerLoginFailure(ctx, user, account, clientIP)
+	}
+	if err := a.lockoutService.ReleaseAttempt(ctx, account, clientIP); err != nil {
+		return nil, err
+	}
+	// With two-factor authentication the failures are only cleared once the
+	// second factor is right too, or logging in again would reset the count of
+	// wrong codes.
+	if !user.TwoFactorEnabled {
+		if err := a.lockoutService.RegisterSuccess(ctx, account); err != nil {
+			return nil, err
+		}
+	}
+	if params.IsWeakerThan(a.config.PasswordHashParams) {
+		if err := a.upgradePasswordHash(ctx, user, payload.Password); err != nil {
+			log.Printf("failed to upgrade password hash of user %s: %v", user.ID, err)
+		}
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	return a.startSession(ctx, user)
+}
+
+// RefreshToken implements ports.IAuthService.
+//
+// The presented token is marked as used and a new pair from the same family is
+// issued. Presenting a token that was already used means it leaked, so the
+// whole family is revoked and the caller has to log in again.
+func (a *AuthServiceImpl) RefreshToken(ctx context.Context, refreshToken string) (*authDomain.TokenPair, error) {
+	var (
+		pair   *authDomain.TokenPair
+		reused bool
+	)
+	err := a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		stored, err := a.refreshTokenRepo.GetRefreshTokenByHash(txCtx, tokens.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if stored.Used {
+			// Returning nil keeps the revocation committed; the error is reported below.
+			reused = true
+			return a.refreshTokenRepo.RevokeRefreshTokenFamily(txCtx, stored.FamilyID)
+		}
+		if stored.Revoked {
+			return ErrInvalidRefreshToken
+		}
+		if time.Now().After(stored.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		if err := a.refreshTokenRepo.MarkRefreshTokenUsed(txCtx, stored.ID); err != nil {
+			return err
+		}
+
+		user, err := a.userRepo.GetUserByID(txCtx, stored.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+		if err := checkUserStatus(user); err != nil {
+			return err
+		}
+
+		pair, err = a.issueTokenPair(txCtx, user, stored.FamilyID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return pair, nil
+}
+
+// GetCurrentUser implements ports.IAuthService.
+func (a *AuthServiceImpl) GetCurrentUser(ctx context.Context, userID uuid.UUID) (*userDomain.User, error) {
+	return a.userRepo.GetUserByID(ctx, userID)
+}
+
+// IssueSession implements ports.IAuthService.
+func (a *AuthServiceImpl) IssueSession(ctx context.Context, user *userDomain.User) (*authDomain.LoginResult, error) {
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+	return a.startSession(ctx, user)
+}
+
+// startSession finishes a successful first login factor: it returns a
+// two-factor challenge when the account requires one, or issues tokens.
+func (a *AuthServiceImpl) startSession(ctx context.Context, user *userDomain.User) (*authDomain.LoginResult, error) {
+	if user.TwoFactorEnabled {
+		token, expiresAt, err := a.jwtManager.GenerateTwoFactorToken(user.ID.String(), twoFactorChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &authDomain.LoginResult{
+			User:      user,
+			Challenge: &authDomain.TwoFactorChallenge{ChallengeToken: token, ExpiresAt: expiresAt},
+		}, nil
+	}
+
+	var pair *authDomain.TokenPair
+	err := a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		pair, err = a.completeLogin(txCtx, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.LoginResult{User: user, Tokens: pair}, nil
+}
+
+// completeLogin stamps LastLogin and starts a new refresh token family. It must
+// run inside a transaction. The user may be a stale read, so only LastLogin is
+// written back.
+func (a *AuthServiceImpl) completeLogin(ctx context.Context, user *userDomain.User) (*authDomain.TokenPair, error) {
+	familyID, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	user.LastLogin = time.Now()
+	if err := a.userRepo.UpdateLastLogin(ctx, user.ID, user.LastLogin); err != nil {
+		return nil, err
+	}
+	return a.issueTokenPair(ctx, user, familyID)
+}
+
+// issueTokenPair signs an access token and stores the hash of a new refresh
+// token belonging to the given family.
+func (a *AuthServiceImpl) issueTokenPair(ctx context.Context, user *userDomain.User, familyID uuid.UUID) (*authDomain.TokenPair, error) {
+	var roleID string
+	if user.RoleID != nil {
+		roleID = user.RoleID.String()
+	}
+	accessToken, _, err := a.jwtManager.GenerateAccessToken(user.ID.String(), roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := tokens.NewOpaqueToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	stored := &authDomain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		Token:     tokens.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(a.config.RefreshTokenTTL),
+	}
+	if err := a.refreshTokenRepo.CreateRefreshToken(ctx, stored); err != nil {
+		return nil, err
+	}
+
+	return &authDomain.TokenPair{
+		AccessToken:           accessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(a.jwtManager.AccessTTL().Seconds()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+func checkUserStatus(user *userDomain.User) error {
+	switch user.Status {
+	case userDomain.USER_STATUS_INACTIVE, userDomain.USER_STATUS_ERASED:
+		return ErrUserInactive
+	case userDomain.USER_STATUS_BANNED:
+		return ErrUserBanned
+	}
+	return nil
+}
@@ -0,0 +1,449 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	lockoutRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	users map[uuid.UUID]*userDomain.User
+	// onLookup, when set, runs after GetUserByUsernameOrEmail has read a
+	// user, to change the stored user behind the caller's back.
+	onLookup func(user *userDomain.User)
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}}
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if u, ok := f.users[id]; ok {
+		clone := *u
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	return f.GetUserByID(ctx, id)
+}
+
+func (f *fakeUserRepo) GetUserByUsernameOrEmail(ctx context.Context, identifier string) (*userDomain.User, error) {
+	for _, u := range f.users {
+		if u.Username == identifier || u.Email == identifier {
+			clone := *u
+			if f.onLookup != nil {
+				f.onLookup(u)
+			}
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) ExistsByUsernameOrEmail(ctx context.Context, username, email string) (bool, error) {
+	for _, u := range f.users {
+		if u.Username == username || u.Username == email || strings.EqualFold(u.Email, email) || strings.EqualFold(u.Email, username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeUserRepo) ListUsersByRole(ctx context.Context, roleID uuid.UUID) ([]userDomain.User, error) {
+	var users []userDomain.User
+	for _, u := range f.users {
+		if u.RoleID != nil && *u.RoleID == roleID {
+			users = append(users, *u)
+		}
+	}
+	return users, nil
+}
+
+func (f *fakeUserRepo) ListUsers(ctx context.Context) (pagination.Pagination[[]userDomain.User], error) {
+	var users []userDomain.User
+	for _, u := range f.users {
+		users = append(users, *u)
+	}
+	return pagination.Pagination[[]userDomain.User]{Total: int64(len(users)), Rows: users}, nil
+}
+
+func (f *fakeUserRepo) CreateUser(ctx context.Context, payload *userDomain.User) error {
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return err
+	}
+	payload.ID = id
+	clone := *payload
+	f.users[id] = &clone
+	return nil
+}
+
+func (f *fakeUserRepo) UpdateUser(ctx context.Context, payload *userDomain.User) error {
+	clone := *payload
+	f.users[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeUserRepo) MarkEmailVerified(ctx context.Context, id uuid.UUID) error {
+	if u, ok := f.users[id]; ok {
+		u.EmailVerified = true
+	}
+	return nil
+}
+
+func (f *fakeUserRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if u, ok := f.users[id]; ok {
+		u.LastLogin = at
+	}
+	return nil
+}
+
+type fakeRefreshTokenRepo struct {
+	tokens map[uuid.UUID]*authDomain.RefreshToken
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{tokens: map[uuid.UUID]*authDomain.RefreshToken{}}
+}
+
+func (f *fakeRefreshTokenRepo) CreateRefreshToken(ctx context.Context, payload *authDomain.RefreshToken) error {
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return err
+	}
+	payload.ID = id
+	clone := *payload
+	f.tokens[id] = &clone
+	return nil
+}
+
+func (f *fakeRefreshTokenRepo) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*authDomain.RefreshToken, error) {
+	for _, t := range f.tokens {
+		if t.Token == tokenHash {
+			clone := *t
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRefreshTokenRepo) MarkRefreshTokenUsed(ctx context.Context, id uuid.UUID) error {
+	f.tokens[id].Used = true
+	return nil
+}
+
+func (f *fakeRefreshTokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	for _, t := range f.tokens {
+		if t.FamilyID == familyID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeRefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	for _, t := range f.tokens {
+		if t.UserID == userID {
+			t.Revoked = true
+		}
+	}
+	return nil
+}
+
+type fakeRecoveryCodeRepo struct {
+	codes map[uuid.UUID]*authDomain.TwoFactorRecoveryCode
+}
+
+func newFakeRecoveryCodeRepo() *fakeRecoveryCodeRepo {
+	return &fakeRecoveryCodeRepo{codes: map[uuid.UUID]*authDomain.TwoFactorRecoveryCode{}}
+}
+
+func (f *fakeRecoveryCodeRepo) CreateRecoveryCodes(ctx context.Context, payload []authDomain.TwoFactorRecoveryCode) error {
+	for i := range payload {
+		payload[i].ID = uuid.New()
+		clone := payload[i]
+		f.codes[clone.ID] = &clone
+	}
+	return nil
+}
+
+func (f *fakeRecoveryCodeRepo) ListUnusedRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]authDomain.TwoFactorRecoveryCode, error) {
+	var codes []authDomain.TwoFactorRecoveryCode
+	for _, c := range f.codes {
+		if c.UserID == userID && c.UsedAt == nil {
+			codes = append(codes, *c)
+		}
+	}
+	return codes, nil
+}
+
+func (f *fakeRecoveryCodeRepo) MarkRecoveryCodeUsed(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	f.codes[id].UsedAt = &now
+	return nil
+}
+
+func (f *fakeRecoveryCodeRepo) DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	for id, c := range f.codes {
+		if c.UserID == userID {
+			delete(f.codes, id)
+		}
+	}
+	return nil
+}
+
+// fakeOTPService issues the fixed code "123456" and remembers it per user and
+// purpose, or fails with issueErr when set.
+type fakeOTPService struct {
+	codes    map[string]string
+	issueErr error
+}
+
+func newFakeOTPService() *fakeOTPService {
+	return &fakeOTPService{codes: map[string]string{}}
+}
+
+func (f *fakeOTPService) Issue(ctx context.Context, payload messageDomain.IssueOTPDomain) (time.Time, error) {
+	if f.issueErr != nil {
+		return time.Time{}, f.issueErr
+	}
+	f.codes[payload.UserID.String()+string(payload.Purpose)] = "123456"
+	return time.Now().Add(time.Minute), nil
+}
+
+func (f *fakeOTPService) Verify(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, code string) error {
+	key := userID.String() + string(purpose)
+	if f.codes[key] == "" || f.codes[key] != code {
+		return otpServices.ErrOTPInvalid
+	}
+	delete(f.codes, key)
+	return nil
+}
+
+type fakeRoleRepo struct {
+	rolePorts.IRoleRepository
+	roles []userDomain.UserRole
+}
+
+func (f *fakeRoleRepo) GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error) {
+	for _, r := range f.roles {
+		if r.RoleName == name {
+			clone := r
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newTestAuthService(repo *fakeUserRepo) *AuthServiceImpl {
+	return newTestAuthServiceWithTokens(repo, newFakeRefreshTokenRepo())
+}
+
+func newTestAuthServiceWithTokens(repo *fakeUserRepo, refreshRepo *fakeRefreshTokenRepo) *AuthServiceImpl {
+	jwtManager := tokens.NewJWTManager("test-secret", "test", 15*time.Minute)
+	customer := userDomain.UserRole{RoleName: userDomain.ROLE_CUSTOMER}
+	customer.ID = uuid.New()
+	roleRepo := &fakeRoleRepo{roles: []userDomain.UserRole{customer}}
+	return NewAuthService(
+		repo,
+		roleRepo,
+		refreshRepo,
+		newFakeRecoveryCodeRepo(),
+		newFakeOTPService(),
+		lockoutServices.NewLockoutService(lockoutRepositories.NewMemoryLoginAttemptCounter(), lockoutServices.DefaultLockoutServiceConfig),
+		transactorstest.Transactor{},
+		jwtManager,
+		encryption.NewCipher("test-key"),
+		AuthServiceConfig{RefreshTokenTTL: time.Hour, TwoFactorIssuer: "test"},
+	).(*AuthServiceImpl)
+}
+
+var registerPayload = userDomain.RegisterUserDomain{
+	FirstName:   "John",
+	LastName:    "Doe",
+	PhoneNumber: "+1234567890",
+	Email:       "John@Example.com",
+	Username:    "john",
+	Password:    "@Test1234",
+}
+
+func TestRegisterHashesPassword(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	user, err := svc.Register(context.Background(), registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Password == registerPayload.Password {
+		t.Fatal("expected password to be hashed")
+	}
+	if user.Email != "john@example.com" {
+		t.Errorf("expected email to be normalised, got %q", user.Email)
+	}
+	if user.Status != userDomain.USER_STATUS_ACTIVE {
+		t.Errorf("expected new user to be active, got %q", user.Status)
+	}
+	if user.RoleID == nil {
+		t.Error("expected new user to get the customer role")
+	}
+
+	if _, err := svc.Register(context.Background(), registerPayload); !errors.Is(err, ErrUserAlreadyExists) {
+		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
+	}
+}
+
+func TestRegisterRefusesUsernameMatchingAnEmail(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	if _, err := svc.Register(context.Background(), registerPayload); err != nil {
+		t.Fatal(err)
+	}
+
+	squatter := registerPayload
+	squatter.Username = "JOHN@example.com"
+	squatter.Email = "mallory@example.com"
+	if err := utils.ValidateStruct(squatter); err == nil {
+		t.Error("expected a username with @ to fail validation")
+	}
+	if _, err := svc.Register(context.Background(), squatter); !errors.Is(err, ErrUserAlreadyExists) {
+		t.Fatalf("expected ErrUserAlreadyExists, got %v", err)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(context.Background(), registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := time.Now()
+	result, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tokens == nil || result.Tokens.AccessToken == "" || result.Tokens.RefreshToken == "" {
+		t.Fatal("expected login to issue a token pair")
+	}
+	if result.User.LastLogin.Before(before) {
+		t.Error("expected LastLogin to be stamped")
+	}
+	if stored := repo.users[registered.ID]; stored.LastLogin.Before(before) {
+		t.Error("expected LastLogin to be persisted")
+	}
+
+	if _, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "john", Password: "wrong"}, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "nobody", Password: "@Test1234"}, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestLoginKeepsConcurrentChanges(t *testing.T) {
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(context.Background(), registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The user is banned after the login has read the row.
+	repo.onLookup = func(user *userDomain.User) { user.Status = userDomain.USER_STATUS_BANNED }
+	if _, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	stored := repo.users[registered.ID]
+	if stored.Status != userDomain.USER_STATUS_BANNED {
+		t.Errorf("login reverted the ban, status = %q", stored.Status)
+	}
+	if stored.LastLogin.IsZero() {
+		t.Error("expected LastLogin to be persisted")
+	}
+}
+
+func TestLoginRefusesInactiveAndBannedUsers(t *testing.T) {
+	tests := []struct {
+		status userDomain.USER_STATUS
+		want   error
+	}{
+		{userDomain.USER_STATUS_INACTIVE, ErrUserInactive},
+		{userDomain.USER_STATUS_BANNED, ErrUserBanned},
+	}
+
+	for _, tt := range tests {
+		repo := newFakeUserRepo()
+		svc := newTestAuthService(repo)
+
+		registered, err := svc.Register(context.Background(), registerPayload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		repo.users[registered.ID].Status = tt.status
+
+		if _, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, ""); !errors.Is(err, tt.want) {
+			t.Errorf("status %q: expected %v, got %v", tt.status, tt.want, err)
+		}
+	}
+}
+
+func TestRefreshTokenRotationAndReuseDetection(t *testing.T) {
+	repo := newFakeUserRepo()
+	refreshRepo := newFakeRefreshTokenRepo()
+	svc := newTestAuthServiceWithTokens(repo, refreshRepo)
+
+	if _, err := svc.Register(context.Background(), registerPayload); err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.Login(context.Background(), userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := result.Tokens
+
+	second, err := svc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("expected a new refresh token")
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("expected ErrRefreshTokenReused, got %v", err)
+	}
+
+	// The reuse revoked the whole family, including the token issued by the rotation.
+	if _, err := svc.RefreshToken(context.Background(), second.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken after family revocation, got %v", err)
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), "unknown"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("expected ErrInvalidRefreshToken, got %v", err)
+	}
+}
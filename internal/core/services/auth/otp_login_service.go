@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+	"gorm.io/gorm"
+)
+
+// ResendEmailVerification implements ports.IAuthService.
+//
+// Unknown and already verified addresses are ignored without an error so the
+// endpoint cannot be used to find out which emails are registered.
+func (a *AuthServiceImpl) ResendEmailVerification(ctx context.Context, email string) error {
+	user, err := a.findUserByEmail(ctx, email)
+	if err != nil || user == nil || user.EmailVerified {
+		return err
+	}
+	a.sendEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION)
+	return nil
+}
+
+// VerifyEmail implements ports.IAuthService.
+func (a *AuthServiceImpl) VerifyEmail(ctx context.Context, payload authDomain.EmailCodeDomain) error {
+	user, err := a.findUserByEmail(ctx, payload.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return otpServices.ErrOTPInvalid
+	}
+	if user.EmailVerified {
+		return nil
+	}
+
+	if err := a.otpService.Verify(ctx, user.ID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, payload.Code); err != nil {
+		return err
+	}
+	return a.userRepo.MarkEmailVerified(ctx, user.ID)
+}
+
+// RequestLoginOTP implements ports.IAuthService.
+//
+// Like ResendEmailVerification it does not reveal whether the address exists
+// or whether the account may log in.
+func (a *AuthServiceImpl) RequestLoginOTP(ctx context.Context, email string) error {
+	user, err := a.findUserByEmail(ctx, email)
+	if err != nil || user == nil || checkUserStatus(user) != nil {
+		return err
+	}
+	a.sendEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_PASSWORDLESS_LOGIN)
+	return nil
+}
+
+// LoginWithOTP implements ports.IAuthService.
+//
+// A valid code proves control of the mailbox, so it also verifies the email
+// address. Accounts with two-factor authentication still get a challenge.
+func (a *AuthServiceImpl) LoginWithOTP(ctx context.Context, payload authDomain.EmailCodeDomain) (*authDomain.LoginResult, error) {
+	user, err := a.findUserByEmail(ctx, payload.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, otpServices.ErrOTPInvalid
+	}
+
+	if err := a.otpService.Verify(ctx, user.ID, messageDomain.OTP_PURPOSE_PASSWORDLESS_LOGIN, payload.Code); err != nil {
+		return nil, err
+	}
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	if !user.EmailVerified {
+		if err := a.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+	return a.startSession(ctx, user)
+}
+
+func (a *AuthServiceImpl) issueEmailOTP(ctx context.Context, user *userDomain.User, purpose messageDomain.OTP_PURPOSE) error {
+	_, err := a.otpService.Issue(ctx, messageDomain.IssueOTPDomain{
+		UserID:      user.ID,
+		Purpose:     purpose,
+		Channel:     messageDomain.OTP_CHANNEL_EMAIL,
+		Destination: user.Email,
+	})
+	return err
+}
+
+// sendEmailOTP issues a code for an endpoint that must answer the same for
+// unknown addresses. Its failures, e.g. too many codes requested, are only
+// logged: reporting them would tell the caller the address has an account.
+func (a *AuthServiceImpl) sendEmailOTP(ctx context.Context, user *userDomain.User, purpose messageDomain.OTP_PURPOSE) {
+	if err := a.issueEmailOTP(ctx, user, purpose); err != nil {
+		log.Printf("failed to send %s code to user %s: %v", purpose, user.ID, err)
+	}
+}
+
+// findUserByEmail returns nil without an error when no user has the address.
+func (a *AuthServiceImpl) findUserByEmail(ctx context.Context, email string) (*userDomain.User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := a.userRepo.GetUserByUsernameOrEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, email) {
+		return nil, nil
+	}
+	return user, nil
+}
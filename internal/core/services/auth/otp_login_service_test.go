@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+)
+
+func TestSignupEmailVerification(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registered.EmailVerified {
+		t.Fatal("expected a new user to be unverified")
+	}
+
+	if err := svc.VerifyEmail(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "000000"}); !errors.Is(err, otpServices.ErrOTPInvalid) {
+		t.Fatalf("expected ErrOTPInvalid, got %v", err)
+	}
+	if err := svc.VerifyEmail(ctx, authDomain.EmailCodeDomain{Email: "John@Example.com", Code: "123456"}); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.users[registered.ID].EmailVerified {
+		t.Error("expected the email to be verified")
+	}
+}
+
+func TestPasswordlessLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	if _, err := svc.Register(ctx, registerPayload); err != nil {
+		t.Fatal(err)
+	}
+
+	// Unknown addresses are accepted silently.
+	if err := svc.RequestLoginOTP(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("expected no error for an unknown address, got %v", err)
+	}
+
+	if err := svc.RequestLoginOTP(ctx, "john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := svc.LoginWithOTP(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "123456"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tokens == nil {
+		t.Fatal("expected tokens after a passwordless login")
+	}
+	if !result.User.EmailVerified {
+		t.Error("expected a passwordless login to verify the email")
+	}
+
+	if _, err := svc.LoginWithOTP(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "123456"}); !errors.Is(err, otpServices.ErrOTPInvalid) {
+		t.Errorf("expected the code to be single use, got %v", err)
+	}
+}
+
+// TestEmailCodesKeepConcurrentChanges changes the password after the user has
+// been read, as a reset running at the same time would, and checks that
+// verifying the email does not write the old password back.
+func TestEmailCodesKeepConcurrentChanges(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.onLookup = func(user *userDomain.User) { user.Password = "changed" }
+
+	if err := svc.VerifyEmail(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "123456"}); err != nil {
+		t.Fatal(err)
+	}
+	if stored := repo.users[registered.ID]; stored.Password != "changed" || !stored.EmailVerified {
+		t.Errorf("VerifyEmail: password = %q, verified = %v", stored.Password, stored.EmailVerified)
+	}
+
+	repo.onLookup = nil
+	repo.users[registered.ID].EmailVerified = false
+	repo.users[registered.ID].Password = registered.Password
+	if err := svc.RequestLoginOTP(ctx, "john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	repo.onLookup = func(user *userDomain.User) { user.Password = "changed" }
+	if _, err := svc.LoginWithOTP(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "123456"}); err != nil {
+		t.Fatal(err)
+	}
+	if stored := repo.users[registered.ID]; stored.Password != "changed" || !stored.EmailVerified {
+		t.Errorf("LoginWithOTP: password = %q, verified = %v", stored.Password, stored.EmailVerified)
+	}
+}
+
+// TestOTPRequestsDoNotRevealAccounts checks that a registered address gets the
+// same answer as an unknown one when no code can be sent.
+func TestOTPRequestsDoNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	if _, err := svc.Register(ctx, registerPayload); err != nil {
+		t.Fatal(err)
+	}
+	svc.otpService.(*fakeOTPService).issueErr = otpServices.ErrOTPRateLimited
+
+	if err := svc.RequestLoginOTP(ctx, "john@example.com"); err != nil {
+		t.Errorf("RequestLoginOTP: got %v, want no error", err)
+	}
+	if err := svc.ResendEmailVerification(ctx, "john@example.com"); err != nil {
+		t.Errorf("ResendEmailVerification: got %v, want no error", err)
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrPasswordUnchanged      = errors.New("new password must differ from the current password")
+)
+
+// ForgotPassword implements ports.IAuthService.
+//
+// A reset code is emailed when the address belongs to a user; unknown
+// addresses are accepted silently so the endpoint does not reveal accounts.
+// For the same reason a code that cannot be sent is not reported either.
+func (a *AuthServiceImpl) ForgotPassword(ctx context.Context, email string) error {
+	user, err := a.findUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+	a.sendEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_PASSWORD_RESET)
+	return nil
+}
+
+// ResetPassword implements ports.IAuthService.
+//
+// Every refresh token of the user is revoked, so sessions that may belong to
+// whoever knew the old password end when their access token expires. A login
+// lockout on the account is cleared as well.
+func (a *AuthServiceImpl) ResetPassword(ctx context.Context, payload authDomain.ResetPasswordDomain) error {
+	user, err := a.findUserByEmail(ctx, payload.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return otpServices.ErrOTPInvalid
+	}
+
+	if err := a.otpService.Verify(ctx, user.ID, messageDomain.OTP_PURPOSE_PASSWORD_RESET, payload.Code); err != nil {
+		return err
+	}
+
+	hash, err := argon2id.CreateHash(payload.NewPassword, a.config.PasswordHashParams)
+	if err != nil {
+		return err
+	}
+	err = a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := a.getUserForUpdate(txCtx, user.ID)
+		if err != nil {
+			return err
+		}
+		user.Password = hash
+		// The code was delivered to the mailbox, which proves the address.
+		user.EmailVerified = true
+		if err := a.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		return a.refreshTokenRepo.RevokeUserRefreshTokens(txCtx, user.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return a.clearAccountLock(ctx, user)
+}
+
+// ChangePassword implements ports.IAuthService.
+//
+// All refresh tokens are revoked, including the caller's, so every device has
+// to log in again with the new password.
+func (a *AuthServiceImpl) ChangePassword(ctx context.Context, userID uuid.UUID, payload authDomain.ChangePasswordDomain) error {
+	if payload.CurrentPassword == payload.NewPassword {
+		return ErrPasswordUnchanged
+	}
+	hash, err := argon2id.CreateHash(payload.NewPassword, a.config.PasswordHashParams)
+	if err != nil {
+		return err
+	}
+
+	return a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := a.getUserForUpdate(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		match, err := argon2id.ComparePasswordAndHash(payload.CurrentPassword, user.Password)
+		if err != nil || !match {
+			return ErrInvalidCurrentPassword
+		}
+
+		user.Password = hash
+		if err := a.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		return a.refreshTokenRepo.RevokeUserRefreshTokens(txCtx, user.ID)
+	})
+}
+
+// upgradePasswordHash rehashes a password that was just verified because its
+// stored hash uses weaker parameters than configured. Nothing is written when
+// the password changed since it was read.
+func (a *AuthServiceImpl) upgradePasswordHash(ctx context.Context, user *userDomain.User, password string) error {
+	hash, err := argon2id.CreateHash(password, a.config.PasswordHashParams)
+	if err != nil {
+		return err
+	}
+	return a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		current, err := a.getUserForUpdate(txCtx, user.ID)
+		if err != nil {
+			return err
+		}
+		if current.Password != user.Password {
+			return nil
+		}
+		current.Password = hash
+		if err := a.userRepo.UpdateUser(txCtx, current); err != nil {
+			return err
+		}
+		user.Password = hash
+		return nil
+	})
+}
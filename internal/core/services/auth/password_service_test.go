@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+)
+
+func TestResetPasswordRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	refreshRepo := newFakeRefreshTokenRepo()
+	svc := newTestAuthServiceWithTokens(repo, refreshRepo)
+
+	if _, err := svc.Register(ctx, registerPayload); err != nil {
+		t.Fatal(err)
+	}
+	session, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.ForgotPassword(ctx, "nobody@example.com"); err != nil {
+		t.Fatalf("expected no error for an unknown address, got %v", err)
+	}
+	if err := svc.ForgotPassword(ctx, "john@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ResetPassword(ctx, authDomain.ResetPasswordDomain{
+		Email:       "john@example.com",
+		Code:        "123456",
+		NewPassword: "@NewPass1234",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.RefreshToken(ctx, session.Tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the old session to be revoked, got %v", err)
+	}
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, ""); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected the old password to be rejected, got %v", err)
+	}
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@NewPass1234"}, ""); err != nil {
+		t.Errorf("expected the new password to work, got %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	refreshRepo := newFakeRefreshTokenRepo()
+	svc := newTestAuthServiceWithTokens(repo, refreshRepo)
+
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.ChangePassword(ctx, registered.ID, authDomain.ChangePasswordDomain{
+		CurrentPassword: "wrong-password",
+		NewPassword:     "@NewPass1234",
+	}); !errors.Is(err, ErrInvalidCurrentPassword) {
+		t.Fatalf("expected ErrInvalidCurrentPassword, got %v", err)
+	}
+	if err := svc.ChangePassword(ctx, registered.ID, authDomain.ChangePasswordDomain{
+		CurrentPassword: "@Test1234",
+		NewPassword:     "@NewPass1234",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.RefreshToken(ctx, session.Tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("expected the session to be revoked, got %v", err)
+	}
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@NewPass1234"}, ""); err != nil {
+		t.Errorf("expected the new password to work, got %v", err)
+	}
+}
+
+func TestLoginUpgradesWeakPasswordHash(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+	svc.config.PasswordHashParams = &argon2id.Params{Memory: 8 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+	user, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weak, err := argon2id.CreateHash("@Test1234", &argon2id.Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.users[user.ID].Password = weak
+
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	upgraded := repo.users[user.ID].Password
+	_, params, err := argon2id.CheckHash("@Test1234", upgraded)
+	if err != nil || params.Iterations != 2 {
+		t.Fatalf("hash was not upgraded: %s", upgraded)
+	}
+
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, ""); err != nil {
+		t.Fatalf("login with the upgraded hash: %v", err)
+	}
+	if repo.users[user.ID].Password != upgraded {
+		t.Error("a hash with the configured params should not be rewritten")
+	}
+}
+
+func TestForgotPasswordDoesNotRevealAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	if _, err := svc.Register(ctx, registerPayload); err != nil {
+		t.Fatal(err)
+	}
+	svc.otpService.(*fakeOTPService).issueErr = otpServices.ErrOTPRateLimited
+
+	if err := svc.ForgotPassword(ctx, "john@example.com"); err != nil {
+		t.Errorf("got %v, want the answer given to unknown addresses", err)
+	}
+}
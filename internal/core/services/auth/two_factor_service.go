@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/totp"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled        = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled       = errors.New("two-factor enrolment has not been started")
+	ErrInvalidTwoFactorCode       = errors.New("invalid two-factor code")
+	ErrInvalidTwoFactorChallenge  = errors.New("invalid or expired two-factor challenge")
+	ErrInvalidTwoFactorRecovery   = errors.New("invalid recovery code")
+	ErrTwoFactorSecretUnavailable = errors.New("two-factor secret cannot be read")
+)
+
+const (
+	// twoFactorChallengeTTL is how long a user has to enter the second factor
+	// after the password step of a login.
+	twoFactorChallengeTTL = 5 * time.Minute
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5 // 8 base32 characters
+	qrCodeSize        = 256
+)
+
+// EnrollTwoFactor implements ports.IAuthService.
+//
+// A new secret is stored (encrypted) every time enrolment starts, so an
+// unfinished enrolment can simply be restarted. It takes effect once confirmed.
+func (a *AuthServiceImpl) EnrollTwoFactor(ctx context.Context, userID uuid.UUID) (*authDomain.TwoFactorEnrollment, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := a.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *userDomain.User
+	err = a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if user, err = a.getUserForUpdate(txCtx, userID); err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		user.TwoFactorSecret = encrypted
+		user.TwoFactorStep = 0
+		return a.userRepo.UpdateUser(txCtx, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uri := totp.URI(secret, a.config.TwoFactorIssuer, user.Email)
+	png, err := totp.QRCodePNG(uri, qrCodeSize)
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.TwoFactorEnrollment{
+		Secret:     secret,
+		OTPAuthURL: uri,
+		QRCode:     "data:image/png;base64," + base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// ConfirmTwoFactor implements ports.IAuthService.
+//
+// A valid code for the pending secret enables two-factor authentication and
+// replaces any previous recovery codes. The plaintext codes are only returned here.
+func (a *AuthServiceImpl) ConfirmTwoFactor(ctx context.Context, userID uuid.UUID, code string) (*authDomain.TwoFactorRecoveryCodes, error) {
+	var codes []string
+	err := a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := a.getUserForUpdate(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if user.TwoFactorEnabled {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		if user.TwoFactorSecret == "" {
+			return ErrTwoFactorNotEnrolled
+		}
+		if err := a.checkTOTP(user, code); err != nil {
+			return err
+		}
+
+		user.TwoFactorEnabled = true
+		if err := a.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		codes, err = a.replaceRecoveryCodes(txCtx, user.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.TwoFactorRecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// DisableTwoFactor implements ports.IAuthService.
+func (a *AuthServiceImpl) DisableTwoFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	return a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := a.getUserForUpdate(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return ErrTwoFactorNotEnabled
+		}
+		if err := a.checkTOTP(user, code); err != nil {
+			return err
+		}
+
+		user.TwoFactorEnabled = false
+		user.TwoFactorSecret = ""
+		user.TwoFactorStep = 0
+		if err := a.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		return a.recoveryCodeRepo.DeleteUserRecoveryCodes(txCtx, user.ID)
+	})
+}
+
+// VerifyTwoFactorLogin implements ports.IAuthService.
+//
+// It completes a login started by Login with either a TOTP code or one of the
+// user's unused recovery codes, and only then issues tokens. Wrong codes count
+// as failed logins of the account, and a challenge is refused after a few of
+// them, so the code cannot be guessed within the lifetime of the challenge.
+func (a *AuthServiceImpl) VerifyTwoFactorLogin(ctx context.Context, payload authDomain.TwoFactorLoginDomain) (*authDomain.LoginResult, error) {
+	claims, err := a.jwtManager.VerifyTwoFactorToken(payload.ChallengeToken)
+	if err != nil || claims.ID == "" {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+	account := userID.String()
+	if err := a.lockoutService.CheckChallenge(ctx, account, claims.ID); err != nil {
+		return nil, err
+	}
+
+	var (
+		user    *userDomain.User
+		pair    *authDomain.TokenPair
+		codeErr error
+		locked  bool
+	)
+	err = a.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if user, err = a.getUserForUpdate(txCtx, userID); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return ErrInvalidTwoFactorChallenge
+			}
+			return err
+		}
+		if err := checkUserStatus(user); err != nil {
+			return err
+		}
+		if !user.TwoFactorEnabled {
+			return ErrInvalidTwoFactorChallenge
+		}
+
+		if payload.Code != "" {
+			codeErr = a.checkTOTP(user, payload.Code)
+		} else {
+			codeErr = a.redeemRecoveryCode(txCtx, user.ID, payload.RecoveryCode)
+		}
+		if codeErr != nil {
+			// Counted outside the transaction, which is rolled back.
+			return codeErr
+		}
+		if err := a.lockoutService.RegisterSuccess(txCtx, account); err != nil {
+			return err
+		}
+
+		pair, err = a.completeLogin(txCtx, user)
+		return err
+	})
+	if codeErr != nil {
+		if locked, err = a.lockoutService.RegisterChallengeFailure(ctx, account, claims.ID); err != nil {
+			return nil, err
+		}
+		if locked {
+			if err := a.issueEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_ACCOUNT_UNLOCK); err != nil {
+				log.Printf("failed to send unlock code to user %s: %v", user.ID, err)
+			}
+			return nil, lockoutServices.ErrAccountLocked
+		}
+		return nil, codeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.LoginResult{User: user, Tokens: pair}, nil
+}
+
+func (a *AuthServiceImpl) getUserForUpdate(ctx context.Context, userID uuid.UUID) (*userDomain.User, error) {
+	user, err := a.userRepo.GetUserByIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkTOTP validates code against the user's secret and records the matched
+// time step on user, so the caller must save the user afterwards. Codes from a
+// step that was already used are rejected.
+func (a *AuthServiceImpl) checkTOTP(user *userDomain.User, code string) error {
+	secret, err := a.cipher.Decrypt(user.TwoFactorSecret)
+	if err != nil {
+		return ErrTwoFactorSecretUnavailable
+	}
+	ok, step, err := totp.Validate(secret, code, time.Now())
+	if err != nil {
+		return ErrTwoFactorSecretUnavailable
+	}
+	if !ok || step <= user.TwoFactorStep {
+		return ErrInvalidTwoFactorCode
+	}
+	user.TwoFactorStep = step
+	return nil
+}
+
+func (a *AuthServiceImpl) redeemRecoveryCode(ctx context.Context, userID uuid.UUID, code string) error {
+	code = normalizeRecoveryCode(code)
+	stored, err := a.recoveryCodeRepo.ListUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range stored {
+		if match, err := argon2id.ComparePasswordAndHash(code, candidate.CodeHash); err == nil && match {
+			return a.recoveryCodeRepo.MarkRecoveryCodeUsed(ctx, candidate.ID)
+		}
+	}
+	return ErrInvalidTwoFactorRecovery
+}
+
+func (a *AuthServiceImpl) replaceRecoveryCodes(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	if err := a.recoveryCodeRepo.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]authDomain.TwoFactorRecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		hash, err := argon2id.CreateHash(normalizeRecoveryCode(code), a.config.PasswordHashParams)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+		records = append(records, authDomain.TwoFactorRecoveryCode{UserID: userID, CodeHash: hash})
+	}
+
+	if err := a.recoveryCodeRepo.CreateRecoveryCodes(ctx, records); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// newRecoveryCode returns a code formatted as "xxxx-xxxx" for readability.
+func newRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate recovery code: %w", err)
+	}
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+	return code[:4] + "-" + code[4:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	lockoutRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/lockout"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/totp"
+)
+
+func TestTwoFactorEnrolmentAndLogin(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enrollment, err := svc.EnrollTwoFactor(ctx, registered.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored := repo.users[registered.ID].TwoFactorSecret; stored == enrollment.Secret || stored == "" {
+		t.Fatal("expected the secret to be stored encrypted")
+	}
+
+	// Not enabled until confirmed.
+	result, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Challenge != nil {
+		t.Fatal("expected no challenge before enrolment is confirmed")
+	}
+
+	now := time.Now()
+	code, _ := totp.Code(enrollment.Secret, now)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if _, err := svc.ConfirmTwoFactor(ctx, registered.ID, wrong); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected ErrInvalidTwoFactorCode, got %v", err)
+	}
+	recovery, err := svc.ConfirmTwoFactor(ctx, registered.ID, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recovery.RecoveryCodes) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(recovery.RecoveryCodes))
+	}
+
+	result, err = svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Challenge == nil || result.Tokens != nil {
+		t.Fatal("expected a challenge instead of tokens")
+	}
+	challenge := result.Challenge.ChallengeToken
+
+	// The code used to confirm enrolment cannot be replayed.
+	if _, err := svc.VerifyTwoFactorLogin(ctx, authDomain.TwoFactorLoginDomain{ChallengeToken: challenge, Code: code}); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("expected ErrInvalidTwoFactorCode for a replayed code, got %v", err)
+	}
+
+	next, _ := totp.Code(enrollment.Secret, now.Add(totp.Period*time.Second))
+	result, err = svc.VerifyTwoFactorLogin(ctx, authDomain.TwoFactorLoginDomain{ChallengeToken: challenge, Code: next})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Tokens == nil {
+		t.Fatal("expected tokens after the second factor")
+	}
+
+	if _, err := svc.VerifyTwoFactorLogin(ctx, authDomain.TwoFactorLoginDomain{ChallengeToken: result.Tokens.AccessToken, Code: next}); !errors.Is(err, ErrInvalidTwoFactorChallenge) {
+		t.Errorf("expected an access token to be rejected as challenge, got %v", err)
+	}
+}
+
+func TestTwoFactorRecoveryCodeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeUserRepo()
+	svc := newTestAuthService(repo)
+
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := svc.EnrollTwoFactor(ctx, registered.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := totp.Code(enrollment.Secret, time.Now())
+	recovery, err := svc.ConfirmTwoFactor(ctx, registered.ID, code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := authDomain.TwoFactorLoginDomain{
+		ChallengeToken: result.Challenge.ChallengeToken,
+		RecoveryCode:   recovery.RecoveryCodes[0],
+	}
+
+	if _, err := svc.VerifyTwoFactorLogin(ctx, payload); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.VerifyTwoFactorLogin(ctx, payload); !errors.Is(err, ErrInvalidTwoFactorRecovery) {
+		t.Errorf("expected a used recovery code to be rejected, got %v", err)
+	}
+}
+
+func TestTwoFactorCodeCannotBeGuessed(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	svc.lockoutService = lockoutServices.NewLockoutService(
+		lockoutRepositories.NewMemoryLoginAttemptCounter(),
+		lockoutServices.LockoutServiceConfig{
+			Window:            time.Hour,
+			Account:           lockoutServices.LockoutPolicy{FreeFailures: 4, MaxFailures: 5, LockDuration: time.Hour},
+			IP:                lockoutServices.LockoutPolicy{FreeFailures: 10, MaxFailures: 20, LockDuration: time.Hour},
+			ChallengeFailures: 3,
+		},
+	)
+	registered, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enrollment, err := svc.EnrollTwoFactor(ctx, registered.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := totp.Code(enrollment.Secret, time.Now())
+	if _, err := svc.ConfirmTwoFactor(ctx, registered.ID, code); err != nil {
+		t.Fatal(err)
+	}
+	login := func() string {
+		t.Helper()
+		result, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "")
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		return result.Challenge.ChallengeToken
+	}
+	guess := func(challenge string) error {
+		_, err := svc.VerifyTwoFactorLogin(ctx, authDomain.TwoFactorLoginDomain{ChallengeToken: challenge, RecoveryCode: "aaaa-aaaa"})
+		return err
+	}
+
+	// A challenge is refused after a few wrong codes.
+	challenge := login()
+	for i := 0; i < 3; i++ {
+		if err := guess(challenge); !errors.Is(err, ErrInvalidTwoFactorRecovery) {
+			t.Fatalf("guess %d: got %v, want ErrInvalidTwoFactorRecovery", i, err)
+		}
+	}
+	if err := guess(challenge); !errors.Is(err, lockoutServices.ErrChallengeExhausted) {
+		t.Fatalf("got %v, want ErrChallengeExhausted", err)
+	}
+
+	// Logging in again does not reset the count of the account, which locks.
+	challenge = login()
+	if err := guess(challenge); !errors.Is(err, ErrInvalidTwoFactorRecovery) {
+		t.Fatalf("got %v, want ErrInvalidTwoFactorRecovery", err)
+	}
+	if err := guess(challenge); !errors.Is(err, lockoutServices.ErrAccountLocked) {
+		t.Fatalf("got %v, want ErrAccountLocked", err)
+	}
+	next, _ := totp.Code(enrollment.Secret, time.Now().Add(totp.Period*time.Second))
+	if _, err := svc.VerifyTwoFactorLogin(ctx, authDomain.TwoFactorLoginDomain{ChallengeToken: challenge, Code: next}); !errors.Is(err, lockoutServices.ErrAccountLocked) {
+		t.Fatalf("right code on a locked account: got %v, want ErrAccountLocked", err)
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+	otpServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/otp"
+)
+
+// RequestAccountUnlock implements ports.IAuthService.
+//
+// An unlock code is only sent while the account is locked; the response is the
+// same either way so the endpoint does not reveal accounts or lock state.
+func (a *AuthServiceImpl) RequestAccountUnlock(ctx context.Context, email string) error {
+	user, err := a.findUserByEmail(ctx, email)
+	if err != nil || user == nil {
+		return err
+	}
+	err = a.lockoutService.Check(ctx, user.ID.String(), "")
+	if !errors.Is(err, lockoutServices.ErrAccountLocked) {
+		return nil
+	}
+	return a.issueEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_ACCOUNT_UNLOCK)
+}
+
+// UnlockAccount implements ports.IAuthService.
+func (a *AuthServiceImpl) UnlockAccount(ctx context.Context, payload authDomain.EmailCodeDomain) error {
+	user, err := a.findUserByEmail(ctx, payload.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return otpServices.ErrOTPInvalid
+	}
+	if err := a.otpService.Verify(ctx, user.ID, messageDomain.OTP_PURPOSE_ACCOUNT_UNLOCK, payload.Code); err != nil {
+		return err
+	}
+	return a.clearAccountLock(ctx, user)
+}
+
+// registerLoginFailure locks after a failed password login that used up the
+// failures, and emails an unlock code when it locks a known account. It returns ErrInvalidCredentials unless
+// the counter itself fails.
+func (a *AuthServiceImpl) registerLoginFailure(ctx context.Context, user *userDomain.User, account, clientIP string) error {
+	locked, err := a.lockoutService.RegisterFailure(ctx, account, clientIP)
+	if err != nil {
+		return err
+	}
+	if locked && user != nil {
+		if err := a.issueEmailOTP(ctx, user, messageDomain.OTP_PURPOSE_ACCOUNT_UNLOCK); err != nil {
+			log.Printf("failed to send unlock code to user %s: %v", user.ID, err)
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+func (a *AuthServiceImpl) clearAccountLock(ctx context.Context, user *userDomain.User) error {
+	err := a.lockoutService.ClearLock(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, user.ID.String())
+	if errors.Is(err, lockoutServices.ErrLockNotFound) {
+		return nil
+	}
+	return err
+}
+
+// lockoutAccount is the subject failed logins are counted under: the user ID,
+// so that username and email share one counter, or the normalised identifier
+// when no user matches.
+func lockoutAccount(user *userDomain.User, identifier string) string {
+	if user != nil {
+		return user.ID.String()
+	}
+	return strings.ToLower(identifier)
+}
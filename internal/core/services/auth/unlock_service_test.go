@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	lockoutRepositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/lockout"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	lockoutServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/lockout"
+)
+
+func TestLoginLockoutAndUnlock(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	svc.lockoutService = lockoutServices.NewLockoutService(
+		lockoutRepositories.NewMemoryLoginAttemptCounter(),
+		lockoutServices.LockoutServiceConfig{
+			Window:  time.Hour,
+			Account: lockoutServices.LockoutPolicy{FreeFailures: 1, MaxFailures: 3, LockDuration: time.Hour},
+			IP:      lockoutServices.LockoutPolicy{FreeFailures: 10, MaxFailures: 20, LockDuration: time.Hour},
+		},
+	)
+	user, err := svc.Register(ctx, registerPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Username and email share one counter.
+	for _, identifier := range []string{"john", "john@example.com", "john"} {
+		_, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: identifier, Password: "wrong"}, "10.0.0.1")
+		if !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("wrong password err = %v, want ErrInvalidCredentials", err)
+		}
+	}
+	_, err = svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "10.0.0.2")
+	if !errors.Is(err, lockoutServices.ErrAccountLocked) {
+		t.Fatalf("locked account err = %v, want ErrAccountLocked", err)
+	}
+
+	otp := svc.otpService.(*fakeOTPService)
+	if otp.codes[user.ID.String()+"account_unlock"] == "" {
+		t.Fatal("expected an unlock code to be emailed")
+	}
+	if err := svc.UnlockAccount(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "000000"}); err == nil {
+		t.Fatal("expected a wrong unlock code to be rejected")
+	}
+	if err := svc.UnlockAccount(ctx, authDomain.EmailCodeDomain{Email: "john@example.com", Code: "123456"}); err != nil {
+		t.Fatalf("UnlockAccount: %v", err)
+	}
+	if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "10.0.0.2"); err != nil {
+		t.Fatalf("login after unlock: %v", err)
+	}
+}
+
+func TestLoginLockoutCountsUnknownAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	svc.lockoutService = lockoutServices.NewLockoutService(
+		lockoutRepositories.NewMemoryLoginAttemptCounter(),
+		lockoutServices.LockoutServiceConfig{
+			Window:  time.Hour,
+			Account: lockoutServices.LockoutPolicy{FreeFailures: 0, MaxFailures: 2, LockDuration: time.Hour},
+			IP:      lockoutServices.LockoutPolicy{FreeFailures: 10, MaxFailures: 20, LockDuration: time.Hour},
+		},
+	)
+
+	// An unknown identifier locks like a real one, so lockouts reveal nothing.
+	var err error
+	for i := 0; i < 3; i++ {
+		_, err = svc.Login(ctx, userDomain.LoginUserDomain{Username: "Nobody", Password: "wrong"}, "")
+	}
+	if !errors.Is(err, lockoutServices.ErrAccountLocked) {
+		t.Errorf("err = %v, want ErrAccountLocked", err)
+	}
+}
+
+// TestSuccessfulLoginsReleaseTheirAttempts checks that the failure reserved
+// before the password check is taken back when the password is right, so
+// customers sharing an address do not lock it by logging in.
+func TestSuccessfulLoginsReleaseTheirAttempts(t *testing.T) {
+	ctx := context.Background()
+	svc := newTestAuthService(newFakeUserRepo())
+	svc.lockoutService = lockoutServices.NewLockoutService(
+		lockoutRepositories.NewMemoryLoginAttemptCounter(),
+		lockoutServices.LockoutServiceConfig{
+			Window:  time.Hour,
+			Account: lockoutServices.LockoutPolicy{FreeFailures: 1, MaxFailures: 2, LockDuration: time.Hour},
+			IP:      lockoutServices.LockoutPolicy{FreeFailures: 1, MaxFailures: 2, LockDuration: time.Hour},
+		},
+	)
+	if _, err := svc.Register(ctx, registerPayload); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := svc.Login(ctx, userDomain.LoginUserDomain{Username: "john", Password: "@Test1234"}, "10.0.0.1"); err != nil {
+			t.Fatalf("login %d: %v", i+1, err)
+		}
+	}
+}
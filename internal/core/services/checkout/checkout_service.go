@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	addressPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/checkout"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	productPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCartEmpty       = errors.New("cart is empty")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrOrderNotFound   = errors.New("order not found")
+	ErrOrderNotPending = errors.New("only a pending order can be confirmed or cancelled")
+	ErrItemUnavailable = errors.New("an item in the cart is no longer sold")
+	// ErrOrderExpired is returned when payment arrives after the stock of
+	// the order was released; the order is cancelled.
+	ErrOrderExpired = errors.New("the order expired before it was paid and has been cancelled")
+)
+
+type CheckoutServiceImpl struct {
+	cartRepo       ports.ICartRepository
+	orderRepo      ports.IOrderRepository
+	userRepo       userPorts.IUserRepository
+	productRepo    productPorts.IProductRepository
+	addressService addressPorts.IAddressService
+	inventory      inventoryPorts.IInventoryService
+	transactor     transactors.IDatabaseTransactor
+	now            func() time.Time
+}
+
+func NewCheckoutService(
+	cartRepo ports.ICartRepository,
+	orderRepo ports.IOrderRepository,
+	userRepo userPorts.IUserRepository,
+	productRepo productPorts.IProductRepository,
+	addressService addressPorts.IAddressService,
+	inventory inventoryPorts.IInventoryService,
+	transactor transactors.IDatabaseTransactor,
+) ports.ICheckoutService {
+	return &CheckoutServiceImpl{
+		cartRepo:       cartRepo,
+		orderRepo:      orderRepo,
+		userRepo:       userRepo,
+		productRepo:    productRepo,
+		addressService: addressService,
+		inventory:      inventory,
+		transactor:     transactor,
+		now:            time.Now,
+	}
+}
+
+// Checkout implements ports.ICheckoutService.
+//
+// The chosen addresses are copied into the shipping and billing information
+// of the order, so later edits to the address book do not change it. Items
+// are priced from the catalog at checkout, not at the price they had when
+// they were put in the cart. The stock of the items is reserved in the same
+// transaction, so an order is only placed when every unit of it can be
+// delivered, and each item records the warehouse the allocation strategy
+// ships it from.
+func (s *CheckoutServiceImpl) Checkout(ctx context.Context, userID uuid.UUID, payload orderDomain.CheckoutDomain) (*orderDomain.Order, error) {
+	var order *orderDomain.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		shipping, err := s.resolveAddress(ctx, userID, payload.ShippingAddressID, userDomain.ADDRESS_KIND_SHIPPING)
+		if err != nil {
+			return err
+		}
+		billing, err := s.resolveAddress(ctx, userID, payload.BillingAddressID, userDomain.ADDRESS_KIND_BILLING)
+		if err != nil {
+			return err
+		}
+
+		cart, err := s.cartRepo.GetActiveCartForUpdate(ctx, userID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCartEmpty
+			}
+			return err
+		}
+		cartItems, err := s.cartRepo.ListCartItems(ctx, cart.ID)
+		if err != nil {
+			return err
+		}
+		if len(cartItems) == 0 {
+			return ErrCartEmpty
+		}
+		items, err := s.priceItems(ctx, cartItems)
+		if err != nil {
+			return err
+		}
+
+		order = &orderDomain.Order{
+			Status:    orderDomain.ORDER_STATUS_PENDING,
+			OrderDate: s.now(),
+			CreatedBy: userID,
+			Items:     items,
+			ShippingInfo: &orderDomain.ShippingInfo{
+				Address:         shipping.Format(),
+				AddressSnapshot: shipping.PostalAddress,
+				Method:          payload.ShippingMethod,
+			},
+			BillingInfo: &orderDomain.BillingInfo{
+				UserID:          userID,
+				Address:         billing.Format(),
+				AddressSnapshot: billing.PostalAddress,
+				Phone:           billing.Phone,
+				Email:           user.Email,
+				Method:          payload.PaymentMethod,
+			},
+		}
+		for _, item := range order.Items {
+			order.TotalPrice += item.TotalPrice
+		}
+		order.TotalPrice = roundCents(order.TotalPrice + order.ShippingInfo.ShippingCost)
+		if err := s.orderRepo.CreateOrder(ctx, order); err != nil {
+			return err
+		}
+		if lines := reservationLines(order.Items); len(lines) > 0 {
+			reservations, err := s.inventory.Reserve(ctx, &userID, order.ID, orderDomain.ReserveDomain{
+				Destination: shipping.PostalAddress,
+				Lines:       lines,
+			})
+			if err != nil {
+				return err
+			}
+			assignWarehouses(order.Items, reservations)
+			if err := s.orderRepo.UpdateOrderItemWarehouses(ctx, order.Items); err != nil {
+				return err
+			}
+		}
+
+		cart.Status = cartDomain.CART_STATUS_COMPLETED
+		return s.cartRepo.UpdateCart(ctx, cart)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ConfirmOrder implements ports.ICheckoutService.
+func (s *CheckoutServiceImpl) ConfirmOrder(ctx context.Context, actorID, orderID uuid.UUID) (*orderDomain.Order, error) {
+	var order *orderDomain.Order
+	expired := false
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if order, err = s.lockPendingOrder(ctx, orderID); err != nil {
+			return err
+		}
+		if len(reservationLines(order.Items)) > 0 {
+			err := s.inventory.Commit(ctx, &actorID, order.ID)
+			if errors.Is(err, inventoryServices.ErrReservationExpired) || errors.Is(err, inventoryServices.ErrReservationNotFound) {
+				// The stock is, or is about to be, someone else's; the
+				// order cannot be fulfilled as placed.
+				expired = true
+				if err := s.inventory.Release(ctx, &actorID, order.ID); err != nil {
+					return err
+				}
+				order.Status = orderDomain.ORDER_STATUS_CANCELLED
+				return s.orderRepo.UpdateOrderStatus(ctx, order)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		order.Status = orderDomain.ORDER_STATUS_PAID
+		return s.orderRepo.UpdateOrderStatus(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, ErrOrderExpired
+	}
+	return order, nil
+}
+
+// CancelOrder implements ports.ICheckoutService.
+//
+// Orders of other users are reported as not found, whatever their status.
+func (s *CheckoutServiceImpl) CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*orderDomain.Order, error) {
+	var order *orderDomain.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if order, err = s.lockOrder(ctx, orderID); err != nil {
+			return err
+		}
+		if order.CreatedBy != userID {
+			return ErrOrderNotFound
+		}
+		if order.Status != orderDomain.ORDER_STATUS_PENDING {
+			return ErrOrderNotPending
+		}
+		if err := s.inventory.Release(ctx, &userID, order.ID); err != nil {
+			return err
+		}
+		order.Status = orderDomain.ORDER_STATUS_CANCELLED
+		return s.orderRepo.UpdateOrderStatus(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+func (s *CheckoutServiceImpl) lockPendingOrder(ctx context.Context, orderID uuid.UUID) (*orderDomain.Order, error) {
+	order, err := s.lockOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != orderDomain.ORDER_STATUS_PENDING {
+		return nil, ErrOrderNotPending
+	}
+	return order, nil
+}
+
+func (s *CheckoutServiceImpl) lockOrder(ctx context.Context, orderID uuid.UUID) (*orderDomain.Order, error) {
+	order, err := s.orderRepo.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return order, nil
+}
+
+// priceItems turns the cart items into order items at the current price of
+// their SKU, or of their product when they have none. Items whose product or
+// SKU was deleted cannot be ordered.
+func (s *CheckoutServiceImpl) priceItems(ctx context.Context, cartItems []cartDomain.CartItem) ([]orderDomain.OrderItem, error) {
+	products := make(map[uuid.UUID]*productDomain.Product)
+	items := make([]orderDomain.OrderItem, 0, len(cartItems))
+	for _, cartItem := range cartItems {
+		product, ok := products[cartItem.ProductID]
+		if !ok {
+			var err error
+			if product, err = s.productRepo.GetProduct(ctx, cartItem.ProductID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return nil, ErrItemUnavailable
+				}
+				return nil, err
+			}
+			products[cartItem.ProductID] = product
+		}
+		price, ok := currentPrice(product, cartItem.SKUID)
+		if !ok {
+			return nil, ErrItemUnavailable
+		}
+		total := math.Max(price*float64(cartItem.Quantity)-cartItem.DiscountApplied, 0)
+		items = append(items, orderDomain.OrderItem{
+			ProductID:  cartItem.ProductID,
+			SKUID:      cartItem.SKUID,
+			Quantity:   cartItem.Quantity,
+			UnitPrice:  price,
+			TotalPrice: roundCents(total),
+		})
+	}
+	return items, nil
+}
+
+func currentPrice(product *productDomain.Product, skuID *uuid.UUID) (float64, bool) {
+	if skuID == nil {
+		return product.Price, true
+	}
+	for _, sku := range product.SKUs {
+		if sku.ID == *skuID {
+			return sku.Price, true
+		}
+	}
+	return 0, false
+}
+
+// resolveAddress returns the chosen address book entry, or the default one of
+// the given kind when none was chosen.
+func (s *CheckoutServiceImpl) resolveAddress(ctx context.Context, userID uuid.UUID, addressID *uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error) {
+	if addressID != nil {
+		return s.addressService.GetAddress(ctx, userID, *addressID)
+	}
+	return s.addressService.GetDefaultAddress(ctx, userID, kind)
+}
+
+// reservationLines asks for the stock of the items sold as SKUs. Products
+// without options have no SKU and no stock to reserve.
+func reservationLines(items []orderDomain.OrderItem) []orderDomain.ReservationLine {
+	var lines []orderDomain.ReservationLine
+	for _, item := range items {
+		if item.SKUID != nil {
+			lines = append(lines, orderDomain.ReservationLine{SKUID: *item.SKUID, Quantity: item.Quantity})
+		}
+	}
+	return lines
+}
+
+// assignWarehouses ships every item sold as a SKU from the warehouse its
+// units are reserved at.
+func assignWarehouses(items []orderDomain.OrderItem, reservations []orderDomain.InventoryReservation) {
+	warehouses := make(map[uuid.UUID]uuid.UUID, len(reservations))
+	for _, reservation := range reservations {
+		warehouses[reservation.SKUID] = reservation.WarehouseID
+	}
+	for i := range items {
+		if items[i].SKUID == nil {
+			continue
+		}
+		if warehouseID, ok := warehouses[*items[i].SKUID]; ok {
+			items[i].WarehouseID = &warehouseID
+		}
+	}
+}
+
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
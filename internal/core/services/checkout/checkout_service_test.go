@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	cartDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/shopping_cart"
+	addressPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/address"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	productPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	addressServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/address"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeCartRepo struct {
+	carts map[uuid.UUID]*cartDomain.Cart
+	items map[uuid.UUID][]cartDomain.CartItem
+}
+
+func (f *fakeCartRepo) GetActiveCartForUpdate(ctx context.Context, userID uuid.UUID) (*cartDomain.Cart, error) {
+	for _, cart := range f.carts {
+		if cart.UserID == userID && cart.Status == cartDomain.CART_STATUS_ACTIVE {
+			clone := *cart
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCartRepo) ListCartItems(ctx context.Context, cartID uuid.UUID) ([]cartDomain.CartItem, error) {
+	return f.items[cartID], nil
+}
+
+func (f *fakeCartRepo) UpdateCart(ctx context.Context, payload *cartDomain.Cart) error {
+	clone := *payload
+	f.carts[payload.ID] = &clone
+	return nil
+}
+
+type fakeOrderRepo struct {
+	orders []orderDomain.Order
+}
+
+func (f *fakeOrderRepo) CreateOrder(ctx context.Context, payload *orderDomain.Order) error {
+	payload.ID = uuid.New()
+	for i := range payload.Items {
+		payload.Items[i].OrderID = payload.ID
+	}
+	f.orders = append(f.orders, *payload)
+	return nil
+}
+
+func (f *fakeOrderRepo) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (*orderDomain.Order, error) {
+	for _, order := range f.orders {
+		if order.ID == id {
+			clone := order
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOrderRepo) UpdateOrderStatus(ctx context.Context, payload *orderDomain.Order) error {
+	for i := range f.orders {
+		if f.orders[i].ID == payload.ID {
+			f.orders[i].Status = payload.Status
+		}
+	}
+	return nil
+}
+
+func (f *fakeOrderRepo) UpdateOrderItemWarehouses(ctx context.Context, items []orderDomain.OrderItem) error {
+	for i := range f.orders {
+		if len(items) > 0 && f.orders[i].ID == items[0].OrderID {
+			f.orders[i].Items = append([]orderDomain.OrderItem(nil), items...)
+		}
+	}
+	return nil
+}
+
+// fakeInventory keeps the reserved units of each reference, and fails
+// reservations beyond the stock it was given. Every unit is reserved at the
+// one warehouse it has.
+type fakeInventory struct {
+	inventoryPorts.IInventoryService
+	warehouseID uuid.UUID
+	stock       map[uuid.UUID]int
+	reserved    map[uuid.UUID][]orderDomain.ReservationLine
+	destination map[uuid.UUID]string
+	expired     map[uuid.UUID]bool
+	sold        map[uuid.UUID]int
+}
+
+func (f *fakeInventory) Reserve(ctx context.Context, actorID *uuid.UUID, reference uuid.UUID, payload orderDomain.ReserveDomain) ([]orderDomain.InventoryReservation, error) {
+	lines := payload.Lines
+	for _, line := range lines {
+		if f.stock[line.SKUID] < line.Quantity {
+			return nil, inventoryServices.ErrOutOfStock
+		}
+	}
+	for _, line := range lines {
+		f.stock[line.SKUID] -= line.Quantity
+	}
+	f.reserved[reference] = lines
+	f.destination[reference] = payload.Destination.PostalCode
+	reservations := make([]orderDomain.InventoryReservation, 0, len(lines))
+	for _, line := range lines {
+		reservations = append(reservations, orderDomain.InventoryReservation{SKUID: line.SKUID, WarehouseID: f.warehouseID, Quantity: line.Quantity})
+	}
+	return reservations, nil
+}
+
+func (f *fakeInventory) Commit(ctx context.Context, actorID *uuid.UUID, reference uuid.UUID) error {
+	if f.expired[reference] {
+		return inventoryServices.ErrReservationExpired
+	}
+	if len(f.reserved[reference]) == 0 {
+		return inventoryServices.ErrReservationNotFound
+	}
+	for _, line := range f.reserved[reference] {
+		f.sold[line.SKUID] += line.Quantity
+	}
+	delete(f.reserved, reference)
+	return nil
+}
+
+func (f *fakeInventory) Release(ctx context.Context, actorID *uuid.UUID, reference uuid.UUID) error {
+	for _, line := range f.reserved[reference] {
+		f.stock[line.SKUID] += line.Quantity
+	}
+	delete(f.reserved, reference)
+	return nil
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if user, ok := f.users[id]; ok {
+		return user, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeProductRepo is the catalog the cart items are priced from.
+type fakeProductRepo struct {
+	productPorts.IProductRepository
+	products map[uuid.UUID]*productDomain.Product
+}
+
+func (f *fakeProductRepo) GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	product, ok := f.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return product, nil
+}
+
+// add puts a product with the given base price in the catalog.
+func (f *fakeProductRepo) add(price float64) uuid.UUID {
+	product := &productDomain.Product{Price: price}
+	product.ID = uuid.New()
+	f.products[product.ID] = product
+	return product.ID
+}
+
+// fakeAddressService serves a fixed address book.
+type fakeAddressService struct {
+	addressPorts.IAddressService
+	addresses []userDomain.UserAddress
+}
+
+func (f *fakeAddressService) GetAddress(ctx context.Context, userID, addressID uuid.UUID) (*userDomain.UserAddress, error) {
+	for _, a := range f.addresses {
+		if a.ID == addressID && a.UserID == userID {
+			clone := a
+			return &clone, nil
+		}
+	}
+	return nil, addressServices.ErrAddressNotFound
+}
+
+func (f *fakeAddressService) GetDefaultAddress(ctx context.Context, userID uuid.UUID, kind userDomain.ADDRESS_KIND) (*userDomain.UserAddress, error) {
+	for _, a := range f.addresses {
+		if a.UserID == userID && ((kind == userDomain.ADDRESS_KIND_BILLING && a.IsDefaultBilling) || (kind == userDomain.ADDRESS_KIND_SHIPPING && a.IsDefaultShipping)) {
+			clone := a
+			return &clone, nil
+		}
+	}
+	return nil, addressServices.ErrNoDefaultAddress
+}
+
+type checkoutFixture struct {
+	service   *CheckoutServiceImpl
+	carts     *fakeCartRepo
+	orders    *fakeOrderRepo
+	products  *fakeProductRepo
+	addresses *fakeAddressService
+	inventory *fakeInventory
+	userID    uuid.UUID
+	cartID    uuid.UUID
+}
+
+func newCheckoutFixture() *checkoutFixture {
+	userID, cartID := uuid.New(), uuid.New()
+	home := userDomain.UserAddress{UserID: userID, IsDefaultBilling: true, IsDefaultShipping: true}
+	home.ID = uuid.New()
+	home.Recipient, home.Line1, home.City, home.Region, home.PostalCode, home.CountryCode, home.Phone =
+		"Somchai Jaidee", "99/1 Sukhumvit Road", "Khlong Toei", "Bangkok", "10110", "TH", "+66 81 234 5678"
+	office := home
+	office.ID = uuid.New()
+	office.IsDefaultBilling, office.IsDefaultShipping = false, false
+	office.Line1 = "1 Silom Road"
+
+	products := &fakeProductRepo{products: map[uuid.UUID]*productDomain.Product{}}
+
+	f := &checkoutFixture{
+		carts: &fakeCartRepo{
+			carts: map[uuid.UUID]*cartDomain.Cart{cartID: {ID: cartID, UserID: userID, Status: cartDomain.CART_STATUS_ACTIVE}},
+			items: map[uuid.UUID][]cartDomain.CartItem{cartID: {
+				{CartID: cartID, ProductID: products.add(19.99), Quantity: 2, UnitPrice: 19.99},
+				{CartID: cartID, ProductID: products.add(50), Quantity: 1, UnitPrice: 50, DiscountApplied: 5},
+			}},
+		},
+		orders:    &fakeOrderRepo{},
+		products:  products,
+		addresses: &fakeAddressService{addresses: []userDomain.UserAddress{home, office}},
+		inventory: &fakeInventory{
+			warehouseID: uuid.New(),
+			stock:       map[uuid.UUID]int{},
+			reserved:    map[uuid.UUID][]orderDomain.ReservationLine{},
+			destination: map[uuid.UUID]string{},
+			expired:     map[uuid.UUID]bool{},
+			sold:        map[uuid.UUID]int{},
+		},
+		userID: userID,
+		cartID: cartID,
+	}
+	users := &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{userID: {Email: "somchai@example.com"}}}
+	f.service = NewCheckoutService(f.carts, f.orders, users, f.products, f.addresses, f.inventory, transactorstest.Transactor{}).(*CheckoutServiceImpl)
+	f.service.now = func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) }
+	return f
+}
+
+func TestCheckoutSnapshotsAddresses(t *testing.T) {
+	f := newCheckoutFixture()
+	office := f.addresses.addresses[1]
+
+	order, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{
+		BillingAddressID: &office.ID,
+		ShippingMethod:   "Express",
+		PaymentMethod:    "Credit Card",
+	})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	if order.TotalPrice != 84.98 || len(order.Items) != 2 || order.Items[1].TotalPrice != 45 {
+		t.Fatalf("order totals = %v, items = %+v", order.TotalPrice, order.Items)
+	}
+	if order.ShippingInfo.AddressSnapshot.Line1 != "99/1 Sukhumvit Road" {
+		t.Fatalf("shipping should default to the default shipping address, got %+v", order.ShippingInfo.AddressSnapshot)
+	}
+	if order.BillingInfo.AddressSnapshot.Line1 != "1 Silom Road" || order.BillingInfo.Email != "somchai@example.com" {
+		t.Fatalf("billing = %+v", order.BillingInfo)
+	}
+	if order.ShippingInfo.Address != "Somchai Jaidee, 99/1 Sukhumvit Road, Khlong Toei, Bangkok, 10110, TH" {
+		t.Fatalf("formatted address = %q", order.ShippingInfo.Address)
+	}
+
+	// Editing the address book afterwards does not change the order.
+	f.addresses.addresses[0].Line1 = "Moved away"
+	if f.orders.orders[0].ShippingInfo.AddressSnapshot.Line1 != "99/1 Sukhumvit Road" {
+		t.Fatal("the order should keep its own copy of the address")
+	}
+	if f.carts.carts[f.cartID].Status != cartDomain.CART_STATUS_COMPLETED {
+		t.Fatal("the cart should be completed")
+	}
+	if _, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{ShippingMethod: "Express", PaymentMethod: "Credit Card"}); !errors.Is(err, ErrCartEmpty) {
+		t.Fatalf("second checkout: got %v, want ErrCartEmpty", err)
+	}
+}
+
+func TestCheckoutRejectsForeignAddress(t *testing.T) {
+	f := newCheckoutFixture()
+	foreign := uuid.New()
+
+	_, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{
+		ShippingAddressID: &foreign,
+		ShippingMethod:    "Standard",
+		PaymentMethod:     "Bank Transfer",
+	})
+	if !errors.Is(err, addressServices.ErrAddressNotFound) {
+		t.Fatalf("got %v, want ErrAddressNotFound", err)
+	}
+	if len(f.orders.orders) != 0 {
+		t.Fatal("no order should be created")
+	}
+}
+
+func TestCheckoutPricesFromTheCatalog(t *testing.T) {
+	f := newCheckoutFixture()
+	items := f.carts.items[f.cartID]
+	f.products.products[items[0].ProductID].Price = 24.99
+
+	order := f.checkout(t)
+	if order.Items[0].UnitPrice != 24.99 || order.Items[0].TotalPrice != 49.98 || order.TotalPrice != 94.98 {
+		t.Fatalf("order totals = %v, items = %+v; want the catalog price", order.TotalPrice, order.Items)
+	}
+}
+
+func TestCheckoutRejectsItemsNoLongerSold(t *testing.T) {
+	f := newCheckoutFixture()
+	f.withSKUItem(1, 5)
+	productID := f.carts.items[f.cartID][0].ProductID
+	f.products.products[productID].SKUs = nil
+
+	_, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{ShippingMethod: "Standard", PaymentMethod: "Credit Card"})
+	if !errors.Is(err, ErrItemUnavailable) {
+		t.Fatalf("deleted SKU: got %v, want ErrItemUnavailable", err)
+	}
+	delete(f.products.products, productID)
+	_, err = f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{ShippingMethod: "Standard", PaymentMethod: "Credit Card"})
+	if !errors.Is(err, ErrItemUnavailable) {
+		t.Fatalf("deleted product: got %v, want ErrItemUnavailable", err)
+	}
+	if len(f.orders.orders) != 0 {
+		t.Fatal("no order should be created")
+	}
+}
+
+// withSKUItem replaces the cart with a single SKU item of the given quantity,
+// and stocks the SKU with stock units.
+func (f *checkoutFixture) withSKUItem(quantity, stock int) uuid.UUID {
+	skuID, productID := uuid.New(), f.products.add(8)
+	sku := productDomain.ProductSKU{ProductID: productID, Price: 10}
+	sku.ID = skuID
+	f.products.products[productID].SKUs = []productDomain.ProductSKU{sku}
+	f.carts.items[f.cartID] = []cartDomain.CartItem{
+		{CartID: f.cartID, ProductID: productID, SKUID: &skuID, Quantity: quantity, UnitPrice: 10},
+	}
+	f.inventory.stock[skuID] = stock
+	return skuID
+}
+
+func (f *checkoutFixture) checkout(t *testing.T) *orderDomain.Order {
+	t.Helper()
+	order, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{ShippingMethod: "Standard", PaymentMethod: "Credit Card"})
+	if err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+	return order
+}
+
+func TestCheckoutReservesStockUntilConfirmed(t *testing.T) {
+	f := newCheckoutFixture()
+	skuID := f.withSKUItem(3, 5)
+
+	order := f.checkout(t)
+	if got := f.inventory.reserved[order.ID]; len(got) != 1 || got[0].SKUID != skuID || got[0].Quantity != 3 {
+		t.Fatalf("reserved = %+v", got)
+	}
+	if f.inventory.destination[order.ID] != "10110" {
+		t.Fatalf("reserved for %q, want the shipping postal code", f.inventory.destination[order.ID])
+	}
+	if item := f.orders.orders[0].Items[0]; item.WarehouseID == nil || *item.WarehouseID != f.inventory.warehouseID {
+		t.Fatalf("item warehouse = %v, want %s", item.WarehouseID, f.inventory.warehouseID)
+	}
+
+	confirmed, err := f.service.ConfirmOrder(context.Background(), uuid.New(), order.ID)
+	if err != nil {
+		t.Fatalf("ConfirmOrder: %v", err)
+	}
+	if confirmed.Status != orderDomain.ORDER_STATUS_PAID || f.inventory.sold[skuID] != 3 {
+		t.Fatalf("status = %s, sold = %d", confirmed.Status, f.inventory.sold[skuID])
+	}
+	if _, err := f.service.ConfirmOrder(context.Background(), uuid.New(), order.ID); !errors.Is(err, ErrOrderNotPending) {
+		t.Fatalf("second confirmation: got %v, want ErrOrderNotPending", err)
+	}
+}
+
+func TestCheckoutFailsWithoutStock(t *testing.T) {
+	f := newCheckoutFixture()
+	f.withSKUItem(3, 2)
+
+	_, err := f.service.Checkout(context.Background(), f.userID, orderDomain.CheckoutDomain{ShippingMethod: "Standard", PaymentMethod: "Credit Card"})
+	if !errors.Is(err, inventoryServices.ErrOutOfStock) {
+		t.Fatalf("got %v, want ErrOutOfStock", err)
+	}
+}
+
+func TestConfirmExpiredOrderCancelsIt(t *testing.T) {
+	f := newCheckoutFixture()
+	skuID := f.withSKUItem(2, 2)
+	order := f.checkout(t)
+	f.inventory.expired[order.ID] = true
+
+	if _, err := f.service.ConfirmOrder(context.Background(), uuid.New(), order.ID); !errors.Is(err, ErrOrderExpired) {
+		t.Fatalf("got %v, want ErrOrderExpired", err)
+	}
+	if f.orders.orders[0].Status != orderDomain.ORDER_STATUS_CANCELLED {
+		t.Fatalf("status = %s, want cancelled", f.orders.orders[0].Status)
+	}
+	if f.inventory.stock[skuID] != 2 || f.inventory.sold[skuID] != 0 {
+		t.Fatalf("stock = %d, sold = %d; the units should be back", f.inventory.stock[skuID], f.inventory.sold[skuID])
+	}
+}
+
+func TestCancelOrderReleasesStock(t *testing.T) {
+	f := newCheckoutFixture()
+	skuID := f.withSKUItem(2, 2)
+	order := f.checkout(t)
+
+	if _, err := f.service.CancelOrder(context.Background(), uuid.New(), order.ID); !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("cancel by another user: got %v, want ErrOrderNotFound", err)
+	}
+	cancelled, err := f.service.CancelOrder(context.Background(), f.userID, order.ID)
+	if err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+	if cancelled.Status != orderDomain.ORDER_STATUS_CANCELLED || f.inventory.stock[skuID] != 2 {
+		t.Fatalf("status = %s, stock = %d", cancelled.Status, f.inventory.stock[skuID])
+	}
+}
+
+func TestCancelOrderOfAnotherUserIsNotFound(t *testing.T) {
+	f := newCheckoutFixture()
+	order := f.checkout(t)
+	if _, err := f.service.CancelOrder(context.Background(), f.userID, order.ID); err != nil {
+		t.Fatalf("CancelOrder: %v", err)
+	}
+
+	// The order is no longer pending, but a stranger must not learn that.
+	if _, err := f.service.CancelOrder(context.Background(), uuid.New(), order.ID); !errors.Is(err, ErrOrderNotFound) {
+		t.Fatalf("got %v, want ErrOrderNotFound", err)
+	}
+}
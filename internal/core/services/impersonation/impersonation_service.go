@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/impersonation"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	roleServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound              = errors.New("user not found")
+	ErrCannotImpersonateSelf     = errors.New("you cannot impersonate yourself")
+	ErrUserNotActive             = errors.New("only active users can be impersonated")
+	ErrCannotImpersonateStaff    = errors.New("staff and service accounts cannot be impersonated")
+	ErrCannotImpersonateGreater  = errors.New("the user holds permissions you do not have")
+	ErrImpersonationNotFound     = errors.New("impersonation session not found")
+	ErrImpersonationEnded        = errors.New("the impersonation session has ended")
+	ErrImpersonationAlreadyEnded = errors.New("the impersonation session was already stopped")
+)
+
+// Actions recorded in the change log.
+const (
+	auditEntityUser         = "User"
+	auditFieldImpersonation = "impersonation"
+	actionImpersonateStart  = "IMPERSONATION_START"
+	actionImpersonateStop   = "IMPERSONATION_STOP"
+)
+
+// ImpersonationServiceConfig holds the tunables of ImpersonationServiceImpl.
+type ImpersonationServiceConfig struct {
+	TokenTTL time.Duration // Lifetime of an impersonation token; there is no refresh
+}
+
+var DefaultImpersonationServiceConfig = ImpersonationServiceConfig{
+	TokenTTL: 15 * time.Minute,
+}
+
+type ImpersonationServiceImpl struct {
+	impersonationRepo ports.IImpersonationRepository
+	userRepo          userPorts.IUserRepository
+	roleService       rolePorts.IRoleService
+	changeLogRepo     auditPorts.IChangeLogRepository
+	transactor        transactors.IDatabaseTransactor
+	jwtManager        *tokens.JWTManager
+	config            ImpersonationServiceConfig
+	now               func() time.Time
+}
+
+func NewImpersonationService(
+	impersonationRepo ports.IImpersonationRepository,
+	userRepo userPorts.IUserRepository,
+	roleService rolePorts.IRoleService,
+	changeLogRepo auditPorts.IChangeLogRepository,
+	transactor transactors.IDatabaseTransactor,
+	jwtManager *tokens.JWTManager,
+	config ImpersonationServiceConfig,
+) ports.IImpersonationService {
+	return &ImpersonationServiceImpl{
+		impersonationRepo: impersonationRepo,
+		userRepo:          userRepo,
+		roleService:       roleService,
+		changeLogRepo:     changeLogRepo,
+		transactor:        transactor,
+		jwtManager:        jwtManager,
+		config:            config,
+		now:               time.Now,
+	}
+}
+
+// StartImpersonation implements ports.IImpersonationService.
+func (s *ImpersonationServiceImpl) StartImpersonation(ctx context.Context, actorID, userID uuid.UUID, payload authDomain.StartImpersonationDomain) (*authDomain.ImpersonationToken, error) {
+	if actorID == userID {
+		return nil, ErrCannotImpersonateSelf
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.Status != userDomain.USER_STATUS_ACTIVE {
+		return nil, ErrUserNotActive
+	}
+	if err := s.checkImpersonable(ctx, actorID, user); err != nil {
+		return nil, err
+	}
+
+	var roleID string
+	if user.RoleID != nil {
+		roleID = user.RoleID.String()
+	}
+	session := &authDomain.ImpersonationSession{
+		ActorID:   actorID,
+		UserID:    userID,
+		Reason:    payload.Reason,
+		ExpiresAt: s.now().Add(s.config.TokenTTL),
+	}
+	var accessToken string
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.impersonationRepo.CreateImpersonationSession(txCtx, session); err != nil {
+			return err
+		}
+		var err error
+		accessToken, _, err = s.jwtManager.GenerateImpersonationToken(userID.String(), roleID, actorID.String(), session.ID.String(), s.config.TokenTTL)
+		if err != nil {
+			return err
+		}
+		return s.audit(txCtx, session, actionImpersonateStart, payload.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &authDomain.ImpersonationToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(s.config.TokenTTL.Seconds()),
+		Session:     session,
+	}, nil
+}
+
+// StopImpersonation implements ports.IImpersonationService.
+func (s *ImpersonationServiceImpl) StopImpersonation(ctx context.Context, sessionID, actorID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		session, err := s.impersonationRepo.GetImpersonationSessionForUpdate(txCtx, sessionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImpersonationNotFound
+			}
+			return err
+		}
+		if session.ActorID != actorID {
+			return ErrImpersonationNotFound
+		}
+		if session.EndedAt != nil {
+			return ErrImpersonationAlreadyEnded
+		}
+
+		now := s.now()
+		session.EndedAt = &now
+		if err := s.impersonationRepo.UpdateImpersonationSession(txCtx, session); err != nil {
+			return err
+		}
+		return s.audit(txCtx, session, actionImpersonateStop, "")
+	})
+}
+
+// CheckImpersonation implements ports.IImpersonationService.
+func (s *ImpersonationServiceImpl) CheckImpersonation(ctx context.Context, sessionID, actorID uuid.UUID) error {
+	session, err := s.impersonationRepo.GetImpersonationSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrImpersonationNotFound
+		}
+		return err
+	}
+	if session.ActorID != actorID {
+		return ErrImpersonationNotFound
+	}
+	if !session.IsActive(s.now()) {
+		return ErrImpersonationEnded
+	}
+	return nil
+}
+
+// checkImpersonable refuses service accounts, users holding an admin
+// permission, and users holding any permission the actor lacks, so that
+// impersonating someone never grants the actor more than they already have.
+// Grants that are not effective yet count too, as they may become so while
+// the session lasts.
+func (s *ImpersonationServiceImpl) checkImpersonable(ctx context.Context, actorID uuid.UUID, user *userDomain.User) error {
+	if user.RoleID == nil {
+		return nil
+	}
+	role, err := s.roleService.GetRole(ctx, *user.RoleID)
+	if err != nil {
+		if errors.Is(err, roleServices.ErrRoleNotFound) {
+			return nil
+		}
+		return err
+	}
+	if role.RoleName == userDomain.ROLE_SERVICE_ACCOUNT {
+		return ErrCannotImpersonateStaff
+	}
+	permissions, err := s.roleService.ListRolePermissions(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	for _, permission := range permissions {
+		if userDomain.IsAdminPermission(permission.Permission) {
+			return ErrCannotImpersonateStaff
+		}
+	}
+	for _, permission := range permissions {
+		held, err := s.roleService.HasPermission(ctx, actorID, permission.Permission)
+		if err != nil {
+			return err
+		}
+		if !held {
+			return ErrCannotImpersonateGreater
+		}
+	}
+	return nil
+}
+
+func (s *ImpersonationServiceImpl) audit(ctx context.Context, session *authDomain.ImpersonationSession, action, description string) error {
+	entry := loggingDomain.NewChangeLog(auditEntityUser, session.UserID, auditFieldImpersonation, "", session.ID.String(), action, session.ActorID, description)
+	entry.Timestamp = s.now()
+	return s.changeLogRepo.CreateChangeLog(ctx, entry)
+}
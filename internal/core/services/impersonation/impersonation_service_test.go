@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeImpersonationRepo struct {
+	sessions map[uuid.UUID]*authDomain.ImpersonationSession
+}
+
+func (f *fakeImpersonationRepo) CreateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.sessions[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeImpersonationRepo) GetImpersonationSession(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error) {
+	if session, ok := f.sessions[id]; ok {
+		clone := *session
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeImpersonationRepo) GetImpersonationSessionForUpdate(ctx context.Context, id uuid.UUID) (*authDomain.ImpersonationSession, error) {
+	return f.GetImpersonationSession(ctx, id)
+}
+
+func (f *fakeImpersonationRepo) UpdateImpersonationSession(ctx context.Context, payload *authDomain.ImpersonationSession) error {
+	clone := *payload
+	f.sessions[payload.ID] = &clone
+	return nil
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if user, ok := f.users[id]; ok {
+		clone := *user
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeRoleService grants permissions to roles, for the impersonated users,
+// and to users directly, for the actors.
+type fakeRoleService struct {
+	rolePorts.IRoleService
+	roles           map[uuid.UUID]*userDomain.UserRole
+	rolePermissions map[uuid.UUID][]string
+	permissions     map[uuid.UUID][]string
+}
+
+func (f *fakeRoleService) GetRole(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error) {
+	if role, ok := f.roles[id]; ok {
+		return role, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleService) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error) {
+	var permissions []userDomain.UserPermission
+	for _, permission := range f.rolePermissions[roleID] {
+		permissions = append(permissions, userDomain.UserPermission{RoleID: roleID, Permission: permission})
+	}
+	return permissions, nil
+}
+
+func (f *fakeRoleService) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	for _, held := range f.permissions[userID] {
+		if held == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type fakeChangeLogRepo struct {
+	auditPorts.IChangeLogRepository
+	entries []loggingDomain.ChangeLogs
+}
+
+func (f *fakeChangeLogRepo) CreateChangeLog(ctx context.Context, payload *loggingDomain.ChangeLogs) error {
+	f.entries = append(f.entries, *payload)
+	return nil
+}
+
+type impersonationFixture struct {
+	service  *ImpersonationServiceImpl
+	jwt      *tokens.JWTManager
+	sessions *fakeImpersonationRepo
+	users    *fakeUserRepo
+	roles    *fakeRoleService
+	logs     *fakeChangeLogRepo
+	roleID   uuid.UUID
+	clock    time.Time
+	adminID  uuid.UUID
+	userID   uuid.UUID
+}
+
+func newImpersonationFixture() *impersonationFixture {
+	customer := &userDomain.UserRole{RoleName: userDomain.ROLE_CUSTOMER}
+	customer.ID = uuid.New()
+
+	f := &impersonationFixture{
+		jwt:      tokens.NewJWTManager("secret", "test", time.Hour),
+		sessions: &fakeImpersonationRepo{sessions: map[uuid.UUID]*authDomain.ImpersonationSession{}},
+		users:    &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}},
+		roles: &fakeRoleService{
+			roles:           map[uuid.UUID]*userDomain.UserRole{customer.ID: customer},
+			rolePermissions: map[uuid.UUID][]string{},
+			permissions:     map[uuid.UUID][]string{},
+		},
+		logs:    &fakeChangeLogRepo{},
+		clock:   time.Now(),
+		adminID: uuid.New(),
+		userID:  uuid.New(),
+		roleID:  customer.ID,
+	}
+	user := &userDomain.User{Username: "somchai", Status: userDomain.USER_STATUS_ACTIVE, RoleID: &customer.ID}
+	user.ID = f.userID
+	f.users.users[f.userID] = user
+
+	f.service = NewImpersonationService(f.sessions, f.users, f.roles, f.logs, transactorstest.Transactor{}, f.jwt, DefaultImpersonationServiceConfig).(*ImpersonationServiceImpl)
+	f.service.now = func() time.Time { return f.clock }
+	return f
+}
+
+func TestStartImpersonation(t *testing.T) {
+	f := newImpersonationFixture()
+	ctx := context.Background()
+
+	token, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, authDomain.StartImpersonationDomain{Reason: "ticket #4411"})
+	if err != nil {
+		t.Fatalf("StartImpersonation: %v", err)
+	}
+	claims, err := f.jwt.VerifyAccessToken(token.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyAccessToken: %v", err)
+	}
+	if !claims.IsImpersonation() || claims.Subject != f.userID.String() || claims.Actor.Subject != f.adminID.String() || claims.ID != token.Session.ID.String() {
+		t.Fatalf("claims = %+v", claims)
+	}
+	if token.ExpiresIn != int64(DefaultImpersonationServiceConfig.TokenTTL.Seconds()) {
+		t.Fatalf("ExpiresIn = %d", token.ExpiresIn)
+	}
+	entry := f.logs.entries[0]
+	if entry.Action != actionImpersonateStart || entry.UserID != f.adminID || entry.EntityID != f.userID || entry.Description != "ticket #4411" {
+		t.Fatalf("audit entry = %+v", entry)
+	}
+	if err := f.service.CheckImpersonation(ctx, token.Session.ID, f.adminID); err != nil {
+		t.Fatalf("CheckImpersonation: %v", err)
+	}
+}
+
+func TestStartImpersonationRefusals(t *testing.T) {
+	f := newImpersonationFixture()
+	ctx := context.Background()
+	payload := authDomain.StartImpersonationDomain{Reason: "support"}
+
+	if _, err := f.service.StartImpersonation(ctx, f.adminID, f.adminID, payload); !errors.Is(err, ErrCannotImpersonateSelf) {
+		t.Fatalf("got %v, want ErrCannotImpersonateSelf", err)
+	}
+	if _, err := f.service.StartImpersonation(ctx, f.adminID, uuid.New(), payload); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("got %v, want ErrUserNotFound", err)
+	}
+
+	// Any admin permission makes the user staff, even one the actor holds.
+	f.roles.permissions[f.adminID] = []string{userDomain.PERMISSION_MANAGE_PRIVACY_REQUESTS, userDomain.PERMISSION_CREATE_PRODUCT}
+	for _, permission := range []string{userDomain.PERMISSION_MANAGE_USERS, userDomain.PERMISSION_MANAGE_PRIVACY_REQUESTS, userDomain.PERMISSION_IMPERSONATE_USERS} {
+		f.roles.rolePermissions[f.roleID] = []string{permission}
+		if _, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, payload); !errors.Is(err, ErrCannotImpersonateStaff) {
+			t.Fatalf("%s: got %v, want ErrCannotImpersonateStaff", permission, err)
+		}
+	}
+
+	f.roles.rolePermissions[f.roleID] = []string{userDomain.PERMISSION_CREATE_PRODUCT, userDomain.PERMISSION_VIEW_ORDERS}
+	if _, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, payload); !errors.Is(err, ErrCannotImpersonateGreater) {
+		t.Fatalf("got %v, want ErrCannotImpersonateGreater", err)
+	}
+
+	delete(f.roles.rolePermissions, f.roleID)
+	f.users.users[f.userID].Status = userDomain.USER_STATUS_BANNED
+	if _, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, payload); !errors.Is(err, ErrUserNotActive) {
+		t.Fatalf("got %v, want ErrUserNotActive", err)
+	}
+	if len(f.sessions.sessions) != 0 || len(f.logs.entries) != 0 {
+		t.Fatal("refused attempts should leave no trace")
+	}
+}
+
+func TestImpersonateSellerWithTheSamePermissions(t *testing.T) {
+	f := newImpersonationFixture()
+	f.roles.rolePermissions[f.roleID] = []string{userDomain.PERMISSION_CREATE_PRODUCT}
+	f.roles.permissions[f.adminID] = []string{userDomain.PERMISSION_IMPERSONATE_USERS, userDomain.PERMISSION_CREATE_PRODUCT}
+
+	if _, err := f.service.StartImpersonation(context.Background(), f.adminID, f.userID, authDomain.StartImpersonationDomain{Reason: "support"}); err != nil {
+		t.Fatalf("StartImpersonation: %v", err)
+	}
+}
+
+func TestStopImpersonation(t *testing.T) {
+	f := newImpersonationFixture()
+	ctx := context.Background()
+
+	token, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, authDomain.StartImpersonationDomain{Reason: "support"})
+	if err != nil {
+		t.Fatalf("StartImpersonation: %v", err)
+	}
+	sessionID := token.Session.ID
+
+	if err := f.service.StopImpersonation(ctx, sessionID, uuid.New()); !errors.Is(err, ErrImpersonationNotFound) {
+		t.Fatalf("another actor: got %v, want ErrImpersonationNotFound", err)
+	}
+	if err := f.service.StopImpersonation(ctx, sessionID, f.adminID); err != nil {
+		t.Fatalf("StopImpersonation: %v", err)
+	}
+	if len(f.logs.entries) != 2 || f.logs.entries[1].Action != actionImpersonateStop {
+		t.Fatalf("audit = %+v", f.logs.entries)
+	}
+	if err := f.service.CheckImpersonation(ctx, sessionID, f.adminID); !errors.Is(err, ErrImpersonationEnded) {
+		t.Fatalf("got %v, want ErrImpersonationEnded", err)
+	}
+	if err := f.service.StopImpersonation(ctx, sessionID, f.adminID); !errors.Is(err, ErrImpersonationAlreadyEnded) {
+		t.Fatalf("got %v, want ErrImpersonationAlreadyEnded", err)
+	}
+}
+
+func TestCheckImpersonationExpires(t *testing.T) {
+	f := newImpersonationFixture()
+	ctx := context.Background()
+
+	token, err := f.service.StartImpersonation(ctx, f.adminID, f.userID, authDomain.StartImpersonationDomain{Reason: "support"})
+	if err != nil {
+		t.Fatalf("StartImpersonation: %v", err)
+	}
+	f.clock = f.clock.Add(DefaultImpersonationServiceConfig.TokenTTL + time.Second)
+	if err := f.service.CheckImpersonation(ctx, token.Session.ID, f.adminID); !errors.Is(err, ErrImpersonationEnded) {
+		t.Fatalf("got %v, want ErrImpersonationEnded", err)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+)
+
+var ErrUnknownAllocationStrategy = errors.New("unknown allocation strategy")
+
+// NewAllocationStrategy returns the allocation strategy of the given name,
+// the priority strategy when the name is empty.
+func NewAllocationStrategy(name orderDomain.ALLOCATION_STRATEGY) (ports.IAllocationStrategy, error) {
+	switch name {
+	case orderDomain.ALLOCATION_STRATEGY_PRIORITY, "":
+		return PriorityOrderStrategy{}, nil
+	case orderDomain.ALLOCATION_STRATEGY_NEAREST:
+		return NearestLocationStrategy{}, nil
+	case orderDomain.ALLOCATION_STRATEGY_FEWEST_SHIPMENTS:
+		return FewestShipmentsStrategy{}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownAllocationStrategy, name)
+}
+
+// PriorityOrderStrategy ships every line from the first warehouse, by
+// priority, that has all of its units.
+type PriorityOrderStrategy struct{}
+
+// Allocate implements ports.IAllocationStrategy.
+func (PriorityOrderStrategy) Allocate(request orderDomain.AllocationRequest) ([]orderDomain.Allocation, error) {
+	return allocateInOrder(request, request.Warehouses)
+}
+
+// NearestLocationStrategy ships every line from the warehouse closest to the
+// destination that has all of its units.
+type NearestLocationStrategy struct{}
+
+// Allocate implements ports.IAllocationStrategy.
+func (NearestLocationStrategy) Allocate(request orderDomain.AllocationRequest) ([]orderDomain.Allocation, error) {
+	return allocateInOrder(request, byProximity(request.Warehouses, request.Destination))
+}
+
+// FewestShipmentsStrategy ships the order from as few warehouses as it can.
+// It keeps choosing the warehouse that can fill the most lines still open,
+// the closest one among equals. Choosing greedily can, in rare cases, use one
+// warehouse more than the best split would.
+type FewestShipmentsStrategy struct{}
+
+// Allocate implements ports.IAllocationStrategy.
+func (FewestShipmentsStrategy) Allocate(request orderDomain.AllocationRequest) ([]orderDomain.Allocation, error) {
+	warehouses := byProximity(request.Warehouses, request.Destination)
+	open := append([]orderDomain.ReservationLine(nil), request.Lines...)
+	allocations := make([]orderDomain.Allocation, 0, len(open))
+	for len(open) > 0 {
+		var best *orderDomain.Warehouse
+		var fills []int
+		for i := range warehouses {
+			var lines []int
+			for j, line := range open {
+				if request.Available[orderDomain.StockKey{SKUID: line.SKUID, WarehouseID: warehouses[i].ID}] >= line.Quantity {
+					lines = append(lines, j)
+				}
+			}
+			if len(lines) > len(fills) {
+				best, fills = &warehouses[i], lines
+			}
+		}
+		if best == nil {
+			return nil, outOfStock(open[0])
+		}
+		filled := make(map[int]bool, len(fills))
+		for _, j := range fills {
+			filled[j] = true
+			allocations = append(allocations, orderDomain.Allocation{SKUID: open[j].SKUID, WarehouseID: best.ID, Quantity: open[j].Quantity})
+		}
+		remaining := open[:0]
+		for j, line := range open {
+			if !filled[j] {
+				remaining = append(remaining, line)
+			}
+		}
+		open = remaining
+	}
+	sort.Slice(allocations, func(i, j int) bool { return allocations[i].SKUID.String() < allocations[j].SKUID.String() })
+	return allocations, nil
+}
+
+// allocateInOrder ships every line from the first of the warehouses that has
+// all of its units.
+func allocateInOrder(request orderDomain.AllocationRequest, warehouses []orderDomain.Warehouse) ([]orderDomain.Allocation, error) {
+	allocations := make([]orderDomain.Allocation, 0, len(request.Lines))
+lines:
+	for _, line := range request.Lines {
+		for _, warehouse := range warehouses {
+			if request.Available[orderDomain.StockKey{SKUID: line.SKUID, WarehouseID: warehouse.ID}] >= line.Quantity {
+				allocations = append(allocations, orderDomain.Allocation{SKUID: line.SKUID, WarehouseID: warehouse.ID, Quantity: line.Quantity})
+				continue lines
+			}
+		}
+		return nil, outOfStock(line)
+	}
+	return allocations, nil
+}
+
+// byProximity orders the warehouses from the closest to the destination,
+// keeping the priority order among those equally close.
+func byProximity(warehouses []orderDomain.Warehouse, destination domain.PostalAddress) []orderDomain.Warehouse {
+	sorted := append([]orderDomain.Warehouse(nil), warehouses...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return proximity(sorted[i].Address, destination) > proximity(sorted[j].Address, destination)
+	})
+	return sorted
+}
+
+// proximity scores how close two addresses are without geocoding them:
+// sharing the country counts most, then the region, then every leading
+// character of the postal code they share, since postal codes are handed out
+// by area in most countries.
+func proximity(from, to domain.PostalAddress) int {
+	if from.CountryCode == "" || !strings.EqualFold(from.CountryCode, to.CountryCode) {
+		return 0
+	}
+	score := 1000
+	if from.Region != "" && strings.EqualFold(from.Region, to.Region) {
+		score += 100
+	}
+	a, b := compactPostalCode(from.PostalCode), compactPostalCode(to.PostalCode)
+	for i := 0; i < len(a) && i < len(b) && a[i] == b[i]; i++ {
+		score++
+	}
+	return score
+}
+
+func compactPostalCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+func outOfStock(line orderDomain.ReservationLine) error {
+	return fmt.Errorf("%w for SKU %s", ErrOutOfStock, line.SKUID)
+}
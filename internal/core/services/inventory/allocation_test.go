@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	"github.com/google/uuid"
+)
+
+// allocationFixture has three warehouses by priority: Bangkok, Chiang Mai
+// and Singapore.
+type allocationFixture struct {
+	request            orderDomain.AllocationRequest
+	bangkok, chiangMai uuid.UUID
+	singapore, a, b, c uuid.UUID
+}
+
+func newAllocationFixture() *allocationFixture {
+	f := &allocationFixture{
+		bangkok: uuid.New(), chiangMai: uuid.New(), singapore: uuid.New(),
+		a: uuid.New(), b: uuid.New(), c: uuid.New(),
+	}
+	f.request = orderDomain.AllocationRequest{
+		Destination: domain.PostalAddress{CountryCode: "TH", Region: "Chiang Mai", PostalCode: "50200"},
+		Warehouses: []orderDomain.Warehouse{
+			{BaseModel: domain.BaseModel{ID: f.bangkok}, Address: domain.PostalAddress{CountryCode: "TH", Region: "Bangkok", PostalCode: "10110"}},
+			{BaseModel: domain.BaseModel{ID: f.chiangMai}, Address: domain.PostalAddress{CountryCode: "TH", Region: "Chiang Mai", PostalCode: "50000"}},
+			{BaseModel: domain.BaseModel{ID: f.singapore}, Address: domain.PostalAddress{CountryCode: "SG", PostalCode: "018956"}},
+		},
+		Lines: []orderDomain.ReservationLine{{SKUID: f.a, Quantity: 1}, {SKUID: f.b, Quantity: 2}, {SKUID: f.c, Quantity: 1}},
+		Available: map[orderDomain.StockKey]int{
+			{SKUID: f.a, WarehouseID: f.bangkok}:   5,
+			{SKUID: f.b, WarehouseID: f.bangkok}:   1,
+			{SKUID: f.c, WarehouseID: f.bangkok}:   5,
+			{SKUID: f.a, WarehouseID: f.chiangMai}: 1,
+			{SKUID: f.b, WarehouseID: f.singapore}: 9,
+			{SKUID: f.c, WarehouseID: f.singapore}: 9,
+		},
+	}
+	return f
+}
+
+// shipsFrom returns the warehouse each SKU ships from.
+func shipsFrom(t *testing.T, allocations []orderDomain.Allocation, err error) map[uuid.UUID]uuid.UUID {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Allocate: %v", err)
+	}
+	warehouses := make(map[uuid.UUID]uuid.UUID, len(allocations))
+	for _, allocation := range allocations {
+		warehouses[allocation.SKUID] = allocation.WarehouseID
+	}
+	return warehouses
+}
+
+func TestAllocationStrategies(t *testing.T) {
+	f := newAllocationFixture()
+	for _, test := range []struct {
+		strategy string
+		want     map[uuid.UUID]uuid.UUID
+	}{
+		{"priority", map[uuid.UUID]uuid.UUID{f.a: f.bangkok, f.b: f.singapore, f.c: f.bangkok}},
+		{"nearest", map[uuid.UUID]uuid.UUID{f.a: f.chiangMai, f.b: f.singapore, f.c: f.bangkok}},
+		{"fewest_shipments", map[uuid.UUID]uuid.UUID{f.a: f.bangkok, f.b: f.singapore, f.c: f.bangkok}},
+	} {
+		strategy, err := NewAllocationStrategy(orderDomain.ALLOCATION_STRATEGY(test.strategy))
+		if err != nil {
+			t.Fatalf("NewAllocationStrategy(%s): %v", test.strategy, err)
+		}
+		allocations, err := strategy.Allocate(f.request)
+		if got := shipsFrom(t, allocations, err); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s ships from %v, want %v", test.strategy, got, test.want)
+		}
+	}
+}
+
+func TestFewestShipmentsUsesOneWarehouseWhenItCan(t *testing.T) {
+	f := newAllocationFixture()
+	f.request.Available[orderDomain.StockKey{SKUID: f.a, WarehouseID: f.singapore}] = 1
+
+	allocations, err := FewestShipmentsStrategy{}.Allocate(f.request)
+	for skuID, warehouseID := range shipsFrom(t, allocations, err) {
+		if warehouseID != f.singapore {
+			t.Fatalf("SKU %s ships from %s, want everything from Singapore", skuID, warehouseID)
+		}
+	}
+	if !sort.SliceIsSorted(allocations, func(i, j int) bool { return allocations[i].SKUID.String() < allocations[j].SKUID.String() }) {
+		t.Fatalf("allocations = %+v, want them by SKU", allocations)
+	}
+}
+
+func TestAllocationNeverSplitsALine(t *testing.T) {
+	f := newAllocationFixture()
+	// 3 units of B are in stock, but no warehouse has 2 of them.
+	f.request.Available[orderDomain.StockKey{SKUID: f.b, WarehouseID: f.singapore}] = 1
+	f.request.Available[orderDomain.StockKey{SKUID: f.b, WarehouseID: f.chiangMai}] = 1
+	for _, strategy := range []orderDomain.ALLOCATION_STRATEGY{
+		orderDomain.ALLOCATION_STRATEGY_PRIORITY,
+		orderDomain.ALLOCATION_STRATEGY_NEAREST,
+		orderDomain.ALLOCATION_STRATEGY_FEWEST_SHIPMENTS,
+	} {
+		allocator, _ := NewAllocationStrategy(strategy)
+		if _, err := allocator.Allocate(f.request); !errors.Is(err, ErrOutOfStock) {
+			t.Errorf("%s: got %v, want ErrOutOfStock", strategy, err)
+		}
+	}
+}
+
+func TestUnknownAllocationStrategy(t *testing.T) {
+	if _, err := NewAllocationStrategy("cheapest"); !errors.Is(err, ErrUnknownAllocationStrategy) {
+		t.Fatalf("got %v, want ErrUnknownAllocationStrategy", err)
+	}
+	if strategy, err := NewAllocationStrategy(""); err != nil || strategy != (PriorityOrderStrategy{}) {
+		t.Fatalf("NewAllocationStrategy(\"\") = %v, %v, want the priority strategy", strategy, err)
+	}
+}
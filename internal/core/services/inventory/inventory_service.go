@@ -0,0 +1,726 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidQuantity     = errors.New("quantity must be positive")
+	ErrOutOfStock          = errors.New("not enough stock")
+	ErrReservationNotFound = errors.New("no active reservation")
+	ErrReservationExpired  = errors.New("the reservation has expired")
+	ErrInventoryNotFound   = errors.New("sku not found")
+	ErrMovementType        = errors.New("only receipts, returns and adjustments can be recorded by hand")
+	ErrStockBelowReserved  = errors.New("the stock cannot drop below the units reserved by checkouts")
+	// ErrStockAtOtherWarehouses is returned when the total stock of a SKU is
+	// set below the units kept outside the default warehouse.
+	ErrStockAtOtherWarehouses = errors.New("the rest of the stock is at other warehouses, count it there")
+	ErrSameWarehouse          = errors.New("stock can only be transferred between two different warehouses")
+	ErrNoDefaultWarehouse     = errors.New("no default warehouse")
+	// ErrInvalidAllocation is returned when the allocation strategy does not
+	// ship every line whole from an active warehouse that has its units.
+	ErrInvalidAllocation = errors.New("the allocation strategy chose stock that cannot ship the order")
+)
+
+// Reasons of the movements the service makes on its own.
+const (
+	reasonOpeningBalance       = "opening balance"
+	reasonReserved             = "reserved at checkout"
+	reasonReleased             = "reservation released"
+	reasonReservationExpired   = "reservation expired"
+	reasonReservationCommitted = "order paid"
+	reasonTransferred          = "transferred"
+)
+
+// InventoryServiceConfig holds the tunables of InventoryServiceImpl.
+type InventoryServiceConfig struct {
+	ReservationTTL  time.Duration // How long reserved units are held before they are released
+	WorkerInterval  time.Duration // How often the worker looks for expired reservations
+	WorkerBatchSize int
+}
+
+var DefaultInventoryServiceConfig = InventoryServiceConfig{
+	ReservationTTL:  15 * time.Minute,
+	WorkerInterval:  time.Minute,
+	WorkerBatchSize: 100,
+}
+
+type InventoryServiceImpl struct {
+	inventoryRepo ports.IInventoryRepository
+	warehouseRepo ports.IWarehouseRepository
+	allocator     ports.IAllocationStrategy
+	transactor    transactors.IDatabaseTransactor
+	config        InventoryServiceConfig
+	now           func() time.Time
+}
+
+func NewInventoryService(
+	inventoryRepo ports.IInventoryRepository,
+	warehouseRepo ports.IWarehouseRepository,
+	allocator ports.IAllocationStrategy,
+	transactor transactors.IDatabaseTransactor,
+	config InventoryServiceConfig,
+) ports.IInventoryService {
+	return &InventoryServiceImpl{
+		inventoryRepo: inventoryRepo,
+		warehouseRepo: warehouseRepo,
+		allocator:     allocator,
+		transactor:    transactor,
+		config:        config,
+		now:           time.Now,
+	}
+}
+
+// Reserve implements ports.IInventoryService.
+//
+// The stock of the lines is locked at every warehouse before the allocation
+// strategy looks at it, so what it chooses is still there when it is
+// reserved. Rows are locked in SKU then warehouse order, the order every
+// other change of the stock locks them in, so concurrent checkouts cannot
+// deadlock.
+func (s *InventoryServiceImpl) Reserve(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID, payload orderDomain.ReserveDomain) ([]orderDomain.InventoryReservation, error) {
+	merged, err := mergeLines(payload.Lines)
+	if err != nil {
+		return nil, err
+	}
+	var reservations []orderDomain.InventoryReservation
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		allocations, err := s.allocate(ctx, actorID, payload.Destination, merged)
+		if err != nil {
+			return err
+		}
+		expiresAt := s.now().Add(s.config.ReservationTTL)
+		for _, allocation := range allocations {
+			key := orderDomain.StockKey{SKUID: allocation.SKUID, WarehouseID: allocation.WarehouseID}
+			// The rows are locked, so this only fails if the stock changed
+			// without taking the lock.
+			ok, err := s.inventoryRepo.ReserveStock(ctx, key, allocation.Quantity)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return outOfStock(orderDomain.ReservationLine{SKUID: allocation.SKUID, Quantity: allocation.Quantity})
+			}
+			reservation := orderDomain.InventoryReservation{
+				SKUID:       allocation.SKUID,
+				WarehouseID: allocation.WarehouseID,
+				Reference:   orderID,
+				Quantity:    allocation.Quantity,
+				Status:      orderDomain.RESERVATION_STATUS_ACTIVE,
+				ExpiresAt:   expiresAt,
+			}
+			if err := s.inventoryRepo.CreateReservation(ctx, &reservation); err != nil {
+				return err
+			}
+			if err := s.inventoryRepo.CreateStockMovement(ctx, &orderDomain.StockMovement{
+				SKUID:         allocation.SKUID,
+				WarehouseID:   allocation.WarehouseID,
+				Type:          orderDomain.STOCK_MOVEMENT_RESERVATION,
+				Reserved:      allocation.Quantity,
+				ActorID:       actorID,
+				Reason:        reasonReserved,
+				ReferenceType: orderDomain.STOCK_REFERENCE_ORDER,
+				ReferenceID:   &orderID,
+			}); err != nil {
+				return err
+			}
+			reservations = append(reservations, reservation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// Commit implements ports.IInventoryService.
+//
+// Expired reservations cannot be committed, even before the worker has
+// released them: their units may already have been promised again.
+func (s *InventoryServiceImpl) Commit(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID) error {
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		reservations, err := s.inventoryRepo.ListActiveReservationsForUpdate(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		if len(reservations) == 0 {
+			return ErrReservationNotFound
+		}
+		now := s.now()
+		for _, reservation := range reservations {
+			if !reservation.ExpiresAt.After(now) {
+				return ErrReservationExpired
+			}
+		}
+		for i := range reservations {
+			reservation := &reservations[i]
+			if err := s.inventoryRepo.CommitStock(ctx, reservation.Key(), reservation.Quantity); err != nil {
+				return err
+			}
+			reservation.Status = orderDomain.RESERVATION_STATUS_COMMITTED
+			reservation.CommittedAt = &now
+			if err := s.inventoryRepo.UpdateReservation(ctx, reservation); err != nil {
+				return err
+			}
+			if err := s.inventoryRepo.CreateStockMovement(ctx, &orderDomain.StockMovement{
+				SKUID:         reservation.SKUID,
+				WarehouseID:   reservation.WarehouseID,
+				Type:          orderDomain.STOCK_MOVEMENT_SALE,
+				Quantity:      -reservation.Quantity,
+				Reserved:      -reservation.Quantity,
+				ActorID:       actorID,
+				Reason:        reasonReservationCommitted,
+				ReferenceType: orderDomain.STOCK_REFERENCE_ORDER,
+				ReferenceID:   &orderID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Release implements ports.IInventoryService.
+//
+// Releasing an order with nothing left to release is not an error, so
+// cancelling twice or after expiry is harmless.
+func (s *InventoryServiceImpl) Release(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID) error {
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		_, err := s.release(ctx, actorID, orderID, false)
+		return err
+	})
+}
+
+// ReleaseExpired implements ports.IInventoryService.
+func (s *InventoryServiceImpl) ReleaseExpired(ctx context.Context) (int, error) {
+	references, err := s.inventoryRepo.ListExpiredReferences(ctx, s.now(), s.config.WorkerBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	released := 0
+	for _, reference := range references {
+		// One transaction per order, so a failure leaves the others
+		// released.
+		var n int
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			var err error
+			n, err = s.release(ctx, nil, reference, true)
+			return err
+		})
+		if err != nil {
+			log.Printf("inventory: release reservations of %s: %v", reference, err)
+			continue
+		}
+		if n > 0 {
+			released++
+		}
+	}
+	return released, nil
+}
+
+// RunWorker implements ports.IInventoryService.
+func (s *InventoryServiceImpl) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+func (s *InventoryServiceImpl) sweep(ctx context.Context) {
+	if _, err := s.ReleaseExpired(ctx); err != nil {
+		log.Printf("inventory: list expired reservations: %v", err)
+	}
+}
+
+// SetStock implements ports.IInventoryService.
+func (s *InventoryServiceImpl) SetStock(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.SetStockDomain) error {
+	if payload.Quantity < 0 {
+		return ErrInvalidQuantity
+	}
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		var inventory *orderDomain.Inventory
+		var delta int
+		if payload.WarehouseID != nil {
+			if _, err := s.getWarehouse(ctx, *payload.WarehouseID); err != nil {
+				return err
+			}
+			var err error
+			if inventory, err = s.lockInventory(ctx, &actorID, orderDomain.StockKey{SKUID: skuID, WarehouseID: *payload.WarehouseID}); err != nil {
+				return err
+			}
+			delta = payload.Quantity - inventory.Quantity
+		} else {
+			inventories, home, err := s.lockSKU(ctx, &actorID, skuID)
+			if err != nil {
+				return err
+			}
+			if delta, err = totalDelta(inventories, home, payload.Quantity); err != nil {
+				return err
+			}
+			inventory = home
+		}
+		if delta == 0 {
+			return nil
+		}
+		_, err := s.move(ctx, inventory, &orderDomain.StockMovement{
+			SKUID:         skuID,
+			WarehouseID:   inventory.WarehouseID,
+			Type:          orderDomain.STOCK_MOVEMENT_ADJUSTMENT,
+			Quantity:      delta,
+			ActorID:       &actorID,
+			Reason:        payload.Reason,
+			ReferenceType: payload.ReferenceType,
+			ReferenceID:   payload.ReferenceID,
+		})
+		return err
+	})
+}
+
+// CheckStock implements ports.IInventoryService.
+//
+// The rows of the SKU stay locked until the transaction of the context ends,
+// so a SetStock made in it cannot be refused for a reason found later.
+func (s *InventoryServiceImpl) CheckStock(ctx context.Context, skuID uuid.UUID, quantity int) error {
+	if quantity < 0 {
+		return ErrInvalidQuantity
+	}
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		inventories, home, err := s.lockSKU(ctx, nil, skuID)
+		if err != nil {
+			return err
+		}
+		_, err = totalDelta(inventories, home, quantity)
+		return err
+	})
+}
+
+// RecordMovement implements ports.IInventoryService.
+func (s *InventoryServiceImpl) RecordMovement(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.RecordStockMovementDomain) (*orderDomain.StockMovement, error) {
+	switch payload.Type {
+	case orderDomain.STOCK_MOVEMENT_RECEIPT, orderDomain.STOCK_MOVEMENT_RETURN:
+		if payload.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+	case orderDomain.STOCK_MOVEMENT_ADJUSTMENT:
+		if payload.Quantity == 0 {
+			return nil, ErrInvalidQuantity
+		}
+	default:
+		return nil, ErrMovementType
+	}
+	var movement *orderDomain.StockMovement
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		var warehouse *orderDomain.Warehouse
+		var err error
+		if payload.WarehouseID != nil {
+			warehouse, err = s.getWarehouse(ctx, *payload.WarehouseID)
+		} else {
+			warehouse, err = s.defaultWarehouse(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		inventory, err := s.lockInventory(ctx, &actorID, orderDomain.StockKey{SKUID: skuID, WarehouseID: warehouse.ID})
+		if err != nil {
+			return err
+		}
+		var referenceType orderDomain.STOCK_REFERENCE_TYPE
+		if payload.ReferenceID != nil {
+			referenceType = orderDomain.STOCK_REFERENCE_DOCUMENT
+		}
+		movement, err = s.move(ctx, inventory, &orderDomain.StockMovement{
+			SKUID:         skuID,
+			WarehouseID:   warehouse.ID,
+			Type:          payload.Type,
+			Quantity:      payload.Quantity,
+			ActorID:       &actorID,
+			Reason:        payload.Reason,
+			ReferenceType: referenceType,
+			ReferenceID:   payload.ReferenceID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// Transfer implements ports.IInventoryService.
+//
+// Both movements carry the same transfer reference. Units held by checkouts
+// cannot leave their warehouse, the reservation ships them from there.
+func (s *InventoryServiceImpl) Transfer(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.TransferStockDomain) ([]orderDomain.StockMovement, error) {
+	if payload.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if payload.FromWarehouseID == payload.ToWarehouseID {
+		return nil, ErrSameWarehouse
+	}
+	transferID, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return nil, err
+	}
+	var movements []orderDomain.StockMovement
+	err = s.inTransaction(ctx, func(ctx context.Context) error {
+		keys := []orderDomain.StockKey{
+			{SKUID: skuID, WarehouseID: payload.FromWarehouseID},
+			{SKUID: skuID, WarehouseID: payload.ToWarehouseID},
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i].WarehouseID.String() < keys[j].WarehouseID.String() })
+		inventories := make(map[uuid.UUID]*orderDomain.Inventory, len(keys))
+		for _, key := range keys {
+			if _, err := s.getWarehouse(ctx, key.WarehouseID); err != nil {
+				return err
+			}
+			inventory, err := s.lockInventory(ctx, &actorID, key)
+			if err != nil {
+				return err
+			}
+			inventories[key.WarehouseID] = inventory
+		}
+		for _, leg := range []struct {
+			warehouseID uuid.UUID
+			quantity    int
+		}{
+			{payload.FromWarehouseID, -payload.Quantity},
+			{payload.ToWarehouseID, payload.Quantity},
+		} {
+			movement, err := s.move(ctx, inventories[leg.warehouseID], &orderDomain.StockMovement{
+				SKUID:         skuID,
+				WarehouseID:   leg.warehouseID,
+				Type:          orderDomain.STOCK_MOVEMENT_TRANSFER,
+				Quantity:      leg.quantity,
+				ActorID:       &actorID,
+				Reason:        payload.Reason,
+				ReferenceType: orderDomain.STOCK_REFERENCE_TRANSFER,
+				ReferenceID:   &transferID,
+			})
+			if err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// GetInventory implements ports.IInventoryService.
+func (s *InventoryServiceImpl) GetInventory(ctx context.Context, skuID uuid.UUID) (*orderDomain.SKUStock, error) {
+	var stock *orderDomain.SKUStock
+	err := s.inTransaction(ctx, func(ctx context.Context) error {
+		warehouse, err := s.defaultWarehouse(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureInventory(ctx, nil, orderDomain.StockKey{SKUID: skuID, WarehouseID: warehouse.ID}); err != nil {
+			return err
+		}
+		inventories, err := s.inventoryRepo.ListInventoriesBySKU(ctx, skuID)
+		if err != nil {
+			return err
+		}
+		if len(inventories) == 0 {
+			return ErrInventoryNotFound
+		}
+		stock = &orderDomain.SKUStock{SKUID: skuID, Locations: inventories}
+		for _, inventory := range inventories {
+			stock.Quantity += inventory.Quantity
+			stock.Reserved += inventory.Reserved
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stock, nil
+}
+
+// ListMovements implements ports.IInventoryService.
+func (s *InventoryServiceImpl) ListMovements(ctx context.Context, skuID uuid.UUID) (pagination.Pagination[[]orderDomain.StockMovement], error) {
+	return s.inventoryRepo.ListStockMovements(ctx, skuID)
+}
+
+// Reconcile implements ports.IInventoryService.
+func (s *InventoryServiceImpl) Reconcile(ctx context.Context) ([]orderDomain.InventoryDiscrepancy, error) {
+	return s.inventoryRepo.ListInventoryDiscrepancies(ctx)
+}
+
+// release returns the units of the active reservations of the order, only of
+// those past their expiry when onlyExpired is set. The reservations are
+// locked first, so a concurrent commit either wins or finds them released.
+func (s *InventoryServiceImpl) release(ctx context.Context, actorID *uuid.UUID, orderID uuid.UUID, onlyExpired bool) (int, error) {
+	reservations, err := s.inventoryRepo.ListActiveReservationsForUpdate(ctx, orderID)
+	if err != nil {
+		return 0, err
+	}
+	now := s.now()
+	reason := reasonReleased
+	if onlyExpired {
+		reason = reasonReservationExpired
+	}
+	released := 0
+	for i := range reservations {
+		reservation := &reservations[i]
+		if onlyExpired && reservation.ExpiresAt.After(now) {
+			continue
+		}
+		if err := s.inventoryRepo.ReleaseStock(ctx, reservation.Key(), reservation.Quantity); err != nil {
+			return released, err
+		}
+		reservation.Status = orderDomain.RESERVATION_STATUS_RELEASED
+		reservation.ReleasedAt = &now
+		if err := s.inventoryRepo.UpdateReservation(ctx, reservation); err != nil {
+			return released, err
+		}
+		if err := s.inventoryRepo.CreateStockMovement(ctx, &orderDomain.StockMovement{
+			SKUID:         reservation.SKUID,
+			WarehouseID:   reservation.WarehouseID,
+			Type:          orderDomain.STOCK_MOVEMENT_RESERVATION,
+			Reserved:      -reservation.Quantity,
+			ActorID:       actorID,
+			Reason:        reason,
+			ReferenceType: orderDomain.STOCK_REFERENCE_ORDER,
+			ReferenceID:   &orderID,
+		}); err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+// allocate locks the stock of the lines at every warehouse and has the
+// allocation strategy choose where each line ships from. The allocations
+// come back in SKU then warehouse order.
+func (s *InventoryServiceImpl) allocate(ctx context.Context, actorID *uuid.UUID, destination domain.PostalAddress, lines []orderDomain.ReservationLine) ([]orderDomain.Allocation, error) {
+	home, err := s.defaultWarehouse(ctx)
+	if err != nil {
+		return nil, err
+	}
+	skuIDs := make([]uuid.UUID, 0, len(lines))
+	for _, line := range lines {
+		if err := s.ensureInventory(ctx, actorID, orderDomain.StockKey{SKUID: line.SKUID, WarehouseID: home.ID}); err != nil {
+			return nil, err
+		}
+		skuIDs = append(skuIDs, line.SKUID)
+	}
+	warehouses, err := s.warehouseRepo.ListWarehouses(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	inventories, err := s.inventoryRepo.ListInventoriesForUpdate(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make(map[uuid.UUID]bool, len(warehouses))
+	for _, warehouse := range warehouses {
+		active[warehouse.ID] = true
+	}
+	request := orderDomain.AllocationRequest{
+		Destination: destination,
+		Lines:       lines,
+		Warehouses:  warehouses,
+		Available:   make(map[orderDomain.StockKey]int, len(inventories)),
+	}
+	for _, inventory := range inventories {
+		if active[inventory.WarehouseID] && inventory.Available() > 0 {
+			request.Available[inventory.Key()] = inventory.Available()
+		}
+	}
+	allocations, err := s.allocator.Allocate(request)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkAllocations(request, allocations, active); err != nil {
+		return nil, err
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].SKUID != allocations[j].SKUID {
+			return allocations[i].SKUID.String() < allocations[j].SKUID.String()
+		}
+		return allocations[i].WarehouseID.String() < allocations[j].WarehouseID.String()
+	})
+	return allocations, nil
+}
+
+// checkAllocations makes sure the strategy shipped every line whole and once,
+// from an active warehouse that has its units.
+func checkAllocations(request orderDomain.AllocationRequest, allocations []orderDomain.Allocation, active map[uuid.UUID]bool) error {
+	open := make(map[uuid.UUID]int, len(request.Lines))
+	for _, line := range request.Lines {
+		open[line.SKUID] = line.Quantity
+	}
+	for _, allocation := range allocations {
+		quantity, ok := open[allocation.SKUID]
+		if !ok || quantity != allocation.Quantity || !active[allocation.WarehouseID] ||
+			request.Available[orderDomain.StockKey{SKUID: allocation.SKUID, WarehouseID: allocation.WarehouseID}] < allocation.Quantity {
+			return fmt.Errorf("%w: %d of SKU %s from warehouse %s", ErrInvalidAllocation, allocation.Quantity, allocation.SKUID, allocation.WarehouseID)
+		}
+		delete(open, allocation.SKUID)
+	}
+	for skuID := range open {
+		return fmt.Errorf("%w: SKU %s was left out", ErrInvalidAllocation, skuID)
+	}
+	return nil
+}
+
+// ensureInventory creates the inventory row of the SKU at the warehouse on
+// its first use, and records the stock it starts from as the opening balance
+// of the ledger.
+func (s *InventoryServiceImpl) ensureInventory(ctx context.Context, actorID *uuid.UUID, key orderDomain.StockKey) error {
+	created, err := s.inventoryRepo.EnsureInventory(ctx, key.SKUID, key.WarehouseID)
+	if err != nil || created == nil || created.Quantity == 0 {
+		return err
+	}
+	return s.inventoryRepo.CreateStockMovement(ctx, &orderDomain.StockMovement{
+		SKUID:         key.SKUID,
+		WarehouseID:   key.WarehouseID,
+		Type:          orderDomain.STOCK_MOVEMENT_ADJUSTMENT,
+		Quantity:      created.Quantity,
+		ActorID:       actorID,
+		Reason:        reasonOpeningBalance,
+		ReferenceType: orderDomain.STOCK_REFERENCE_SKU,
+		ReferenceID:   &key.SKUID,
+	})
+}
+
+func (s *InventoryServiceImpl) lockInventory(ctx context.Context, actorID *uuid.UUID, key orderDomain.StockKey) (*orderDomain.Inventory, error) {
+	if err := s.ensureInventory(ctx, actorID, key); err != nil {
+		return nil, err
+	}
+	inventory, err := s.inventoryRepo.GetInventoryForUpdate(ctx, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInventoryNotFound
+		}
+		return nil, err
+	}
+	return inventory, nil
+}
+
+// lockSKU locks the rows of the SKU at every warehouse, and returns them
+// with the row of the default warehouse.
+func (s *InventoryServiceImpl) lockSKU(ctx context.Context, actorID *uuid.UUID, skuID uuid.UUID) ([]orderDomain.Inventory, *orderDomain.Inventory, error) {
+	home, err := s.defaultWarehouse(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.ensureInventory(ctx, actorID, orderDomain.StockKey{SKUID: skuID, WarehouseID: home.ID}); err != nil {
+		return nil, nil, err
+	}
+	inventories, err := s.inventoryRepo.ListInventoriesForUpdate(ctx, []uuid.UUID{skuID})
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range inventories {
+		if inventories[i].WarehouseID == home.ID {
+			return inventories, &inventories[i], nil
+		}
+	}
+	return nil, nil, ErrInventoryNotFound
+}
+
+// totalDelta returns the change of the default warehouse that brings the
+// total stock of a SKU to quantity.
+func totalDelta(inventories []orderDomain.Inventory, home *orderDomain.Inventory, quantity int) (int, error) {
+	var total, reserved int
+	for _, inventory := range inventories {
+		total += inventory.Quantity
+		reserved += inventory.Reserved
+	}
+	delta := quantity - total
+	if home.Quantity+delta < home.Reserved {
+		if quantity < reserved {
+			return 0, ErrStockBelowReserved
+		}
+		return 0, ErrStockAtOtherWarehouses
+	}
+	return delta, nil
+}
+
+// move applies a change of the units on hand to the locked inventory row and
+// records it.
+func (s *InventoryServiceImpl) move(ctx context.Context, inventory *orderDomain.Inventory, movement *orderDomain.StockMovement) (*orderDomain.StockMovement, error) {
+	if inventory.Quantity+movement.Quantity < inventory.Reserved {
+		return nil, ErrStockBelowReserved
+	}
+	if err := s.inventoryRepo.AddStock(ctx, inventory.Key(), movement.Quantity); err != nil {
+		return nil, err
+	}
+	if err := s.inventoryRepo.CreateStockMovement(ctx, movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+func (s *InventoryServiceImpl) defaultWarehouse(ctx context.Context) (*orderDomain.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.GetDefaultWarehouse(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoDefaultWarehouse
+	}
+	return warehouse, err
+}
+
+func (s *InventoryServiceImpl) getWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.GetWarehouse(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWarehouseNotFound
+	}
+	return warehouse, err
+}
+
+// inTransaction runs fn in the transaction of the context when there is one,
+// so a checkout can reserve stock atomically with creating its order, and in
+// a transaction of its own otherwise.
+func (s *InventoryServiceImpl) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if transactors.ExtractTx(ctx) != nil {
+		return fn(ctx)
+	}
+	return s.transactor.WithinTransaction(ctx, fn)
+}
+
+// mergeLines adds up the lines of the same SKU and sorts them by SKU.
+func mergeLines(lines []orderDomain.ReservationLine) ([]orderDomain.ReservationLine, error) {
+	totals := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		totals[line.SKUID] += line.Quantity
+	}
+	merged := make([]orderDomain.ReservationLine, 0, len(totals))
+	for skuID, quantity := range totals {
+		merged = append(merged, orderDomain.ReservationLine{SKUID: skuID, Quantity: quantity})
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].SKUID.String() < merged[j].SKUID.String() })
+	return merged, nil
+}
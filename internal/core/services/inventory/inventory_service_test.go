@@ -0,0 +1,738 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeInventoryRepo keeps the inventory in memory. Each method holds the
+// mutex for its whole run, the way the database serialises the conditional
+// UPDATE of ReserveStock on the inventory row.
+type fakeInventoryRepo struct {
+	mu           sync.Mutex
+	warehouses   *fakeWarehouseRepo
+	stock        map[uuid.UUID]int // Stock of the SKUs, the sum of their inventory rows once counted
+	inventories  map[orderDomain.StockKey]*orderDomain.Inventory
+	reservations []orderDomain.InventoryReservation
+	movements    []orderDomain.StockMovement
+}
+
+func newFakeInventoryRepo(warehouses *fakeWarehouseRepo) *fakeInventoryRepo {
+	return &fakeInventoryRepo{
+		warehouses:  warehouses,
+		stock:       map[uuid.UUID]int{},
+		inventories: map[orderDomain.StockKey]*orderDomain.Inventory{},
+	}
+}
+
+func (f *fakeInventoryRepo) EnsureInventory(ctx context.Context, skuID, warehouseID uuid.UUID) (*orderDomain.Inventory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := orderDomain.StockKey{SKUID: skuID, WarehouseID: warehouseID}
+	stock, ok := f.stock[skuID]
+	if _, exists := f.inventories[key]; exists || !ok {
+		return nil, nil
+	}
+	inventory := &orderDomain.Inventory{SKUID: skuID, WarehouseID: warehouseID}
+	if home, _ := f.warehouses.GetDefaultWarehouse(ctx); home != nil && home.ID == warehouseID {
+		inventory.Quantity = stock
+		for _, other := range f.inventories {
+			if other.SKUID == skuID {
+				inventory.Quantity -= other.Quantity
+			}
+		}
+		inventory.Quantity = max(inventory.Quantity, 0)
+	}
+	f.inventories[key] = inventory
+	clone := *inventory
+	return &clone, nil
+}
+
+func (f *fakeInventoryRepo) ReserveStock(ctx context.Context, key orderDomain.StockKey, quantity int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	inventory, ok := f.inventories[key]
+	if !ok || inventory.Available() < quantity {
+		return false, nil
+	}
+	inventory.Reserved += quantity
+	return true, nil
+}
+
+func (f *fakeInventoryRepo) ReleaseStock(ctx context.Context, key orderDomain.StockKey, quantity int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inventories[key].Reserved -= quantity
+	return nil
+}
+
+func (f *fakeInventoryRepo) CommitStock(ctx context.Context, key orderDomain.StockKey, quantity int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inventories[key].Quantity -= quantity
+	f.inventories[key].Reserved -= quantity
+	f.stock[key.SKUID] -= quantity
+	return nil
+}
+
+func (f *fakeInventoryRepo) AddStock(ctx context.Context, key orderDomain.StockKey, delta int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.inventories[key].Quantity += delta
+	f.stock[key.SKUID] += delta
+	return nil
+}
+
+func (f *fakeInventoryRepo) ListInventoriesBySKU(ctx context.Context, skuID uuid.UUID) ([]orderDomain.Inventory, error) {
+	return f.ListInventoriesForUpdate(ctx, []uuid.UUID{skuID})
+}
+
+func (f *fakeInventoryRepo) ListInventoriesForUpdate(ctx context.Context, skuIDs []uuid.UUID) ([]orderDomain.Inventory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var inventories []orderDomain.Inventory
+	for _, skuID := range skuIDs {
+		for _, inventory := range f.inventories {
+			if inventory.SKUID == skuID {
+				inventories = append(inventories, *inventory)
+			}
+		}
+	}
+	sort.Slice(inventories, func(i, j int) bool {
+		if inventories[i].SKUID != inventories[j].SKUID {
+			return inventories[i].SKUID.String() < inventories[j].SKUID.String()
+		}
+		return inventories[i].WarehouseID.String() < inventories[j].WarehouseID.String()
+	})
+	return inventories, nil
+}
+
+func (f *fakeInventoryRepo) GetInventoryForUpdate(ctx context.Context, key orderDomain.StockKey) (*orderDomain.Inventory, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if inventory, ok := f.inventories[key]; ok {
+		clone := *inventory
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeInventoryRepo) CreateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	payload.ID = uuid.New()
+	f.reservations = append(f.reservations, *payload)
+	return nil
+}
+
+func (f *fakeInventoryRepo) ListActiveReservationsForUpdate(ctx context.Context, reference uuid.UUID) ([]orderDomain.InventoryReservation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var active []orderDomain.InventoryReservation
+	for _, reservation := range f.reservations {
+		if reservation.Reference == reference && reservation.Status == orderDomain.RESERVATION_STATUS_ACTIVE {
+			active = append(active, reservation)
+		}
+	}
+	return active, nil
+}
+
+func (f *fakeInventoryRepo) ListExpiredReferences(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	seen := map[uuid.UUID]bool{}
+	var references []uuid.UUID
+	for _, reservation := range f.reservations {
+		if reservation.Status == orderDomain.RESERVATION_STATUS_ACTIVE && !reservation.ExpiresAt.After(now) && !seen[reservation.Reference] {
+			seen[reservation.Reference] = true
+			references = append(references, reservation.Reference)
+		}
+	}
+	return references, nil
+}
+
+func (f *fakeInventoryRepo) UpdateReservation(ctx context.Context, payload *orderDomain.InventoryReservation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.reservations {
+		if f.reservations[i].ID == payload.ID {
+			f.reservations[i] = *payload
+		}
+	}
+	return nil
+}
+
+func (f *fakeInventoryRepo) CreateStockMovement(ctx context.Context, payload *orderDomain.StockMovement) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	payload.ID = uuid.New()
+	f.movements = append(f.movements, *payload)
+	return nil
+}
+
+func (f *fakeInventoryRepo) ListStockMovements(ctx context.Context, skuID uuid.UUID) (pagination.Pagination[[]orderDomain.StockMovement], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var movements []orderDomain.StockMovement
+	for _, movement := range f.movements {
+		if movement.SKUID == skuID {
+			movements = append(movements, movement)
+		}
+	}
+	return pagination.Pagination[[]orderDomain.StockMovement]{Rows: movements, Total: int64(len(movements))}, nil
+}
+
+func (f *fakeInventoryRepo) ListInventoryDiscrepancies(ctx context.Context) ([]orderDomain.InventoryDiscrepancy, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	quantities, reserved := map[orderDomain.StockKey]int{}, map[orderDomain.StockKey]int{}
+	for _, movement := range f.movements {
+		key := orderDomain.StockKey{SKUID: movement.SKUID, WarehouseID: movement.WarehouseID}
+		quantities[key] += movement.Quantity
+		reserved[key] += movement.Reserved
+	}
+	var discrepancies []orderDomain.InventoryDiscrepancy
+	for key, inventory := range f.inventories {
+		if inventory.Quantity != quantities[key] || inventory.Reserved != reserved[key] {
+			discrepancies = append(discrepancies, orderDomain.InventoryDiscrepancy{
+				SKUID:          key.SKUID,
+				WarehouseID:    key.WarehouseID,
+				Quantity:       inventory.Quantity,
+				LedgerQuantity: quantities[key],
+				Reserved:       inventory.Reserved,
+				LedgerReserved: reserved[key],
+			})
+		}
+	}
+	return discrepancies, nil
+}
+
+// fakeWarehouseRepo keeps the warehouses in memory, by priority.
+type fakeWarehouseRepo struct {
+	mu         sync.Mutex
+	warehouses []orderDomain.Warehouse
+}
+
+func (f *fakeWarehouseRepo) CreateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	payload.ID = uuid.New()
+	f.warehouses = append(f.warehouses, *payload)
+	sort.SliceStable(f.warehouses, func(i, j int) bool { return f.warehouses[i].Priority < f.warehouses[j].Priority })
+	return nil
+}
+
+func (f *fakeWarehouseRepo) UpdateWarehouse(ctx context.Context, payload *orderDomain.Warehouse) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.warehouses {
+		if f.warehouses[i].ID == payload.ID {
+			f.warehouses[i] = *payload
+		}
+	}
+	return nil
+}
+
+func (f *fakeWarehouseRepo) GetWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, warehouse := range f.warehouses {
+		if warehouse.ID == id {
+			return &warehouse, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeWarehouseRepo) GetDefaultWarehouse(ctx context.Context) (*orderDomain.Warehouse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, warehouse := range f.warehouses {
+		if warehouse.IsDefault {
+			return &warehouse, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeWarehouseRepo) ListWarehouses(ctx context.Context, activeOnly bool) ([]orderDomain.Warehouse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var warehouses []orderDomain.Warehouse
+	for _, warehouse := range f.warehouses {
+		if warehouse.Active || !activeOnly {
+			warehouses = append(warehouses, warehouse)
+		}
+	}
+	return warehouses, nil
+}
+
+func (f *fakeWarehouseRepo) ExistsWarehouseCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, warehouse := range f.warehouses {
+		if warehouse.Code == code && (exceptID == nil || warehouse.ID != *exceptID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeWarehouseRepo) ClearDefaultWarehouse(ctx context.Context, exceptID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := range f.warehouses {
+		if f.warehouses[i].ID != exceptID {
+			f.warehouses[i].IsDefault = false
+		}
+	}
+	return nil
+}
+
+type inventoryFixture struct {
+	service    *InventoryServiceImpl
+	repo       *fakeInventoryRepo
+	warehouses *fakeWarehouseRepo
+	home       uuid.UUID // The default warehouse
+	now        time.Time
+}
+
+func newInventoryFixture() *inventoryFixture {
+	warehouses := &fakeWarehouseRepo{}
+	f := &inventoryFixture{
+		repo:       newFakeInventoryRepo(warehouses),
+		warehouses: warehouses,
+		now:        time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.home = f.warehouse("MAIN", 0, "TH", "10110")
+	f.warehouses.warehouses[0].IsDefault = true
+	f.service = NewInventoryService(f.repo, f.warehouses, PriorityOrderStrategy{}, transactorstest.Transactor{}, DefaultInventoryServiceConfig).(*InventoryServiceImpl)
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+// warehouse adds an active warehouse and returns its ID.
+func (f *inventoryFixture) warehouse(code string, priority int, countryCode, postalCode string) uuid.UUID {
+	warehouse := orderDomain.Warehouse{Code: code, Priority: priority, Active: true}
+	warehouse.Address.CountryCode, warehouse.Address.PostalCode = countryCode, postalCode
+	f.warehouses.CreateWarehouse(context.Background(), &warehouse)
+	return warehouse.ID
+}
+
+func (f *inventoryFixture) sku(stock int) uuid.UUID {
+	id := uuid.New()
+	f.repo.stock[id] = stock
+	return id
+}
+
+// inventory returns the row of the SKU at the default warehouse.
+func (f *inventoryFixture) inventory(t *testing.T, skuID uuid.UUID) orderDomain.Inventory {
+	t.Helper()
+	return f.inventoryAt(t, skuID, f.home)
+}
+
+func (f *inventoryFixture) inventoryAt(t *testing.T, skuID, warehouseID uuid.UUID) orderDomain.Inventory {
+	t.Helper()
+	inventory, err := f.repo.GetInventoryForUpdate(context.Background(), orderDomain.StockKey{SKUID: skuID, WarehouseID: warehouseID})
+	if err != nil {
+		t.Fatalf("GetInventoryForUpdate: %v", err)
+	}
+	return *inventory
+}
+
+// lines reserves the quantities of the SKU as separate lines.
+func lines(skuID uuid.UUID, quantities ...int) orderDomain.ReserveDomain {
+	payload := orderDomain.ReserveDomain{}
+	for _, quantity := range quantities {
+		payload.Lines = append(payload.Lines, orderDomain.ReservationLine{SKUID: skuID, Quantity: quantity})
+	}
+	return payload
+}
+
+// reconciled fails the test when an inventory row and its ledger disagree.
+func (f *inventoryFixture) reconciled(t *testing.T) {
+	t.Helper()
+	discrepancies, err := f.service.Reconcile(context.Background())
+	if err != nil || len(discrepancies) != 0 {
+		t.Fatalf("Reconcile = %+v, %v", discrepancies, err)
+	}
+}
+
+func TestReserveCommitAndRelease(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	skuID := f.sku(5)
+	sold, cancelled := uuid.New(), uuid.New()
+
+	// Lines of the same SKU are added up.
+	reservations, err := f.service.Reserve(ctx, nil, sold, lines(skuID, 1, 2))
+	if err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if len(reservations) != 1 || reservations[0].Quantity != 3 || !reservations[0].ExpiresAt.Equal(f.now.Add(DefaultInventoryServiceConfig.ReservationTTL)) {
+		t.Fatalf("reservations = %+v", reservations)
+	}
+	if _, err := f.service.Reserve(ctx, nil, cancelled, lines(skuID, 3)); !errors.Is(err, ErrOutOfStock) {
+		t.Fatalf("got %v, want ErrOutOfStock with 2 units left", err)
+	}
+	if _, err := f.service.Reserve(ctx, nil, cancelled, lines(skuID, 2)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if inventory := f.inventory(t, skuID); inventory.Reserved != 5 || inventory.Available() != 0 {
+		t.Fatalf("inventory = %+v", inventory)
+	}
+
+	if err := f.service.Commit(ctx, nil, sold); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	if err := f.service.Release(ctx, nil, cancelled); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+	if inventory := f.inventory(t, skuID); inventory.Quantity != 2 || inventory.Reserved != 0 || f.repo.stock[skuID] != 2 {
+		t.Fatalf("inventory = %+v, SKU stock = %d", inventory, f.repo.stock[skuID])
+	}
+	if err := f.service.Release(ctx, nil, cancelled); err != nil {
+		t.Fatalf("releasing twice should be harmless, got %v", err)
+	}
+	if err := f.service.Commit(ctx, nil, cancelled); !errors.Is(err, ErrReservationNotFound) {
+		t.Fatalf("got %v, want ErrReservationNotFound", err)
+	}
+	f.reconciled(t)
+}
+
+func TestReserveRejectsInvalidQuantity(t *testing.T) {
+	f := newInventoryFixture()
+	skuID := f.sku(5)
+	if _, err := f.service.Reserve(context.Background(), nil, uuid.New(), lines(skuID, 0)); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("got %v, want ErrInvalidQuantity", err)
+	}
+}
+
+func TestExpiredReservationsAreReleased(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	skuID := f.sku(2)
+	abandoned := uuid.New()
+	if _, err := f.service.Reserve(ctx, nil, abandoned, lines(skuID, 2)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	f.now = f.now.Add(DefaultInventoryServiceConfig.ReservationTTL / 2)
+	if n, _ := f.service.ReleaseExpired(ctx); n != 0 {
+		t.Fatalf("released %d references before expiry", n)
+	}
+	f.now = f.now.Add(DefaultInventoryServiceConfig.ReservationTTL)
+	// Payment arriving after expiry cannot take the units any more.
+	if err := f.service.Commit(ctx, nil, abandoned); !errors.Is(err, ErrReservationExpired) {
+		t.Fatalf("got %v, want ErrReservationExpired", err)
+	}
+	if n, err := f.service.ReleaseExpired(ctx); err != nil || n != 1 {
+		t.Fatalf("ReleaseExpired = %d, %v", n, err)
+	}
+	if inventory := f.inventory(t, skuID); inventory.Available() != 2 {
+		t.Fatalf("inventory = %+v, the units should be back", inventory)
+	}
+	if _, err := f.service.Reserve(ctx, nil, uuid.New(), lines(skuID, 2)); err != nil {
+		t.Fatalf("the released units should be reservable: %v", err)
+	}
+}
+
+// TestConcurrentReservationsNeverOversell has many checkouts race for the
+// last units of a SKU. The fake repository serialises its calls, so this only
+// checks the bookkeeping of the service; that the conditional UPDATE holds
+// under real concurrency is tested against Postgres in the repository
+// package (go test -tags integration).
+func TestConcurrentReservationsNeverOversell(t *testing.T) {
+	const (
+		stock     = 10
+		checkouts = 200
+	)
+	f := newInventoryFixture()
+	ctx := context.Background()
+	skuID := f.sku(stock)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		won      []uuid.UUID
+		start    = make(chan struct{})
+		failures = make(chan error, checkouts)
+	)
+	for i := 0; i < checkouts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reference := uuid.New()
+			<-start
+			_, err := f.service.Reserve(ctx, nil, reference, lines(skuID, 1))
+			switch {
+			case err == nil:
+				mu.Lock()
+				won = append(won, reference)
+				mu.Unlock()
+			case !errors.Is(err, ErrOutOfStock):
+				failures <- err
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(failures)
+	for err := range failures {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if len(won) != stock {
+		t.Fatalf("%d checkouts got a unit, want %d", len(won), stock)
+	}
+
+	// Committing the winners concurrently sells exactly the stock.
+	for _, reference := range won {
+		wg.Add(1)
+		go func(reference uuid.UUID) {
+			defer wg.Done()
+			if err := f.service.Commit(ctx, nil, reference); err != nil {
+				t.Errorf("Commit: %v", err)
+			}
+		}(reference)
+	}
+	wg.Wait()
+	if inventory := f.inventory(t, skuID); inventory.Quantity != 0 || inventory.Reserved != 0 {
+		t.Fatalf("inventory = %+v, want everything sold", inventory)
+	}
+	f.reconciled(t)
+}
+
+func TestStockMovementsLedger(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	actorID := uuid.New()
+	skuID := f.sku(4)
+
+	movement, err := f.service.RecordMovement(ctx, actorID, skuID, orderDomain.RecordStockMovementDomain{
+		Type:     orderDomain.STOCK_MOVEMENT_RECEIPT,
+		Quantity: 6,
+		Reason:   "delivery",
+	})
+	if err != nil || movement.Quantity != 6 || *movement.ActorID != actorID {
+		t.Fatalf("RecordMovement = %+v, %v", movement, err)
+	}
+	if _, err := f.service.Reserve(ctx, nil, uuid.New(), lines(skuID, 7)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	// Neither a count nor a write-off can take the units checkouts hold.
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 6, Reason: "count"}); !errors.Is(err, ErrStockBelowReserved) {
+		t.Fatalf("got %v, want ErrStockBelowReserved", err)
+	}
+	if _, err := f.service.RecordMovement(ctx, actorID, skuID, orderDomain.RecordStockMovementDomain{
+		Type:     orderDomain.STOCK_MOVEMENT_ADJUSTMENT,
+		Quantity: -4,
+		Reason:   "damaged",
+	}); !errors.Is(err, ErrStockBelowReserved) {
+		t.Fatalf("got %v, want ErrStockBelowReserved", err)
+	}
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 8, Reason: "count"}); err != nil {
+		t.Fatalf("SetStock: %v", err)
+	}
+	// Setting the same count again records nothing.
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 8, Reason: "count"}); err != nil {
+		t.Fatalf("SetStock: %v", err)
+	}
+
+	page, err := f.service.ListMovements(ctx, skuID)
+	if err != nil {
+		t.Fatalf("ListMovements: %v", err)
+	}
+	var types []orderDomain.STOCK_MOVEMENT_TYPE
+	var deltas []int
+	for _, movement := range page.Rows {
+		types = append(types, movement.Type)
+		deltas = append(deltas, movement.Quantity)
+	}
+	wantTypes := []orderDomain.STOCK_MOVEMENT_TYPE{
+		orderDomain.STOCK_MOVEMENT_ADJUSTMENT, // opening balance
+		orderDomain.STOCK_MOVEMENT_RECEIPT,
+		orderDomain.STOCK_MOVEMENT_RESERVATION,
+		orderDomain.STOCK_MOVEMENT_ADJUSTMENT,
+	}
+	if !reflect.DeepEqual(types, wantTypes) || !reflect.DeepEqual(deltas, []int{4, 6, 0, -2}) {
+		t.Fatalf("movements = %v %v", types, deltas)
+	}
+	if inventory := f.inventory(t, skuID); inventory.Quantity != 8 || f.repo.stock[skuID] != 8 {
+		t.Fatalf("inventory = %+v, SKU stock = %d", inventory, f.repo.stock[skuID])
+	}
+	f.reconciled(t)
+
+	// A change made behind the ledger's back shows up.
+	f.repo.inventories[orderDomain.StockKey{SKUID: skuID, WarehouseID: f.home}].Quantity++
+	discrepancies, err := f.service.Reconcile(ctx)
+	if err != nil || len(discrepancies) != 1 || discrepancies[0].Quantity != 9 || discrepancies[0].LedgerQuantity != 8 {
+		t.Fatalf("Reconcile = %+v, %v", discrepancies, err)
+	}
+}
+
+func TestRecordMovementRefusals(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	skuID := f.sku(1)
+	for _, payload := range []orderDomain.RecordStockMovementDomain{
+		{Type: orderDomain.STOCK_MOVEMENT_SALE, Quantity: -1},
+		{Type: orderDomain.STOCK_MOVEMENT_TRANSFER, Quantity: 1},
+	} {
+		if _, err := f.service.RecordMovement(ctx, uuid.New(), skuID, payload); !errors.Is(err, ErrMovementType) {
+			t.Fatalf("%s: got %v, want ErrMovementType", payload.Type, err)
+		}
+	}
+	if _, err := f.service.RecordMovement(ctx, uuid.New(), skuID, orderDomain.RecordStockMovementDomain{Type: orderDomain.STOCK_MOVEMENT_RETURN, Quantity: -1}); !errors.Is(err, ErrInvalidQuantity) {
+		t.Fatalf("got %v, want ErrInvalidQuantity", err)
+	}
+	if _, err := f.service.RecordMovement(ctx, uuid.New(), uuid.New(), orderDomain.RecordStockMovementDomain{Type: orderDomain.STOCK_MOVEMENT_RECEIPT, Quantity: 1}); !errors.Is(err, ErrInventoryNotFound) {
+		t.Fatalf("got %v, want ErrInventoryNotFound", err)
+	}
+}
+
+func TestReserveAllocatesAcrossWarehouses(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	actorID := uuid.New()
+	skuID := f.sku(1)
+	north := f.warehouse("CNX", 1, "TH", "50000")
+	if _, err := f.service.RecordMovement(ctx, actorID, skuID, orderDomain.RecordStockMovementDomain{
+		Type:        orderDomain.STOCK_MOVEMENT_RECEIPT,
+		Quantity:    5,
+		Reason:      "delivery",
+		WarehouseID: &north,
+	}); err != nil {
+		t.Fatalf("RecordMovement: %v", err)
+	}
+
+	// The default warehouse comes first, but only the other one has 3 units.
+	reservations, err := f.service.Reserve(ctx, nil, uuid.New(), lines(skuID, 3))
+	if err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	if len(reservations) != 1 || reservations[0].WarehouseID != north {
+		t.Fatalf("reservations = %+v, want the units from %s", reservations, north)
+	}
+	stock, err := f.service.GetInventory(ctx, skuID)
+	if err != nil || stock.Quantity != 6 || stock.Reserved != 3 || stock.Available() != 3 || len(stock.Locations) != 2 {
+		t.Fatalf("GetInventory = %+v, %v", stock, err)
+	}
+	if f.repo.stock[skuID] != 6 {
+		t.Fatalf("SKU stock = %d, want the sum of the warehouses", f.repo.stock[skuID])
+	}
+
+	// An inactive warehouse keeps its units but ships none of them.
+	f.warehouses.warehouses[1].Active = false
+	if _, err := f.service.Reserve(ctx, nil, uuid.New(), lines(skuID, 2)); !errors.Is(err, ErrOutOfStock) {
+		t.Fatalf("got %v, want ErrOutOfStock", err)
+	}
+	f.reconciled(t)
+}
+
+// badStrategy ships every line from the first warehouse, whether it has the
+// units or not.
+type badStrategy struct{}
+
+func (badStrategy) Allocate(request orderDomain.AllocationRequest) ([]orderDomain.Allocation, error) {
+	var allocations []orderDomain.Allocation
+	for _, line := range request.Lines {
+		allocations = append(allocations, orderDomain.Allocation{SKUID: line.SKUID, WarehouseID: request.Warehouses[0].ID, Quantity: line.Quantity})
+	}
+	return allocations, nil
+}
+
+func TestReserveChecksTheAllocation(t *testing.T) {
+	f := newInventoryFixture()
+	f.service.allocator = badStrategy{}
+	skuID := f.sku(1)
+	if _, err := f.service.Reserve(context.Background(), nil, uuid.New(), lines(skuID, 2)); !errors.Is(err, ErrInvalidAllocation) {
+		t.Fatalf("got %v, want ErrInvalidAllocation", err)
+	}
+	if inventory := f.inventory(t, skuID); inventory.Reserved != 0 {
+		t.Fatalf("inventory = %+v, nothing should be reserved", inventory)
+	}
+}
+
+func TestTransfer(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	actorID := uuid.New()
+	skuID := f.sku(5)
+	north := f.warehouse("CNX", 1, "TH", "50000")
+	if _, err := f.service.Reserve(ctx, nil, uuid.New(), lines(skuID, 2)); err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+
+	transfer := orderDomain.TransferStockDomain{FromWarehouseID: f.home, ToWarehouseID: north, Quantity: 3, Reason: "rebalance"}
+	movements, err := f.service.Transfer(ctx, actorID, skuID, transfer)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if len(movements) != 2 || movements[0].Quantity != -3 || movements[1].Quantity != 3 ||
+		movements[0].ReferenceID == nil || *movements[0].ReferenceID != *movements[1].ReferenceID {
+		t.Fatalf("movements = %+v", movements)
+	}
+	if home, other := f.inventory(t, skuID), f.inventoryAt(t, skuID, north); home.Quantity != 2 || other.Quantity != 3 || f.repo.stock[skuID] != 5 {
+		t.Fatalf("home = %+v, north = %+v, SKU stock = %d", home, other, f.repo.stock[skuID])
+	}
+
+	// The 2 units left at home are held by the checkout.
+	transfer.Quantity = 1
+	if _, err := f.service.Transfer(ctx, actorID, skuID, transfer); !errors.Is(err, ErrStockBelowReserved) {
+		t.Fatalf("got %v, want ErrStockBelowReserved", err)
+	}
+	transfer.ToWarehouseID = f.home
+	if _, err := f.service.Transfer(ctx, actorID, skuID, transfer); !errors.Is(err, ErrSameWarehouse) {
+		t.Fatalf("got %v, want ErrSameWarehouse", err)
+	}
+	transfer.ToWarehouseID = uuid.New()
+	if _, err := f.service.Transfer(ctx, actorID, skuID, transfer); !errors.Is(err, ErrWarehouseNotFound) {
+		t.Fatalf("got %v, want ErrWarehouseNotFound", err)
+	}
+	f.reconciled(t)
+}
+
+func TestSetStockOfTheTotal(t *testing.T) {
+	f := newInventoryFixture()
+	ctx := context.Background()
+	actorID := uuid.New()
+	skuID := f.sku(5)
+	north := f.warehouse("CNX", 1, "TH", "50000")
+	if _, err := f.service.Transfer(ctx, actorID, skuID, orderDomain.TransferStockDomain{FromWarehouseID: f.home, ToWarehouseID: north, Quantity: 3, Reason: "rebalance"}); err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+
+	// A total counts the difference at the default warehouse.
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 4, Reason: "count"}); err != nil {
+		t.Fatalf("SetStock: %v", err)
+	}
+	if home := f.inventory(t, skuID); home.Quantity != 1 || f.repo.stock[skuID] != 4 {
+		t.Fatalf("home = %+v, SKU stock = %d", home, f.repo.stock[skuID])
+	}
+	if err := f.service.CheckStock(ctx, skuID, 2); !errors.Is(err, ErrStockAtOtherWarehouses) {
+		t.Fatalf("got %v, want ErrStockAtOtherWarehouses", err)
+	}
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 2, Reason: "count"}); !errors.Is(err, ErrStockAtOtherWarehouses) {
+		t.Fatalf("got %v, want ErrStockAtOtherWarehouses", err)
+	}
+	// A count of one warehouse sets that warehouse only.
+	if err := f.service.SetStock(ctx, actorID, skuID, orderDomain.SetStockDomain{Quantity: 1, Reason: "count", WarehouseID: &north}); err != nil {
+		t.Fatalf("SetStock: %v", err)
+	}
+	if other := f.inventoryAt(t, skuID, north); other.Quantity != 1 || f.repo.stock[skuID] != 2 {
+		t.Fatalf("north = %+v, SKU stock = %d", other, f.repo.stock[skuID])
+	}
+	f.reconciled(t)
+}
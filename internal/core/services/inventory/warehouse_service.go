@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/postal"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWarehouseNotFound   = errors.New("warehouse not found")
+	ErrWarehouseCodeTaken  = errors.New("warehouse code is already in use")
+	ErrDefaultWarehouseOff = errors.New("the default warehouse must stay active")
+	// ErrDefaultWarehouseUnset is returned when the default mark is taken off
+	// a warehouse; marking another warehouse as the default moves it.
+	ErrDefaultWarehouseUnset = errors.New("mark another warehouse as the default instead")
+)
+
+type WarehouseServiceImpl struct {
+	warehouseRepo ports.IWarehouseRepository
+	transactor    transactors.IDatabaseTransactor
+}
+
+func NewWarehouseService(
+	warehouseRepo ports.IWarehouseRepository,
+	transactor transactors.IDatabaseTransactor,
+) ports.IWarehouseService {
+	return &WarehouseServiceImpl{
+		warehouseRepo: warehouseRepo,
+		transactor:    transactor,
+	}
+}
+
+// ListWarehouses implements ports.IWarehouseService.
+func (s *WarehouseServiceImpl) ListWarehouses(ctx context.Context) ([]orderDomain.Warehouse, error) {
+	return s.warehouseRepo.ListWarehouses(ctx, false)
+}
+
+// GetWarehouse implements ports.IWarehouseService.
+func (s *WarehouseServiceImpl) GetWarehouse(ctx context.Context, id uuid.UUID) (*orderDomain.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.GetWarehouse(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWarehouseNotFound
+	}
+	return warehouse, err
+}
+
+// CreateWarehouse implements ports.IWarehouseService.
+func (s *WarehouseServiceImpl) CreateWarehouse(ctx context.Context, payload orderDomain.UpsertWarehouseDomain) (*orderDomain.Warehouse, error) {
+	warehouse := &orderDomain.Warehouse{}
+	if err := applyWarehouse(warehouse, payload); err != nil {
+		return nil, err
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkCodeFree(ctx, warehouse.Code, nil); err != nil {
+			return err
+		}
+		if err := s.takeDefault(ctx, warehouse); err != nil {
+			return err
+		}
+		return s.warehouseRepo.CreateWarehouse(ctx, warehouse)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+// UpdateWarehouse implements ports.IWarehouseService.
+func (s *WarehouseServiceImpl) UpdateWarehouse(ctx context.Context, id uuid.UUID, payload orderDomain.UpsertWarehouseDomain) (*orderDomain.Warehouse, error) {
+	var warehouse *orderDomain.Warehouse
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if warehouse, err = s.GetWarehouse(ctx, id); err != nil {
+			return err
+		}
+		if warehouse.IsDefault && !payload.IsDefault {
+			return ErrDefaultWarehouseUnset
+		}
+		if err := applyWarehouse(warehouse, payload); err != nil {
+			return err
+		}
+		if err := s.checkCodeFree(ctx, warehouse.Code, &warehouse.ID); err != nil {
+			return err
+		}
+		if err := s.takeDefault(ctx, warehouse); err != nil {
+			return err
+		}
+		return s.warehouseRepo.UpdateWarehouse(ctx, warehouse)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return warehouse, nil
+}
+
+func (s *WarehouseServiceImpl) checkCodeFree(ctx context.Context, code string, exceptID *uuid.UUID) error {
+	taken, err := s.warehouseRepo.ExistsWarehouseCode(ctx, code, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrWarehouseCodeTaken
+	}
+	return nil
+}
+
+// takeDefault takes the default mark off the other warehouses when the
+// warehouse is to be the default. It runs before the warehouse is saved, as
+// only one warehouse may carry the mark at any time.
+func (s *WarehouseServiceImpl) takeDefault(ctx context.Context, warehouse *orderDomain.Warehouse) error {
+	if !warehouse.IsDefault {
+		return nil
+	}
+	return s.warehouseRepo.ClearDefaultWarehouse(ctx, warehouse.ID)
+}
+
+// applyWarehouse copies the payload onto the warehouse, with its code upper
+// cased and its address normalised like the address book does.
+func applyWarehouse(warehouse *orderDomain.Warehouse, payload orderDomain.UpsertWarehouseDomain) error {
+	if payload.IsDefault && !payload.Active {
+		return ErrDefaultWarehouseOff
+	}
+	codes := postal.Normalize(postal.Address{
+		CountryCode: payload.CountryCode,
+		Region:      payload.Region,
+		PostalCode:  payload.PostalCode,
+		Phone:       payload.Phone,
+	})
+	if err := postal.Validate(codes); err != nil {
+		return err
+	}
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(payload.Code))
+	warehouse.Name = strings.TrimSpace(payload.Name)
+	warehouse.Address = domain.PostalAddress{
+		Recipient:   warehouse.Name,
+		Line1:       strings.TrimSpace(payload.Line1),
+		Line2:       strings.TrimSpace(payload.Line2),
+		City:        strings.TrimSpace(payload.City),
+		Region:      codes.Region,
+		PostalCode:  codes.PostalCode,
+		CountryCode: codes.CountryCode,
+		Phone:       codes.Phone,
+	}
+	warehouse.Priority = payload.Priority
+	warehouse.IsDefault = payload.IsDefault
+	warehouse.Active = payload.Active
+	return nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/postal"
+	"github.com/google/uuid"
+)
+
+func warehousePayload(code string) orderDomain.UpsertWarehouseDomain {
+	return orderDomain.UpsertWarehouseDomain{
+		Code:        code,
+		Name:        "Warehouse " + code,
+		Line1:       "1 Rama IV Road",
+		City:        "Pathum Wan",
+		Region:      "Bangkok",
+		PostalCode:  "10330",
+		CountryCode: "th",
+		Active:      true,
+	}
+}
+
+func TestWarehouseDefaultMoves(t *testing.T) {
+	repo := &fakeWarehouseRepo{}
+	service := NewWarehouseService(repo, transactorstest.Transactor{})
+	ctx := context.Background()
+
+	payload := warehousePayload(" bkk-1 ")
+	payload.IsDefault = true
+	first, err := service.CreateWarehouse(ctx, payload)
+	if err != nil {
+		t.Fatalf("CreateWarehouse: %v", err)
+	}
+	if first.Code != "BKK-1" || first.Address.CountryCode != "TH" || first.Address.Recipient != first.Name {
+		t.Fatalf("warehouse = %+v", first)
+	}
+	if _, err := service.CreateWarehouse(ctx, warehousePayload("Bkk-1")); !errors.Is(err, ErrWarehouseCodeTaken) {
+		t.Fatalf("got %v, want ErrWarehouseCodeTaken", err)
+	}
+
+	payload = warehousePayload("CNX-1")
+	payload.IsDefault = true
+	second, err := service.CreateWarehouse(ctx, payload)
+	if err != nil {
+		t.Fatalf("CreateWarehouse: %v", err)
+	}
+	if home, _ := repo.GetDefaultWarehouse(ctx); home == nil || home.ID != second.ID {
+		t.Fatalf("default = %+v, want %s", home, second.ID)
+	}
+	if first, _ := repo.GetWarehouse(ctx, first.ID); first.IsDefault {
+		t.Fatal("the previous default should have lost the mark")
+	}
+
+	payload.IsDefault = false
+	if _, err := service.UpdateWarehouse(ctx, second.ID, payload); !errors.Is(err, ErrDefaultWarehouseUnset) {
+		t.Fatalf("got %v, want ErrDefaultWarehouseUnset", err)
+	}
+	payload.IsDefault, payload.Active = true, false
+	if _, err := service.UpdateWarehouse(ctx, second.ID, payload); !errors.Is(err, ErrDefaultWarehouseOff) {
+		t.Fatalf("got %v, want ErrDefaultWarehouseOff", err)
+	}
+}
+
+func TestWarehouseAddressIsValidated(t *testing.T) {
+	service := NewWarehouseService(&fakeWarehouseRepo{}, transactorstest.Transactor{})
+	payload := warehousePayload("BKK-1")
+	payload.PostalCode = "1033"
+	if _, err := service.CreateWarehouse(context.Background(), payload); !errors.Is(err, postal.ErrInvalidPostalCode) {
+		t.Fatalf("got %v, want ErrInvalidPostalCode", err)
+	}
+	if _, err := service.GetWarehouse(context.Background(), uuid.New()); !errors.Is(err, ErrWarehouseNotFound) {
+		t.Fatalf("got %v, want ErrWarehouseNotFound", err)
+	}
+}
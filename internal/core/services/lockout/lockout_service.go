@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/lockout"
+)
+
+var (
+	ErrAccountLocked        = errors.New("account is temporarily locked after too many failed logins, check your email to unlock it")
+	ErrIPLocked             = errors.New("too many failed logins from this address, try again later")
+	ErrLoginThrottled       = errors.New("too many failed logins, wait before trying again")
+	ErrLockNotFound         = errors.New("no failed logins recorded for this subject")
+	ErrChallengeExhausted   = errors.New("too many wrong codes for this login, log in again")
+	ErrLockoutMisconfigured = errors.New("lockout policy needs MaxFailures above FreeFailures")
+)
+
+// LockoutPolicy sets the limits for one scope.
+type LockoutPolicy struct {
+	FreeFailures int           // Failures allowed before the progressive delay starts
+	MaxFailures  int           // Failures within the window that lock the subject
+	LockDuration time.Duration // How long a lock lasts unless it is cleared
+}
+
+// LockoutServiceConfig holds the tunables of LockoutServiceImpl.
+type LockoutServiceConfig struct {
+	Window    time.Duration // A quiet period this long resets the counters
+	BaseDelay time.Duration // Delay after the first failure beyond FreeFailures, doubled for each further failure
+	MaxDelay  time.Duration
+	Account   LockoutPolicy
+	IP        LockoutPolicy
+	// ChallengeFailures is how many wrong second factors a two-factor
+	// challenge accepts before it is refused.
+	ChallengeFailures int
+}
+
+// DefaultLockoutServiceConfig is used by the app wiring. The IP limits are
+// looser because many customers can share an address behind NAT.
+var DefaultLockoutServiceConfig = LockoutServiceConfig{
+	Window:    15 * time.Minute,
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+	Account:   LockoutPolicy{FreeFailures: 3, MaxFailures: 10, LockDuration: 30 * time.Minute},
+	IP:        LockoutPolicy{FreeFailures: 10, MaxFailures: 50, LockDuration: 15 * time.Minute},
+
+	ChallengeFailures: 5,
+}
+
+type LockoutServiceImpl struct {
+	counter ports.ILoginAttemptCounter
+	config  LockoutServiceConfig
+	now     func() time.Time
+}
+
+func NewLockoutService(counter ports.ILoginAttemptCounter, config LockoutServiceConfig) ports.ILockoutService {
+	return &LockoutServiceImpl{counter: counter, config: config, now: time.Now}
+}
+
+// Check implements ports.ILockoutService.
+//
+// Delays are enforced by refusing attempts that come too early rather than by
+// sleeping, so an attacker cannot tie up server goroutines.
+func (s *LockoutServiceImpl) Check(ctx context.Context, account, ip string) error {
+	now := s.now()
+	if ip != "" {
+		attempt, err := s.counter.GetAttempt(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_IP, ip)
+		if err != nil {
+			return err
+		}
+		if err := s.checkAttempt(attempt, s.config.IP, now, ErrIPLocked); err != nil {
+			return err
+		}
+	}
+
+	attempt, err := s.counter.GetAttempt(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account)
+	if err != nil {
+		return err
+	}
+	return s.checkAttempt(attempt, s.config.Account, now, ErrAccountLocked)
+}
+
+// ReserveAttempt implements ports.ILockoutService.
+func (s *LockoutServiceImpl) ReserveAttempt(ctx context.Context, account, ip string) error {
+	if ip != "" {
+		if err := s.reserve(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_IP, ip, s.config.IP, ErrIPLocked); err != nil {
+			return err
+		}
+	}
+	return s.reserve(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account, s.config.Account, ErrAccountLocked)
+}
+
+// ReleaseAttempt implements ports.ILockoutService.
+func (s *LockoutServiceImpl) ReleaseAttempt(ctx context.Context, account, ip string) error {
+	if ip != "" {
+		if err := s.counter.ReleaseFailure(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_IP, ip); err != nil {
+			return err
+		}
+	}
+	return s.counter.ReleaseFailure(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account)
+}
+
+// RegisterFailure implements ports.ILockoutService.
+//
+// The failure itself was counted by ReserveAttempt.
+func (s *LockoutServiceImpl) RegisterFailure(ctx context.Context, account, ip string) (bool, error) {
+	now := s.now()
+	if ip != "" {
+		attempt, err := s.counter.GetAttempt(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_IP, ip)
+		if err != nil {
+			return false, err
+		}
+		if _, err := s.lockIfExhausted(ctx, attempt, s.config.IP, now); err != nil {
+			return false, err
+		}
+	}
+	attempt, err := s.counter.GetAttempt(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account)
+	if err != nil {
+		return false, err
+	}
+	return s.lockIfExhausted(ctx, attempt, s.config.Account, now)
+}
+
+// CheckChallenge implements ports.ILockoutService.
+func (s *LockoutServiceImpl) CheckChallenge(ctx context.Context, account, challenge string) error {
+	attempt, err := s.counter.GetAttempt(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_CHALLENGE, challenge)
+	if err != nil {
+		return err
+	}
+	if attempt != nil && attempt.Failures >= s.config.ChallengeFailures {
+		return ErrChallengeExhausted
+	}
+	return s.Check(ctx, account, "")
+}
+
+// RegisterChallengeFailure implements ports.ILockoutService.
+//
+// The challenge is counted over the whole window, which outlives any
+// challenge, so its count never restarts.
+func (s *LockoutServiceImpl) RegisterChallengeFailure(ctx context.Context, account, challenge string) (bool, error) {
+	now := s.now()
+	if _, err := s.counter.RegisterFailure(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_CHALLENGE, challenge, now, now.Add(-s.config.Window)); err != nil {
+		return false, err
+	}
+	return s.registerFailure(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account, s.config.Account)
+}
+
+// RegisterSuccess implements ports.ILockoutService.
+//
+// Only the account counter is reset: one valid password from an address that
+// is stuffing credentials should not clear the address.
+func (s *LockoutServiceImpl) RegisterSuccess(ctx context.Context, account string) error {
+	return s.counter.Reset(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, account)
+}
+
+// ListLocks implements ports.ILockoutService.
+func (s *LockoutServiceImpl) ListLocks(ctx context.Context) ([]authDomain.LoginAttempt, error) {
+	return s.counter.ListLocked(ctx, s.now())
+}
+
+// ClearLock implements ports.ILockoutService.
+func (s *LockoutServiceImpl) ClearLock(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string) error {
+	attempt, err := s.counter.GetAttempt(ctx, scope, subject)
+	if err != nil {
+		return err
+	}
+	if attempt == nil {
+		return ErrLockNotFound
+	}
+	return s.counter.Reset(ctx, scope, subject)
+}
+
+func (s *LockoutServiceImpl) checkAttempt(attempt *authDomain.LoginAttempt, policy LockoutPolicy, now time.Time, lockedErr error) error {
+	if attempt == nil {
+		return nil
+	}
+	if attempt.IsLocked(now) {
+		return lockedErr
+	}
+	if attempt.LastFailureAt.Before(now.Add(-s.config.Window)) {
+		return nil
+	}
+	// Reserved attempts can use up the failures before the one that fails
+	// last gets to lock the subject.
+	if attempt.LockedUntil == nil && attempt.Failures >= policy.MaxFailures {
+		return lockedErr
+	}
+	if wait := attempt.LastFailureAt.Add(s.delay(attempt.Failures, policy)).Sub(now); wait > 0 {
+		return fmt.Errorf("%w (retry in %s)", ErrLoginThrottled, wait.Round(time.Second))
+	}
+	return nil
+}
+
+// maxReserveTries bounds how often reserve reads a counter again after other
+// attempts changed it. Each retry means another attempt was counted, so a
+// subject this busy is throttled anyway.
+const maxReserveTries = 5
+
+// reserve checks the counter and counts a failure on the state it checked,
+// starting over when another attempt was counted in between.
+func (s *LockoutServiceImpl) reserve(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, policy LockoutPolicy, lockedErr error) error {
+	if policy.MaxFailures <= policy.FreeFailures {
+		return ErrLockoutMisconfigured
+	}
+	for i := 0; i < maxReserveTries; i++ {
+		now := s.now()
+		attempt, err := s.counter.GetAttempt(ctx, scope, subject)
+		if err != nil {
+			return err
+		}
+		if err := s.checkAttempt(attempt, policy, now, lockedErr); err != nil {
+			return err
+		}
+		reserved, err := s.counter.ReserveFailure(ctx, scope, subject, attempt, now, now.Add(-s.config.Window))
+		if err != nil {
+			return err
+		}
+		if reserved != nil {
+			return nil
+		}
+	}
+	return ErrLoginThrottled
+}
+
+func (s *LockoutServiceImpl) registerFailure(ctx context.Context, scope authDomain.LOGIN_ATTEMPT_SCOPE, subject string, policy LockoutPolicy) (bool, error) {
+	if policy.MaxFailures <= policy.FreeFailures {
+		return false, ErrLockoutMisconfigured
+	}
+	now := s.now()
+	attempt, err := s.counter.RegisterFailure(ctx, scope, subject, now, now.Add(-s.config.Window))
+	if err != nil {
+		return false, err
+	}
+	return s.lockIfExhausted(ctx, attempt, policy, now)
+}
+
+// lockIfExhausted locks the subject of the attempt once its failures reached
+// the limit, and reports whether it did.
+func (s *LockoutServiceImpl) lockIfExhausted(ctx context.Context, attempt *authDomain.LoginAttempt, policy LockoutPolicy, now time.Time) (bool, error) {
+	if attempt == nil || attempt.Failures < policy.MaxFailures || attempt.IsLocked(now) {
+		return false, nil
+	}
+	if err := s.counter.Lock(ctx, attempt.Scope, attempt.Subject, now.Add(policy.LockDuration)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// delay is BaseDelay doubled for every failure past FreeFailures, capped at
+// MaxDelay.
+func (s *LockoutServiceImpl) delay(failures int, policy LockoutPolicy) time.Duration {
+	excess := failures - policy.FreeFailures
+	if excess <= 0 {
+		return 0
+	}
+	delay := s.config.BaseDelay
+	for i := 1; i < excess && delay < s.config.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxDelay {
+		delay = s.config.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	repositories "github.com/billowdev/go-fiber-e-commerce/internal/adapters/repositories/lockout"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+)
+
+var testLockoutConfig = LockoutServiceConfig{
+	Window:    15 * time.Minute,
+	BaseDelay: time.Second,
+	MaxDelay:  4 * time.Second,
+	Account:   LockoutPolicy{FreeFailures: 2, MaxFailures: 6, LockDuration: 30 * time.Minute},
+	IP:        LockoutPolicy{FreeFailures: 3, MaxFailures: 5, LockDuration: 10 * time.Minute},
+}
+
+func newTestLockoutService() (*LockoutServiceImpl, *time.Time) {
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc := NewLockoutService(repositories.NewMemoryLoginAttemptCounter(), testLockoutConfig).(*LockoutServiceImpl)
+	svc.now = func() time.Time { return clock }
+	return svc, &clock
+}
+
+// fail records a failed login the way Login does: the attempt is reserved
+// before the password is checked and the failure registered afterwards.
+func fail(t *testing.T, svc *LockoutServiceImpl, account, ip string) bool {
+	t.Helper()
+	ctx := context.Background()
+	if err := svc.ReserveAttempt(ctx, account, ip); err != nil {
+		t.Fatalf("ReserveAttempt: %v", err)
+	}
+	locked, err := svc.RegisterFailure(ctx, account, ip)
+	if err != nil {
+		t.Fatalf("RegisterFailure: %v", err)
+	}
+	return locked
+}
+
+// withoutDelays lets failures follow each other without waiting.
+func withoutDelays(svc *LockoutServiceImpl) {
+	svc.config.BaseDelay = 0
+}
+
+func TestProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	svc, clock := newTestLockoutService()
+
+	wantDelays := []time.Duration{0, 0, time.Second, 2 * time.Second, 4 * time.Second}
+	for i, want := range wantDelays {
+		fail(t, svc, "alice", "")
+		err := svc.Check(ctx, "alice", "")
+		if want == 0 {
+			if err != nil {
+				t.Fatalf("failure %d: unexpected %v", i+1, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrLoginThrottled) {
+			t.Fatalf("failure %d: err = %v, want ErrLoginThrottled", i+1, err)
+		}
+		*clock = clock.Add(want - time.Millisecond)
+		if err := svc.Check(ctx, "alice", ""); !errors.Is(err, ErrLoginThrottled) {
+			t.Fatalf("failure %d: throttle lifted before %s", i+1, want)
+		}
+		*clock = clock.Add(time.Millisecond)
+		if err := svc.Check(ctx, "alice", ""); err != nil {
+			t.Fatalf("failure %d: still throttled after %s: %v", i+1, want, err)
+		}
+	}
+
+	if err := svc.RegisterSuccess(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	fail(t, svc, "alice", "")
+	if err := svc.Check(ctx, "alice", ""); err != nil {
+		t.Errorf("a success should reset the account counter, got %v", err)
+	}
+}
+
+func TestAccountLockExpiresAndCanBeCleared(t *testing.T) {
+	ctx := context.Background()
+	svc, clock := newTestLockoutService()
+	withoutDelays(svc)
+
+	var locked bool
+	for i := 0; i < testLockoutConfig.Account.MaxFailures; i++ {
+		locked = fail(t, svc, "alice", "")
+	}
+	if !locked {
+		t.Fatal("expected the last failure to lock the account")
+	}
+	if err := svc.Check(ctx, "alice", ""); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("err = %v, want ErrAccountLocked", err)
+	}
+
+	locks, err := svc.ListLocks(ctx)
+	if err != nil || len(locks) != 1 || locks[0].Subject != "alice" {
+		t.Fatalf("ListLocks = %+v, %v", locks, err)
+	}
+
+	*clock = clock.Add(testLockoutConfig.Account.LockDuration)
+	if err := svc.Check(ctx, "alice", ""); err != nil {
+		t.Fatalf("lock should have expired, got %v", err)
+	}
+	// The first failure after a lock starts a fresh count.
+	if fail(t, svc, "alice", "") {
+		t.Fatal("an expired lock must not relock on the next failure")
+	}
+
+	for i := 1; i < testLockoutConfig.Account.MaxFailures; i++ {
+		fail(t, svc, "alice", "")
+	}
+	if err := svc.ClearLock(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Check(ctx, "alice", ""); err != nil {
+		t.Errorf("cleared lock still refuses logins: %v", err)
+	}
+	if err := svc.ClearLock(ctx, authDomain.LOGIN_ATTEMPT_SCOPE_ACCOUNT, "alice"); !errors.Is(err, ErrLockNotFound) {
+		t.Errorf("err = %v, want ErrLockNotFound", err)
+	}
+}
+
+func TestIPLockAcrossAccounts(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestLockoutService()
+	withoutDelays(svc)
+
+	for i := 0; i < testLockoutConfig.IP.MaxFailures; i++ {
+		fail(t, svc, "user"+string(rune('a'+i)), "203.0.113.7")
+	}
+	if err := svc.Check(ctx, "someone-else", "203.0.113.7"); !errors.Is(err, ErrIPLocked) {
+		t.Errorf("err = %v, want ErrIPLocked", err)
+	}
+	if err := svc.Check(ctx, "someone-else", "198.51.100.1"); err != nil {
+		t.Errorf("another address should not be affected: %v", err)
+	}
+}
+
+func TestQuietWindowResetsCounter(t *testing.T) {
+	svc, clock := newTestLockoutService()
+	withoutDelays(svc)
+
+	for i := 0; i < testLockoutConfig.Account.MaxFailures-1; i++ {
+		fail(t, svc, "alice", "")
+	}
+	*clock = clock.Add(testLockoutConfig.Window + time.Second)
+	if fail(t, svc, "alice", "") {
+		t.Error("failures before the window should not count")
+	}
+}
+
+// TestConcurrentReservationsStopAtTheLimit races more attempts than the
+// account allows. Each reservation is counted before the next one is
+// checked, so only MaxFailures of them get through.
+func TestConcurrentReservationsStopAtTheLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestLockoutService()
+	withoutDelays(svc)
+
+	const attempts = 40
+	var (
+		reserved atomic.Int32
+		wg       sync.WaitGroup
+		start    = make(chan struct{})
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if err := svc.ReserveAttempt(ctx, "alice", ""); err == nil {
+				reserved.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got := reserved.Load(); got != int32(testLockoutConfig.Account.MaxFailures) {
+		t.Fatalf("%d attempts reserved, want %d", got, testLockoutConfig.Account.MaxFailures)
+	}
+	if err := svc.ReserveAttempt(ctx, "alice", ""); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("err = %v, want ErrAccountLocked", err)
+	}
+	if locked, err := svc.RegisterFailure(ctx, "alice", ""); err != nil || !locked {
+		t.Fatalf("RegisterFailure = %v, %v; want the account locked", locked, err)
+	}
+}
@@ -0,0 +1,578 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	authPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/oauth"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthProviderNotFound      = errors.New("oauth provider not found")
+	ErrOAuthProviderExists        = errors.New("oauth provider name is already in use")
+	ErrInvalidOAuthState          = errors.New("invalid or expired oauth state")
+	ErrOAuthDenied                = errors.New("oauth provider denied the request")
+	ErrOAuthEmailRequired         = errors.New("oauth provider did not return an email address")
+	ErrOAuthEmailInUse            = errors.New("an account with this email already exists, log in and link the provider instead")
+	ErrOAuthIdentityLinked        = errors.New("this provider account is linked to another user")
+	ErrOAuthProviderAlreadyLinked = errors.New("another account of this provider is already linked")
+	ErrOAuthNotLinked             = errors.New("no provider tokens stored for this user")
+)
+
+const (
+	// oauthStateTTL is how long the user has to finish at the provider.
+	oauthStateTTL = 10 * time.Minute
+
+	oauthRandomBytes = 32
+)
+
+var usernameDisallowed = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+type OAuthServiceImpl struct {
+	providerRepo ports.IOAuthProviderRepository
+	oauthRepo    ports.IOAuthRepository
+	userRepo     userPorts.IUserRepository
+	roleRepo     rolePorts.IRoleRepository
+	client       ports.IOAuthClient
+	authService  authPorts.IAuthService
+	transactor   transactors.IDatabaseTransactor
+	cipher       *encryption.Cipher
+	hashParams   *argon2id.Params
+}
+
+func NewOAuthService(
+	providerRepo ports.IOAuthProviderRepository,
+	oauthRepo ports.IOAuthRepository,
+	userRepo userPorts.IUserRepository,
+	roleRepo rolePorts.IRoleRepository,
+	client ports.IOAuthClient,
+	authService authPorts.IAuthService,
+	transactor transactors.IDatabaseTransactor,
+	cipher *encryption.Cipher,
+	hashParams *argon2id.Params,
+) ports.IOAuthService {
+	return &OAuthServiceImpl{
+		providerRepo: providerRepo,
+		oauthRepo:    oauthRepo,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		client:       client,
+		authService:  authService,
+		transactor:   transactor,
+		cipher:       cipher,
+		hashParams:   hashParams,
+	}
+}
+
+// ListEnabledProviders implements ports.IOAuthService.
+func (s *OAuthServiceImpl) ListEnabledProviders(ctx context.Context) ([]string, error) {
+	providers, err := s.providerRepo.ListProviders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		if !p.Disabled {
+			names = append(names, p.ProviderName)
+		}
+	}
+	return names, nil
+}
+
+// Authorize implements ports.IOAuthService.
+//
+// The state and the PKCE verifier are random per request. Only the hash of the
+// state and the encrypted verifier are stored until the callback.
+func (s *OAuthServiceImpl) Authorize(ctx context.Context, providerName string, linkUserID *uuid.UUID) (*authDomain.OAuthAuthorization, error) {
+	provider, err := s.getEnabledProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := tokens.NewOpaqueToken(oauthRandomBytes)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := tokens.NewOpaqueToken(oauthRandomBytes)
+	if err != nil {
+		return nil, err
+	}
+	encryptedVerifier, err := s.cipher.Encrypt(verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &authDomain.OAuthState{
+		StateHash:    tokens.HashToken(state),
+		ProviderID:   provider.ID,
+		CodeVerifier: encryptedVerifier,
+		LinkUserID:   linkUserID,
+		ExpiresAt:    time.Now().Add(oauthStateTTL),
+	}
+	if err := s.oauthRepo.CreateState(ctx, record); err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := buildAuthorizationURL(provider, state, tokens.PKCEChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+	return &authDomain.OAuthAuthorization{
+		AuthorizationURL: authorizationURL,
+		State:            state,
+		ExpiresAt:        record.ExpiresAt,
+	}, nil
+}
+
+// Callback implements ports.IOAuthService.
+//
+// The identity comes from the provider's userinfo endpoint, called with the
+// access token we just redeemed over TLS, so the ID token does not need to be
+// verified separately. An identity is matched by (provider, sub); failing
+// that, a verified email links to the existing user with that address, and
+// otherwise a new customer account is created.
+func (s *OAuthServiceImpl) Callback(ctx context.Context, providerName string, payload authDomain.OAuthCallbackDomain) (*authDomain.LoginResult, error) {
+	if payload.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrOAuthDenied, payload.Error, payload.ErrorDescription)
+	}
+	provider, err := s.getEnabledProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.consumeState(ctx, provider.ID, payload.State)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Code == "" {
+		return nil, ErrInvalidOAuthState
+	}
+
+	verifier, err := s.cipher.Decrypt(state.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := s.decryptOptional(provider.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.client.ExchangeCode(ctx, provider, clientSecret, payload.Code, verifier)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.client.UserInfo(ctx, provider, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *userDomain.User
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if user, err = s.resolveUser(txCtx, provider, info, state.LinkUserID); err != nil {
+			return err
+		}
+		return s.storeTokens(txCtx, user.ID, provider.ID, token)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if state.LinkUserID != nil {
+		return &authDomain.LoginResult{User: user}, nil
+	}
+	return s.authService.IssueSession(ctx, user)
+}
+
+// RefreshProviderToken implements ports.IOAuthService.
+func (s *OAuthServiceImpl) RefreshProviderToken(ctx context.Context, userID uuid.UUID, providerName string) (*authDomain.OAuthAccessToken, error) {
+	provider, err := s.getEnabledProvider(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.oauthRepo.GetRefreshToken(ctx, userID, provider.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthNotLinked
+		}
+		return nil, err
+	}
+	refreshToken, err := s.cipher.Decrypt(stored.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, err := s.decryptOptional(provider.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.client.RefreshToken(ctx, provider, clientSecret, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		// Providers that do not rotate refresh tokens keep the old one valid.
+		token.RefreshToken = refreshToken
+	}
+
+	var access *authDomain.OAuthAccessToken
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.storeTokens(txCtx, userID, provider.ID, token); err != nil {
+			return err
+		}
+		access, err = s.accessTokenRecord(userID, provider.ID, token)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// ListProviders implements ports.IOAuthService.
+func (s *OAuthServiceImpl) ListProviders(ctx context.Context) ([]authDomain.OAuthProvider, error) {
+	return s.providerRepo.ListProviders(ctx)
+}
+
+// CreateProvider implements ports.IOAuthService.
+func (s *OAuthServiceImpl) CreateProvider(ctx context.Context, payload authDomain.UpsertOAuthProviderDomain) (*authDomain.OAuthProvider, error) {
+	provider := &authDomain.OAuthProvider{}
+	if err := s.applyProvider(provider, payload); err != nil {
+		return nil, err
+	}
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.ensureProviderNameAvailable(txCtx, provider.ProviderName, uuid.Nil); err != nil {
+			return err
+		}
+		return s.providerRepo.CreateProvider(txCtx, provider)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// UpdateProvider implements ports.IOAuthService.
+func (s *OAuthServiceImpl) UpdateProvider(ctx context.Context, id uuid.UUID, payload authDomain.UpsertOAuthProviderDomain) (*authDomain.OAuthProvider, error) {
+	var provider *authDomain.OAuthProvider
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		provider, err = s.providerRepo.GetProviderByID(txCtx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOAuthProviderNotFound
+			}
+			return err
+		}
+		if err := s.applyProvider(provider, payload); err != nil {
+			return err
+		}
+		if err := s.ensureProviderNameAvailable(txCtx, provider.ProviderName, id); err != nil {
+			return err
+		}
+		return s.providerRepo.UpdateProvider(txCtx, provider)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (s *OAuthServiceImpl) getEnabledProvider(ctx context.Context, name string) (*authDomain.OAuthProvider, error) {
+	provider, err := s.providerRepo.GetProviderByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOAuthProviderNotFound
+		}
+		return nil, err
+	}
+	if provider.Disabled {
+		return nil, ErrOAuthProviderNotFound
+	}
+	return provider, nil
+}
+
+// consumeState marks the state as used in its own transaction, so it cannot be
+// replayed even when the code exchange that follows fails.
+func (s *OAuthServiceImpl) consumeState(ctx context.Context, providerID uuid.UUID, rawState string) (*authDomain.OAuthState, error) {
+	var state *authDomain.OAuthState
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		state, err = s.oauthRepo.GetStateByHash(txCtx, tokens.HashToken(rawState))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidOAuthState
+			}
+			return err
+		}
+		if state.Used || state.ProviderID != providerID || time.Now().After(state.ExpiresAt) {
+			return ErrInvalidOAuthState
+		}
+		return s.oauthRepo.MarkStateUsed(txCtx, state.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (s *OAuthServiceImpl) resolveUser(ctx context.Context, provider *authDomain.OAuthProvider, info *authDomain.OAuthUserInfo, linkUserID *uuid.UUID) (*userDomain.User, error) {
+	identity, err := s.oauthRepo.GetIdentity(ctx, provider.ID, info.Subject)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if identity != nil {
+		if linkUserID != nil && *linkUserID != identity.UserID {
+			return nil, ErrOAuthIdentityLinked
+		}
+		return s.userRepo.GetUserByID(ctx, identity.UserID)
+	}
+
+	var user *userDomain.User
+	switch {
+	case linkUserID != nil:
+		if user, err = s.userRepo.GetUserByID(ctx, *linkUserID); err != nil {
+			return nil, err
+		}
+		if _, err := s.oauthRepo.GetUserIdentity(ctx, user.ID, provider.ID); err == nil {
+			return nil, ErrOAuthProviderAlreadyLinked
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	default:
+		if user, err = s.findOrCreateUser(ctx, info); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.oauthRepo.CreateIdentity(ctx, &authDomain.OAuthIdentity{
+		UserID:     user.ID,
+		ProviderID: provider.ID,
+		Subject:    info.Subject,
+		Email:      info.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// findOrCreateUser links to an existing account only when the provider vouches
+// for the email address; otherwise anyone could claim an account by creating a
+// provider account with the victim's address.
+func (s *OAuthServiceImpl) findOrCreateUser(ctx context.Context, info *authDomain.OAuthUserInfo) (*userDomain.User, error) {
+	email := strings.ToLower(strings.TrimSpace(info.Email))
+	if email == "" {
+		return nil, ErrOAuthEmailRequired
+	}
+
+	existing, err := s.userRepo.GetUserByUsernameOrEmail(ctx, email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil && strings.EqualFold(existing.Email, email) {
+		if !info.EmailVerified {
+			return nil, ErrOAuthEmailInUse
+		}
+		if !existing.EmailVerified {
+			existing.EmailVerified = true
+			if err := s.userRepo.UpdateUser(ctx, existing); err != nil {
+				return nil, err
+			}
+		}
+		return existing, nil
+	}
+
+	username, err := s.availableUsername(ctx, info, email)
+	if err != nil {
+		return nil, err
+	}
+	// OAuth users have no password of their own; they can set one through the
+	// password reset flow.
+	unusable, err := tokens.NewOpaqueToken(oauthRandomBytes)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := argon2id.CreateHash(unusable, s.hashParams)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &userDomain.User{
+		FirstName:     firstNonEmpty(info.GivenName, info.Name, username),
+		LastName:      info.FamilyName,
+		Email:         email,
+		EmailVerified: info.EmailVerified,
+		Username:      username,
+		Password:      hash,
+		Status:        userDomain.USER_STATUS_ACTIVE,
+	}
+	role, err := s.roleRepo.GetRoleByName(ctx, userDomain.ROLE_CUSTOMER)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if role != nil {
+		user.RoleID = &role.ID
+	}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *OAuthServiceImpl) availableUsername(ctx context.Context, info *authDomain.OAuthUserInfo, email string) (string, error) {
+	base := info.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(email, "@")
+	}
+	base = usernameDisallowed.ReplaceAllString(strings.ToLower(base), "")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		_, err := s.userRepo.GetUserByUsernameOrEmail(ctx, candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", fmt.Errorf("could not find a free username for %q", base)
+}
+
+func (s *OAuthServiceImpl) storeTokens(ctx context.Context, userID, providerID uuid.UUID, token *authDomain.OAuthTokenResponse) error {
+	access, err := s.accessTokenRecord(userID, providerID, token)
+	if err != nil {
+		return err
+	}
+	if err := s.oauthRepo.UpsertAccessToken(ctx, access); err != nil {
+		return err
+	}
+	if token.RefreshToken == "" {
+		return nil
+	}
+
+	encrypted, err := s.cipher.Encrypt(token.RefreshToken)
+	if err != nil {
+		return err
+	}
+	return s.oauthRepo.UpsertRefreshToken(ctx, &authDomain.OAuthRefreshToken{
+		UserID:       userID,
+		ProviderID:   providerID,
+		RefreshToken: encrypted,
+	})
+}
+
+func (s *OAuthServiceImpl) accessTokenRecord(userID, providerID uuid.UUID, token *authDomain.OAuthTokenResponse) (*authDomain.OAuthAccessToken, error) {
+	encrypted, err := s.cipher.Encrypt(token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	record := &authDomain.OAuthAccessToken{
+		UserID:      userID,
+		ProviderID:  providerID,
+		AccessToken: encrypted,
+		TokenType:   token.TokenType,
+		ExpiresIn:   token.ExpiresIn,
+	}
+	if token.ExpiresIn > 0 {
+		expiresAt := time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+		record.ExpiresAt = &expiresAt
+	}
+	return record, nil
+}
+
+func (s *OAuthServiceImpl) applyProvider(provider *authDomain.OAuthProvider, payload authDomain.UpsertOAuthProviderDomain) error {
+	provider.ProviderName = strings.ToLower(strings.TrimSpace(payload.ProviderName))
+	provider.ClientID = payload.ClientID
+	provider.AuthorizeURL = payload.AuthorizeURL
+	provider.TokenURL = payload.TokenURL
+	provider.UserInfoURL = payload.UserInfoURL
+	provider.Scopes = payload.Scopes
+	provider.RedirectURL = payload.RedirectURL
+	provider.Disabled = payload.Disabled
+
+	if payload.ClientSecret != "" {
+		encrypted, err := s.cipher.Encrypt(payload.ClientSecret)
+		if err != nil {
+			return err
+		}
+		provider.ClientSecret = encrypted
+	}
+	return nil
+}
+
+func (s *OAuthServiceImpl) ensureProviderNameAvailable(ctx context.Context, name string, currentID uuid.UUID) error {
+	existing, err := s.providerRepo.GetProviderByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != currentID {
+		return ErrOAuthProviderExists
+	}
+	return nil
+}
+
+// decryptOptional decrypts a value that may be empty, as the client secret of
+// a public client is.
+func (s *OAuthServiceImpl) decryptOptional(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	return s.cipher.Decrypt(value)
+}
+
+func buildAuthorizationURL(provider *authDomain.OAuthProvider, state, challenge string) (string, error) {
+	u, err := url.Parse(provider.AuthorizeURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.ClientID)
+	query.Set("redirect_uri", provider.RedirectURL)
+	if provider.Scopes != "" {
+		query.Set("scope", provider.Scopes)
+	}
+	query.Set("state", state)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", tokens.PKCEChallengeMethod)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
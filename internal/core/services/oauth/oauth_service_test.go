@@ -0,0 +1,601 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	oauthClients "github.com/billowdev/go-fiber-e-commerce/internal/adapters/oauth"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	authDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/auth"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	authPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/encryption"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/tokens"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/uuidv7"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeIdP is a minimal authorization server: it issues codes bound to a PKCE
+// challenge, redeems them for tokens and serves a userinfo endpoint.
+type fakeIdP struct {
+	server       *httptest.Server
+	clientID     string
+	clientSecret string
+	claims       map[string]interface{}
+
+	mu            sync.Mutex
+	codes         map[string]string // code -> code_challenge
+	accessToken   string
+	refreshToken  string
+	issuedAccess  int
+	refreshCalled int
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	idp := &fakeIdP{
+		clientID:     "shop",
+		clientSecret: "idp-secret",
+		codes:        map[string]string{},
+		claims: map[string]interface{}{
+			"sub":                "idp-user-1",
+			"email":              "Jane@Example.com",
+			"email_verified":     true,
+			"given_name":         "Jane",
+			"family_name":        "Doe",
+			"preferred_username": "jane",
+		},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", idp.handleToken)
+	mux.HandleFunc("/userinfo", idp.handleUserInfo)
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *fakeIdP) provider() *authDomain.OAuthProvider {
+	return &authDomain.OAuthProvider{
+		ProviderName: "fake",
+		ClientID:     idp.clientID,
+		AuthorizeURL: idp.server.URL + "/authorize?prompt=login",
+		TokenURL:     idp.server.URL + "/token",
+		UserInfoURL:  idp.server.URL + "/userinfo",
+		Scopes:       "openid email profile",
+		RedirectURL:  "http://localhost/v1/auth/oauth/fake/callback",
+	}
+}
+
+// authorize plays the user approving the request in the browser.
+func (idp *fakeIdP) authorize(t *testing.T, authorizationURL string) (code, state string) {
+	t.Helper()
+	u, err := url.Parse(authorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != idp.clientID || q.Get("response_type") != "code" || q.Get("prompt") != "login" {
+		t.Fatalf("unexpected authorization request %s", authorizationURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		t.Fatalf("authorization request is missing PKCE: %s", authorizationURL)
+	}
+
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	code = "code-" + q.Get("state")[:8]
+	idp.codes[code] = q.Get("code_challenge")
+	return code, q.Get("state")
+}
+
+func (idp *fakeIdP) handleToken(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+
+	if err := r.ParseForm(); err != nil ||
+		r.PostForm.Get("client_id") != idp.clientID ||
+		r.PostForm.Get("client_secret") != idp.clientSecret {
+		writeOAuthError(w, "invalid_client")
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		challenge, ok := idp.codes[r.PostForm.Get("code")]
+		delete(idp.codes, r.PostForm.Get("code"))
+		if !ok || tokens.PKCEChallenge(r.PostForm.Get("code_verifier")) != challenge {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		idp.refreshToken = "refresh-1"
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != idp.refreshToken {
+			writeOAuthError(w, "invalid_grant")
+			return
+		}
+		idp.refreshCalled++
+	default:
+		writeOAuthError(w, "unsupported_grant_type")
+		return
+	}
+
+	idp.issuedAccess++
+	idp.accessToken = fmt.Sprintf("access-%d", idp.issuedAccess)
+	resp := map[string]interface{}{
+		"access_token": idp.accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	}
+	if r.PostForm.Get("grant_type") == "authorization_code" {
+		resp["refresh_token"] = idp.refreshToken
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (idp *fakeIdP) handleUserInfo(w http.ResponseWriter, r *http.Request) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer "+idp.accessToken {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(idp.claims)
+}
+
+func writeOAuthError(w http.ResponseWriter, code string) {
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+type fakeProviderRepo struct {
+	providers map[uuid.UUID]*authDomain.OAuthProvider
+}
+
+func (f *fakeProviderRepo) ListProviders(ctx context.Context) ([]authDomain.OAuthProvider, error) {
+	var out []authDomain.OAuthProvider
+	for _, p := range f.providers {
+		out = append(out, *p)
+	}
+	return out, nil
+}
+
+func (f *fakeProviderRepo) GetProviderByID(ctx context.Context, id uuid.UUID) (*authDomain.OAuthProvider, error) {
+	if p, ok := f.providers[id]; ok {
+		clone := *p
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProviderRepo) GetProviderByName(ctx context.Context, name string) (*authDomain.OAuthProvider, error) {
+	for _, p := range f.providers {
+		if strings.EqualFold(p.ProviderName, name) {
+			clone := *p
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProviderRepo) CreateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error {
+	id, err := uuidv7.GenerateUUIDv7()
+	if err != nil {
+		return err
+	}
+	payload.ID = id
+	clone := *payload
+	f.providers[id] = &clone
+	return nil
+}
+
+func (f *fakeProviderRepo) UpdateProvider(ctx context.Context, payload *authDomain.OAuthProvider) error {
+	clone := *payload
+	f.providers[payload.ID] = &clone
+	return nil
+}
+
+type fakeOAuthRepo struct {
+	states     []*authDomain.OAuthState
+	identities []*authDomain.OAuthIdentity
+	access     map[[2]uuid.UUID]*authDomain.OAuthAccessToken
+	refresh    map[[2]uuid.UUID]*authDomain.OAuthRefreshToken
+}
+
+func newFakeOAuthRepo() *fakeOAuthRepo {
+	return &fakeOAuthRepo{
+		access:  map[[2]uuid.UUID]*authDomain.OAuthAccessToken{},
+		refresh: map[[2]uuid.UUID]*authDomain.OAuthRefreshToken{},
+	}
+}
+
+func (f *fakeOAuthRepo) CreateState(ctx context.Context, payload *authDomain.OAuthState) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.states = append(f.states, &clone)
+	return nil
+}
+
+func (f *fakeOAuthRepo) GetStateByHash(ctx context.Context, stateHash string) (*authDomain.OAuthState, error) {
+	for _, s := range f.states {
+		if s.StateHash == stateHash {
+			clone := *s
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOAuthRepo) MarkStateUsed(ctx context.Context, id uuid.UUID) error {
+	for _, s := range f.states {
+		if s.ID == id {
+			s.Used = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeOAuthRepo) GetIdentity(ctx context.Context, providerID uuid.UUID, subject string) (*authDomain.OAuthIdentity, error) {
+	for _, i := range f.identities {
+		if i.ProviderID == providerID && i.Subject == subject {
+			clone := *i
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOAuthRepo) GetUserIdentity(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthIdentity, error) {
+	for _, i := range f.identities {
+		if i.UserID == userID && i.ProviderID == providerID {
+			clone := *i
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeOAuthRepo) CreateIdentity(ctx context.Context, payload *authDomain.OAuthIdentity) error {
+	clone := *payload
+	f.identities = append(f.identities, &clone)
+	return nil
+}
+
+func (f *fakeOAuthRepo) UpsertAccessToken(ctx context.Context, payload *authDomain.OAuthAccessToken) error {
+	clone := *payload
+	f.access[[2]uuid.UUID{payload.UserID, payload.ProviderID}] = &clone
+	return nil
+}
+
+func (f *fakeOAuthRepo) UpsertRefreshToken(ctx context.Context, payload *authDomain.OAuthRefreshToken) error {
+	clone := *payload
+	f.refresh[[2]uuid.UUID{payload.UserID, payload.ProviderID}] = &clone
+	return nil
+}
+
+func (f *fakeOAuthRepo) GetRefreshToken(ctx context.Context, userID, providerID uuid.UUID) (*authDomain.OAuthRefreshToken, error) {
+	if t, ok := f.refresh[[2]uuid.UUID{userID, providerID}]; ok {
+		clone := *t
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if u, ok := f.users[id]; ok {
+		clone := *u
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) GetUserByUsernameOrEmail(ctx context.Context, identifier string) (*userDomain.User, error) {
+	for _, u := range f.users {
+		if u.Username == identifier || u.Email == identifier {
+			clone := *u
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) CreateUser(ctx context.Context, payload *userDomain.User) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.users[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeUserRepo) UpdateUser(ctx context.Context, payload *userDomain.User) error {
+	clone := *payload
+	f.users[payload.ID] = &clone
+	return nil
+}
+
+type fakeRoleRepo struct {
+	rolePorts.IRoleRepository
+	customer userDomain.UserRole
+}
+
+func (f *fakeRoleRepo) GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error) {
+	if name == f.customer.RoleName {
+		clone := f.customer
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeAuthService struct {
+	authPorts.IAuthService
+	sessions []uuid.UUID
+}
+
+func (f *fakeAuthService) IssueSession(ctx context.Context, user *userDomain.User) (*authDomain.LoginResult, error) {
+	f.sessions = append(f.sessions, user.ID)
+	return &authDomain.LoginResult{User: user, Tokens: &authDomain.TokenPair{AccessToken: "session"}}, nil
+}
+
+type oauthTestEnv struct {
+	idp      *fakeIdP
+	service  *OAuthServiceImpl
+	provider *authDomain.OAuthProvider
+	oauth    *fakeOAuthRepo
+	users    *fakeUserRepo
+	auth     *fakeAuthService
+	cipher   *encryption.Cipher
+}
+
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	idp := newFakeIdP(t)
+	cipher := encryption.NewCipher("test-key")
+	env := &oauthTestEnv{
+		idp:    idp,
+		oauth:  newFakeOAuthRepo(),
+		users:  &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}},
+		auth:   &fakeAuthService{},
+		cipher: cipher,
+	}
+	providers := &fakeProviderRepo{providers: map[uuid.UUID]*authDomain.OAuthProvider{}}
+	env.service = NewOAuthService(
+		providers,
+		env.oauth,
+		env.users,
+		&fakeRoleRepo{customer: userDomain.UserRole{RoleName: userDomain.ROLE_CUSTOMER}},
+		oauthClients.NewHTTPClient(idp.server.Client()),
+		env.auth,
+		transactorstest.Transactor{},
+		cipher,
+		argon2id.DefaultParams,
+	).(*OAuthServiceImpl)
+
+	p := idp.provider()
+	provider, err := env.service.CreateProvider(context.Background(), authDomain.UpsertOAuthProviderDomain{
+		ProviderName: p.ProviderName,
+		ClientID:     p.ClientID,
+		ClientSecret: idp.clientSecret,
+		AuthorizeURL: p.AuthorizeURL,
+		TokenURL:     p.TokenURL,
+		UserInfoURL:  p.UserInfoURL,
+		Scopes:       p.Scopes,
+		RedirectURL:  p.RedirectURL,
+	})
+	if err != nil {
+		t.Fatalf("CreateProvider: %v", err)
+	}
+	env.provider = provider
+	return env
+}
+
+// login runs the whole authorization code flow and returns the callback result.
+func (env *oauthTestEnv) login(t *testing.T, linkUserID *uuid.UUID) (*authDomain.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	authorization, err := env.service.Authorize(ctx, "fake", linkUserID)
+	if err != nil {
+		t.Fatalf("Authorize: %v", err)
+	}
+	code, state := env.idp.authorize(t, authorization.AuthorizationURL)
+	if state != authorization.State {
+		t.Fatalf("state in URL %q differs from returned state %q", state, authorization.State)
+	}
+	return env.service.Callback(ctx, "fake", authDomain.OAuthCallbackDomain{Code: code, State: state})
+}
+
+func TestOAuthLoginCreatesUser(t *testing.T) {
+	env := newOAuthTestEnv(t)
+
+	result, err := env.login(t, nil)
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if result.Tokens == nil || len(env.auth.sessions) != 1 {
+		t.Fatal("expected a session to be issued")
+	}
+	user := env.users.users[result.User.ID]
+	if user.Email != "jane@example.com" || user.Username != "jane" || !user.EmailVerified {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if user.FirstName != "Jane" || user.LastName != "Doe" || user.RoleID == nil {
+		t.Errorf("unexpected profile or role on %+v", user)
+	}
+
+	key := [2]uuid.UUID{user.ID, env.provider.ID}
+	stored := env.oauth.access[key]
+	if stored == nil || stored.AccessToken == "access-1" || stored.ExpiresAt == nil {
+		t.Fatalf("access token not stored encrypted: %+v", stored)
+	}
+	if plain, _ := env.cipher.Decrypt(stored.AccessToken); plain != "access-1" {
+		t.Errorf("decrypted access token = %q", plain)
+	}
+
+	// A second login resolves the same identity instead of creating a user.
+	again, err := env.login(t, nil)
+	if err != nil {
+		t.Fatalf("second Callback: %v", err)
+	}
+	if again.User.ID != user.ID || len(env.users.users) != 1 {
+		t.Error("expected the existing identity to be reused")
+	}
+}
+
+func TestOAuthCallbackRejectsReplayedState(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	ctx := context.Background()
+
+	authorization, err := env.service.Authorize(ctx, "fake", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := env.idp.authorize(t, authorization.AuthorizationURL)
+	if _, err := env.service.Callback(ctx, "fake", authDomain.OAuthCallbackDomain{Code: code, State: state}); err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	_, err = env.service.Callback(ctx, "fake", authDomain.OAuthCallbackDomain{Code: code, State: state})
+	if !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("replayed state err = %v, want ErrInvalidOAuthState", err)
+	}
+	_, err = env.service.Callback(ctx, "fake", authDomain.OAuthCallbackDomain{Code: code, State: "forged"})
+	if !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("unknown state err = %v, want ErrInvalidOAuthState", err)
+	}
+}
+
+func TestOAuthCallbackRejectsWrongVerifier(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	ctx := context.Background()
+
+	authorization, err := env.service.Authorize(ctx, "fake", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := env.idp.authorize(t, authorization.AuthorizationURL)
+	// Swap the stored verifier as an attacker holding only the code would have to.
+	other, _ := env.cipher.Encrypt("not-the-verifier")
+	env.oauth.states[0].CodeVerifier = other
+
+	if _, err := env.service.Callback(ctx, "fake", authDomain.OAuthCallbackDomain{Code: code, State: state}); err == nil {
+		t.Fatal("expected the IdP to reject the code exchange")
+	}
+	if len(env.users.users) != 0 {
+		t.Error("no user should be created")
+	}
+}
+
+func TestOAuthEmailMatching(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	existing := &userDomain.User{Email: "jane@example.com", Username: "jane", Status: userDomain.USER_STATUS_ACTIVE}
+	_ = env.users.CreateUser(context.Background(), existing)
+
+	env.idp.claims["email_verified"] = false
+	if _, err := env.login(t, nil); !errors.Is(err, ErrOAuthEmailInUse) {
+		t.Fatalf("unverified email err = %v, want ErrOAuthEmailInUse", err)
+	}
+
+	env.idp.claims["email_verified"] = true
+	result, err := env.login(t, nil)
+	if err != nil {
+		t.Fatalf("verified email: %v", err)
+	}
+	if result.User.ID != existing.ID || len(env.users.users) != 1 {
+		t.Error("expected the provider to be linked to the existing user")
+	}
+}
+
+func TestOAuthLinkToSignedInUser(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	me := &userDomain.User{Email: "me@example.com", Username: "me", Status: userDomain.USER_STATUS_ACTIVE}
+	_ = env.users.CreateUser(context.Background(), me)
+
+	result, err := env.login(t, &me.ID)
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if result.User.ID != me.ID || result.Tokens != nil || len(env.auth.sessions) != 0 {
+		t.Errorf("linking should not start a session: %+v", result)
+	}
+
+	other := &userDomain.User{Email: "other@example.com", Username: "other", Status: userDomain.USER_STATUS_ACTIVE}
+	_ = env.users.CreateUser(context.Background(), other)
+	if _, err := env.login(t, &other.ID); !errors.Is(err, ErrOAuthIdentityLinked) {
+		t.Errorf("linking a taken identity err = %v, want ErrOAuthIdentityLinked", err)
+	}
+}
+
+func TestOAuthRefreshProviderToken(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	result, err := env.login(t, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	access, err := env.service.RefreshProviderToken(context.Background(), result.User.ID, "fake")
+	if err != nil {
+		t.Fatalf("RefreshProviderToken: %v", err)
+	}
+	if env.idp.refreshCalled != 1 {
+		t.Fatal("expected the IdP refresh endpoint to be called")
+	}
+	if plain, _ := env.cipher.Decrypt(access.AccessToken); plain != "access-2" {
+		t.Errorf("refreshed access token = %q", plain)
+	}
+	// The IdP did not rotate the refresh token, so the old one must be kept.
+	stored := env.oauth.refresh[[2]uuid.UUID{result.User.ID, env.provider.ID}]
+	if plain, _ := env.cipher.Decrypt(stored.RefreshToken); plain != "refresh-1" {
+		t.Errorf("stored refresh token = %q", plain)
+	}
+
+	if _, err := env.service.RefreshProviderToken(context.Background(), uuid.New(), "fake"); !errors.Is(err, ErrOAuthNotLinked) {
+		t.Errorf("unlinked user err = %v, want ErrOAuthNotLinked", err)
+	}
+}
+
+func TestOAuthProviderSecretIsEncryptedAndHidden(t *testing.T) {
+	env := newOAuthTestEnv(t)
+
+	if env.provider.ClientSecret == env.idp.clientSecret || !encryption.IsEncrypted(env.provider.ClientSecret) {
+		t.Fatalf("client secret stored as %q", env.provider.ClientSecret)
+	}
+	body, err := json.Marshal(env.provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "client_secret") || strings.Contains(string(body), env.provider.ClientSecret) {
+		t.Errorf("client secret serialized: %s", body)
+	}
+
+	// Updating without a secret keeps the stored one.
+	payload := authDomain.UpsertOAuthProviderDomain{
+		ProviderName: "fake",
+		ClientID:     "shop",
+		AuthorizeURL: env.provider.AuthorizeURL,
+		TokenURL:     env.provider.TokenURL,
+		UserInfoURL:  env.provider.UserInfoURL,
+		RedirectURL:  env.provider.RedirectURL,
+	}
+	updated, err := env.service.UpdateProvider(context.Background(), env.provider.ID, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ClientSecret != env.provider.ClientSecret {
+		t.Error("client secret was dropped on update")
+	}
+
+	if _, err := env.service.CreateProvider(context.Background(), payload); !errors.Is(err, ErrOAuthProviderExists) {
+		t.Errorf("duplicate name err = %v, want ErrOAuthProviderExists", err)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/otp"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOTPInvalid         = errors.New("invalid verification code")
+	ErrOTPExpired         = errors.New("verification code has expired")
+	ErrOTPTooManyAttempts = errors.New("too many attempts, request a new verification code")
+	ErrOTPRateLimited     = errors.New("too many verification codes requested, try again later")
+)
+
+// OTPServiceConfig holds the tunables of OTPServiceImpl.
+type OTPServiceConfig struct {
+	Length      int           // Number of digits per code
+	TTL         time.Duration // How long a code stays valid
+	MaxAttempts int           // Failed verifications before a code is burned
+	MaxIssued   int           // Codes a user may request per purpose within IssueWindow
+	IssueWindow time.Duration
+	HashParams  *argon2id.Params // argon2id parameters for stored codes; argon2id.DefaultParams when nil
+}
+
+// DefaultOTPServiceConfig is used by the app wiring.
+var DefaultOTPServiceConfig = OTPServiceConfig{
+	Length:      6,
+	TTL:         10 * time.Minute,
+	MaxAttempts: 5,
+	MaxIssued:   5,
+	IssueWindow: time.Hour,
+	HashParams:  argon2id.DefaultParams,
+}
+
+type OTPServiceImpl struct {
+	otpRepo    ports.IOTPRepository
+	sender     ports.IOTPSender
+	transactor transactors.IDatabaseTransactor
+	config     OTPServiceConfig
+}
+
+func NewOTPService(
+	otpRepo ports.IOTPRepository,
+	sender ports.IOTPSender,
+	transactor transactors.IDatabaseTransactor,
+	config OTPServiceConfig,
+) ports.IOTPService {
+	if config.HashParams == nil {
+		config.HashParams = argon2id.DefaultParams
+	}
+	return &OTPServiceImpl{
+		otpRepo:    otpRepo,
+		sender:     sender,
+		transactor: transactor,
+		config:     config,
+	}
+}
+
+// Issue implements ports.IOTPService.
+//
+// The code is stored as an argon2id hash; the plaintext only leaves through the
+// sender. Sending happens after the transaction commits so a slow mail server
+// never holds row locks.
+func (s *OTPServiceImpl) Issue(ctx context.Context, payload messageDomain.IssueOTPDomain) (time.Time, error) {
+	code, err := generateNumericCode(s.config.Length)
+	if err != nil {
+		return time.Time{}, err
+	}
+	hash, err := argon2id.CreateHash(code, s.config.HashParams)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	otp := &messageDomain.OTP{
+		UserID:    payload.UserID,
+		Purpose:   payload.Purpose,
+		Channel:   payload.Channel,
+		Otp:       hash,
+		ExpiresAt: time.Now().Add(s.config.TTL),
+	}
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		issued, err := s.otpRepo.CountOTPsSince(txCtx, payload.UserID, payload.Purpose, time.Now().Add(-s.config.IssueWindow))
+		if err != nil {
+			return err
+		}
+		if issued >= int64(s.config.MaxIssued) {
+			return ErrOTPRateLimited
+		}
+		if err := s.otpRepo.InvalidateUnusedOTPs(txCtx, payload.UserID, payload.Purpose); err != nil {
+			return err
+		}
+		return s.otpRepo.CreateOTP(txCtx, otp)
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	err = s.sender.Send(ctx, messageDomain.OTPMessage{
+		Channel:     payload.Channel,
+		Destination: payload.Destination,
+		Purpose:     payload.Purpose,
+		Code:        code,
+		ExpiresAt:   otp.ExpiresAt,
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("send verification code: %w", err)
+	}
+	return otp.ExpiresAt, nil
+}
+
+// Verify implements ports.IOTPService.
+//
+// Only the most recent code for the purpose is checked. Failed attempts are
+// counted and committed even though an error is returned, and a code is burned
+// once MaxAttempts is reached.
+func (s *OTPServiceImpl) Verify(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, code string) error {
+	var failure error
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		otp, err := s.otpRepo.GetLatestUnusedOTP(txCtx, userID, purpose)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOTPInvalid
+			}
+			return err
+		}
+		if time.Now().After(otp.ExpiresAt) {
+			return ErrOTPExpired
+		}
+		if otp.Attempts >= s.config.MaxAttempts {
+			return ErrOTPTooManyAttempts
+		}
+
+		match, err := argon2id.ComparePasswordAndHash(strings.TrimSpace(code), otp.Otp)
+		if err != nil {
+			return err
+		}
+		if !match {
+			// Returning nil keeps the attempt committed; the error is reported below.
+			failure = ErrOTPInvalid
+			if otp.Attempts+1 >= s.config.MaxAttempts {
+				failure = ErrOTPTooManyAttempts
+			}
+			return s.otpRepo.IncrementOTPAttempts(txCtx, otp.ID)
+		}
+		return s.otpRepo.MarkOTPUsed(txCtx, otp.ID)
+	})
+	if err != nil {
+		return err
+	}
+	return failure
+}
+
+func generateNumericCode(length int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("generate verification code: %w", err)
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	messageDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/message"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeOTPRepo struct {
+	otps []*messageDomain.OTP
+}
+
+func (f *fakeOTPRepo) CreateOTP(ctx context.Context, payload *messageDomain.OTP) error {
+	payload.ID = uuid.New()
+	payload.CreatedAt = time.Now()
+	clone := *payload
+	f.otps = append(f.otps, &clone)
+	return nil
+}
+
+func (f *fakeOTPRepo) CountOTPsSince(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE, since time.Time) (int64, error) {
+	var count int64
+	for _, o := range f.otps {
+		if o.UserID == userID && o.Purpose == purpose && !o.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeOTPRepo) GetLatestUnusedOTP(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) (*messageDomain.OTP, error) {
+	var candidates []*messageDomain.OTP
+	for _, o := range f.otps {
+		if o.UserID == userID && o.Purpose == purpose && !o.Used {
+			candidates = append(candidates, o)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].CreatedAt.After(candidates[j].CreatedAt) })
+	clone := *candidates[0]
+	return &clone, nil
+}
+
+func (f *fakeOTPRepo) InvalidateUnusedOTPs(ctx context.Context, userID uuid.UUID, purpose messageDomain.OTP_PURPOSE) error {
+	for _, o := range f.otps {
+		if o.UserID == userID && o.Purpose == purpose {
+			o.Used = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeOTPRepo) IncrementOTPAttempts(ctx context.Context, id uuid.UUID) error {
+	f.find(id).Attempts++
+	return nil
+}
+
+func (f *fakeOTPRepo) MarkOTPUsed(ctx context.Context, id uuid.UUID) error {
+	f.find(id).Used = true
+	return nil
+}
+
+func (f *fakeOTPRepo) find(id uuid.UUID) *messageDomain.OTP {
+	for _, o := range f.otps {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+type capturingSender struct {
+	messages []messageDomain.OTPMessage
+}
+
+func (s *capturingSender) Send(ctx context.Context, message messageDomain.OTPMessage) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func (s *capturingSender) lastCode() string {
+	return s.messages[len(s.messages)-1].Code
+}
+
+func newTestOTPService(config OTPServiceConfig) (*OTPServiceImpl, *fakeOTPRepo, *capturingSender) {
+	repo := &fakeOTPRepo{}
+	sender := &capturingSender{}
+	return NewOTPService(repo, sender, transactorstest.Transactor{}, config).(*OTPServiceImpl), repo, sender
+}
+
+func issue(t *testing.T, svc *OTPServiceImpl, userID uuid.UUID) {
+	t.Helper()
+	_, err := svc.Issue(context.Background(), messageDomain.IssueOTPDomain{
+		UserID:      userID,
+		Purpose:     messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION,
+		Channel:     messageDomain.OTP_CHANNEL_EMAIL,
+		Destination: "john@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOTPIsHashedAndSingleUse(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, sender := newTestOTPService(DefaultOTPServiceConfig)
+	userID := uuid.New()
+
+	issue(t, svc, userID)
+	code := sender.lastCode()
+	if len(code) != DefaultOTPServiceConfig.Length {
+		t.Fatalf("expected a %d digit code, got %q", DefaultOTPServiceConfig.Length, code)
+	}
+	if repo.otps[0].Otp == code {
+		t.Fatal("expected the stored code to be hashed")
+	}
+
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_PASSWORDLESS_LOGIN, code); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("expected a code for another purpose to be rejected, got %v", err)
+	}
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, code); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, code); !errors.Is(err, ErrOTPInvalid) {
+		t.Errorf("expected a used code to be rejected, got %v", err)
+	}
+}
+
+func TestOTPNewCodeSupersedesOldOne(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sender := newTestOTPService(DefaultOTPServiceConfig)
+	userID := uuid.New()
+
+	issue(t, svc, userID)
+	first := sender.lastCode()
+	issue(t, svc, userID)
+	second := sender.lastCode()
+
+	if first != second {
+		if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, first); !errors.Is(err, ErrOTPInvalid) {
+			t.Errorf("expected the superseded code to be rejected, got %v", err)
+		}
+	}
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, second); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOTPExpiry(t *testing.T) {
+	config := DefaultOTPServiceConfig
+	config.TTL = -time.Second
+	svc, _, sender := newTestOTPService(config)
+	userID := uuid.New()
+
+	issue(t, svc, userID)
+	if err := svc.Verify(context.Background(), userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, sender.lastCode()); !errors.Is(err, ErrOTPExpired) {
+		t.Errorf("expected ErrOTPExpired, got %v", err)
+	}
+}
+
+func TestOTPAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	config := DefaultOTPServiceConfig
+	config.MaxAttempts = 2
+	svc, _, sender := newTestOTPService(config)
+	userID := uuid.New()
+
+	issue(t, svc, userID)
+	code := sender.lastCode()
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, wrong); !errors.Is(err, ErrOTPInvalid) {
+		t.Fatalf("expected ErrOTPInvalid, got %v", err)
+	}
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, wrong); !errors.Is(err, ErrOTPTooManyAttempts) {
+		t.Fatalf("expected ErrOTPTooManyAttempts, got %v", err)
+	}
+	if err := svc.Verify(ctx, userID, messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION, code); !errors.Is(err, ErrOTPTooManyAttempts) {
+		t.Errorf("expected the burned code to stay rejected, got %v", err)
+	}
+}
+
+func TestOTPIssueRateLimit(t *testing.T) {
+	config := DefaultOTPServiceConfig
+	config.MaxIssued = 2
+	svc, _, _ := newTestOTPService(config)
+	userID := uuid.New()
+
+	issue(t, svc, userID)
+	issue(t, svc, userID)
+	_, err := svc.Issue(context.Background(), messageDomain.IssueOTPDomain{
+		UserID:  userID,
+		Purpose: messageDomain.OTP_PURPOSE_EMAIL_VERIFICATION,
+		Channel: messageDomain.OTP_CHANNEL_EMAIL,
+	})
+	if !errors.Is(err, ErrOTPRateLimited) {
+		t.Errorf("expected ErrOTPRateLimited, got %v", err)
+	}
+}
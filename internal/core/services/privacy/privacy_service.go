@@ -0,0 +1,476 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/privacy"
+	storagePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserErased          = errors.New("the personal data of this user has been erased")
+	ErrExportInProgress    = errors.New("an export is already in progress")
+	ErrDataRequestNotFound = errors.New("data request not found")
+	ErrExportNotReady      = errors.New("the export is not ready yet")
+	ErrExportExpired       = errors.New("the export has expired, request a new one")
+	ErrErasureNotConfirmed = errors.New("confirm must match the username of the account")
+)
+
+// Actions recorded in the change log.
+const (
+	auditEntityUser         = "User"
+	auditFieldDataRequest   = "data_request"
+	actionExportRequested   = "EXPORT_REQUESTED"
+	actionExportCompleted   = "EXPORT_COMPLETED"
+	actionExportFailed      = "EXPORT_FAILED"
+	actionExportDownloaded  = "EXPORT_DOWNLOADED"
+	actionPersonalDataErase = "ERASE"
+)
+
+// erasedEmailDomain is a reserved domain (RFC 6761) so anonymised addresses
+// can never receive mail.
+const erasedEmailDomain = "erased.invalid"
+
+// PrivacyServiceConfig holds the tunables of PrivacyServiceImpl.
+type PrivacyServiceConfig struct {
+	ExportTTL       time.Duration // How long an archive can be downloaded
+	WorkerInterval  time.Duration // How often the worker looks for queued exports and expired archives
+	WorkerBatchSize int
+	QueueSize       int
+}
+
+var DefaultPrivacyServiceConfig = PrivacyServiceConfig{
+	ExportTTL:       7 * 24 * time.Hour,
+	WorkerInterval:  time.Minute,
+	WorkerBatchSize: 10,
+	QueueSize:       100,
+}
+
+type PrivacyServiceImpl struct {
+	requestRepo   ports.IDataRequestRepository
+	userDataRepo  ports.IUserDataRepository
+	userRepo      userPorts.IUserRepository
+	changeLogRepo auditPorts.IChangeLogRepository
+	exportStorage storagePorts.IFileStorage // private, export archives
+	publicStorage storagePorts.IFileStorage // uploads such as avatars
+	transactor    transactors.IDatabaseTransactor
+	config        PrivacyServiceConfig
+	queue         chan uuid.UUID
+	now           func() time.Time
+}
+
+func NewPrivacyService(
+	requestRepo ports.IDataRequestRepository,
+	userDataRepo ports.IUserDataRepository,
+	userRepo userPorts.IUserRepository,
+	changeLogRepo auditPorts.IChangeLogRepository,
+	exportStorage storagePorts.IFileStorage,
+	publicStorage storagePorts.IFileStorage,
+	transactor transactors.IDatabaseTransactor,
+	config PrivacyServiceConfig,
+) ports.IPrivacyService {
+	return &PrivacyServiceImpl{
+		requestRepo:   requestRepo,
+		userDataRepo:  userDataRepo,
+		userRepo:      userRepo,
+		changeLogRepo: changeLogRepo,
+		exportStorage: exportStorage,
+		publicStorage: publicStorage,
+		transactor:    transactor,
+		config:        config,
+		queue:         make(chan uuid.UUID, config.QueueSize),
+		now:           time.Now,
+	}
+}
+
+// RequestExport implements ports.IPrivacyService.
+func (s *PrivacyServiceImpl) RequestExport(ctx context.Context, userID, actorID uuid.UUID, payload privacyDomain.RequestDataExportDomain) (*privacyDomain.DataRequest, error) {
+	format := payload.Format
+	if format == "" {
+		format = privacyDomain.EXPORT_FORMAT_ZIP
+	}
+
+	request := &privacyDomain.DataRequest{
+		UserID:        userID,
+		RequestedByID: actorID,
+		Type:          privacyDomain.DATA_REQUEST_TYPE_EXPORT,
+		Status:        privacyDomain.DATA_REQUEST_STATUS_PENDING,
+		Format:        format,
+		Reason:        payload.Reason,
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.lockUser(ctx, userID); err != nil {
+			return err
+		}
+		open, err := s.requestRepo.HasOpenDataRequest(ctx, userID, privacyDomain.DATA_REQUEST_TYPE_EXPORT)
+		if err != nil {
+			return err
+		}
+		if open {
+			return ErrExportInProgress
+		}
+		if err := s.requestRepo.CreateDataRequest(ctx, request); err != nil {
+			return err
+		}
+		return s.audit(ctx, userID, actorID, actionExportRequested, request.ID.String(), payload.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A full queue is fine, the worker picks the request up on its next sweep.
+	select {
+	case s.queue <- request.ID:
+	default:
+	}
+	return request, nil
+}
+
+// ListDataRequests implements ports.IPrivacyService.
+func (s *PrivacyServiceImpl) ListDataRequests(ctx context.Context, userID uuid.UUID) ([]privacyDomain.DataRequest, error) {
+	return s.requestRepo.ListUserDataRequests(ctx, userID)
+}
+
+// DownloadExport implements ports.IPrivacyService.
+func (s *PrivacyServiceImpl) DownloadExport(ctx context.Context, userID, requestID, actorID uuid.UUID) (*privacyDomain.ExportFile, error) {
+	var request *privacyDomain.DataRequest
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.requestRepo.GetDataRequestForUpdate(ctx, requestID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDataRequestNotFound
+			}
+			return err
+		}
+		if request.UserID != userID || request.Type != privacyDomain.DATA_REQUEST_TYPE_EXPORT {
+			return ErrDataRequestNotFound
+		}
+		switch {
+		case request.Status == privacyDomain.DATA_REQUEST_STATUS_EXPIRED,
+			request.ExpiresAt != nil && !s.now().Before(*request.ExpiresAt):
+			return ErrExportExpired
+		case request.Status != privacyDomain.DATA_REQUEST_STATUS_COMPLETED:
+			return ErrExportNotReady
+		}
+		return s.audit(ctx, userID, actorID, actionExportDownloaded, request.ID.String(), "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.exportStorage.Get(ctx, request.FileKey)
+	if err != nil {
+		return nil, err
+	}
+	file := &privacyDomain.ExportFile{
+		Name:        fmt.Sprintf("personal-data-%s.%s", request.CreatedAt.Format("20060102"), request.Format),
+		ContentType: "application/json",
+		Data:        data,
+	}
+	if request.Format == privacyDomain.EXPORT_FORMAT_ZIP {
+		file.ContentType = "application/zip"
+	}
+	return file, nil
+}
+
+// EraseUser implements ports.IPrivacyService.
+//
+// The account is kept, anonymised, so that the orders and payments it placed
+// still add up in the books. Uploaded files and export archives are deleted
+// once the transaction has committed.
+func (s *PrivacyServiceImpl) EraseUser(ctx context.Context, userID, actorID uuid.UUID, payload privacyDomain.RequestErasureDomain) (*privacyDomain.ErasureSummary, error) {
+	var (
+		summary        *privacyDomain.ErasureSummary
+		avatarKey      string
+		exportFileKeys []string
+	)
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := s.lockUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if payload.Confirm != user.Username {
+			return ErrErasureNotConfirmed
+		}
+
+		data, err := s.userDataRepo.CollectUserData(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if data.Profile != nil {
+			avatarKey = data.Profile.ProfilePictureKey
+		}
+
+		summary, err = s.userDataRepo.EraseUserData(ctx, anonymise(user))
+		if err != nil {
+			return err
+		}
+
+		for i := range data.DataRequests {
+			request := &data.DataRequests[i]
+			if request.FileKey == "" {
+				continue
+			}
+			exportFileKeys = append(exportFileKeys, request.FileKey)
+			request.FileKey = ""
+			request.Status = privacyDomain.DATA_REQUEST_STATUS_EXPIRED
+			if err := s.requestRepo.UpdateDataRequest(ctx, request); err != nil {
+				return err
+			}
+		}
+
+		now := s.now()
+		erasure := &privacyDomain.DataRequest{
+			UserID:        userID,
+			RequestedByID: actorID,
+			Type:          privacyDomain.DATA_REQUEST_TYPE_ERASURE,
+			Status:        privacyDomain.DATA_REQUEST_STATUS_COMPLETED,
+			Reason:        payload.Reason,
+			CompletedAt:   &now,
+		}
+		if err := s.requestRepo.CreateDataRequest(ctx, erasure); err != nil {
+			return err
+		}
+		description := fmt.Sprintf("deleted %d records, anonymised %d orders", summary.DeletedRecords, summary.AnonymisedOrders)
+		if payload.Reason != "" {
+			description += ": " + payload.Reason
+		}
+		return s.audit(ctx, userID, actorID, actionPersonalDataErase, erasure.ID.String(), description)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if avatarKey != "" {
+		if err := s.publicStorage.Delete(ctx, avatarKey); err != nil {
+			log.Printf("privacy: failed to delete avatar %s of erased user %s: %v", avatarKey, userID, err)
+		}
+	}
+	for _, key := range exportFileKeys {
+		if err := s.exportStorage.Delete(ctx, key); err != nil {
+			log.Printf("privacy: failed to delete export %s of erased user %s: %v", key, userID, err)
+		}
+	}
+	return summary, nil
+}
+
+// ProcessExport implements ports.IPrivacyService.
+//
+// The request is claimed under a row lock, so several workers can share the
+// queue without building the same archive twice.
+func (s *PrivacyServiceImpl) ProcessExport(ctx context.Context, requestID uuid.UUID) error {
+	var request *privacyDomain.DataRequest
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		request, err = s.requestRepo.GetDataRequestForUpdate(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		if request.Type != privacyDomain.DATA_REQUEST_TYPE_EXPORT || request.Status != privacyDomain.DATA_REQUEST_STATUS_PENDING {
+			request = nil
+			return nil
+		}
+		request.Status = privacyDomain.DATA_REQUEST_STATUS_PROCESSING
+		return s.requestRepo.UpdateDataRequest(ctx, request)
+	})
+	if err != nil || request == nil {
+		return err
+	}
+
+	key, size, buildErr := s.buildExport(ctx, request)
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := s.now()
+		request.CompletedAt = &now
+		action, description := actionExportCompleted, ""
+		if buildErr != nil {
+			request.Status = privacyDomain.DATA_REQUEST_STATUS_FAILED
+			request.Error = buildErr.Error()
+			action, description = actionExportFailed, buildErr.Error()
+		} else {
+			expiresAt := now.Add(s.config.ExportTTL)
+			request.Status = privacyDomain.DATA_REQUEST_STATUS_COMPLETED
+			request.FileKey = key
+			request.FileSize = size
+			request.ExpiresAt = &expiresAt
+		}
+		if err := s.requestRepo.UpdateDataRequest(ctx, request); err != nil {
+			return err
+		}
+		return s.audit(ctx, request.UserID, request.RequestedByID, action, request.ID.String(), description)
+	})
+}
+
+// RunWorker implements ports.IPrivacyService.
+func (s *PrivacyServiceImpl) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.ProcessExport(ctx, id); err != nil {
+				log.Printf("privacy: export %s: %v", id, err)
+			}
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// sweep processes exports that missed the queue (full queue, restart) and
+// deletes archives past their expiry.
+func (s *PrivacyServiceImpl) sweep(ctx context.Context) {
+	pending, err := s.requestRepo.ListDataRequestsByStatus(ctx, privacyDomain.DATA_REQUEST_TYPE_EXPORT, privacyDomain.DATA_REQUEST_STATUS_PENDING, s.config.WorkerBatchSize)
+	if err != nil {
+		log.Printf("privacy: list pending exports: %v", err)
+	}
+	for _, request := range pending {
+		if err := s.ProcessExport(ctx, request.ID); err != nil {
+			log.Printf("privacy: export %s: %v", request.ID, err)
+		}
+	}
+
+	expired, err := s.requestRepo.ListExpiredExports(ctx, s.now(), s.config.WorkerBatchSize)
+	if err != nil {
+		log.Printf("privacy: list expired exports: %v", err)
+	}
+	for i := range expired {
+		request := &expired[i]
+		if err := s.exportStorage.Delete(ctx, request.FileKey); err != nil {
+			log.Printf("privacy: delete export %s: %v", request.ID, err)
+			continue
+		}
+		request.FileKey = ""
+		request.Status = privacyDomain.DATA_REQUEST_STATUS_EXPIRED
+		if err := s.requestRepo.UpdateDataRequest(ctx, request); err != nil {
+			log.Printf("privacy: expire export %s: %v", request.ID, err)
+		}
+	}
+}
+
+// buildExport collects the data of the user and stores the archive.
+func (s *PrivacyServiceImpl) buildExport(ctx context.Context, request *privacyDomain.DataRequest) (string, int64, error) {
+	data, err := s.userDataRepo.CollectUserData(ctx, request.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+	data.GeneratedAt = s.now()
+	data.User.Password = ""
+
+	var archive []byte
+	contentType := "application/json"
+	if request.Format == privacyDomain.EXPORT_FORMAT_ZIP {
+		archive, err = zipExport(data)
+		contentType = "application/zip"
+	} else {
+		archive, err = json.MarshalIndent(data, "", "  ")
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("exports/%s/%s.%s", request.UserID, request.ID, request.Format)
+	if _, err := s.exportStorage.Put(ctx, key, contentType, archive); err != nil {
+		return "", 0, err
+	}
+	return key, int64(len(archive)), nil
+}
+
+// zipExport writes every section of the export to its own JSON file.
+func zipExport(data *privacyDomain.UserDataExport) ([]byte, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &sections); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := archive.Create(name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, sections[name], "", "  "); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(pretty.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// lockUser loads the user under a row lock and refuses erased accounts.
+func (s *PrivacyServiceImpl) lockUser(ctx context.Context, userID uuid.UUID) (*userDomain.User, error) {
+	user, err := s.userRepo.GetUserByIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if user.Status == userDomain.USER_STATUS_ERASED {
+		return nil, ErrUserErased
+	}
+	return user, nil
+}
+
+func (s *PrivacyServiceImpl) audit(ctx context.Context, userID, actorID uuid.UUID, action, requestID, description string) error {
+	entry := loggingDomain.NewChangeLog(auditEntityUser, userID, auditFieldDataRequest, "", requestID, action, actorID, description)
+	entry.Timestamp = s.now()
+	return s.changeLogRepo.CreateChangeLog(ctx, entry)
+}
+
+// anonymise replaces the personal fields of the user. The username and email
+// stay unique, derived from the user ID, and the password can never match.
+func anonymise(user *userDomain.User) *userDomain.User {
+	id := hex.EncodeToString(user.ID[:])
+	user.FirstName = "Erased"
+	user.LastName = "User"
+	user.Email = id + "@" + erasedEmailDomain
+	user.EmailVerified = false
+	user.Username = "erased_" + id
+	user.PhoneNumber = ""
+	user.Address = ""
+	user.Password = ""
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	user.TwoFactorStep = 0
+	user.Status = userDomain.USER_STATUS_ERASED
+	return user
+}
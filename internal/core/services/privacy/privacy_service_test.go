@@ -0,0 +1,296 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/storage"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	privacyDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/privacy"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeDataRequestRepo struct {
+	requests map[uuid.UUID]*privacyDomain.DataRequest
+}
+
+func (f *fakeDataRequestRepo) CreateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error {
+	payload.ID = uuid.New()
+	payload.CreatedAt = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	clone := *payload
+	f.requests[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeDataRequestRepo) GetDataRequestForUpdate(ctx context.Context, id uuid.UUID) (*privacyDomain.DataRequest, error) {
+	if request, ok := f.requests[id]; ok {
+		clone := *request
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeDataRequestRepo) ListUserDataRequests(ctx context.Context, userID uuid.UUID) ([]privacyDomain.DataRequest, error) {
+	var requests []privacyDomain.DataRequest
+	for _, request := range f.requests {
+		if request.UserID == userID {
+			requests = append(requests, *request)
+		}
+	}
+	return requests, nil
+}
+
+func (f *fakeDataRequestRepo) ListDataRequestsByStatus(ctx context.Context, requestType privacyDomain.DATA_REQUEST_TYPE, status privacyDomain.DATA_REQUEST_STATUS, limit int) ([]privacyDomain.DataRequest, error) {
+	var requests []privacyDomain.DataRequest
+	for _, request := range f.requests {
+		if request.Type == requestType && request.Status == status {
+			requests = append(requests, *request)
+		}
+	}
+	return requests, nil
+}
+
+func (f *fakeDataRequestRepo) ListExpiredExports(ctx context.Context, before time.Time, limit int) ([]privacyDomain.DataRequest, error) {
+	var requests []privacyDomain.DataRequest
+	for _, request := range f.requests {
+		if request.Status == privacyDomain.DATA_REQUEST_STATUS_COMPLETED && request.ExpiresAt != nil && request.ExpiresAt.Before(before) {
+			requests = append(requests, *request)
+		}
+	}
+	return requests, nil
+}
+
+func (f *fakeDataRequestRepo) HasOpenDataRequest(ctx context.Context, userID uuid.UUID, requestType privacyDomain.DATA_REQUEST_TYPE) (bool, error) {
+	for _, request := range f.requests {
+		if request.UserID == userID && request.Type == requestType &&
+			(request.Status == privacyDomain.DATA_REQUEST_STATUS_PENDING || request.Status == privacyDomain.DATA_REQUEST_STATUS_PROCESSING) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeDataRequestRepo) UpdateDataRequest(ctx context.Context, payload *privacyDomain.DataRequest) error {
+	clone := *payload
+	f.requests[payload.ID] = &clone
+	return nil
+}
+
+type fakeUserDataRepo struct {
+	users    map[uuid.UUID]*userDomain.User
+	profile  *userDomain.UserProfile
+	requests *fakeDataRequestRepo
+	erased   *userDomain.User
+}
+
+func (f *fakeUserDataRepo) CollectUserData(ctx context.Context, userID uuid.UUID) (*privacyDomain.UserDataExport, error) {
+	user, ok := f.users[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	requests, _ := f.requests.ListUserDataRequests(ctx, userID)
+	return &privacyDomain.UserDataExport{User: *user, Profile: f.profile, DataRequests: requests}, nil
+}
+
+func (f *fakeUserDataRepo) EraseUserData(ctx context.Context, anonymised *userDomain.User) (*privacyDomain.ErasureSummary, error) {
+	clone := *anonymised
+	f.users[anonymised.ID] = &clone
+	f.erased = &clone
+	return &privacyDomain.ErasureSummary{AnonymisedOrders: 2, DeletedRecords: 7}, nil
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if user, ok := f.users[id]; ok {
+		clone := *user
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type fakeChangeLogRepo struct {
+	auditPorts.IChangeLogRepository
+	entries []loggingDomain.ChangeLogs
+}
+
+func (f *fakeChangeLogRepo) CreateChangeLog(ctx context.Context, payload *loggingDomain.ChangeLogs) error {
+	f.entries = append(f.entries, *payload)
+	return nil
+}
+
+func (f *fakeChangeLogRepo) actions() []string {
+	actions := make([]string, 0, len(f.entries))
+	for _, entry := range f.entries {
+		actions = append(actions, entry.Action)
+	}
+	return actions
+}
+
+type privacyFixture struct {
+	service  *PrivacyServiceImpl
+	requests *fakeDataRequestRepo
+	userData *fakeUserDataRepo
+	logs     *fakeChangeLogRepo
+	userID   uuid.UUID
+	now      time.Time
+}
+
+func newPrivacyFixture(t *testing.T) *privacyFixture {
+	userID := uuid.New()
+	user := &userDomain.User{
+		Username: "somchai",
+		Email:    "somchai@example.com",
+		Password: "$argon2id$secret",
+		Status:   userDomain.USER_STATUS_ACTIVE,
+	}
+	user.ID = userID
+	users := map[uuid.UUID]*userDomain.User{userID: user}
+
+	f := &privacyFixture{
+		requests: &fakeDataRequestRepo{requests: map[uuid.UUID]*privacyDomain.DataRequest{}},
+		logs:     &fakeChangeLogRepo{},
+		userID:   userID,
+		now:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.userData = &fakeUserDataRepo{users: users, requests: f.requests}
+	f.service = NewPrivacyService(
+		f.requests,
+		f.userData,
+		&fakeUserRepo{users: users},
+		f.logs,
+		storage.NewLocalStorage(t.TempDir(), ""),
+		storage.NewLocalStorage(t.TempDir(), "/uploads"),
+		transactorstest.Transactor{},
+		DefaultPrivacyServiceConfig,
+	).(*PrivacyServiceImpl)
+	f.service.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestExportLifecycle(t *testing.T) {
+	f := newPrivacyFixture(t)
+	ctx := context.Background()
+
+	request, err := f.service.RequestExport(ctx, f.userID, f.userID, privacyDomain.RequestDataExportDomain{})
+	if err != nil {
+		t.Fatalf("RequestExport: %v", err)
+	}
+	if request.Format != privacyDomain.EXPORT_FORMAT_ZIP || request.Status != privacyDomain.DATA_REQUEST_STATUS_PENDING {
+		t.Fatalf("request = %+v", request)
+	}
+	if _, err := f.service.RequestExport(ctx, f.userID, f.userID, privacyDomain.RequestDataExportDomain{}); !errors.Is(err, ErrExportInProgress) {
+		t.Fatalf("second request: got %v, want ErrExportInProgress", err)
+	}
+	if _, err := f.service.DownloadExport(ctx, f.userID, request.ID, f.userID); !errors.Is(err, ErrExportNotReady) {
+		t.Fatalf("download before processing: got %v, want ErrExportNotReady", err)
+	}
+
+	if err := f.service.ProcessExport(ctx, request.ID); err != nil {
+		t.Fatalf("ProcessExport: %v", err)
+	}
+	stored := f.requests.requests[request.ID]
+	if stored.Status != privacyDomain.DATA_REQUEST_STATUS_COMPLETED || stored.ExpiresAt == nil || stored.FileSize == 0 {
+		t.Fatalf("processed request = %+v", stored)
+	}
+
+	if _, err := f.service.DownloadExport(ctx, uuid.New(), request.ID, f.userID); !errors.Is(err, ErrDataRequestNotFound) {
+		t.Fatalf("download by another user: got %v, want ErrDataRequestNotFound", err)
+	}
+	file, err := f.service.DownloadExport(ctx, f.userID, request.ID, f.userID)
+	if err != nil {
+		t.Fatalf("DownloadExport: %v", err)
+	}
+	if file.ContentType != "application/zip" || file.Name != "personal-data-20240501.zip" {
+		t.Fatalf("file = %s %s", file.Name, file.ContentType)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(file.Data), int64(len(file.Data)))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	var userJSON string
+	for _, entry := range archive.File {
+		if entry.Name != "user.json" {
+			continue
+		}
+		r, _ := entry.Open()
+		raw, _ := io.ReadAll(r)
+		r.Close()
+		userJSON = string(raw)
+	}
+	if !strings.Contains(userJSON, "somchai@example.com") {
+		t.Fatalf("user.json = %q", userJSON)
+	}
+	if strings.Contains(userJSON, "argon2id") {
+		t.Fatal("the export must not contain the password hash")
+	}
+
+	// Past its expiry the archive can no longer be downloaded and is deleted by the sweep.
+	f.now = f.now.Add(DefaultPrivacyServiceConfig.ExportTTL + time.Minute)
+	if _, err := f.service.DownloadExport(ctx, f.userID, request.ID, f.userID); !errors.Is(err, ErrExportExpired) {
+		t.Fatalf("download after expiry: got %v, want ErrExportExpired", err)
+	}
+	f.service.sweep(ctx)
+	if stored := f.requests.requests[request.ID]; stored.Status != privacyDomain.DATA_REQUEST_STATUS_EXPIRED || stored.FileKey != "" {
+		t.Fatalf("swept request = %+v", stored)
+	}
+
+	want := []string{actionExportRequested, actionExportCompleted, actionExportDownloaded}
+	if got := f.logs.actions(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("audit actions = %v, want %v", got, want)
+	}
+}
+
+func TestEraseUser(t *testing.T) {
+	f := newPrivacyFixture(t)
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	if _, err := f.service.EraseUser(ctx, f.userID, adminID, privacyDomain.RequestErasureDomain{Confirm: "someone"}); !errors.Is(err, ErrErasureNotConfirmed) {
+		t.Fatalf("got %v, want ErrErasureNotConfirmed", err)
+	}
+	if f.userData.erased != nil {
+		t.Fatal("nothing should be erased without confirmation")
+	}
+
+	summary, err := f.service.EraseUser(ctx, f.userID, adminID, privacyDomain.RequestErasureDomain{Confirm: "somchai", Reason: "ticket 42"})
+	if err != nil {
+		t.Fatalf("EraseUser: %v", err)
+	}
+	if summary.DeletedRecords != 7 || summary.AnonymisedOrders != 2 {
+		t.Fatalf("summary = %+v", summary)
+	}
+	erased := f.userData.erased
+	if erased.Status != userDomain.USER_STATUS_ERASED || erased.Password != "" ||
+		strings.Contains(erased.Email, "somchai") || strings.Contains(erased.Username, "somchai") {
+		t.Fatalf("user was not anonymised: %+v", erased)
+	}
+
+	if len(f.logs.entries) != 1 {
+		t.Fatalf("audit entries = %d, want 1", len(f.logs.entries))
+	}
+	entry := f.logs.entries[0]
+	if entry.Action != actionPersonalDataErase || entry.UserID != adminID || entry.EntityID != f.userID ||
+		!strings.Contains(entry.Description, "ticket 42") {
+		t.Fatalf("audit entry = %+v", entry)
+	}
+
+	if _, err := f.service.RequestExport(ctx, f.userID, f.userID, privacyDomain.RequestDataExportDomain{}); !errors.Is(err, ErrUserErased) {
+		t.Fatalf("export after erasure: got %v, want ErrUserErased", err)
+	}
+}
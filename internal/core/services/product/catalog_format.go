@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+)
+
+// catalogColumns is the header of catalog CSV files, in the order exports
+// write it. Imports accept the columns in any order.
+var catalogColumns = []string{
+	"product_sku", "name", "description", "category", "product_price",
+	"sku", "barcode", "price", "weight_grams", "stock", "options",
+}
+
+// requiredCatalogColumns must be present in the header of a CSV import.
+var requiredCatalogColumns = []string{"product_sku", "name", "category"}
+
+var reportColumns = []string{"line", "product_sku", "sku", "error"}
+
+// maxNDJSONLine bounds a single line of an NDJSON import.
+const maxNDJSONLine = 1 << 20
+
+// parsedRow is a row read from an import file, or the reason it could not
+// be read.
+type parsedRow struct {
+	line int
+	row  productDomain.CatalogRow
+	err  error
+}
+
+// readCatalog reads every row of an import file. A malformed row is returned
+// with its error; an error of readCatalog itself means the file as a whole is
+// unreadable.
+func readCatalog(format productDomain.CATALOG_FORMAT, data []byte, maxRows int) ([]parsedRow, error) {
+	if format == productDomain.CATALOG_FORMAT_NDJSON {
+		return readCatalogNDJSON(data, maxRows)
+	}
+	return readCatalogCSV(data, maxRows)
+}
+
+func readCatalogCSV(data []byte, maxRows int) ([]parsedRow, error) {
+	// Spreadsheets often save UTF-8 with a byte order mark.
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrImportEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredCatalogColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrImportMissingColumn, name)
+		}
+	}
+
+	var rows []parsedRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxRows {
+			return nil, ErrImportTooManyRows
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			rows = append(rows, parsedRow{line: line, err: fmt.Errorf("expected %d fields, got %d", len(header), len(record))})
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		row, err := catalogRowFromFields(field)
+		rows = append(rows, parsedRow{line: line, row: row, err: err})
+	}
+}
+
+// catalogRowFromFields converts the text fields of a CSV record.
+func catalogRowFromFields(field func(name string) string) (productDomain.CatalogRow, error) {
+	row := productDomain.CatalogRow{
+		ProductSKU:  field("product_sku"),
+		Name:        field("name"),
+		Description: field("description"),
+		Category:    field("category"),
+		SKU:         field("sku"),
+		Barcode:     field("barcode"),
+	}
+	if value := field("product_price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return row, fmt.Errorf("product_price is not a number")
+		}
+		row.ProductPrice = &price
+	}
+	if value := field("price"); value != "" {
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return row, fmt.Errorf("price is not a number")
+		}
+		row.Price = price
+	}
+	for name, target := range map[string]*int{"weight_grams": &row.WeightGrams, "stock": &row.Stock} {
+		if value := field(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return row, fmt.Errorf("%s is not a whole number", name)
+			}
+			*target = n
+		}
+	}
+	if value := field("options"); value != "" {
+		query, err := url.ParseQuery(value)
+		if err != nil {
+			return row, fmt.Errorf(`options must look like "Color=Red&Size=L"`)
+		}
+		row.Options = make(map[string]string, len(query))
+		for name, values := range query {
+			if len(values) != 1 {
+				return row, fmt.Errorf("option %q is given more than once", name)
+			}
+			row.Options[name] = values[0]
+		}
+	}
+	return row, nil
+}
+
+func readCatalogNDJSON(data []byte, maxRows int) ([]parsedRow, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxNDJSONLine)
+	var rows []parsedRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, ErrImportTooManyRows
+		}
+		var row productDomain.CatalogRow
+		if err := json.Unmarshal(text, &row); err != nil {
+			rows = append(rows, parsedRow{line: line, err: fmt.Errorf("invalid JSON: %v", err)})
+			continue
+		}
+		rows = append(rows, parsedRow{line: line, row: row})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	return rows, nil
+}
+
+// catalogWriter writes catalog rows in one of the catalog formats.
+type catalogWriter interface {
+	Write(row productDomain.CatalogRow) error
+	// Flush writes out buffered rows and reports any write error.
+	Flush() error
+}
+
+func newCatalogWriter(format productDomain.CATALOG_FORMAT, w io.Writer) (catalogWriter, error) {
+	if format == productDomain.CATALOG_FORMAT_NDJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &ndjsonCatalogWriter{encoder: encoder}, nil
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogColumns); err != nil {
+		return nil, err
+	}
+	return &csvCatalogWriter{writer: writer}, nil
+}
+
+type csvCatalogWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvCatalogWriter) Write(row productDomain.CatalogRow) error {
+	productPrice := ""
+	if row.ProductPrice != nil {
+		productPrice = strconv.FormatFloat(*row.ProductPrice, 'f', -1, 64)
+	}
+	price, weight, stock := "", "", ""
+	if row.SKU != "" {
+		price = strconv.FormatFloat(row.Price, 'f', -1, 64)
+		weight = strconv.Itoa(row.WeightGrams)
+		stock = strconv.Itoa(row.Stock)
+	}
+	options := make(url.Values, len(row.Options))
+	for name, value := range row.Options {
+		options.Set(name, value)
+	}
+	return w.writer.Write([]string{
+		row.ProductSKU, row.Name, row.Description, row.Category, productPrice,
+		row.SKU, row.Barcode, price, weight, stock, options.Encode(),
+	})
+}
+
+func (w *csvCatalogWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type ndjsonCatalogWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonCatalogWriter) Write(row productDomain.CatalogRow) error {
+	return w.encoder.Encode(row)
+}
+
+// Flush is a no-op, the encoder does not buffer.
+func (w *ndjsonCatalogWriter) Flush() error {
+	return nil
+}
+
+// encodeReport writes the rows that failed to import, by line, in the format
+// of the uploaded file.
+func encodeReport(format productDomain.CATALOG_FORMAT, failures []productDomain.CatalogRowError) ([]byte, error) {
+	sort.SliceStable(failures, func(i, j int) bool { return failures[i].Line < failures[j].Line })
+	var buf bytes.Buffer
+	if format == productDomain.CATALOG_FORMAT_NDJSON {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false)
+		for _, failure := range failures {
+			if err := encoder.Encode(failure); err != nil {
+				return nil, err
+			}
+		}
+		return buf.Bytes(), nil
+	}
+
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(reportColumns); err != nil {
+		return nil, err
+	}
+	for _, failure := range failures {
+		if err := writer.Write([]string{strconv.Itoa(failure.Line), failure.ProductSKU, failure.SKU, failure.Error}); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
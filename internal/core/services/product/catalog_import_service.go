@@ -0,0 +1,789 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	storagePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImportFormat        = errors.New("format must be csv or ndjson")
+	ErrImportEmpty         = errors.New("the file has no rows")
+	ErrImportTooLarge      = errors.New("the file is too large")
+	ErrImportTooManyRows   = errors.New("the file has too many rows, split it up")
+	ErrImportMissingColumn = errors.New("the header is missing a required column")
+	ErrImportNotFound      = errors.New("import not found")
+	ErrImportNotFinished   = errors.New("the import has not finished yet")
+	ErrImportNoReport      = errors.New("every row of the import succeeded, there is no error report")
+	ErrImportInterrupted   = errors.New("the import was interrupted too many times")
+)
+
+// CatalogImportServiceConfig holds the tunables of CatalogImportServiceImpl.
+type CatalogImportServiceConfig struct {
+	MaxFileBytes    int
+	MaxRows         int
+	ChunkSize       int           // Rows upserted per transaction
+	ExportBatchSize int           // Products read per query when exporting
+	MaxOptions      int           // Option types an import may give a product, as ProductSKUServiceConfig.MaxOptions
+	WorkerInterval  time.Duration // How often the worker looks for queued imports
+	WorkerBatchSize int
+	QueueSize       int
+	StaleAfter      time.Duration // How long a processing import may go without progress before it is queued again
+	MaxAttempts     int           // Times an import is started before an interrupted one is given up
+}
+
+var DefaultCatalogImportServiceConfig = CatalogImportServiceConfig{
+	MaxFileBytes:    16 << 20,
+	MaxRows:         100000,
+	ChunkSize:       200,
+	ExportBatchSize: 200,
+	MaxOptions:      DefaultProductSKUServiceConfig.MaxOptions,
+	WorkerInterval:  time.Minute,
+	WorkerBatchSize: 10,
+	QueueSize:       100,
+	StaleAfter:      15 * time.Minute,
+	MaxAttempts:     3,
+}
+
+type CatalogImportServiceImpl struct {
+	importRepo    ports.ICatalogImportRepository
+	productRepo   ports.IProductRepository
+	skuRepo       ports.IProductSKURepository
+	categoryRepo  ports.ICategoryRepository
+	importStorage storagePorts.IFileStorage // private, uploaded files and error reports
+	inventory     inventoryPorts.IInventoryService
+	transactor    transactors.IDatabaseTransactor
+	config        CatalogImportServiceConfig
+	queue         chan uuid.UUID
+	now           func() time.Time
+}
+
+func NewCatalogImportService(
+	importRepo ports.ICatalogImportRepository,
+	productRepo ports.IProductRepository,
+	skuRepo ports.IProductSKURepository,
+	categoryRepo ports.ICategoryRepository,
+	importStorage storagePorts.IFileStorage,
+	inventory inventoryPorts.IInventoryService,
+	transactor transactors.IDatabaseTransactor,
+	config CatalogImportServiceConfig,
+) ports.ICatalogImportService {
+	return &CatalogImportServiceImpl{
+		importRepo:    importRepo,
+		productRepo:   productRepo,
+		skuRepo:       skuRepo,
+		categoryRepo:  categoryRepo,
+		importStorage: importStorage,
+		inventory:     inventory,
+		transactor:    transactor,
+		config:        config,
+		queue:         make(chan uuid.UUID, config.QueueSize),
+		now:           time.Now,
+	}
+}
+
+// rowError is a reason a single row cannot be imported; any other error
+// rolls back the whole chunk.
+type rowError struct {
+	err error
+}
+
+func (e rowError) Error() string { return e.err.Error() }
+func (e rowError) Unwrap() error { return e.err }
+
+// StartImport implements ports.ICatalogImportService.
+func (s *CatalogImportServiceImpl) StartImport(ctx context.Context, actorID uuid.UUID, payload productDomain.StartCatalogImportDomain) (*productDomain.CatalogImportJob, error) {
+	format, ok := productDomain.ParseCatalogFormat(string(payload.Format))
+	if !ok {
+		return nil, ErrImportFormat
+	}
+	if len(payload.Data) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(payload.Data) > s.config.MaxFileBytes {
+		return nil, ErrImportTooLarge
+	}
+
+	job := &productDomain.CatalogImportJob{
+		RequestedByID: actorID,
+		Format:        format,
+		Status:        productDomain.CATALOG_IMPORT_STATUS_PENDING,
+		FileName:      payload.FileName,
+	}
+	if err := s.importRepo.CreateCatalogImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+	job.FileKey = fmt.Sprintf("catalog-imports/%s/source.%s", job.ID, format)
+	_, putErr := s.importStorage.Put(ctx, job.FileKey, format.ContentType(), payload.Data)
+	if putErr != nil {
+		now := s.now()
+		job.FileKey = ""
+		job.Status = productDomain.CATALOG_IMPORT_STATUS_FAILED
+		job.Error = putErr.Error()
+		job.CompletedAt = &now
+	}
+	if err := s.importRepo.UpdateCatalogImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+	if putErr != nil {
+		return nil, putErr
+	}
+
+	// A full queue is fine, the worker picks the job up on its next sweep.
+	select {
+	case s.queue <- job.ID:
+	default:
+	}
+	return job, nil
+}
+
+// ListImports implements ports.ICatalogImportService.
+func (s *CatalogImportServiceImpl) ListImports(ctx context.Context) (pagination.Pagination[[]productDomain.CatalogImportJob], error) {
+	return s.importRepo.ListCatalogImportJobs(ctx)
+}
+
+// GetImport implements ports.ICatalogImportService.
+func (s *CatalogImportServiceImpl) GetImport(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error) {
+	job, err := s.importRepo.GetCatalogImportJob(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImportNotFound
+	}
+	return job, err
+}
+
+// DownloadImportReport implements ports.ICatalogImportService.
+func (s *CatalogImportServiceImpl) DownloadImportReport(ctx context.Context, id uuid.UUID) (*productDomain.CatalogFile, error) {
+	job, err := s.GetImport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case job.Status == productDomain.CATALOG_IMPORT_STATUS_PENDING, job.Status == productDomain.CATALOG_IMPORT_STATUS_PROCESSING:
+		return nil, ErrImportNotFinished
+	case job.ReportKey == "":
+		return nil, ErrImportNoReport
+	}
+	data, err := s.importStorage.Get(ctx, job.ReportKey)
+	if err != nil {
+		return nil, err
+	}
+	return &productDomain.CatalogFile{
+		Name:        fmt.Sprintf("catalog-import-%s-errors.%s", job.CreatedAt.Format("20060102-150405"), job.Format),
+		ContentType: job.Format.ContentType(),
+		Data:        data,
+	}, nil
+}
+
+// ProcessImport implements ports.ICatalogImportService.
+//
+// The job is claimed under a row lock, so several workers can share the
+// queue without importing the same file twice. The worker records its
+// progress after every chunk; a job that stops progressing is queued again
+// by the sweep of any instance.
+func (s *CatalogImportServiceImpl) ProcessImport(ctx context.Context, id uuid.UUID) error {
+	var job *productDomain.CatalogImportJob
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		job, err = s.importRepo.GetCatalogImportJobForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if job.Status != productDomain.CATALOG_IMPORT_STATUS_PENDING {
+			job = nil
+			return nil
+		}
+		job.Status = productDomain.CATALOG_IMPORT_STATUS_PROCESSING
+		job.Attempts++
+		job.UpdatedAt = s.now()
+		return s.importRepo.UpdateCatalogImportJob(ctx, job)
+	})
+	if err != nil || job == nil {
+		return err
+	}
+
+	if err := s.runImport(ctx, job); err != nil {
+		job.Status = productDomain.CATALOG_IMPORT_STATUS_FAILED
+		job.Error = err.Error()
+	} else {
+		job.Status = productDomain.CATALOG_IMPORT_STATUS_COMPLETED
+	}
+	now := s.now()
+	job.CompletedAt = &now
+	return s.importRepo.UpdateCatalogImportJob(ctx, job)
+}
+
+// runImport reads the file of the job, imports its rows and stores the error
+// report, filling in the counters of the job.
+func (s *CatalogImportServiceImpl) runImport(ctx context.Context, job *productDomain.CatalogImportJob) error {
+	data, err := s.importStorage.Get(ctx, job.FileKey)
+	if err != nil {
+		return err
+	}
+	parsed, err := readCatalog(job.Format, data, s.config.MaxRows)
+	if err != nil {
+		return err
+	}
+	job.TotalRows = len(parsed)
+
+	var (
+		failures []productDomain.CatalogRowError
+		valid    []parsedRow
+	)
+	fail := func(p parsedRow, err error) {
+		failures = append(failures, productDomain.CatalogRowError{
+			Line:       p.line,
+			ProductSKU: p.row.ProductSKU,
+			SKU:        p.row.SKU,
+			Error:      err.Error(),
+		})
+	}
+	seen := make(map[string]int, len(parsed))
+	for _, p := range parsed {
+		if p.err == nil {
+			p.row, p.err = normalizeCatalogRow(p.row)
+		}
+		if p.err == nil && p.row.SKU != "" {
+			if line, ok := seen[p.row.SKU]; ok {
+				p.err = fmt.Errorf("sku %s is already on line %d", p.row.SKU, line)
+			} else {
+				seen[p.row.SKU] = p.line
+			}
+		}
+		if p.err != nil {
+			fail(p, p.err)
+			continue
+		}
+		valid = append(valid, p)
+	}
+
+	categories := map[string]uuid.UUID{}
+	for start := 0; start < len(valid); start += s.config.ChunkSize {
+		chunk := valid[start:min(start+s.config.ChunkSize, len(valid))]
+		var chunkFailures []productDomain.CatalogRowError
+		imported := 0
+		err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			chunkFailures, imported = nil, 0
+			for _, p := range chunk {
+				err := s.importRow(ctx, job, p.row, categories)
+				var rowErr rowError
+				if errors.As(err, &rowErr) {
+					chunkFailures = append(chunkFailures, productDomain.CatalogRowError{
+						Line: p.line, ProductSKU: p.row.ProductSKU, SKU: p.row.SKU, Error: rowErr.Error(),
+					})
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				imported++
+			}
+			return nil
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// The chunk was rolled back, none of its rows were written.
+			for _, p := range chunk {
+				fail(p, fmt.Errorf("not imported, its batch failed: %v", err))
+			}
+			continue
+		}
+		failures = append(failures, chunkFailures...)
+		job.ImportedRows += imported
+
+		job.UpdatedAt = s.now()
+		if err := s.importRepo.UpdateCatalogImportJob(ctx, job); err != nil {
+			log.Printf("catalog import: job %s: record progress: %v", job.ID, err)
+		}
+	}
+
+	job.FailedRows = len(failures)
+	if len(failures) == 0 {
+		return nil
+	}
+	report, err := encodeReport(job.Format, failures)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("catalog-imports/%s/errors.%s", job.ID, job.Format)
+	if _, err := s.importStorage.Put(ctx, key, job.Format.ContentType(), report); err != nil {
+		return err
+	}
+	job.ReportKey = key
+	return nil
+}
+
+// normalizeCatalogRow trims the fields of a row and checks them the way the
+// payloads of the catalog endpoints are validated.
+func normalizeCatalogRow(row productDomain.CatalogRow) (productDomain.CatalogRow, error) {
+	row.ProductSKU = normalizeSKU(row.ProductSKU)
+	row.Name = strings.TrimSpace(row.Name)
+	row.Category = strings.TrimSpace(row.Category)
+	row.SKU = normalizeSKU(row.SKU)
+	row.Barcode = strings.TrimSpace(row.Barcode)
+
+	switch {
+	case row.ProductSKU == "":
+		return row, errors.New("product_sku is required")
+	case utf8.RuneCountInString(row.ProductSKU) > maxSKUCodeLength:
+		return row, fmt.Errorf("product_sku is longer than %d characters", maxSKUCodeLength)
+	case row.Name == "":
+		return row, errors.New("name is required")
+	case utf8.RuneCountInString(row.Name) > 150:
+		return row, errors.New("name is longer than 150 characters")
+	case utf8.RuneCountInString(row.Description) > 10000:
+		return row, errors.New("description is longer than 10000 characters")
+	case row.Category == "":
+		return row, errors.New("category is required")
+	case row.ProductPrice != nil && *row.ProductPrice < 0:
+		return row, errors.New("product_price cannot be negative")
+	}
+	if row.SKU == "" {
+		if row.Barcode != "" || len(row.Options) > 0 {
+			return row, errors.New("sku is required when barcode or options are given")
+		}
+		return row, nil
+	}
+
+	switch {
+	case utf8.RuneCountInString(row.SKU) > maxSKUCodeLength:
+		return row, fmt.Errorf("sku is longer than %d characters", maxSKUCodeLength)
+	case utf8.RuneCountInString(row.Barcode) > 64:
+		return row, errors.New("barcode is longer than 64 characters")
+	case row.Price < 0:
+		return row, errors.New("price cannot be negative")
+	case row.WeightGrams < 0:
+		return row, errors.New("weight_grams cannot be negative")
+	case row.Stock < 0:
+		return row, errors.New("stock cannot be negative")
+	}
+	options := make(map[string]string, len(row.Options))
+	for rawName, rawValue := range row.Options {
+		name, value := strings.TrimSpace(rawName), strings.TrimSpace(rawValue)
+		if name == "" || value == "" {
+			return row, errors.New("option names and values cannot be empty")
+		}
+		if utf8.RuneCountInString(name) > 50 || utf8.RuneCountInString(value) > 50 {
+			return row, errors.New("option names and values are limited to 50 characters")
+		}
+		for existing := range options {
+			if strings.EqualFold(existing, name) {
+				return row, fmt.Errorf("option %q is given more than once", name)
+			}
+		}
+		options[name] = value
+	}
+	row.Options = options
+	return row, nil
+}
+
+// importRow upserts the product of the row by its SKU, then the SKU of the
+// row by its code. Every check runs before the first write, so a row that
+// fails leaves nothing behind in the chunk.
+func (s *CatalogImportServiceImpl) importRow(ctx context.Context, job *productDomain.CatalogImportJob, row productDomain.CatalogRow, categories map[string]uuid.UUID) error {
+	categoryID, err := s.resolveCategory(ctx, row.Category, categories)
+	if err != nil {
+		return err
+	}
+
+	product, err := s.importRepo.GetProductBySKUForUpdate(ctx, row.ProductSKU)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// A soft deleted product still holds its SKU.
+		taken, err := s.productRepo.ExistsProductSKU(ctx, row.ProductSKU, nil)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return rowError{ErrSKUTaken}
+		}
+		product = nil
+	case err != nil:
+		return err
+	}
+
+	var plan *skuPlan
+	if row.SKU != "" {
+		if plan, err = s.planSKU(ctx, product, row); err != nil {
+			return err
+		}
+	}
+
+	if product == nil {
+		product = &productDomain.Product{CreatedBy: job.RequestedByID, SKU: row.ProductSKU, Price: row.Price}
+	}
+	product.Name = row.Name
+	product.Description = row.Description
+	product.CategoryID = categoryID
+	if row.ProductPrice != nil {
+		product.Price = *row.ProductPrice
+	}
+	if product.ID == uuid.Nil {
+		err = s.productRepo.CreateProduct(ctx, product)
+	} else {
+		err = s.productRepo.UpdateProduct(ctx, product)
+	}
+	if err != nil || plan == nil {
+		return err
+	}
+	return s.applySKU(ctx, job, product, row, plan)
+}
+
+// resolveCategory finds the category of a row by ID or slug; results are
+// remembered for the rest of the job.
+func (s *CatalogImportServiceImpl) resolveCategory(ctx context.Context, ref string, categories map[string]uuid.UUID) (uuid.UUID, error) {
+	key := strings.ToLower(ref)
+	if id, ok := categories[key]; ok {
+		return id, nil
+	}
+	var (
+		category *productDomain.Category
+		err      error
+	)
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		category, err = s.categoryRepo.GetCategory(ctx, id)
+	} else {
+		category, err = s.categoryRepo.GetCategoryBySlug(ctx, key)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, rowError{fmt.Errorf("%w: %s", ErrCategoryNotFound, ref)}
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	categories[key] = category.ID
+	return category.ID, nil
+}
+
+// skuPlan is what importing the SKU of a row will change.
+type skuPlan struct {
+	existing   *productDomain.ProductSKU      // The SKU to update, nil to create one
+	options    []productDomain.ProductOption  // Options of the product, with the values of the row appended
+	newOptions []*productDomain.ProductOption // Options the row introduces, created with their value
+	newValues  []*productDomain.ProductOptionValue
+}
+
+// planSKU checks that the SKU of the row can be written. product is nil when
+// the row creates it.
+func (s *CatalogImportServiceImpl) planSKU(ctx context.Context, product *productDomain.Product, row productDomain.CatalogRow) (*skuPlan, error) {
+	plan := &skuPlan{}
+	existing, err := s.importRepo.GetSKUByCodeForUpdate(ctx, row.SKU)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := s.checkSKUCodeFree(ctx, row.SKU); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case product == nil || existing.ProductID != product.ID:
+		return nil, rowError{fmt.Errorf("sku %s belongs to another product", row.SKU)}
+	default:
+		// Units held by checkouts, or kept at other warehouses, cannot be
+		// counted away.
+		err := s.inventory.CheckStock(ctx, existing.ID, row.Stock)
+		if errors.Is(err, inventoryServices.ErrStockBelowReserved) || errors.Is(err, inventoryServices.ErrStockAtOtherWarehouses) {
+			return nil, rowError{err}
+		}
+		if err != nil {
+			return nil, err
+		}
+		plan.existing = existing
+	}
+
+	var skus []productDomain.ProductSKU
+	if product != nil {
+		if plan.options, err = s.skuRepo.ListProductOptions(ctx, product.ID); err != nil {
+			return nil, err
+		}
+		if skus, err = s.skuRepo.ListProductSKUs(ctx, product.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Every option of the product needs a value in the row.
+	for _, option := range plan.options {
+		if _, ok := optionValue(row.Options, option.Name); !ok {
+			return nil, rowError{fmt.Errorf("%w, %s is missing", ErrIncompleteCombination, option.Name)}
+		}
+	}
+	// New options are added in the order of their names, so that the
+	// positions do not depend on the iteration order of the map.
+	names := make([]string, 0, len(row.Options))
+	for name := range row.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := row.Options[name]
+		option := findOption(plan.options, name)
+		if option == nil {
+			if len(skus) > 0 {
+				return nil, rowError{ErrProductHasSKUs}
+			}
+			if len(plan.options) >= s.config.MaxOptions {
+				return nil, rowError{ErrTooManyOptions}
+			}
+			plan.options = append(plan.options, productDomain.ProductOption{Name: name, Position: len(plan.options)})
+			option = &plan.options[len(plan.options)-1]
+		}
+		if findOptionValue(option.Values, value) == nil {
+			option.Values = append(option.Values, productDomain.ProductOptionValue{Value: value, Position: len(option.Values)})
+		}
+	}
+
+	ids, complete := combinationIDs(plan.options, row.Options)
+	if plan.existing != nil {
+		if !complete || plan.existing.Signature != productDomain.SKUSignature(ids) {
+			return nil, rowError{fmt.Errorf("the options of sku %s cannot be changed", row.SKU)}
+		}
+		return plan, nil
+	}
+	if complete {
+		signature := productDomain.SKUSignature(ids)
+		for _, sku := range skus {
+			if sku.Signature == signature {
+				return nil, rowError{ErrSKUCombinationExists}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// applySKU creates the options, values and SKU planned by planSKU, or
+// updates the SKU. The stock of the row is recorded as a count of the units
+// on hand.
+func (s *CatalogImportServiceImpl) applySKU(ctx context.Context, job *productDomain.CatalogImportJob, product *productDomain.Product, row productDomain.CatalogRow, plan *skuPlan) error {
+	sku := plan.existing
+	if sku == nil {
+		for i := range plan.options {
+			option := &plan.options[i]
+			if option.ID == uuid.Nil {
+				option.ProductID = product.ID
+				if err := s.skuRepo.CreateProductOption(ctx, option); err != nil {
+					return err
+				}
+				continue
+			}
+			for j := range option.Values {
+				if value := &option.Values[j]; value.ID == uuid.Nil {
+					value.OptionID = option.ID
+					if err := s.skuRepo.CreateProductOptionValue(ctx, value); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		ids, _ := combinationIDs(plan.options, row.Options)
+		values, err := combination(plan.options, ids)
+		if err != nil {
+			return err
+		}
+		sku = &productDomain.ProductSKU{
+			ProductID:    product.ID,
+			Code:         row.SKU,
+			Signature:    productDomain.SKUSignature(ids),
+			OptionValues: values,
+		}
+	}
+	sku.Barcode = row.Barcode
+	sku.Price = row.Price
+	sku.WeightGrams = row.WeightGrams
+	sku.Stock = row.Stock
+	var err error
+	if sku.ID == uuid.Nil {
+		err = s.skuRepo.CreateProductSKU(ctx, sku)
+	} else {
+		err = s.skuRepo.UpdateProductSKU(ctx, sku)
+	}
+	if err != nil {
+		return err
+	}
+	return s.inventory.SetStock(ctx, job.RequestedByID, sku.ID, orderDomain.SetStockDomain{
+		Quantity:      row.Stock,
+		Reason:        fmt.Sprintf("catalog import of %s", job.FileName),
+		ReferenceType: orderDomain.STOCK_REFERENCE_CATALOG_IMPORT,
+		ReferenceID:   &job.ID,
+	})
+}
+
+func (s *CatalogImportServiceImpl) checkSKUCodeFree(ctx context.Context, code string) error {
+	// Soft deleted SKUs still hold their code.
+	taken, err := s.skuRepo.ExistsSKUCode(ctx, code, nil)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return rowError{ErrSKUCodeTaken}
+	}
+	return nil
+}
+
+// combinationIDs picks the value of every option named in the row. complete
+// is false while some of the values are yet to be created.
+func combinationIDs(options []productDomain.ProductOption, values map[string]string) (ids []uuid.UUID, complete bool) {
+	complete = true
+	for _, option := range options {
+		name, _ := optionValue(values, option.Name)
+		value := findOptionValue(option.Values, name)
+		if value == nil || value.ID == uuid.Nil {
+			complete = false
+			continue
+		}
+		ids = append(ids, value.ID)
+	}
+	return ids, complete
+}
+
+// optionValue looks an option up by name, case-insensitively.
+func optionValue(values map[string]string, name string) (string, bool) {
+	for key, value := range values {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+func findOption(options []productDomain.ProductOption, name string) *productDomain.ProductOption {
+	for i := range options {
+		if strings.EqualFold(options[i].Name, name) {
+			return &options[i]
+		}
+	}
+	return nil
+}
+
+func findOptionValue(values []productDomain.ProductOptionValue, value string) *productDomain.ProductOptionValue {
+	for i := range values {
+		if strings.EqualFold(values[i].Value, value) {
+			return &values[i]
+		}
+	}
+	return nil
+}
+
+// RunWorker implements ports.ICatalogImportService.
+func (s *CatalogImportServiceImpl) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	s.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-s.queue:
+			if err := s.ProcessImport(ctx, id); err != nil {
+				log.Printf("catalog import: job %s: %v", id, err)
+			}
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// sweep processes imports that missed the queue (full queue, restart), and
+// the ones left half done by an instance that stopped.
+func (s *CatalogImportServiceImpl) sweep(ctx context.Context) {
+	s.requeueStale(ctx)
+
+	pending, err := s.importRepo.ListCatalogImportJobsByStatus(ctx, productDomain.CATALOG_IMPORT_STATUS_PENDING, s.config.WorkerBatchSize)
+	if err != nil {
+		log.Printf("catalog import: list pending jobs: %v", err)
+	}
+	for _, job := range pending {
+		if err := s.ProcessImport(ctx, job.ID); err != nil {
+			log.Printf("catalog import: job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// requeueStale puts the imports whose worker stopped recording progress back
+// in the queue. Rows are upserted by SKU and stock is set, not added, so the
+// rows imported before the interruption can safely be imported again.
+func (s *CatalogImportServiceImpl) requeueStale(ctx context.Context) {
+	stale, err := s.importRepo.ListStaleCatalogImportJobs(ctx, s.now().Add(-s.config.StaleAfter), s.config.WorkerBatchSize)
+	if err != nil {
+		log.Printf("catalog import: list stale jobs: %v", err)
+		return
+	}
+	for _, job := range stale {
+		if err := s.requeue(ctx, job.ID); err != nil {
+			log.Printf("catalog import: requeue job %s: %v", job.ID, err)
+		}
+	}
+}
+
+func (s *CatalogImportServiceImpl) requeue(ctx context.Context, id uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		job, err := s.importRepo.GetCatalogImportJobForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		// The worker may have recorded progress since the jobs were listed.
+		if job.Status != productDomain.CATALOG_IMPORT_STATUS_PROCESSING || job.UpdatedAt.After(s.now().Add(-s.config.StaleAfter)) {
+			return nil
+		}
+		if job.Attempts >= s.config.MaxAttempts {
+			now := s.now()
+			job.Status = productDomain.CATALOG_IMPORT_STATUS_FAILED
+			job.Error = ErrImportInterrupted.Error()
+			job.CompletedAt = &now
+		} else {
+			job.Status = productDomain.CATALOG_IMPORT_STATUS_PENDING
+			job.TotalRows, job.ImportedRows, job.FailedRows = 0, 0, 0
+		}
+		return s.importRepo.UpdateCatalogImportJob(ctx, job)
+	})
+}
+
+// ExportCatalog implements ports.ICatalogImportService.
+//
+// Each batch is flushed to w as soon as it is written, so the response
+// starts before the whole catalog has been read and memory stays bounded by
+// one batch.
+func (s *CatalogImportServiceImpl) ExportCatalog(ctx context.Context, format productDomain.CATALOG_FORMAT, w io.Writer) error {
+	writer, err := newCatalogWriter(format, w)
+	if err != nil {
+		return err
+	}
+	flush := func() error {
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(interface{ Flush() error }); ok {
+			return flusher.Flush()
+		}
+		return nil
+	}
+	err = s.importRepo.EachCatalogBatch(ctx, s.config.ExportBatchSize, func(rows []productDomain.CatalogRow) error {
+		for _, row := range rows {
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
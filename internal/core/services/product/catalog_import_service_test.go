@@ -0,0 +1,364 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/storage"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeImportRepo struct {
+	ports.ICatalogImportRepository
+	jobs     map[uuid.UUID]*productDomain.CatalogImportJob
+	products *fakeProductRepo
+	skus     *fakeSKURepo
+	batches  [][]productDomain.CatalogRow
+}
+
+func (f *fakeImportRepo) CreateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error {
+	payload.ID = uuid.New()
+	return f.UpdateCatalogImportJob(ctx, payload)
+}
+
+func (f *fakeImportRepo) GetCatalogImportJob(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error) {
+	if job, ok := f.jobs[id]; ok {
+		clone := *job
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeImportRepo) GetCatalogImportJobForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.CatalogImportJob, error) {
+	return f.GetCatalogImportJob(ctx, id)
+}
+
+func (f *fakeImportRepo) UpdateCatalogImportJob(ctx context.Context, payload *productDomain.CatalogImportJob) error {
+	clone := *payload
+	f.jobs[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeImportRepo) ListCatalogImportJobsByStatus(ctx context.Context, status productDomain.CATALOG_IMPORT_STATUS, limit int) ([]productDomain.CatalogImportJob, error) {
+	var jobs []productDomain.CatalogImportJob
+	for _, job := range f.jobs {
+		if job.Status == status && len(jobs) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *fakeImportRepo) ListStaleCatalogImportJobs(ctx context.Context, before time.Time, limit int) ([]productDomain.CatalogImportJob, error) {
+	var jobs []productDomain.CatalogImportJob
+	for _, job := range f.jobs {
+		if job.Status == productDomain.CATALOG_IMPORT_STATUS_PROCESSING && job.UpdatedAt.Before(before) && len(jobs) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (f *fakeImportRepo) GetProductBySKUForUpdate(ctx context.Context, sku string) (*productDomain.Product, error) {
+	for _, product := range f.products.products {
+		if product.SKU == sku {
+			clone := *product
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeImportRepo) GetSKUByCodeForUpdate(ctx context.Context, code string) (*productDomain.ProductSKU, error) {
+	for _, sku := range f.skus.skus {
+		if sku.Code == code {
+			clone := *sku
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeImportRepo) EachCatalogBatch(ctx context.Context, batchSize int, fn func(rows []productDomain.CatalogRow) error) error {
+	for _, rows := range f.batches {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type importFixture struct {
+	*catalogFixture
+	repo      *fakeImportRepo
+	skus      *fakeSKURepo
+	inventory *fakeInventory
+	service   *CatalogImportServiceImpl
+}
+
+func newImportFixture(t *testing.T) *importFixture {
+	catalog := newCatalogFixture()
+	skus := &fakeSKURepo{
+		options: map[uuid.UUID]*productDomain.ProductOption{},
+		skus:    map[uuid.UUID]*productDomain.ProductSKU{},
+	}
+	repo := &fakeImportRepo{jobs: map[uuid.UUID]*productDomain.CatalogImportJob{}, products: catalog.products, skus: skus}
+	config := DefaultCatalogImportServiceConfig
+	config.ChunkSize = 3
+	inventory := newFakeInventory()
+	service := NewCatalogImportService(repo, catalog.products, skus, catalog.categories, storage.NewLocalStorage(t.TempDir(), ""), inventory, transactorstest.Transactor{}, config)
+	catalog.createCategory(t, "Clothing", nil)
+	return &importFixture{catalogFixture: catalog, repo: repo, skus: skus, inventory: inventory, service: service.(*CatalogImportServiceImpl)}
+}
+
+// run uploads the file and processes it right away.
+func (f *importFixture) run(t *testing.T, format productDomain.CATALOG_FORMAT, data string) *productDomain.CatalogImportJob {
+	t.Helper()
+	ctx := context.Background()
+	job, err := f.service.StartImport(ctx, f.adminID, productDomain.StartCatalogImportDomain{FileName: "catalog", Format: format, Data: []byte(data)})
+	if err != nil {
+		t.Fatalf("StartImport: %v", err)
+	}
+	if err := f.service.ProcessImport(ctx, job.ID); err != nil {
+		t.Fatalf("ProcessImport: %v", err)
+	}
+	job, err = f.service.GetImport(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("GetImport: %v", err)
+	}
+	return job
+}
+
+func (f *importFixture) report(t *testing.T, job *productDomain.CatalogImportJob) string {
+	t.Helper()
+	file, err := f.service.DownloadImportReport(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("DownloadImportReport: %v", err)
+	}
+	return string(file.Data)
+}
+
+func (f *importFixture) sku(t *testing.T, code string) *productDomain.ProductSKU {
+	t.Helper()
+	sku, err := f.repo.GetSKUByCodeForUpdate(context.Background(), code)
+	if err != nil {
+		t.Fatalf("sku %s: %v", code, err)
+	}
+	return sku
+}
+
+func TestCatalogImportCSV(t *testing.T) {
+	f := newImportFixture(t)
+	job := f.run(t, productDomain.CATALOG_FORMAT_CSV, "\ufeffproduct_sku,name,category,price,sku,stock,options\n"+
+		"tee,Tee,clothing,19.9,tee-red-s,5,Color=Red&Size=S\n"+
+		"TEE,Tee,clothing,19.9,TEE-RED-M,3,Color=Red&Size=M\n"+
+		"TEE,Tee,clothing,21,TEE-BLUE-M,2,Color=Blue&Size=M\n"+
+		"TEE,Tee,clothing,19.9,TEE-RED-S2,1,Color=Red&Size=S\n"+
+		"TEE,Tee,clothing,19.9,TEE-RED-L,1,Color=Red\n"+
+		"MUG,Mug,kitchen,9,,,\n"+
+		"CAP,Cap,clothing,-1,CAP-1,1,\n"+
+		"CAP,Cap,clothing,12,CAP-1,1,\n"+
+		"CAP,Cap,clothing,12,CAP-1,4,\n"+
+		"BAG,Bag,clothing,,,,\n")
+
+	if job.Status != productDomain.CATALOG_IMPORT_STATUS_COMPLETED || job.TotalRows != 10 || job.ImportedRows != 5 || job.FailedRows != 5 {
+		t.Fatalf("job = %+v", job)
+	}
+	report := strings.Split(strings.TrimSpace(f.report(t, job)), "\n")
+	wantLines := []string{"line,", "5,", "6,", "7,", "8,", "10,"}
+	if len(report) != len(wantLines) {
+		t.Fatalf("report = %q", report)
+	}
+	for i, prefix := range wantLines {
+		if !strings.HasPrefix(report[i], prefix) {
+			t.Fatalf("report line %d = %q, want prefix %q", i, report[i], prefix)
+		}
+	}
+	if !strings.Contains(report[1], ErrSKUCombinationExists.Error()) || !strings.Contains(report[3], "kitchen") {
+		t.Fatalf("report = %q", report)
+	}
+
+	tee, err := f.repo.GetProductBySKUForUpdate(context.Background(), "TEE")
+	if err != nil || tee.Price != 19.9 || tee.CreatedBy != f.adminID {
+		t.Fatalf("product TEE = %+v, %v", tee, err)
+	}
+	options, _ := f.skus.ListProductOptions(context.Background(), tee.ID)
+	if len(options) != 2 || options[0].Name != "Color" || len(options[0].Values) != 2 || len(options[1].Values) != 2 {
+		t.Fatalf("options = %+v", options)
+	}
+	if sku := f.sku(t, "TEE-BLUE-M"); sku.Price != 21 || sku.Stock != 2 || len(sku.OptionValues) != 2 {
+		t.Fatalf("sku = %+v", sku)
+	}
+	if _, err := f.repo.GetProductBySKUForUpdate(context.Background(), "BAG"); err != nil {
+		t.Fatalf("product BAG: %v", err)
+	}
+}
+
+func TestCatalogImportUpdatesBySKU(t *testing.T) {
+	f := newImportFixture(t)
+	f.run(t, productDomain.CATALOG_FORMAT_CSV, "product_sku,name,category,price,sku,stock,options\n"+
+		"TEE,Tee,clothing,19.9,TEE-RED-S,5,Color=Red&Size=S\n"+
+		"TEE,Tee,clothing,19.9,TEE-RED-M,3,Color=Red&Size=M\n"+
+		"CAP,Cap,clothing,12,CAP-1,1,\n")
+
+	job := f.run(t, productDomain.CATALOG_FORMAT_NDJSON, `{"product_sku":"TEE","name":"Tee shirt","category":"clothing","product_price":25,"sku":"TEE-RED-S","price":18,"stock":9,"options":{"size":"s","Color":"red"}}
+
+{"product_sku":"TEE","name":"Tee shirt","category":"clothing","sku":"TEE-RED-M","price":18,"options":{"Color":"Blue","Size":"M"}}
+{"product_sku":"TEE","name":"Tee shirt","category":"clothing","sku":"CAP-1","price":12}
+{"product_sku":"TEE","name":"Tee shirt","category":"clothing","sku":"TEE-RED-S-SLIM","price":18,"options":{"Color":"Red","Size":"S","Fit":"Slim"}}
+not json
+`)
+	if job.ImportedRows != 1 || job.FailedRows != 4 {
+		t.Fatalf("job = %+v", job)
+	}
+	report := f.report(t, job)
+	for _, want := range []string{`"line":3`, "cannot be changed", `"line":4`, "belongs to another product", `"line":5`, ErrProductHasSKUs.Error(), `"line":6`, "invalid JSON"} {
+		if !strings.Contains(report, want) {
+			t.Fatalf("report %s does not mention %q", report, want)
+		}
+	}
+
+	tee, _ := f.repo.GetProductBySKUForUpdate(context.Background(), "TEE")
+	if tee.Name != "Tee shirt" || tee.Price != 25 {
+		t.Fatalf("product TEE = %+v", tee)
+	}
+	if sku := f.sku(t, "TEE-RED-S"); sku.Stock != 9 || sku.Price != 18 {
+		t.Fatalf("sku = %+v", sku)
+	}
+	if sku := f.sku(t, "TEE-RED-M"); sku.Stock != 3 {
+		t.Fatalf("a refused row changed sku %+v", sku)
+	}
+	counts := f.inventory.counts[f.sku(t, "TEE-RED-S").ID]
+	if len(counts) != 2 || counts[1].Quantity != 9 || counts[1].ReferenceType != orderDomain.STOCK_REFERENCE_CATALOG_IMPORT || *counts[1].ReferenceID != job.ID {
+		t.Fatalf("stock counts = %+v", counts)
+	}
+}
+
+func TestCatalogImportKeepsReservedStock(t *testing.T) {
+	f := newImportFixture(t)
+	f.run(t, productDomain.CATALOG_FORMAT_CSV, "product_sku,name,category,price,sku,stock\nCAP,Cap,clothing,12,CAP-1,5\n")
+	f.inventory.reserved[f.sku(t, "CAP-1").ID] = 4
+
+	job := f.run(t, productDomain.CATALOG_FORMAT_CSV, "product_sku,name,category,price,sku,stock\nCAP,Cap,clothing,12,CAP-1,3\n")
+	if job.FailedRows != 1 || !strings.Contains(f.report(t, job), inventoryServices.ErrStockBelowReserved.Error()) {
+		t.Fatalf("job = %+v", job)
+	}
+	if sku := f.sku(t, "CAP-1"); sku.Stock != 5 {
+		t.Fatalf("sku = %+v, the refused row should leave it alone", sku)
+	}
+}
+
+func TestCatalogImportRefusals(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.service.StartImport(ctx, f.adminID, productDomain.StartCatalogImportDomain{Format: "xlsx", Data: []byte("x")}); !errors.Is(err, ErrImportFormat) {
+		t.Fatalf("got %v, want ErrImportFormat", err)
+	}
+	if _, err := f.service.StartImport(ctx, f.adminID, productDomain.StartCatalogImportDomain{Format: productDomain.CATALOG_FORMAT_CSV}); !errors.Is(err, ErrImportEmpty) {
+		t.Fatalf("got %v, want ErrImportEmpty", err)
+	}
+
+	job := f.run(t, productDomain.CATALOG_FORMAT_CSV, "product_sku,name\nTEE,Tee\n")
+	if job.Status != productDomain.CATALOG_IMPORT_STATUS_FAILED || !strings.Contains(job.Error, "category") {
+		t.Fatalf("job = %+v, want failed for the missing column", job)
+	}
+	if _, err := f.service.DownloadImportReport(ctx, job.ID); !errors.Is(err, ErrImportNoReport) {
+		t.Fatalf("got %v, want ErrImportNoReport", err)
+	}
+
+	f.service.config.MaxRows = 1
+	job = f.run(t, productDomain.CATALOG_FORMAT_CSV, "product_sku,name,category\nA,A,clothing\nB,B,clothing\n")
+	if job.Status != productDomain.CATALOG_IMPORT_STATUS_FAILED || job.Error != ErrImportTooManyRows.Error() {
+		t.Fatalf("job = %+v, want failed for too many rows", job)
+	}
+}
+
+func TestStaleImportIsQueuedAgain(t *testing.T) {
+	f := newImportFixture(t)
+	ctx := context.Background()
+	start := func() *productDomain.CatalogImportJob {
+		job, err := f.service.StartImport(ctx, f.adminID, productDomain.StartCatalogImportDomain{
+			FileName: "catalog",
+			Format:   productDomain.CATALOG_FORMAT_CSV,
+			Data:     []byte("product_sku,name,category,price,sku,stock\nTEE,T-Shirt,Clothing,199,TEE-S,5\n"),
+		})
+		if err != nil {
+			t.Fatalf("StartImport: %v", err)
+		}
+		return job
+	}
+	// interrupt leaves the job as a worker that stopped mid-import would.
+	interrupt := func(job *productDomain.CatalogImportJob, attempts int, lastProgress time.Duration) {
+		stored := f.repo.jobs[job.ID]
+		stored.Status = productDomain.CATALOG_IMPORT_STATUS_PROCESSING
+		stored.Attempts = attempts
+		stored.ImportedRows = 1
+		stored.UpdatedAt = time.Now().Add(-lastProgress)
+	}
+
+	working, stopped, exhausted := start(), start(), start()
+	interrupt(working, 1, time.Minute)
+	interrupt(stopped, 1, time.Hour)
+	interrupt(exhausted, DefaultCatalogImportServiceConfig.MaxAttempts, time.Hour)
+	f.service.sweep(ctx)
+
+	if job := f.repo.jobs[working.ID]; job.Status != productDomain.CATALOG_IMPORT_STATUS_PROCESSING {
+		t.Fatalf("an import still making progress should be left alone, status = %s", job.Status)
+	}
+	if job := f.repo.jobs[stopped.ID]; job.Status != productDomain.CATALOG_IMPORT_STATUS_COMPLETED || job.Attempts != 2 || job.ImportedRows != 1 {
+		t.Fatalf("stopped import = %+v, want it imported again", job)
+	}
+	if f.sku(t, "TEE-S").Stock != 5 {
+		t.Fatalf("stock = %d, want 5", f.sku(t, "TEE-S").Stock)
+	}
+	if job := f.repo.jobs[exhausted.ID]; job.Status != productDomain.CATALOG_IMPORT_STATUS_FAILED || job.Error != ErrImportInterrupted.Error() {
+		t.Fatalf("exhausted import = %+v, want it failed", job)
+	}
+}
+
+func TestExportCatalogRoundTrip(t *testing.T) {
+	f := newImportFixture(t)
+	price := 25.0
+	f.repo.batches = [][]productDomain.CatalogRow{
+		{
+			{ProductSKU: "TEE", Name: "Tee, cotton", Description: "Soft \"heavy\" tee\nwith pocket", Category: "clothing", ProductPrice: &price,
+				SKU: "TEE-RED-S", Barcode: "0123", Price: 19.9, WeightGrams: 200, Stock: 5, Options: map[string]string{"Color": "Red & Blue", "Size": "S"}},
+			{ProductSKU: "TEE", Name: "Tee, cotton", Category: "clothing", ProductPrice: &price, SKU: "TEE-RED-M", Price: 19.9, Stock: 1, Options: map[string]string{"Color": "Red", "Size": "M"}},
+		},
+		{
+			{ProductSKU: "BAG", Name: "Bag", Category: "clothing", ProductPrice: &price},
+		},
+	}
+	want := append(append([]productDomain.CatalogRow{}, f.repo.batches[0]...), f.repo.batches[1]...)
+
+	for _, format := range []productDomain.CATALOG_FORMAT{productDomain.CATALOG_FORMAT_CSV, productDomain.CATALOG_FORMAT_NDJSON} {
+		var buf bytes.Buffer
+		if err := f.service.ExportCatalog(context.Background(), format, &buf); err != nil {
+			t.Fatalf("ExportCatalog %s: %v", format, err)
+		}
+		parsed, err := readCatalog(format, buf.Bytes(), 10)
+		if err != nil {
+			t.Fatalf("reading the %s export back: %v", format, err)
+		}
+		if len(parsed) != len(want) {
+			t.Fatalf("%s export has %d rows, want %d:\n%s", format, len(parsed), len(want), buf.String())
+		}
+		for i, p := range parsed {
+			if p.err != nil || !reflect.DeepEqual(p.row, want[i]) {
+				t.Fatalf("%s row %d = %+v, %v; want %+v", format, i, p.row, p.err, want[i])
+			}
+		}
+	}
+}
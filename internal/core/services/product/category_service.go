@@ -0,0 +1,324 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/slug"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryInUse       = errors.New("category still has products, move or delete them first")
+	ErrCategoryHasChildren = errors.New("category still has sub-categories, move or delete them first")
+	ErrCategoryCycle       = errors.New("a category cannot be moved below itself or one of its sub-categories")
+	ErrParentNotFound      = errors.New("parent category not found")
+	ErrSlugTaken           = errors.New("slug is already used by another category")
+	ErrInvalidSlug         = errors.New("slug may only contain lowercase letters, digits and single hyphens")
+)
+
+// defaultCategorySlug is used when a name has no letters or digits at all.
+const defaultCategorySlug = "category"
+
+type CategoryServiceImpl struct {
+	categoryRepo ports.ICategoryRepository
+	productRepo  ports.IProductRepository
+	transactor   transactors.IDatabaseTransactor
+}
+
+func NewCategoryService(
+	categoryRepo ports.ICategoryRepository,
+	productRepo ports.IProductRepository,
+	transactor transactors.IDatabaseTransactor,
+) ports.ICategoryService {
+	return &CategoryServiceImpl{
+		categoryRepo: categoryRepo,
+		productRepo:  productRepo,
+		transactor:   transactor,
+	}
+}
+
+// ListCategories implements ports.ICategoryService.
+func (s *CategoryServiceImpl) ListCategories(ctx context.Context) (pagination.Pagination[[]productDomain.Category], error) {
+	return s.categoryRepo.ListCategories(ctx)
+}
+
+// GetCategory implements ports.ICategoryService.
+func (s *CategoryServiceImpl) GetCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	category, err := s.categoryRepo.GetCategory(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// GetCategoryBySlug implements ports.ICategoryService.
+func (s *CategoryServiceImpl) GetCategoryBySlug(ctx context.Context, slug string) (*productDomain.Category, error) {
+	category, err := s.categoryRepo.GetCategoryBySlug(ctx, slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// GetCategoryTree implements ports.ICategoryService.
+//
+// The tree is built in memory from a single query; catalogs have hundreds of
+// categories, not millions.
+func (s *CategoryServiceImpl) GetCategoryTree(ctx context.Context, rootID *uuid.UUID) ([]productDomain.Category, error) {
+	categories, err := s.categoryRepo.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uuid.UUID][]productDomain.Category)
+	var roots []productDomain.Category
+	for _, category := range categories {
+		switch {
+		case rootID != nil && category.ID == *rootID:
+			roots = append(roots, category)
+		case rootID == nil && category.ParentID == nil:
+			roots = append(roots, category)
+		case category.ParentID != nil:
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+	if rootID != nil && len(roots) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+
+	visited := make(map[uuid.UUID]bool)
+	var attach func(nodes []productDomain.Category) []productDomain.Category
+	attach = func(nodes []productDomain.Category) []productDomain.Category {
+		for i := range nodes {
+			if visited[nodes[i].ID] {
+				continue
+			}
+			visited[nodes[i].ID] = true
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	return attach(roots), nil
+}
+
+// GetBreadcrumbs implements ports.ICategoryService.
+func (s *CategoryServiceImpl) GetBreadcrumbs(ctx context.Context, id uuid.UUID) ([]productDomain.Category, error) {
+	path, err := s.categoryRepo.ListCategoryAncestors(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+	return path, nil
+}
+
+// CreateCategory implements ports.ICategoryService.
+func (s *CategoryServiceImpl) CreateCategory(ctx context.Context, actorID uuid.UUID, payload productDomain.CreateCategoryDomain) (*productDomain.Category, error) {
+	category := &productDomain.Category{
+		Name:      strings.TrimSpace(payload.Name),
+		ParentID:  payload.ParentID,
+		CreatedBy: actorID,
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockCategoryTree(ctx); err != nil {
+			return err
+		}
+		if category.ParentID != nil {
+			if err := s.checkParent(ctx, *category.ParentID); err != nil {
+				return err
+			}
+		}
+		var err error
+		if category.Slug, err = s.resolveSlug(ctx, payload.Slug, category.Name, nil); err != nil {
+			return err
+		}
+		if category.Position, err = s.position(ctx, category.ParentID, payload.Position); err != nil {
+			return err
+		}
+		return s.categoryRepo.CreateCategory(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory implements ports.ICategoryService.
+func (s *CategoryServiceImpl) UpdateCategory(ctx context.Context, id uuid.UUID, payload productDomain.UpsertCategoryDomain) (*productDomain.Category, error) {
+	var category *productDomain.Category
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockCategoryTree(ctx); err != nil {
+			return err
+		}
+		var err error
+		if category, err = s.lockCategory(ctx, id); err != nil {
+			return err
+		}
+		category.Name = strings.TrimSpace(payload.Name)
+		if payload.Slug != "" && payload.Slug != category.Slug {
+			if category.Slug, err = s.resolveSlug(ctx, payload.Slug, category.Name, &category.ID); err != nil {
+				return err
+			}
+		}
+		if payload.Position != nil {
+			category.Position = *payload.Position
+		}
+		return s.categoryRepo.UpdateCategory(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// MoveCategory implements ports.ICategoryService.
+func (s *CategoryServiceImpl) MoveCategory(ctx context.Context, id uuid.UUID, payload productDomain.MoveCategoryDomain) (*productDomain.Category, error) {
+	var category *productDomain.Category
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Two concurrent moves could each pass the cycle check and together
+		// close a loop, so moves are serialised on the whole tree.
+		if err := s.categoryRepo.LockCategoryTree(ctx); err != nil {
+			return err
+		}
+		var err error
+		if category, err = s.lockCategory(ctx, id); err != nil {
+			return err
+		}
+		if payload.ParentID != nil {
+			if *payload.ParentID == id {
+				return ErrCategoryCycle
+			}
+			if err := s.checkParent(ctx, *payload.ParentID); err != nil {
+				return err
+			}
+			subtree, err := s.categoryRepo.ListCategorySubtreeIDs(ctx, id)
+			if err != nil {
+				return err
+			}
+			for _, descendant := range subtree {
+				if descendant == *payload.ParentID {
+					return ErrCategoryCycle
+				}
+			}
+		}
+
+		if sameParent(category.ParentID, payload.ParentID) {
+			if payload.Position != nil {
+				category.Position = *payload.Position
+			}
+		} else {
+			if category.Position, err = s.position(ctx, payload.ParentID, payload.Position); err != nil {
+				return err
+			}
+			category.ParentID = payload.ParentID
+		}
+		return s.categoryRepo.UpdateCategory(ctx, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// DeleteCategory implements ports.ICategoryService.
+func (s *CategoryServiceImpl) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.LockCategoryTree(ctx); err != nil {
+			return err
+		}
+		children, err := s.categoryRepo.CountCategoryChildren(ctx, &id)
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+		count, err := s.productRepo.CountCategoryProducts(ctx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCategoryInUse
+		}
+		err = s.categoryRepo.DeleteCategory(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	})
+}
+
+func (s *CategoryServiceImpl) lockCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	category, err := s.categoryRepo.GetCategoryForUpdate(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (s *CategoryServiceImpl) checkParent(ctx context.Context, parentID uuid.UUID) error {
+	_, err := s.categoryRepo.GetCategory(ctx, parentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrParentNotFound
+	}
+	return err
+}
+
+// resolveSlug validates a requested slug, or derives a free one from the
+// name by appending -2, -3, ... until it no longer collides.
+func (s *CategoryServiceImpl) resolveSlug(ctx context.Context, requested, name string, exceptID *uuid.UUID) (string, error) {
+	if requested != "" {
+		if !slug.Valid(requested) {
+			return "", ErrInvalidSlug
+		}
+		taken, err := s.categoryRepo.ExistsCategorySlug(ctx, requested, exceptID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", ErrSlugTaken
+		}
+		return requested, nil
+	}
+
+	base := slug.Make(name)
+	if base == "" {
+		base = defaultCategorySlug
+	}
+	candidate := base
+	for i := 2; ; i++ {
+		taken, err := s.categoryRepo.ExistsCategorySlug(ctx, candidate, exceptID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// position returns the requested position, or the slot after the last
+// sibling under parentID.
+func (s *CategoryServiceImpl) position(ctx context.Context, parentID *uuid.UUID, requested *int) (int, error) {
+	if requested != nil {
+		return *requested, nil
+	}
+	count, err := s.categoryRepo.CountCategoryChildren(ctx, parentID)
+	return int(count), err
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
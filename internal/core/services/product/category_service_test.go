@@ -0,0 +1,230 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (f *fakeCategoryRepo) ListAllCategories(ctx context.Context) ([]productDomain.Category, error) {
+	var categories []productDomain.Category
+	for _, category := range f.categories {
+		categories = append(categories, *category)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Position != categories[j].Position {
+			return categories[i].Position < categories[j].Position
+		}
+		return categories[i].Name < categories[j].Name
+	})
+	return categories, nil
+}
+
+func (f *fakeCategoryRepo) GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	return f.GetCategory(ctx, id)
+}
+
+func (f *fakeCategoryRepo) GetCategoryBySlug(ctx context.Context, slug string) (*productDomain.Category, error) {
+	for _, category := range f.categories {
+		if category.Slug == slug {
+			clone := *category
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCategoryRepo) ExistsCategorySlug(ctx context.Context, slug string, exceptID *uuid.UUID) (bool, error) {
+	for id, category := range f.categories {
+		if category.Slug == slug && (exceptID == nil || id != *exceptID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeCategoryRepo) CountCategoryChildren(ctx context.Context, parentID *uuid.UUID) (int64, error) {
+	var count int64
+	for _, category := range f.categories {
+		if sameParent(category.ParentID, parentID) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeCategoryRepo) ListCategorySubtreeIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{id}
+	for i := 0; i < len(ids); i++ {
+		for childID, category := range f.categories {
+			if category.ParentID != nil && *category.ParentID == ids[i] {
+				ids = append(ids, childID)
+			}
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeCategoryRepo) ListCategoryAncestors(ctx context.Context, id uuid.UUID) ([]productDomain.Category, error) {
+	var path []productDomain.Category
+	for current, ok := f.categories[id]; ok; {
+		path = append([]productDomain.Category{*current}, path...)
+		if current.ParentID == nil {
+			break
+		}
+		current, ok = f.categories[*current.ParentID]
+	}
+	return path, nil
+}
+
+func (f *fakeCategoryRepo) LockCategoryTree(ctx context.Context) error {
+	return nil
+}
+
+func (f *fakeCategoryRepo) CreateCategory(ctx context.Context, payload *productDomain.Category) error {
+	payload.ID = uuid.New()
+	return f.UpdateCategory(ctx, payload)
+}
+
+func (f *fakeCategoryRepo) UpdateCategory(ctx context.Context, payload *productDomain.Category) error {
+	clone := *payload
+	f.categories[payload.ID] = &clone
+	return nil
+}
+
+func (f *catalogFixture) createCategory(t *testing.T, name string, parentID *uuid.UUID) *productDomain.Category {
+	t.Helper()
+	category, err := f.category.CreateCategory(context.Background(), f.adminID, productDomain.CreateCategoryDomain{
+		UpsertCategoryDomain: productDomain.UpsertCategoryDomain{Name: name},
+		ParentID:             parentID,
+	})
+	if err != nil {
+		t.Fatalf("CreateCategory(%q): %v", name, err)
+	}
+	return category
+}
+
+func TestCreateCategorySlugsAndPositions(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+
+	electronics := f.createCategory(t, "Electronics", nil)
+	phones := f.createCategory(t, "Phones & Tablets", &electronics.ID)
+	tvs := f.createCategory(t, "TVs", &electronics.ID)
+	if electronics.Slug != "electronics" || phones.Slug != "phones-tablets" {
+		t.Fatalf("slugs = %q, %q", electronics.Slug, phones.Slug)
+	}
+	if phones.Position != 0 || tvs.Position != 1 {
+		t.Fatalf("positions = %d, %d; new categories go after their siblings", phones.Position, tvs.Position)
+	}
+	if again := f.createCategory(t, "Electronics", &tvs.ID); again.Slug != "electronics-2" {
+		t.Fatalf("slug = %q, want electronics-2", again.Slug)
+	}
+
+	explicit := productDomain.CreateCategoryDomain{UpsertCategoryDomain: productDomain.UpsertCategoryDomain{Name: "Audio", Slug: "tvs"}}
+	if _, err := f.category.CreateCategory(ctx, f.adminID, explicit); !errors.Is(err, ErrSlugTaken) {
+		t.Fatalf("got %v, want ErrSlugTaken", err)
+	}
+	explicit.Slug = "Audio Gear"
+	if _, err := f.category.CreateCategory(ctx, f.adminID, explicit); !errors.Is(err, ErrInvalidSlug) {
+		t.Fatalf("got %v, want ErrInvalidSlug", err)
+	}
+	missing := uuid.New()
+	explicit.Slug, explicit.ParentID = "", &missing
+	if _, err := f.category.CreateCategory(ctx, f.adminID, explicit); !errors.Is(err, ErrParentNotFound) {
+		t.Fatalf("got %v, want ErrParentNotFound", err)
+	}
+
+	// Renaming keeps the slug so existing links stay valid.
+	renamed, err := f.category.UpdateCategory(ctx, tvs.ID, productDomain.UpsertCategoryDomain{Name: "Televisions"})
+	if err != nil || renamed.Slug != "tvs" {
+		t.Fatalf("renamed = %+v, err = %v", renamed, err)
+	}
+}
+
+func TestCategoryTreeAndBreadcrumbs(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+	electronics := f.createCategory(t, "Electronics", nil)
+	phones := f.createCategory(t, "Phones", &electronics.ID)
+	android := f.createCategory(t, "Android", &phones.ID)
+
+	tree, err := f.category.GetCategoryTree(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetCategoryTree: %v", err)
+	}
+	// The fixture's "Shoes" category is a root as well.
+	var root *productDomain.Category
+	for i := range tree {
+		if tree[i].ID == electronics.ID {
+			root = &tree[i]
+		}
+	}
+	if len(tree) != 2 || root == nil || len(root.Children) != 1 || root.Children[0].Children[0].ID != android.ID {
+		t.Fatalf("tree = %+v", tree)
+	}
+
+	subtree, err := f.category.GetCategoryTree(ctx, &phones.ID)
+	if err != nil || len(subtree) != 1 || subtree[0].ID != phones.ID || len(subtree[0].Children) != 1 {
+		t.Fatalf("subtree = %+v, err = %v", subtree, err)
+	}
+
+	path, err := f.category.GetBreadcrumbs(ctx, android.ID)
+	if err != nil {
+		t.Fatalf("GetBreadcrumbs: %v", err)
+	}
+	if len(path) != 3 || path[0].ID != electronics.ID || path[2].ID != android.ID {
+		t.Fatalf("breadcrumbs = %+v", path)
+	}
+	if _, err := f.category.GetBreadcrumbs(ctx, uuid.New()); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("got %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func TestMoveCategory(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+	electronics := f.createCategory(t, "Electronics", nil)
+	phones := f.createCategory(t, "Phones", &electronics.ID)
+	android := f.createCategory(t, "Android", &phones.ID)
+	gadgets := f.createCategory(t, "Gadgets", nil)
+
+	for _, target := range []uuid.UUID{electronics.ID, phones.ID, android.ID} {
+		_, err := f.category.MoveCategory(ctx, electronics.ID, productDomain.MoveCategoryDomain{ParentID: &target})
+		if !errors.Is(err, ErrCategoryCycle) {
+			t.Fatalf("move below %v: got %v, want ErrCategoryCycle", target, err)
+		}
+	}
+
+	moved, err := f.category.MoveCategory(ctx, phones.ID, productDomain.MoveCategoryDomain{ParentID: &gadgets.ID})
+	if err != nil {
+		t.Fatalf("MoveCategory: %v", err)
+	}
+	if *moved.ParentID != gadgets.ID || moved.Position != 0 {
+		t.Fatalf("moved = %+v", moved)
+	}
+	path, _ := f.category.GetBreadcrumbs(ctx, android.ID)
+	if len(path) != 3 || path[0].ID != gadgets.ID {
+		t.Fatalf("the subtree should move along, breadcrumbs = %+v", path)
+	}
+
+	toRoot, err := f.category.MoveCategory(ctx, phones.ID, productDomain.MoveCategoryDomain{})
+	if err != nil || toRoot.ParentID != nil {
+		t.Fatalf("move to root = %+v, err = %v", toRoot, err)
+	}
+}
+
+func TestDeleteCategoryWithChildren(t *testing.T) {
+	f := newCatalogFixture()
+	electronics := f.createCategory(t, "Electronics", nil)
+	f.createCategory(t, "Phones", &electronics.ID)
+
+	if err := f.category.DeleteCategory(context.Background(), electronics.ID); !errors.Is(err, ErrCategoryHasChildren) {
+		t.Fatalf("got %v, want ErrCategoryHasChildren", err)
+	}
+}
@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFacetOptionName = errors.New("option facets need the option_name of a product option")
+	ErrFacetPriceBands = errors.New("price facets need price_bands in ascending order")
+	ErrDuplicateFacet  = errors.New("a category has at most one facet of each kind, and one per option")
+)
+
+type ProductFacetServiceImpl struct {
+	facetRepo    ports.IProductFacetRepository
+	categoryRepo ports.ICategoryRepository
+	transactor   transactors.IDatabaseTransactor
+}
+
+func NewProductFacetService(
+	facetRepo ports.IProductFacetRepository,
+	categoryRepo ports.ICategoryRepository,
+	transactor transactors.IDatabaseTransactor,
+) ports.IProductFacetService {
+	return &ProductFacetServiceImpl{
+		facetRepo:    facetRepo,
+		categoryRepo: categoryRepo,
+		transactor:   transactor,
+	}
+}
+
+// ListFacets implements ports.IProductFacetService.
+func (s *ProductFacetServiceImpl) ListFacets(ctx context.Context) ([]productDomain.Facet, error) {
+	filters := pagination.GetFilters[productDomain.ProductFilters](ctx).Filters
+	var current *productDomain.Category
+	if filters.InCategory != "" {
+		var err error
+		if current, err = s.findCategory(ctx, filters.InCategory); err != nil {
+			// The listing is empty as well; there is nothing to count.
+			if errors.Is(err, ErrCategoryNotFound) {
+				return []productDomain.Facet{}, nil
+			}
+			return nil, err
+		}
+	}
+	var currentID *uuid.UUID
+	if current != nil {
+		currentID = &current.ID
+	}
+	definitions, _, err := s.effectiveFacets(ctx, currentID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.facetRepo.CountProductFacets(ctx, definitions)
+	if err != nil {
+		return nil, err
+	}
+	byFacet := make(map[int][]productDomain.FacetCount)
+	for _, count := range counts {
+		byFacet[count.Facet] = append(byFacet[count.Facet], count)
+	}
+
+	facets := make([]productDomain.Facet, 0, len(definitions))
+	for i, definition := range definitions {
+		facet := productDomain.Facet{Kind: definition.Kind, Label: definition.Label}
+		switch definition.Kind {
+		case productDomain.FacetKindCategory:
+			facet.Key = "in_category"
+			if facet.Buckets, err = s.categoryBuckets(ctx, currentID, byFacet[i]); err != nil {
+				return nil, err
+			}
+		case productDomain.FacetKindPrice:
+			facet.Key = "price"
+			facet.Buckets = priceBuckets(definition.Bands(), byFacet[i], filters.PriceBand)
+		case productDomain.FacetKindRating:
+			facet.Key = "min_rating"
+			facet.Buckets = ratingBuckets(byFacet[i], filters.MinRating)
+		case productDomain.FacetKindOption:
+			name := strings.ToLower(definition.OptionName)
+			facet.Key = "option." + name
+			facet.Buckets = optionBuckets(byFacet[i], filters.Options[name])
+		}
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+// GetCategoryFacets implements ports.IProductFacetService.
+func (s *ProductFacetServiceImpl) GetCategoryFacets(ctx context.Context, categoryID uuid.UUID) (*productDomain.CategoryFacetsDomain, error) {
+	if _, err := s.categoryRepo.GetCategory(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	facets, definedBy, err := s.effectiveFacets(ctx, &categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return &productDomain.CategoryFacetsDomain{Facets: facets, DefinedBy: definedBy}, nil
+}
+
+// SetCategoryFacets implements ports.IProductFacetService.
+func (s *ProductFacetServiceImpl) SetCategoryFacets(ctx context.Context, categoryID uuid.UUID, payload productDomain.SetCategoryFacetsDomain) (*productDomain.CategoryFacetsDomain, error) {
+	facets, err := buildCategoryFacets(categoryID, payload.Facets)
+	if err != nil {
+		return nil, err
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.categoryRepo.GetCategoryForUpdate(ctx, categoryID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCategoryNotFound
+			}
+			return err
+		}
+		return s.facetRepo.ReplaceCategoryFacets(ctx, categoryID, facets)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCategoryFacets(ctx, categoryID)
+}
+
+// buildCategoryFacets validates the payload and numbers the facets in order.
+func buildCategoryFacets(categoryID uuid.UUID, payload []productDomain.UpsertCategoryFacetDomain) ([]productDomain.CategoryFacet, error) {
+	facets := make([]productDomain.CategoryFacet, 0, len(payload))
+	seen := make(map[string]bool)
+	for i, item := range payload {
+		facet := productDomain.CategoryFacet{
+			CategoryID: categoryID,
+			Kind:       item.Kind,
+			Label:      strings.TrimSpace(item.Label),
+			Position:   i,
+		}
+		key := item.Kind
+		switch item.Kind {
+		case productDomain.FacetKindOption:
+			facet.OptionName = strings.TrimSpace(item.OptionName)
+			if facet.OptionName == "" {
+				return nil, ErrFacetOptionName
+			}
+			key += ":" + strings.ToLower(facet.OptionName)
+		case productDomain.FacetKindPrice:
+			if len(item.PriceBands) == 0 {
+				return nil, ErrFacetPriceBands
+			}
+			bands := make([]string, len(item.PriceBands))
+			for j, limit := range item.PriceBands {
+				if j > 0 && limit <= item.PriceBands[j-1] {
+					return nil, ErrFacetPriceBands
+				}
+				bands[j] = formatPrice(limit)
+			}
+			facet.PriceBands = strings.Join(bands, ",")
+		}
+		if seen[key] {
+			return nil, ErrDuplicateFacet
+		}
+		seen[key] = true
+		facets = append(facets, facet)
+	}
+	return facets, nil
+}
+
+// effectiveFacets returns the facets defined on the category or its nearest
+// ancestor that has some, with the ID of that category. Without a category,
+// or when no category on the path defines facets, it returns the defaults
+// and a nil ID.
+func (s *ProductFacetServiceImpl) effectiveFacets(ctx context.Context, categoryID *uuid.UUID) ([]productDomain.CategoryFacet, *uuid.UUID, error) {
+	if categoryID == nil {
+		return productDomain.DefaultCategoryFacets, nil, nil
+	}
+	path, err := s.categoryRepo.ListCategoryAncestors(ctx, *categoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	ids := make([]uuid.UUID, len(path))
+	for i, category := range path {
+		ids[i] = category.ID
+	}
+	facets, err := s.facetRepo.ListCategoryFacets(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byCategory := make(map[uuid.UUID][]productDomain.CategoryFacet)
+	for _, facet := range facets {
+		byCategory[facet.CategoryID] = append(byCategory[facet.CategoryID], facet)
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if defined := byCategory[path[i].ID]; len(defined) > 0 {
+			id := path[i].ID
+			return defined, &id, nil
+		}
+	}
+	return productDomain.DefaultCategoryFacets, nil, nil
+}
+
+// findCategory resolves an in_category filter, a category ID or slug.
+func (s *ProductFacetServiceImpl) findCategory(ctx context.Context, idOrSlug string) (*productDomain.Category, error) {
+	var category *productDomain.Category
+	var err error
+	if id, parseErr := uuid.Parse(idOrSlug); parseErr == nil {
+		category, err = s.categoryRepo.GetCategory(ctx, id)
+	} else {
+		category, err = s.categoryRepo.GetCategoryBySlug(ctx, idOrSlug)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// categoryBuckets rolls the product counts per category up into the children
+// of the listed category, or the root categories when none is listed.
+func (s *ProductFacetServiceImpl) categoryBuckets(ctx context.Context, currentID *uuid.UUID, counts []productDomain.FacetCount) ([]productDomain.FacetBucket, error) {
+	buckets := []productDomain.FacetBucket{}
+	if len(counts) == 0 {
+		return buckets, nil
+	}
+	categories, err := s.categoryRepo.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[uuid.UUID]productDomain.Category, len(categories))
+	for _, category := range categories {
+		index[category.ID] = category
+	}
+
+	totals := make(map[uuid.UUID]int64)
+	for _, count := range counts {
+		id, err := uuid.Parse(count.Bucket)
+		if err != nil {
+			continue
+		}
+		// Walk up to the category right below the listed one. The depth
+		// bound guards against a cycle.
+		for depth := 0; depth < len(categories); depth++ {
+			category, ok := index[id]
+			if !ok {
+				break
+			}
+			if sameParent(category.ParentID, currentID) {
+				totals[id] += count.Count
+				break
+			}
+			if category.ParentID == nil {
+				break
+			}
+			id = *category.ParentID
+		}
+	}
+
+	// ListAllCategories is in display order already.
+	for _, category := range categories {
+		if total := totals[category.ID]; total > 0 && sameParent(category.ParentID, currentID) {
+			buckets = append(buckets, productDomain.FacetBucket{Value: category.Slug, Label: category.Name, Count: total})
+		}
+	}
+	return buckets, nil
+}
+
+// priceBuckets labels the price bands counted by the repository, lowest
+// first.
+func priceBuckets(bands []float64, counts []productDomain.FacetCount, selected string) []productDomain.FacetBucket {
+	byBand := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		byBand[count.Bucket] = count.Count
+	}
+	buckets := []productDomain.FacetBucket{}
+	for i := 0; i <= len(bands); i++ {
+		bucket := productDomain.FacetBucket{Count: byBand[strconv.Itoa(i)]}
+		switch {
+		case len(bands) == 0:
+			continue
+		case i == 0:
+			bucket.Max = &bands[0]
+			bucket.Value = "-" + formatPrice(bands[0])
+			bucket.Label = "Under " + formatPrice(bands[0])
+		case i == len(bands):
+			bucket.Min = &bands[i-1]
+			bucket.Value = formatPrice(bands[i-1]) + "-"
+			bucket.Label = formatPrice(bands[i-1]) + " and above"
+		default:
+			bucket.Min, bucket.Max = &bands[i-1], &bands[i]
+			bucket.Value = formatPrice(bands[i-1]) + "-" + formatPrice(bands[i])
+			bucket.Label = formatPrice(bands[i-1]) + " - " + formatPrice(bands[i])
+		}
+		bucket.Selected = bucket.Value == selected
+		if bucket.Count > 0 || bucket.Selected {
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// ratingBuckets turns the count of products per whole star into cumulative
+// "N & up" buckets.
+func ratingBuckets(counts []productDomain.FacetCount, selected *int) []productDomain.FacetBucket {
+	byFloor := make(map[int]int64, len(counts))
+	for _, count := range counts {
+		if floor, err := strconv.Atoi(count.Bucket); err == nil {
+			byFloor[floor] += count.Count
+		}
+	}
+	buckets := []productDomain.FacetBucket{}
+	// A perfect 5 counts towards "4 & up"; there is no "5 & up" bucket.
+	total := byFloor[5]
+	for floor := 4; floor >= 1; floor-- {
+		total += byFloor[floor]
+		bucket := productDomain.FacetBucket{
+			Value:    strconv.Itoa(floor),
+			Label:    strconv.Itoa(floor) + " & up",
+			Count:    total,
+			Selected: selected != nil && *selected == floor,
+		}
+		if bucket.Count > 0 || bucket.Selected {
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// optionBuckets lists the values of an option, most common first.
+func optionBuckets(counts []productDomain.FacetCount, selected []string) []productDomain.FacetBucket {
+	buckets := make([]productDomain.FacetBucket, 0, len(counts))
+	for _, count := range counts {
+		bucket := productDomain.FacetBucket{Value: count.Bucket, Label: count.Label, Count: count.Count}
+		for _, value := range selected {
+			if strings.EqualFold(value, count.Bucket) {
+				bucket.Selected = true
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Label < buckets[j].Label
+	})
+	return buckets
+}
+
+func formatPrice(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
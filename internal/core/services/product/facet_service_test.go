@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+)
+
+type fakeFacetRepo struct {
+	facets  map[uuid.UUID][]productDomain.CategoryFacet
+	counts  []productDomain.FacetCount
+	counted []productDomain.CategoryFacet
+}
+
+func (f *fakeFacetRepo) CountProductFacets(ctx context.Context, facets []productDomain.CategoryFacet) ([]productDomain.FacetCount, error) {
+	f.counted = facets
+	return f.counts, nil
+}
+
+func (f *fakeFacetRepo) ListCategoryFacets(ctx context.Context, categoryIDs []uuid.UUID) ([]productDomain.CategoryFacet, error) {
+	var facets []productDomain.CategoryFacet
+	for _, id := range categoryIDs {
+		facets = append(facets, f.facets[id]...)
+	}
+	return facets, nil
+}
+
+func (f *fakeFacetRepo) ReplaceCategoryFacets(ctx context.Context, categoryID uuid.UUID, facets []productDomain.CategoryFacet) error {
+	f.facets[categoryID] = facets
+	return nil
+}
+
+func newFacetFixture() (*catalogFixture, *fakeFacetRepo, *ProductFacetServiceImpl) {
+	f := newCatalogFixture()
+	repo := &fakeFacetRepo{facets: map[uuid.UUID][]productDomain.CategoryFacet{}}
+	service := NewProductFacetService(repo, f.categories, transactorstest.Transactor{}).(*ProductFacetServiceImpl)
+	return f, repo, service
+}
+
+func facetsContext(filters productDomain.ProductFilters) context.Context {
+	return pagination.SetFilters(context.Background(), pagination.PaginationParams[productDomain.ProductFilters]{Filters: filters})
+}
+
+func TestCategoryFacetInheritance(t *testing.T) {
+	f, _, service := newFacetFixture()
+	ctx := context.Background()
+	clothing := f.createCategory(t, "Clothing", nil)
+	shirts := f.createCategory(t, "Shirts", &clothing.ID)
+
+	got, err := service.GetCategoryFacets(ctx, shirts.ID)
+	if err != nil {
+		t.Fatalf("GetCategoryFacets: %v", err)
+	}
+	if got.DefinedBy != nil || len(got.Facets) != len(productDomain.DefaultCategoryFacets) {
+		t.Fatalf("without definitions got %+v, want the defaults", got)
+	}
+
+	size := productDomain.SetCategoryFacetsDomain{Facets: []productDomain.UpsertCategoryFacetDomain{
+		{Kind: productDomain.FacetKindOption, Label: "Size", OptionName: " Size "},
+		{Kind: productDomain.FacetKindPrice, Label: "Price", PriceBands: []float64{20, 49.5}},
+	}}
+	if _, err := service.SetCategoryFacets(ctx, clothing.ID, size); err != nil {
+		t.Fatalf("SetCategoryFacets: %v", err)
+	}
+	got, err = service.GetCategoryFacets(ctx, shirts.ID)
+	if err != nil {
+		t.Fatalf("GetCategoryFacets: %v", err)
+	}
+	if got.DefinedBy == nil || *got.DefinedBy != clothing.ID || len(got.Facets) != 2 {
+		t.Fatalf("got %+v, want the facets of Clothing", got)
+	}
+	if got.Facets[0].OptionName != "Size" || got.Facets[1].PriceBands != "20,49.5" || got.Facets[1].Position != 1 {
+		t.Fatalf("facets = %+v", got.Facets)
+	}
+
+	color := productDomain.SetCategoryFacetsDomain{Facets: []productDomain.UpsertCategoryFacetDomain{
+		{Kind: productDomain.FacetKindOption, Label: "Color", OptionName: "Color"},
+	}}
+	if got, err = service.SetCategoryFacets(ctx, shirts.ID, color); err != nil {
+		t.Fatalf("SetCategoryFacets: %v", err)
+	}
+	if *got.DefinedBy != shirts.ID || got.Facets[0].Label != "Color" {
+		t.Fatalf("got %+v, want the facets of Shirts", got)
+	}
+
+	// An empty list inherits again.
+	if got, err = service.SetCategoryFacets(ctx, shirts.ID, productDomain.SetCategoryFacetsDomain{}); err != nil {
+		t.Fatalf("SetCategoryFacets: %v", err)
+	}
+	if *got.DefinedBy != clothing.ID {
+		t.Fatalf("got %+v, want the facets of Clothing", got)
+	}
+}
+
+func TestSetCategoryFacetsRefusals(t *testing.T) {
+	f, _, service := newFacetFixture()
+	ctx := context.Background()
+	category := f.createCategory(t, "Clothing", nil)
+
+	cases := []struct {
+		facets []productDomain.UpsertCategoryFacetDomain
+		want   error
+	}{
+		{[]productDomain.UpsertCategoryFacetDomain{{Kind: productDomain.FacetKindOption, Label: "Color"}}, ErrFacetOptionName},
+		{[]productDomain.UpsertCategoryFacetDomain{{Kind: productDomain.FacetKindPrice, Label: "Price"}}, ErrFacetPriceBands},
+		{[]productDomain.UpsertCategoryFacetDomain{{Kind: productDomain.FacetKindPrice, Label: "Price", PriceBands: []float64{50, 50}}}, ErrFacetPriceBands},
+		{[]productDomain.UpsertCategoryFacetDomain{
+			{Kind: productDomain.FacetKindRating, Label: "Rating"},
+			{Kind: productDomain.FacetKindRating, Label: "Stars"},
+		}, ErrDuplicateFacet},
+		{[]productDomain.UpsertCategoryFacetDomain{
+			{Kind: productDomain.FacetKindOption, Label: "Color", OptionName: "color"},
+			{Kind: productDomain.FacetKindOption, Label: "Colour", OptionName: "COLOR"},
+		}, ErrDuplicateFacet},
+	}
+	for _, tc := range cases {
+		if _, err := service.SetCategoryFacets(ctx, category.ID, productDomain.SetCategoryFacetsDomain{Facets: tc.facets}); !errors.Is(err, tc.want) {
+			t.Fatalf("facets %+v: got %v, want %v", tc.facets, err, tc.want)
+		}
+	}
+	if _, err := service.SetCategoryFacets(ctx, uuid.New(), productDomain.SetCategoryFacetsDomain{}); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("got %v, want ErrCategoryNotFound", err)
+	}
+}
+
+func TestListFacetsBuckets(t *testing.T) {
+	f, repo, service := newFacetFixture()
+	clothing := f.createCategory(t, "Clothing", nil)
+	shirts := f.createCategory(t, "Shirts", &clothing.ID)
+	pants := f.createCategory(t, "Pants", &clothing.ID)
+	jeans := f.createCategory(t, "Jeans", &pants.ID)
+	f.createCategory(t, "Shorts", &clothing.ID)
+
+	repo.counts = []productDomain.FacetCount{
+		{Facet: 0, Bucket: shirts.ID.String(), Count: 3},
+		{Facet: 0, Bucket: pants.ID.String(), Count: 1},
+		{Facet: 0, Bucket: jeans.ID.String(), Count: 2},
+		{Facet: 1, Bucket: "0", Count: 1},
+		{Facet: 1, Bucket: "5", Count: 4},
+		{Facet: 2, Bucket: "5", Count: 1},
+		{Facet: 2, Bucket: "4", Count: 2},
+		{Facet: 2, Bucket: "2", Count: 1},
+	}
+	minRating := 2
+	facets, err := service.ListFacets(facetsContext(productDomain.ProductFilters{
+		InCategory: clothing.Slug,
+		PriceBand:  "500-",
+		MinRating:  &minRating,
+	}))
+	if err != nil {
+		t.Fatalf("ListFacets: %v", err)
+	}
+	if len(repo.counted) != len(productDomain.DefaultCategoryFacets) || len(facets) != 3 {
+		t.Fatalf("facets = %+v", facets)
+	}
+
+	categories := facets[0].Buckets
+	if facets[0].Key != "in_category" || len(categories) != 2 {
+		t.Fatalf("category buckets = %+v", categories)
+	}
+	// Jeans count towards Pants; Shorts has no products.
+	if categories[0].Value != "shirts" || categories[0].Count != 3 || categories[1].Value != "pants" || categories[1].Count != 3 {
+		t.Fatalf("category buckets = %+v", categories)
+	}
+
+	prices := facets[1].Buckets
+	if len(prices) != 2 || prices[0].Value != "-25" || prices[0].Label != "Under 25" || *prices[0].Max != 25 || prices[0].Min != nil {
+		t.Fatalf("price buckets = %+v", prices)
+	}
+	if prices[1].Value != "500-" || prices[1].Count != 4 || !prices[1].Selected || prices[0].Selected {
+		t.Fatalf("price buckets = %+v", prices)
+	}
+
+	ratings := facets[2].Buckets
+	want := []struct {
+		value string
+		count int64
+	}{{"4", 3}, {"3", 3}, {"2", 4}, {"1", 4}}
+	if len(ratings) != len(want) {
+		t.Fatalf("rating buckets = %+v", ratings)
+	}
+	for i, bucket := range ratings {
+		if bucket.Value != want[i].value || bucket.Count != want[i].count || bucket.Selected != (bucket.Value == "2") {
+			t.Fatalf("rating buckets = %+v", ratings)
+		}
+	}
+}
+
+func TestListFacetsOptions(t *testing.T) {
+	f, repo, service := newFacetFixture()
+	ctx := context.Background()
+	shirts := f.createCategory(t, "Shirts", nil)
+	color := productDomain.SetCategoryFacetsDomain{Facets: []productDomain.UpsertCategoryFacetDomain{
+		{Kind: productDomain.FacetKindOption, Label: "Color", OptionName: "Color"},
+	}}
+	if _, err := service.SetCategoryFacets(ctx, shirts.ID, color); err != nil {
+		t.Fatalf("SetCategoryFacets: %v", err)
+	}
+	repo.counts = []productDomain.FacetCount{
+		{Facet: 0, Bucket: "blue", Label: "Blue", Count: 4},
+		{Facet: 0, Bucket: "red", Label: "Red", Count: 12},
+		{Facet: 0, Bucket: "black", Label: "Black", Count: 4},
+	}
+
+	facets, err := service.ListFacets(facetsContext(productDomain.ProductFilters{
+		InCategory: shirts.ID.String(),
+		Options:    map[string][]string{"color": {"Blue"}},
+	}))
+	if err != nil {
+		t.Fatalf("ListFacets: %v", err)
+	}
+	if len(facets) != 1 || facets[0].Key != "option.color" {
+		t.Fatalf("facets = %+v", facets)
+	}
+	buckets := facets[0].Buckets
+	if len(buckets) != 3 || buckets[0].Label != "Red" || buckets[1].Label != "Black" || buckets[2].Label != "Blue" {
+		t.Fatalf("buckets = %+v, want most common first", buckets)
+	}
+	if !buckets[2].Selected || buckets[0].Selected {
+		t.Fatalf("buckets = %+v, want only blue selected", buckets)
+	}
+
+	// An unknown category lists nothing, so it has no facets either.
+	facets, err = service.ListFacets(facetsContext(productDomain.ProductFilters{InCategory: "no-such-category"}))
+	if err != nil || len(facets) != 0 {
+		t.Fatalf("got %+v, %v, want no facets", facets, err)
+	}
+}
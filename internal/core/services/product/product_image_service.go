@@ -0,0 +1,388 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	storagePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/imaging"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImageNotFound      = errors.New("product image not found")
+	ErrInvalidImage       = errors.New("image must be a PNG, JPEG, GIF or WebP file")
+	ErrImageTypeMismatch  = errors.New("image content does not match its declared type")
+	ErrImageTooLarge      = errors.New("image is too large")
+	ErrImageOrderMismatch = errors.New("the new order must list every image of the product exactly once")
+)
+
+// ImageSize names a rendition and the longest side it is scaled down to.
+type ImageSize struct {
+	Name    string
+	MaxSide int
+}
+
+// ProductImageServiceConfig holds the tunables of ProductImageServiceImpl.
+type ProductImageServiceConfig struct {
+	MaxBytes     int         // Size of an uploaded file
+	MaxPixels    int         // Width × height of an uploaded image, checked before decoding
+	MaxDimension int         // Longest side of the stored image; larger uploads are scaled down
+	JPEGQuality  int         // Quality of re-encoded JPEGs
+	Thumbnails   []ImageSize // Scaled copies stored next to the image
+}
+
+var DefaultProductImageServiceConfig = ProductImageServiceConfig{
+	MaxBytes:     10 << 20,
+	MaxPixels:    50_000_000,
+	MaxDimension: 2048,
+	JPEGQuality:  85,
+	Thumbnails: []ImageSize{
+		{Name: "thumb", MaxSide: 200},
+		{Name: "medium", MaxSide: 800},
+	},
+}
+
+// fullRenditionName names the WebP copy of the stored image at full size.
+const fullRenditionName = "full"
+
+type ProductImageServiceImpl struct {
+	productRepo ports.IProductRepository
+	roleService rolePorts.IRoleService
+	storage     storagePorts.IFileStorage
+	transactor  transactors.IDatabaseTransactor
+	config      ProductImageServiceConfig
+}
+
+func NewProductImageService(
+	productRepo ports.IProductRepository,
+	roleService rolePorts.IRoleService,
+	storage storagePorts.IFileStorage,
+	transactor transactors.IDatabaseTransactor,
+	config ProductImageServiceConfig,
+) ports.IProductImageService {
+	return &ProductImageServiceImpl{
+		productRepo: productRepo,
+		roleService: roleService,
+		storage:     storage,
+		transactor:  transactor,
+		config:      config,
+	}
+}
+
+// ListImages implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) ListImages(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductImage, error) {
+	if _, err := s.productRepo.GetProduct(ctx, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	return s.productRepo.ListProductImages(ctx, productID)
+}
+
+// AddImage implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) AddImage(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.AddProductImageDomain) (*productDomain.ProductImage, error) {
+	image := &productDomain.ProductImage{
+		ProductID: productID,
+		ImageURL:  payload.ImageURL,
+		IsPrimary: payload.IsPrimary,
+	}
+	if err := s.appendImage(ctx, actorID, image); err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// UploadImage implements ports.IProductImageService.
+//
+// The files are stored before the image row is created and deleted again if
+// that fails, so a row never points at a missing file.
+func (s *ProductImageServiceImpl) UploadImage(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UploadProductImageDomain) (*productDomain.ProductImage, error) {
+	// Decoding is the expensive part; refuse strangers before doing it.
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload.Data) > s.config.MaxBytes {
+		return nil, fmt.Errorf("%w, the limit is %d MiB", ErrImageTooLarge, s.config.MaxBytes>>20)
+	}
+	decoded, format, err := imaging.Decode(payload.Data, s.config.MaxPixels)
+	switch {
+	case errors.Is(err, imaging.ErrTooManyPixels):
+		return nil, fmt.Errorf("%w, the limit is %d megapixels", ErrImageTooLarge, s.config.MaxPixels/1_000_000)
+	case err != nil:
+		return nil, ErrInvalidImage
+	}
+	if declared := declaredImageType(payload.ContentType); declared != "" && declared != format.ContentType() {
+		return nil, ErrImageTypeMismatch
+	}
+
+	image := &productDomain.ProductImage{ProductID: productID, IsPrimary: payload.IsPrimary}
+	if err := s.storeImage(ctx, image, decoded, format); err != nil {
+		return nil, err
+	}
+	if err := s.appendImage(ctx, actorID, image); err != nil {
+		s.deleteFiles(ctx, image)
+		return nil, err
+	}
+	return image, nil
+}
+
+// UploadImageBase64 implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) UploadImageBase64(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UploadProductImageBase64Domain) (*productDomain.ProductImage, error) {
+	imageType, err := utils.ExtractImageTypeFromBase64(payload.Image)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	encoded := payload.Image[strings.Index(payload.Image, ";base64,")+len(";base64,"):]
+	if base64.StdEncoding.DecodedLen(len(encoded)) > s.config.MaxBytes+2 {
+		return nil, fmt.Errorf("%w, the limit is %d MiB", ErrImageTooLarge, s.config.MaxBytes>>20)
+	}
+	data, err := utils.DecodeBase64(encoded)
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	return s.UploadImage(ctx, actorID, productID, productDomain.UploadProductImageDomain{
+		Data:        data,
+		ContentType: "image/" + imageType,
+		IsPrimary:   payload.IsPrimary,
+	})
+}
+
+// SetPrimaryImage implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) SetPrimaryImage(ctx context.Context, actorID, productID, imageID uuid.UUID) (*productDomain.ProductImage, error) {
+	var image *productDomain.ProductImage
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		var err error
+		if image, err = s.getImage(ctx, productID, imageID); err != nil {
+			return err
+		}
+		if image.IsPrimary {
+			return nil
+		}
+		if err := s.productRepo.UnsetPrimaryProductImage(ctx, productID); err != nil {
+			return err
+		}
+		image.IsPrimary = true
+		return s.productRepo.UpdateProductImage(ctx, image)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return image, nil
+}
+
+// ReorderImages implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) ReorderImages(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.ReorderProductImagesDomain) ([]productDomain.ProductImage, error) {
+	var images []productDomain.ProductImage
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		current, err := s.productRepo.ListProductImages(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(current) != len(payload.ImageIDs) {
+			return ErrImageOrderMismatch
+		}
+		listed := make(map[uuid.UUID]bool, len(payload.ImageIDs))
+		for _, id := range payload.ImageIDs {
+			listed[id] = true
+		}
+		for _, image := range current {
+			if !listed[image.ID] {
+				return ErrImageOrderMismatch
+			}
+		}
+		if err := s.productRepo.SetProductImagePositions(ctx, productID, payload.ImageIDs); err != nil {
+			return err
+		}
+		images, err = s.productRepo.ListProductImages(ctx, productID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// DeleteImage implements ports.IProductImageService.
+func (s *ProductImageServiceImpl) DeleteImage(ctx context.Context, actorID, productID, imageID uuid.UUID) error {
+	var image *productDomain.ProductImage
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		var err error
+		if image, err = s.getImage(ctx, productID, imageID); err != nil {
+			return err
+		}
+		if err := s.productRepo.DeleteProductImage(ctx, productID, imageID); err != nil {
+			return err
+		}
+		if !image.IsPrimary {
+			return nil
+		}
+		remaining, err := s.productRepo.ListProductImages(ctx, productID)
+		if err != nil || len(remaining) == 0 {
+			return err
+		}
+		next := remaining[0]
+		next.IsPrimary = true
+		return s.productRepo.UpdateProductImage(ctx, &next)
+	})
+	if err != nil {
+		return err
+	}
+	s.deleteFiles(ctx, image)
+	return nil
+}
+
+// appendImage adds the image after the product's other images. The product
+// row lock serialises changes to the order and the primary image.
+func (s *ProductImageServiceImpl) appendImage(ctx context.Context, actorID uuid.UUID, image *productDomain.ProductImage) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, image.ProductID); err != nil {
+			return err
+		}
+		images, err := s.productRepo.ListProductImages(ctx, image.ProductID)
+		if err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			image.IsPrimary = true
+		} else {
+			image.Position = images[len(images)-1].Position + 1
+		}
+		if image.IsPrimary {
+			if err := s.productRepo.UnsetPrimaryProductImage(ctx, image.ProductID); err != nil {
+				return err
+			}
+		}
+		return s.productRepo.CreateProductImage(ctx, image)
+	})
+}
+
+// storeImage re-encodes the image, which drops EXIF and any other metadata,
+// and stores it with its renditions. JPEGs stay JPEG; other formats become
+// PNG so transparency survives. Every size also gets a WebP copy.
+func (s *ProductImageServiceImpl) storeImage(ctx context.Context, image *productDomain.ProductImage, decoded image.Image, source imaging.Format) error {
+	format := imaging.PNG
+	if source == imaging.JPEG {
+		format = imaging.JPEG
+	}
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+	prefix := fmt.Sprintf("products/%s/%s", image.ProductID, hex.EncodeToString(token))
+
+	full := imaging.Fit(decoded, s.config.MaxDimension)
+	key := fmt.Sprintf("%s/original.%s", prefix, format.Extension())
+	url, err := s.putImage(ctx, key, full, format)
+	if err != nil {
+		return err
+	}
+	bounds := full.Bounds()
+	image.ImageURL = url
+	image.StorageKey = key
+	image.ContentType = format.ContentType()
+	image.Width, image.Height = bounds.Dx(), bounds.Dy()
+
+	sizes := append([]ImageSize{{Name: fullRenditionName}}, s.config.Thumbnails...)
+	for _, size := range sizes {
+		scaled := full
+		formats := []imaging.Format{imaging.WebP}
+		if size.Name != fullRenditionName {
+			scaled = imaging.Fit(full, size.MaxSide)
+			formats = []imaging.Format{format, imaging.WebP}
+		}
+		bounds := scaled.Bounds()
+		for _, f := range formats {
+			key := fmt.Sprintf("%s/%s.%s", prefix, size.Name, f.Extension())
+			url, err := s.putImage(ctx, key, scaled, f)
+			if err != nil {
+				s.deleteFiles(ctx, image)
+				return err
+			}
+			image.Renditions = append(image.Renditions, productDomain.ProductImageRendition{
+				Name:        size.Name,
+				Format:      string(f),
+				ContentType: f.ContentType(),
+				ImageURL:    url,
+				StorageKey:  key,
+				Width:       bounds.Dx(),
+				Height:      bounds.Dy(),
+			})
+		}
+	}
+	return nil
+}
+
+func (s *ProductImageServiceImpl) putImage(ctx context.Context, key string, img image.Image, format imaging.Format) (string, error) {
+	data, err := imaging.Encode(img, format, s.config.JPEGQuality)
+	if err != nil {
+		return "", err
+	}
+	return s.storage.Put(ctx, key, format.ContentType(), data)
+}
+
+// deleteFiles removes the stored files of an uploaded image. Failures are
+// only logged: the image row is already gone or was never created.
+func (s *ProductImageServiceImpl) deleteFiles(ctx context.Context, image *productDomain.ProductImage) {
+	keys := []string{image.StorageKey}
+	for _, rendition := range image.Renditions {
+		keys = append(keys, rendition.StorageKey)
+	}
+	for _, key := range keys {
+		if key == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, key); err != nil {
+			log.Printf("product images: delete stored file %s: %v", key, err)
+		}
+	}
+}
+
+func (s *ProductImageServiceImpl) getImage(ctx context.Context, productID, imageID uuid.UUID) (*productDomain.ProductImage, error) {
+	image, err := s.productRepo.GetProductImage(ctx, productID, imageID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImageNotFound
+	}
+	return image, err
+}
+
+// declaredImageType normalises the content type a client declared. Generic
+// types declare nothing and are left to sniffing.
+func declaredImageType(contentType string) string {
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	switch contentType {
+	case "", "application/octet-stream":
+		return ""
+	case "image/jpg", "image/pjpeg":
+		return "image/jpeg"
+	}
+	return contentType
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/storage"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/google/uuid"
+)
+
+type imageFixture struct {
+	*catalogFixture
+	images  ports.IProductImageService
+	dir     string
+	product *productDomain.Product
+}
+
+func newImageFixture(t *testing.T) *imageFixture {
+	catalog := newCatalogFixture()
+	f := &imageFixture{catalogFixture: catalog, dir: t.TempDir()}
+	roles := &fakeRoleService{managers: map[uuid.UUID]bool{catalog.adminID: true}}
+	config := DefaultProductImageServiceConfig
+	config.MaxBytes = 64 << 10
+	config.MaxDimension = 300
+	f.images = NewProductImageService(catalog.products, roles, storage.NewLocalStorage(f.dir, "/uploads"), transactorstest.Transactor{}, config)
+	f.product = catalog.createProduct(t, "RUN-01")
+	return f
+}
+
+// stored reads an uploaded file back from its URL.
+func (f *imageFixture) stored(t *testing.T, url string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(f.dir, strings.TrimPrefix(url, "/uploads/")))
+	if err != nil {
+		t.Fatalf("stored file %s: %v", url, err)
+	}
+	return data
+}
+
+// photo encodes a w×h JPEG carrying an EXIF segment, as cameras write them.
+func photo(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	exif := append([]byte{0xFF, 0xE1, 0x00, 0x1A}, []byte("Exif\x00\x00MM\x00\x2A\x00\x00\x00\x08GPS-SECRET")...)
+	return append(append([]byte{0xFF, 0xD8}, exif...), buf.Bytes()[2:]...)
+}
+
+func TestUploadImage(t *testing.T) {
+	f := newImageFixture(t)
+	ctx := context.Background()
+
+	image, err := f.images.UploadImage(ctx, f.sellerID, f.product.ID, productDomain.UploadProductImageDomain{
+		Data: photo(t, 600, 400), ContentType: "image/jpg",
+	})
+	if err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	if !image.IsPrimary || image.ContentType != "image/jpeg" || image.Width != 300 || image.Height != 200 {
+		t.Fatalf("image = %+v", image)
+	}
+	if original := f.stored(t, image.ImageURL); bytes.Contains(original, []byte("Exif")) || bytes.Contains(original, []byte("GPS-SECRET")) {
+		t.Fatal("the stored image still carries its EXIF segment")
+	}
+
+	// full.webp, thumb.jpg, thumb.webp, medium.jpg, medium.webp
+	got := map[string]productDomain.ProductImageRendition{}
+	for _, rendition := range image.Renditions {
+		got[rendition.Name+"."+rendition.Format] = rendition
+		f.stored(t, rendition.ImageURL)
+	}
+	if len(got) != 5 || got["thumb.jpeg"].Width != 200 || got["thumb.webp"].Height != 133 || got["full.webp"].Width != 300 || got["medium.jpeg"].Width != 300 {
+		t.Fatalf("renditions = %+v", got)
+	}
+
+	if err := f.images.DeleteImage(ctx, f.sellerID, f.product.ID, image.ID); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(f.dir, image.StorageKey)); !os.IsNotExist(err) {
+		t.Fatalf("the stored image survived its deletion: %v", err)
+	}
+}
+
+func TestUploadImageBase64(t *testing.T) {
+	f := newImageFixture(t)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 40, 20))); err != nil {
+		t.Fatal(err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	image, err := f.images.UploadImageBase64(context.Background(), f.sellerID, f.product.ID, productDomain.UploadProductImageBase64Domain{
+		Image: "data:image/png;base64," + encoded,
+	})
+	if err != nil {
+		t.Fatalf("UploadImageBase64: %v", err)
+	}
+	if image.ContentType != "image/png" || !strings.HasSuffix(image.ImageURL, ".png") || image.Width != 40 {
+		t.Fatalf("image = %+v", image)
+	}
+
+	_, err = f.images.UploadImageBase64(context.Background(), f.sellerID, f.product.ID, productDomain.UploadProductImageBase64Domain{
+		Image: "data:image/jpeg;base64," + encoded,
+	})
+	if !errors.Is(err, ErrImageTypeMismatch) {
+		t.Fatalf("got %v, want ErrImageTypeMismatch", err)
+	}
+}
+
+func TestUploadImageRefusals(t *testing.T) {
+	f := newImageFixture(t)
+	ctx := context.Background()
+	upload := func(actorID uuid.UUID, data []byte, contentType string) error {
+		_, err := f.images.UploadImage(ctx, actorID, f.product.ID, productDomain.UploadProductImageDomain{Data: data, ContentType: contentType})
+		return err
+	}
+
+	if err := upload(f.otherID, photo(t, 10, 10), ""); !errors.Is(err, ErrNotProductOwner) {
+		t.Fatalf("got %v, want ErrNotProductOwner", err)
+	}
+	if err := upload(f.sellerID, []byte("<svg onload='alert(1)'/>"), "image/svg+xml"); !errors.Is(err, ErrInvalidImage) {
+		t.Fatalf("got %v, want ErrInvalidImage", err)
+	}
+	if err := upload(f.sellerID, photo(t, 10, 10), "image/gif"); !errors.Is(err, ErrImageTypeMismatch) {
+		t.Fatalf("got %v, want ErrImageTypeMismatch", err)
+	}
+	if err := upload(f.sellerID, make([]byte, 65<<10), ""); !errors.Is(err, ErrImageTooLarge) {
+		t.Fatalf("got %v, want ErrImageTooLarge", err)
+	}
+	if entries, _ := os.ReadDir(f.dir); len(entries) != 0 {
+		t.Fatalf("refused uploads left %d files behind", len(entries))
+	}
+}
+
+func TestPrimaryImage(t *testing.T) {
+	f := newImageFixture(t)
+	ctx := context.Background()
+	add := func(url string, primary bool) *productDomain.ProductImage {
+		t.Helper()
+		image, err := f.images.AddImage(ctx, f.sellerID, f.product.ID, productDomain.AddProductImageDomain{ImageURL: url, IsPrimary: primary})
+		if err != nil {
+			t.Fatalf("AddImage: %v", err)
+		}
+		return image
+	}
+
+	first := add("https://cdn.example.com/1.jpg", false)
+	if !first.IsPrimary {
+		t.Fatal("the first image should become the primary image")
+	}
+	second := add("https://cdn.example.com/2.jpg", false)
+	third := add("https://cdn.example.com/3.jpg", true)
+	if f.products.primaryImages(f.product.ID) != 1 || !f.products.images[third.ID].IsPrimary {
+		t.Fatal("adding a primary image should replace the previous one")
+	}
+
+	if _, err := f.images.SetPrimaryImage(ctx, f.sellerID, f.product.ID, first.ID); err != nil {
+		t.Fatalf("SetPrimaryImage: %v", err)
+	}
+	got, _ := f.service.GetProduct(ctx, f.product.ID)
+	if got.PrimaryImage == nil || got.PrimaryImage.ID != first.ID || f.products.primaryImages(f.product.ID) != 1 {
+		t.Fatalf("primary image = %+v", got.PrimaryImage)
+	}
+
+	if err := f.images.DeleteImage(ctx, f.sellerID, f.product.ID, first.ID); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if !f.products.images[second.ID].IsPrimary || f.products.primaryImages(f.product.ID) != 1 {
+		t.Fatal("the next image in line should take over as primary")
+	}
+	if err := f.images.DeleteImage(ctx, f.sellerID, f.product.ID, first.ID); !errors.Is(err, ErrImageNotFound) {
+		t.Fatalf("got %v, want ErrImageNotFound", err)
+	}
+}
+
+func TestReorderImages(t *testing.T) {
+	f := newImageFixture(t)
+	ctx := context.Background()
+	var ids []uuid.UUID
+	for _, url := range []string{"https://cdn.example.com/1.jpg", "https://cdn.example.com/2.jpg", "https://cdn.example.com/3.jpg"} {
+		image, err := f.images.AddImage(ctx, f.sellerID, f.product.ID, productDomain.AddProductImageDomain{ImageURL: url})
+		if err != nil {
+			t.Fatalf("AddImage: %v", err)
+		}
+		ids = append(ids, image.ID)
+	}
+
+	for _, order := range [][]uuid.UUID{{ids[0], ids[1]}, {ids[0], ids[1], uuid.New()}, {ids[0], ids[0], ids[1]}} {
+		_, err := f.images.ReorderImages(ctx, f.sellerID, f.product.ID, productDomain.ReorderProductImagesDomain{ImageIDs: order})
+		if !errors.Is(err, ErrImageOrderMismatch) {
+			t.Fatalf("order %v: got %v, want ErrImageOrderMismatch", order, err)
+		}
+	}
+
+	images, err := f.images.ReorderImages(ctx, f.sellerID, f.product.ID, productDomain.ReorderProductImagesDomain{
+		ImageIDs: []uuid.UUID{ids[2], ids[0], ids[1]},
+	})
+	if err != nil {
+		t.Fatalf("ReorderImages: %v", err)
+	}
+	if images[0].ID != ids[2] || images[1].ID != ids[0] || images[2].ID != ids[1] || !images[1].IsPrimary {
+		t.Fatalf("images = %+v", images)
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrSKUTaken         = errors.New("sku is already used by another product")
+	ErrNotProductOwner  = errors.New("you can only change your own products")
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+type ProductServiceImpl struct {
+	productRepo  ports.IProductRepository
+	categoryRepo ports.ICategoryRepository
+	roleService  rolePorts.IRoleService
+	transactor   transactors.IDatabaseTransactor
+}
+
+func NewProductService(
+	productRepo ports.IProductRepository,
+	categoryRepo ports.ICategoryRepository,
+	roleService rolePorts.IRoleService,
+	transactor transactors.IDatabaseTransactor,
+) ports.IProductService {
+	return &ProductServiceImpl{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		roleService:  roleService,
+		transactor:   transactor,
+	}
+}
+
+// normalizeSKU makes SKUs case-insensitive so "ab-1" and "AB-1" collide.
+func normalizeSKU(sku string) string {
+	return strings.ToUpper(strings.TrimSpace(sku))
+}
+
+// ListProducts implements ports.IProductService.
+func (s *ProductServiceImpl) ListProducts(ctx context.Context) (pagination.Pagination[[]productDomain.Product], error) {
+	return s.productRepo.ListProducts(ctx)
+}
+
+// GetProduct implements ports.IProductService.
+func (s *ProductServiceImpl) GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	product, err := s.productRepo.GetProduct(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProductNotFound
+	}
+	return product, err
+}
+
+// CreateProduct implements ports.IProductService.
+func (s *ProductServiceImpl) CreateProduct(ctx context.Context, actorID uuid.UUID, payload productDomain.UpsertProductDomain) (*productDomain.Product, error) {
+	product := &productDomain.Product{
+		Name:        strings.TrimSpace(payload.Name),
+		SKU:         normalizeSKU(payload.SKU),
+		Description: payload.Description,
+		Price:       payload.Price,
+		CategoryID:  payload.CategoryID,
+		CreatedBy:   actorID,
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.checkProductFields(ctx, product, nil); err != nil {
+			return err
+		}
+		return s.productRepo.CreateProduct(ctx, product)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, product.ID)
+}
+
+// UpdateProduct implements ports.IProductService.
+func (s *ProductServiceImpl) UpdateProduct(ctx context.Context, actorID, id uuid.UUID, payload productDomain.UpsertProductDomain) (*productDomain.Product, error) {
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := s.lockOwnedProduct(ctx, actorID, id)
+		if err != nil {
+			return err
+		}
+		product.Name = strings.TrimSpace(payload.Name)
+		product.SKU = normalizeSKU(payload.SKU)
+		product.Description = payload.Description
+		product.Price = payload.Price
+		product.CategoryID = payload.CategoryID
+		if err := s.checkProductFields(ctx, product, &product.ID); err != nil {
+			return err
+		}
+		return s.productRepo.UpdateProduct(ctx, product)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProduct(ctx, id)
+}
+
+// DeleteProduct implements ports.IProductService.
+func (s *ProductServiceImpl) DeleteProduct(ctx context.Context, actorID, id uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.lockOwnedProduct(ctx, actorID, id); err != nil {
+			return err
+		}
+		return s.productRepo.DeleteProduct(ctx, id)
+	})
+}
+
+// lockOwnedProduct locks the product and checks that the actor may change it.
+func (s *ProductServiceImpl) lockOwnedProduct(ctx context.Context, actorID, id uuid.UUID) (*productDomain.Product, error) {
+	return lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, id)
+}
+
+// lockOwnedProduct locks the product for the rest of the transaction. Sellers
+// may only change the products they created; PERMISSION_MANAGE_CATALOG lifts
+// that restriction.
+func lockOwnedProduct(ctx context.Context, productRepo ports.IProductRepository, roleService rolePorts.IRoleService, actorID, id uuid.UUID) (*productDomain.Product, error) {
+	product, err := productRepo.GetProductForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if product.CreatedBy == actorID {
+		return product, nil
+	}
+	manager, err := roleService.HasPermission(ctx, actorID, userDomain.PERMISSION_MANAGE_CATALOG)
+	if err != nil {
+		return nil, err
+	}
+	if !manager {
+		return nil, ErrNotProductOwner
+	}
+	return product, nil
+}
+
+// checkProductFields verifies the category and the uniqueness of the SKU.
+func (s *ProductServiceImpl) checkProductFields(ctx context.Context, product *productDomain.Product, exceptID *uuid.UUID) error {
+	if _, err := s.categoryRepo.GetCategory(ctx, product.CategoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	taken, err := s.productRepo.ExistsProductSKU(ctx, product.SKU, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrSKUTaken
+	}
+	return nil
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeProductRepo struct {
+	ports.IProductRepository
+	products map[uuid.UUID]*productDomain.Product
+	images   map[uuid.UUID]*productDomain.ProductImage
+	clock    time.Time
+}
+
+func newFakeProductRepo() *fakeProductRepo {
+	return &fakeProductRepo{
+		products: map[uuid.UUID]*productDomain.Product{},
+		images:   map[uuid.UUID]*productDomain.ProductImage{},
+		clock:    time.Now(),
+	}
+}
+
+// tick hands out increasing timestamps so ordering by CreatedAt is stable.
+func (f *fakeProductRepo) tick() time.Time {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock
+}
+
+func (f *fakeProductRepo) GetProduct(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	product, ok := f.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	clone := *product
+	clone.Options = []productDomain.ProductOption{}
+	clone.SKUs = []productDomain.ProductSKU{}
+	for _, image := range f.images {
+		if image.ProductID == id && image.IsPrimary {
+			primary := *image
+			clone.PrimaryImage = &primary
+		}
+	}
+	return &clone, nil
+}
+
+func (f *fakeProductRepo) GetProductForUpdate(ctx context.Context, id uuid.UUID) (*productDomain.Product, error) {
+	if product, ok := f.products[id]; ok {
+		clone := *product
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProductRepo) ExistsProductSKU(ctx context.Context, sku string, exceptID *uuid.UUID) (bool, error) {
+	for id, product := range f.products {
+		if product.SKU == sku && (exceptID == nil || id != *exceptID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeProductRepo) CountCategoryProducts(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	var count int64
+	for _, product := range f.products {
+		if product.CategoryID == categoryID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeProductRepo) CreateProduct(ctx context.Context, payload *productDomain.Product) error {
+	payload.ID = uuid.New()
+	return f.UpdateProduct(ctx, payload)
+}
+
+func (f *fakeProductRepo) UpdateProduct(ctx context.Context, payload *productDomain.Product) error {
+	clone := *payload
+	f.products[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeProductRepo) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	delete(f.products, id)
+	return nil
+}
+
+func (f *fakeProductRepo) ListProductImages(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductImage, error) {
+	var images []productDomain.ProductImage
+	for _, image := range f.images {
+		if image.ProductID == productID {
+			images = append(images, *image)
+		}
+	}
+	sort.Slice(images, func(i, j int) bool {
+		if images[i].Position != images[j].Position {
+			return images[i].Position < images[j].Position
+		}
+		return images[i].CreatedAt.Before(images[j].CreatedAt)
+	})
+	return images, nil
+}
+
+func (f *fakeProductRepo) GetProductImage(ctx context.Context, productID, imageID uuid.UUID) (*productDomain.ProductImage, error) {
+	if image, ok := f.images[imageID]; ok && image.ProductID == productID {
+		clone := *image
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProductRepo) CreateProductImage(ctx context.Context, payload *productDomain.ProductImage) error {
+	payload.ID = uuid.New()
+	payload.CreatedAt = f.tick()
+	return f.UpdateProductImage(ctx, payload)
+}
+
+func (f *fakeProductRepo) UpdateProductImage(ctx context.Context, payload *productDomain.ProductImage) error {
+	clone := *payload
+	f.images[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeProductRepo) DeleteProductImage(ctx context.Context, productID, imageID uuid.UUID) error {
+	delete(f.images, imageID)
+	return nil
+}
+
+func (f *fakeProductRepo) UnsetPrimaryProductImage(ctx context.Context, productID uuid.UUID) error {
+	for _, image := range f.images {
+		if image.ProductID == productID {
+			image.IsPrimary = false
+		}
+	}
+	return nil
+}
+
+func (f *fakeProductRepo) SetProductImagePositions(ctx context.Context, productID uuid.UUID, imageIDs []uuid.UUID) error {
+	for position, id := range imageIDs {
+		if image, ok := f.images[id]; ok && image.ProductID == productID {
+			image.Position = position
+		}
+	}
+	return nil
+}
+
+// primaryImages counts the images of the product flagged IsPrimary.
+func (f *fakeProductRepo) primaryImages(productID uuid.UUID) int {
+	count := 0
+	for _, image := range f.images {
+		if image.ProductID == productID && image.IsPrimary {
+			count++
+		}
+	}
+	return count
+}
+
+type fakeCategoryRepo struct {
+	ports.ICategoryRepository
+	categories map[uuid.UUID]*productDomain.Category
+}
+
+func (f *fakeCategoryRepo) GetCategory(ctx context.Context, id uuid.UUID) (*productDomain.Category, error) {
+	if category, ok := f.categories[id]; ok {
+		clone := *category
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeCategoryRepo) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	if _, ok := f.categories[id]; !ok {
+		return gorm.ErrRecordNotFound
+	}
+	delete(f.categories, id)
+	return nil
+}
+
+type fakeRoleService struct {
+	rolePorts.IRoleService
+	managers map[uuid.UUID]bool
+}
+
+func (f *fakeRoleService) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	return permission == userDomain.PERMISSION_MANAGE_CATALOG && f.managers[userID], nil
+}
+
+type catalogFixture struct {
+	products   *fakeProductRepo
+	categories *fakeCategoryRepo
+	service    ports.IProductService
+	category   *CategoryServiceImpl
+	categoryID uuid.UUID
+	sellerID   uuid.UUID
+	otherID    uuid.UUID
+	adminID    uuid.UUID
+}
+
+func newCatalogFixture() *catalogFixture {
+	f := &catalogFixture{
+		products:   newFakeProductRepo(),
+		categories: &fakeCategoryRepo{categories: map[uuid.UUID]*productDomain.Category{}},
+		categoryID: uuid.New(),
+		sellerID:   uuid.New(),
+		otherID:    uuid.New(),
+		adminID:    uuid.New(),
+	}
+	f.categories.categories[f.categoryID] = &productDomain.Category{Name: "Shoes"}
+	roles := &fakeRoleService{managers: map[uuid.UUID]bool{f.adminID: true}}
+	f.service = NewProductService(f.products, f.categories, roles, transactorstest.Transactor{})
+	f.category = NewCategoryService(f.categories, f.products, transactorstest.Transactor{}).(*CategoryServiceImpl)
+	return f
+}
+
+func (f *catalogFixture) createProduct(t *testing.T, sku string) *productDomain.Product {
+	t.Helper()
+	product, err := f.service.CreateProduct(context.Background(), f.sellerID, productDomain.UpsertProductDomain{
+		Name: "Runner", SKU: sku, Price: 59.9, CategoryID: f.categoryID,
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	return product
+}
+
+func TestCreateProduct(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+
+	product := f.createProduct(t, " run-01 ")
+	if product.SKU != "RUN-01" || product.CreatedBy != f.sellerID || product.Price != 59.9 || product.SKUs == nil {
+		t.Fatalf("product = %+v", product)
+	}
+
+	payload := productDomain.UpsertProductDomain{Name: "Copy", SKU: "Run-01", CategoryID: f.categoryID}
+	if _, err := f.service.CreateProduct(ctx, f.otherID, payload); !errors.Is(err, ErrSKUTaken) {
+		t.Fatalf("got %v, want ErrSKUTaken", err)
+	}
+	payload.SKU, payload.CategoryID = "RUN-02", uuid.New()
+	if _, err := f.service.CreateProduct(ctx, f.otherID, payload); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("got %v, want ErrCategoryNotFound", err)
+	}
+
+	// Keeping its own SKU is not a conflict.
+	payload.SKU, payload.CategoryID = "run-01", f.categoryID
+	if _, err := f.service.UpdateProduct(ctx, f.sellerID, product.ID, payload); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+}
+
+func TestProductOwnership(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+	product := f.createProduct(t, "RUN-01")
+	payload := productDomain.UpsertProductDomain{Name: "Renamed", SKU: "RUN-01", Price: 10, CategoryID: f.categoryID}
+
+	if _, err := f.service.UpdateProduct(ctx, f.otherID, product.ID, payload); !errors.Is(err, ErrNotProductOwner) {
+		t.Fatalf("got %v, want ErrNotProductOwner", err)
+	}
+	if err := f.service.DeleteProduct(ctx, f.otherID, product.ID); !errors.Is(err, ErrNotProductOwner) {
+		t.Fatalf("got %v, want ErrNotProductOwner", err)
+	}
+
+	updated, err := f.service.UpdateProduct(ctx, f.adminID, product.ID, payload)
+	if err != nil {
+		t.Fatalf("catalog manager UpdateProduct: %v", err)
+	}
+	if updated.Name != "Renamed" || updated.CreatedBy != f.sellerID {
+		t.Fatalf("product = %+v", updated)
+	}
+	if err := f.service.DeleteProduct(ctx, f.sellerID, product.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if _, err := f.service.GetProduct(ctx, product.ID); !errors.Is(err, ErrProductNotFound) {
+		t.Fatalf("got %v, want ErrProductNotFound", err)
+	}
+}
+
+func TestDeleteCategoryInUse(t *testing.T) {
+	f := newCatalogFixture()
+	ctx := context.Background()
+	product := f.createProduct(t, "RUN-01")
+
+	if err := f.category.DeleteCategory(ctx, f.categoryID); !errors.Is(err, ErrCategoryInUse) {
+		t.Fatalf("got %v, want ErrCategoryInUse", err)
+	}
+	if err := f.service.DeleteProduct(ctx, f.sellerID, product.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if err := f.category.DeleteCategory(ctx, f.categoryID); err != nil {
+		t.Fatalf("DeleteCategory: %v", err)
+	}
+	if err := f.category.DeleteCategory(ctx, f.categoryID); !errors.Is(err, ErrCategoryNotFound) {
+		t.Fatalf("got %v, want ErrCategoryNotFound", err)
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+)
+
+var (
+	ErrSearchQueryRequired = errors.New("search query is required")
+	ErrSearchQueryTooLong  = errors.New("search query is too long")
+	ErrInvalidPriceRange   = errors.New("min_price cannot be greater than max_price")
+)
+
+// maxSearchQueryLength bounds the query in characters; longer ones are not
+// searches but pasted text.
+const maxSearchQueryLength = 200
+
+type ProductSearchServiceImpl struct {
+	searchRepo ports.IProductSearchRepository
+}
+
+func NewProductSearchService(searchRepo ports.IProductSearchRepository) ports.IProductSearchService {
+	return &ProductSearchServiceImpl{searchRepo: searchRepo}
+}
+
+// SearchProducts implements ports.IProductSearchService.
+func (s *ProductSearchServiceImpl) SearchProducts(ctx context.Context) (pagination.Pagination[[]productDomain.ProductSearchHit], error) {
+	params := pagination.GetFilters[productDomain.ProductSearchFilters](ctx)
+	query := strings.Join(strings.Fields(params.Filters.Query), " ")
+	if query == "" {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, ErrSearchQueryRequired
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, ErrSearchQueryTooLong
+	}
+	if min, max := params.Filters.MinPrice, params.Filters.MaxPrice; min != nil && max != nil && *min > *max {
+		return pagination.Pagination[[]productDomain.ProductSearchHit]{}, ErrInvalidPriceRange
+	}
+	params.Filters.Query = query
+	return s.searchRepo.SearchProducts(pagination.SetFilters(ctx, params))
+}
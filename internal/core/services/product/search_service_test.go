@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+)
+
+type fakeSearchRepo struct {
+	calls   int
+	filters productDomain.ProductSearchFilters
+}
+
+func (f *fakeSearchRepo) SearchProducts(ctx context.Context) (pagination.Pagination[[]productDomain.ProductSearchHit], error) {
+	f.calls++
+	f.filters = pagination.GetFilters[productDomain.ProductSearchFilters](ctx).Filters
+	return pagination.Pagination[[]productDomain.ProductSearchHit]{Rows: []productDomain.ProductSearchHit{}}, nil
+}
+
+func searchContext(filters productDomain.ProductSearchFilters) context.Context {
+	return pagination.SetFilters(context.Background(), pagination.PaginationParams[productDomain.ProductSearchFilters]{Filters: filters})
+}
+
+func TestSearchProductsNormalizesQuery(t *testing.T) {
+	repo := &fakeSearchRepo{}
+	service := NewProductSearchService(repo)
+
+	if _, err := service.SearchProducts(searchContext(productDomain.ProductSearchFilters{Query: "  red \t running   shoes "})); err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	if repo.filters.Query != "red running shoes" {
+		t.Fatalf("query = %q", repo.filters.Query)
+	}
+}
+
+func TestSearchProductsRefusals(t *testing.T) {
+	repo := &fakeSearchRepo{}
+	service := NewProductSearchService(repo)
+	low, high := 10.0, 5.0
+
+	cases := []struct {
+		filters productDomain.ProductSearchFilters
+		want    error
+	}{
+		{productDomain.ProductSearchFilters{Query: "   "}, ErrSearchQueryRequired},
+		{productDomain.ProductSearchFilters{Query: strings.Repeat("é", maxSearchQueryLength+1)}, ErrSearchQueryTooLong},
+		{productDomain.ProductSearchFilters{Query: "shoes", MinPrice: &low, MaxPrice: &high}, ErrInvalidPriceRange},
+	}
+	for _, tc := range cases {
+		if _, err := service.SearchProducts(searchContext(tc.filters)); !errors.Is(err, tc.want) {
+			t.Fatalf("filters %+v: got %v, want %v", tc.filters, err, tc.want)
+		}
+	}
+	if repo.calls != 0 {
+		t.Fatalf("repository searched %d times", repo.calls)
+	}
+}
@@ -0,0 +1,566 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/slug"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOptionNotFound        = errors.New("product option not found")
+	ErrOptionValueNotFound   = errors.New("option value not found")
+	ErrSKUNotFound           = errors.New("sku not found")
+	ErrOptionExists          = errors.New("the product already has an option with this name")
+	ErrDuplicateOptionValue  = errors.New("the option already has this value")
+	ErrTooManyOptions        = errors.New("the product has reached the maximum number of options")
+	ErrProductHasSKUs        = errors.New("options cannot be added or removed once the product has skus, delete the skus first")
+	ErrOptionValueInUse      = errors.New("option value is used by a sku, delete the sku first")
+	ErrIncompleteCombination = errors.New("choose exactly one value of every option")
+	ErrSKUCombinationExists  = errors.New("the product already has a sku for this combination")
+	ErrSKUCodeTaken          = errors.New("sku code is already used")
+	ErrNoOptions             = errors.New("the product has no options to generate skus from")
+	ErrTooManySKUs           = errors.New("too many combinations, choose fewer option values")
+)
+
+// maxSKUCodeLength matches the size of product_skus.code.
+const maxSKUCodeLength = 64
+
+// ProductSKUServiceConfig holds the tunables of ProductSKUServiceImpl.
+type ProductSKUServiceConfig struct {
+	MaxOptions       int // Option types per product
+	MaxGeneratedSKUs int // Combinations a single GenerateSKUs call may produce
+}
+
+var DefaultProductSKUServiceConfig = ProductSKUServiceConfig{
+	MaxOptions:       5,
+	MaxGeneratedSKUs: 500,
+}
+
+type ProductSKUServiceImpl struct {
+	skuRepo     ports.IProductSKURepository
+	productRepo ports.IProductRepository
+	roleService rolePorts.IRoleService
+	inventory   inventoryPorts.IInventoryService
+	transactor  transactors.IDatabaseTransactor
+	config      ProductSKUServiceConfig
+}
+
+func NewProductSKUService(
+	skuRepo ports.IProductSKURepository,
+	productRepo ports.IProductRepository,
+	roleService rolePorts.IRoleService,
+	inventory inventoryPorts.IInventoryService,
+	transactor transactors.IDatabaseTransactor,
+	config ProductSKUServiceConfig,
+) ports.IProductSKUService {
+	return &ProductSKUServiceImpl{
+		skuRepo:     skuRepo,
+		productRepo: productRepo,
+		roleService: roleService,
+		inventory:   inventory,
+		transactor:  transactor,
+		config:      config,
+	}
+}
+
+// ListOptions implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) ListOptions(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductOption, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.skuRepo.ListProductOptions(ctx, productID)
+}
+
+// CreateOption implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) CreateOption(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.CreateProductOptionDomain) (*productDomain.ProductOption, error) {
+	option := &productDomain.ProductOption{
+		ProductID: productID,
+		Name:      strings.TrimSpace(payload.Name),
+	}
+	for i, raw := range payload.Values {
+		value := strings.TrimSpace(raw)
+		for _, existing := range option.Values {
+			if strings.EqualFold(existing.Value, value) {
+				return nil, ErrDuplicateOptionValue
+			}
+		}
+		option.Values = append(option.Values, productDomain.ProductOptionValue{Value: value, Position: i})
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		if err := s.checkNoSKUs(ctx, productID); err != nil {
+			return err
+		}
+		options, err := s.skuRepo.ListProductOptions(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if len(options) >= s.config.MaxOptions {
+			return ErrTooManyOptions
+		}
+		if optionNameTaken(options, option.Name, uuid.Nil) {
+			return ErrOptionExists
+		}
+		option.Position = len(options)
+		return s.skuRepo.CreateProductOption(ctx, option)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+// UpdateOption implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) UpdateOption(ctx context.Context, actorID, productID, optionID uuid.UUID, payload productDomain.UpdateProductOptionDomain) (*productDomain.ProductOption, error) {
+	var option *productDomain.ProductOption
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		options, err := s.skuRepo.ListProductOptions(ctx, productID)
+		if err != nil {
+			return err
+		}
+		for i := range options {
+			if options[i].ID == optionID {
+				option = &options[i]
+			}
+		}
+		if option == nil {
+			return ErrOptionNotFound
+		}
+		name := strings.TrimSpace(payload.Name)
+		if optionNameTaken(options, name, optionID) {
+			return ErrOptionExists
+		}
+		option.Name = name
+		if payload.Position != nil {
+			option.Position = *payload.Position
+		}
+		return s.skuRepo.UpdateProductOption(ctx, option)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return option, nil
+}
+
+// DeleteOption implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) DeleteOption(ctx context.Context, actorID, productID, optionID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		if err := s.checkNoSKUs(ctx, productID); err != nil {
+			return err
+		}
+		err := s.skuRepo.DeleteProductOption(ctx, productID, optionID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOptionNotFound
+		}
+		return err
+	})
+}
+
+// AddOptionValue implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) AddOptionValue(ctx context.Context, actorID, productID, optionID uuid.UUID, payload productDomain.AddProductOptionValueDomain) (*productDomain.ProductOptionValue, error) {
+	value := &productDomain.ProductOptionValue{
+		OptionID: optionID,
+		Value:    strings.TrimSpace(payload.Value),
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		option, err := s.getOption(ctx, productID, optionID)
+		if err != nil {
+			return err
+		}
+		for _, existing := range option.Values {
+			if strings.EqualFold(existing.Value, value.Value) {
+				return ErrDuplicateOptionValue
+			}
+		}
+		value.Position = len(option.Values)
+		return s.skuRepo.CreateProductOptionValue(ctx, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// DeleteOptionValue implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) DeleteOptionValue(ctx context.Context, actorID, productID, optionID, valueID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		if _, err := s.getOption(ctx, productID, optionID); err != nil {
+			return err
+		}
+		used, err := s.skuRepo.CountSKUsWithOptionValue(ctx, valueID)
+		if err != nil {
+			return err
+		}
+		if used > 0 {
+			return ErrOptionValueInUse
+		}
+		err = s.skuRepo.DeleteProductOptionValue(ctx, optionID, valueID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOptionValueNotFound
+		}
+		return err
+	})
+}
+
+// ListSKUs implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) ListSKUs(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductSKU, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.skuRepo.ListProductSKUs(ctx, productID)
+}
+
+// CreateSKU implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) CreateSKU(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.UpsertProductSKUDomain) (*productDomain.ProductSKU, error) {
+	sku := &productDomain.ProductSKU{
+		ProductID:   productID,
+		Code:        normalizeSKU(payload.Code),
+		Barcode:     strings.TrimSpace(payload.Barcode),
+		Price:       payload.Price,
+		WeightGrams: payload.WeightGrams,
+		Stock:       payload.Stock,
+	}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		options, err := s.skuRepo.ListProductOptions(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if sku.OptionValues, err = combination(options, payload.OptionValueIDs); err != nil {
+			return err
+		}
+		sku.Signature = productDomain.SKUSignature(payload.OptionValueIDs)
+		signatures, err := s.signatures(ctx, productID)
+		if err != nil {
+			return err
+		}
+		if signatures[sku.Signature] {
+			return ErrSKUCombinationExists
+		}
+		if err := s.checkCode(ctx, sku.Code, nil); err != nil {
+			return err
+		}
+		if err := s.skuRepo.CreateProductSKU(ctx, sku); err != nil {
+			return err
+		}
+		return s.setStock(ctx, actorID, sku, "sku created")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+// UpdateSKU implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) UpdateSKU(ctx context.Context, actorID, productID, skuID uuid.UUID, payload productDomain.UpsertProductSKUDomain) (*productDomain.ProductSKU, error) {
+	var sku *productDomain.ProductSKU
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		var err error
+		sku, err = s.skuRepo.GetProductSKU(ctx, productID, skuID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrSKUNotFound
+			}
+			return err
+		}
+		code := normalizeSKU(payload.Code)
+		if code != sku.Code {
+			if err := s.checkCode(ctx, code, &sku.ID); err != nil {
+				return err
+			}
+			sku.Code = code
+		}
+		sku.Barcode = strings.TrimSpace(payload.Barcode)
+		sku.Price = payload.Price
+		sku.WeightGrams = payload.WeightGrams
+		sku.Stock = payload.Stock
+		if err := s.skuRepo.UpdateProductSKU(ctx, sku); err != nil {
+			return err
+		}
+		return s.setStock(ctx, actorID, sku, "sku edited")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sku, nil
+}
+
+// setStock records the stock given to the SKU in the inventory ledger.
+func (s *ProductSKUServiceImpl) setStock(ctx context.Context, actorID uuid.UUID, sku *productDomain.ProductSKU, reason string) error {
+	return s.inventory.SetStock(ctx, actorID, sku.ID, orderDomain.SetStockDomain{
+		Quantity:      sku.Stock,
+		Reason:        reason,
+		ReferenceType: orderDomain.STOCK_REFERENCE_SKU,
+		ReferenceID:   &sku.ID,
+	})
+}
+
+// DeleteSKU implements ports.IProductSKUService.
+func (s *ProductSKUServiceImpl) DeleteSKU(ctx context.Context, actorID, productID, skuID uuid.UUID) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID); err != nil {
+			return err
+		}
+		err := s.skuRepo.DeleteProductSKU(ctx, productID, skuID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSKUNotFound
+		}
+		return err
+	})
+}
+
+// GenerateSKUs implements ports.IProductSKUService.
+//
+// Codes are the product SKU followed by the value slugs, e.g. TEE-RED-L.
+func (s *ProductSKUServiceImpl) GenerateSKUs(ctx context.Context, actorID, productID uuid.UUID, payload productDomain.GenerateProductSKUsDomain) ([]productDomain.ProductSKU, error) {
+	created := []productDomain.ProductSKU{}
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		product, err := lockOwnedProduct(ctx, s.productRepo, s.roleService, actorID, productID)
+		if err != nil {
+			return err
+		}
+		options, err := s.skuRepo.ListProductOptions(ctx, productID)
+		if err != nil {
+			return err
+		}
+		axes, err := s.matrixAxes(options, payload.OptionValueIDs)
+		if err != nil {
+			return err
+		}
+		signatures, err := s.signatures(ctx, productID)
+		if err != nil {
+			return err
+		}
+		price := product.Price
+		if payload.Price != nil {
+			price = *payload.Price
+		}
+
+		// Walk the cartesian product like an odometer, last option fastest.
+		cursor := make([]int, len(axes))
+		for {
+			values := make([]productDomain.ProductOptionValue, len(axes))
+			ids := make([]uuid.UUID, len(axes))
+			for i, axis := range axes {
+				values[i] = axis[cursor[i]]
+				ids[i] = values[i].ID
+			}
+			signature := productDomain.SKUSignature(ids)
+			if !signatures[signature] {
+				code, err := s.generateCode(ctx, product.SKU, values)
+				if err != nil {
+					return err
+				}
+				sku := productDomain.ProductSKU{
+					ProductID:    productID,
+					Code:         code,
+					Price:        price,
+					WeightGrams:  payload.WeightGrams,
+					Signature:    signature,
+					OptionValues: values,
+				}
+				if err := s.skuRepo.CreateProductSKU(ctx, &sku); err != nil {
+					return err
+				}
+				created = append(created, sku)
+			}
+
+			i := len(cursor) - 1
+			for ; i >= 0; i-- {
+				cursor[i]++
+				if cursor[i] < len(axes[i]) {
+					break
+				}
+				cursor[i] = 0
+			}
+			if i < 0 {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// matrixAxes returns, per option, the values taking part in the matrix.
+func (s *ProductSKUServiceImpl) matrixAxes(options []productDomain.ProductOption, chosen []uuid.UUID) ([][]productDomain.ProductOptionValue, error) {
+	if len(options) == 0 {
+		return nil, ErrNoOptions
+	}
+	wanted := make(map[uuid.UUID]bool, len(chosen))
+	for _, id := range chosen {
+		wanted[id] = true
+	}
+
+	axes := make([][]productDomain.ProductOptionValue, 0, len(options))
+	total, matched := 1, 0
+	for _, option := range options {
+		var axis []productDomain.ProductOptionValue
+		for _, value := range option.Values {
+			if len(chosen) == 0 || wanted[value.ID] {
+				axis = append(axis, value)
+			}
+		}
+		if len(axis) == 0 {
+			return nil, ErrIncompleteCombination
+		}
+		matched += len(axis)
+		total *= len(axis)
+		if total > s.config.MaxGeneratedSKUs {
+			return nil, ErrTooManySKUs
+		}
+		axes = append(axes, axis)
+	}
+	if len(chosen) > 0 && matched != len(wanted) {
+		return nil, ErrOptionValueNotFound
+	}
+	return axes, nil
+}
+
+// generateCode derives a free SKU code from the product SKU and the values.
+func (s *ProductSKUServiceImpl) generateCode(ctx context.Context, productSKU string, values []productDomain.ProductOptionValue) (string, error) {
+	parts := []string{productSKU}
+	for _, value := range values {
+		if part := slug.Make(value.Value); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	base := normalizeSKU(strings.Join(parts, "-"))
+	if utf8.RuneCountInString(base) > maxSKUCodeLength-4 {
+		base = string([]rune(base)[:maxSKUCodeLength-4])
+	}
+	code := base
+	for i := 2; ; i++ {
+		taken, err := s.skuRepo.ExistsSKUCode(ctx, code, nil)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return code, nil
+		}
+		code = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// combination checks that ids holds exactly one value of every option and
+// returns those values.
+func combination(options []productDomain.ProductOption, ids []uuid.UUID) ([]productDomain.ProductOptionValue, error) {
+	if len(ids) != len(options) {
+		return nil, ErrIncompleteCombination
+	}
+	picked := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		picked[id] = true
+	}
+	values := make([]productDomain.ProductOptionValue, 0, len(options))
+	for _, option := range options {
+		found := false
+		for _, value := range option.Values {
+			if picked[value.ID] {
+				if found {
+					return nil, ErrIncompleteCombination
+				}
+				found = true
+				values = append(values, value)
+			}
+		}
+		if !found {
+			return nil, ErrIncompleteCombination
+		}
+	}
+	return values, nil
+}
+
+func optionNameTaken(options []productDomain.ProductOption, name string, exceptID uuid.UUID) bool {
+	for _, option := range options {
+		if option.ID != exceptID && strings.EqualFold(option.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// signatures returns the combinations the product already has a SKU for.
+func (s *ProductSKUServiceImpl) signatures(ctx context.Context, productID uuid.UUID) (map[string]bool, error) {
+	skus, err := s.skuRepo.ListProductSKUs(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	signatures := make(map[string]bool, len(skus))
+	for _, sku := range skus {
+		signatures[sku.Signature] = true
+	}
+	return signatures, nil
+}
+
+func (s *ProductSKUServiceImpl) getOption(ctx context.Context, productID, optionID uuid.UUID) (*productDomain.ProductOption, error) {
+	option, err := s.skuRepo.GetProductOption(ctx, productID, optionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOptionNotFound
+	}
+	return option, err
+}
+
+func (s *ProductSKUServiceImpl) checkProduct(ctx context.Context, productID uuid.UUID) error {
+	_, err := s.productRepo.GetProduct(ctx, productID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProductNotFound
+	}
+	return err
+}
+
+func (s *ProductSKUServiceImpl) checkNoSKUs(ctx context.Context, productID uuid.UUID) error {
+	count, err := s.skuRepo.CountProductSKUs(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrProductHasSKUs
+	}
+	return nil
+}
+
+func (s *ProductSKUServiceImpl) checkCode(ctx context.Context, code string, exceptID *uuid.UUID) error {
+	taken, err := s.skuRepo.ExistsSKUCode(ctx, code, exceptID)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrSKUCodeTaken
+	}
+	return nil
+}
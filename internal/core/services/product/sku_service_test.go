@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	orderDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/order"
+	productDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/product"
+	inventoryPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/inventory"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/product"
+	inventoryServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/inventory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeSKURepo struct {
+	ports.IProductSKURepository
+	options map[uuid.UUID]*productDomain.ProductOption
+	skus    map[uuid.UUID]*productDomain.ProductSKU
+}
+
+func (f *fakeSKURepo) ListProductOptions(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductOption, error) {
+	var options []productDomain.ProductOption
+	for _, option := range f.options {
+		if option.ProductID == productID {
+			clone := *option
+			clone.Values = append([]productDomain.ProductOptionValue(nil), option.Values...)
+			options = append(options, clone)
+		}
+	}
+	sort.Slice(options, func(i, j int) bool { return options[i].Position < options[j].Position })
+	return options, nil
+}
+
+func (f *fakeSKURepo) GetProductOption(ctx context.Context, productID, optionID uuid.UUID) (*productDomain.ProductOption, error) {
+	if option, ok := f.options[optionID]; ok && option.ProductID == productID {
+		clone := *option
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSKURepo) CreateProductOption(ctx context.Context, payload *productDomain.ProductOption) error {
+	payload.ID = uuid.New()
+	for i := range payload.Values {
+		payload.Values[i].ID = uuid.New()
+		payload.Values[i].OptionID = payload.ID
+	}
+	return f.UpdateProductOption(ctx, payload)
+}
+
+func (f *fakeSKURepo) UpdateProductOption(ctx context.Context, payload *productDomain.ProductOption) error {
+	clone := *payload
+	f.options[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeSKURepo) DeleteProductOption(ctx context.Context, productID, optionID uuid.UUID) error {
+	if _, err := f.GetProductOption(ctx, productID, optionID); err != nil {
+		return err
+	}
+	delete(f.options, optionID)
+	return nil
+}
+
+func (f *fakeSKURepo) CreateProductOptionValue(ctx context.Context, payload *productDomain.ProductOptionValue) error {
+	payload.ID = uuid.New()
+	option := f.options[payload.OptionID]
+	option.Values = append(option.Values, *payload)
+	return nil
+}
+
+func (f *fakeSKURepo) DeleteProductOptionValue(ctx context.Context, optionID, valueID uuid.UUID) error {
+	option := f.options[optionID]
+	for i, value := range option.Values {
+		if value.ID == valueID {
+			option.Values = append(option.Values[:i:i], option.Values[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (f *fakeSKURepo) CountProductSKUs(ctx context.Context, productID uuid.UUID) (int64, error) {
+	skus, _ := f.ListProductSKUs(ctx, productID)
+	return int64(len(skus)), nil
+}
+
+func (f *fakeSKURepo) CountSKUsWithOptionValue(ctx context.Context, valueID uuid.UUID) (int64, error) {
+	var count int64
+	for _, sku := range f.skus {
+		for _, value := range sku.OptionValues {
+			if value.ID == valueID {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (f *fakeSKURepo) ListProductSKUs(ctx context.Context, productID uuid.UUID) ([]productDomain.ProductSKU, error) {
+	var skus []productDomain.ProductSKU
+	for _, sku := range f.skus {
+		if sku.ProductID == productID {
+			skus = append(skus, *sku)
+		}
+	}
+	return skus, nil
+}
+
+func (f *fakeSKURepo) GetProductSKU(ctx context.Context, productID, skuID uuid.UUID) (*productDomain.ProductSKU, error) {
+	if sku, ok := f.skus[skuID]; ok && sku.ProductID == productID {
+		clone := *sku
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSKURepo) ExistsSKUCode(ctx context.Context, code string, exceptID *uuid.UUID) (bool, error) {
+	for id, sku := range f.skus {
+		if sku.Code == code && (exceptID == nil || id != *exceptID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeSKURepo) CreateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error {
+	payload.ID = uuid.New()
+	return f.UpdateProductSKU(ctx, payload)
+}
+
+func (f *fakeSKURepo) UpdateProductSKU(ctx context.Context, payload *productDomain.ProductSKU) error {
+	clone := *payload
+	f.skus[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeSKURepo) DeleteProductSKU(ctx context.Context, productID, skuID uuid.UUID) error {
+	if _, err := f.GetProductSKU(ctx, productID, skuID); err != nil {
+		return err
+	}
+	delete(f.skus, skuID)
+	return nil
+}
+
+// fakeInventory keeps the stock counts the catalog records, and the units
+// reserved by checkouts.
+type fakeInventory struct {
+	inventoryPorts.IInventoryService
+	counts   map[uuid.UUID][]orderDomain.SetStockDomain
+	reserved map[uuid.UUID]int
+}
+
+func newFakeInventory() *fakeInventory {
+	return &fakeInventory{counts: map[uuid.UUID][]orderDomain.SetStockDomain{}, reserved: map[uuid.UUID]int{}}
+}
+
+func (f *fakeInventory) SetStock(ctx context.Context, actorID, skuID uuid.UUID, payload orderDomain.SetStockDomain) error {
+	if payload.Quantity < f.reserved[skuID] {
+		return inventoryServices.ErrStockBelowReserved
+	}
+	f.counts[skuID] = append(f.counts[skuID], payload)
+	return nil
+}
+
+func (f *fakeInventory) CheckStock(ctx context.Context, skuID uuid.UUID, quantity int) error {
+	if quantity < f.reserved[skuID] {
+		return inventoryServices.ErrStockBelowReserved
+	}
+	return nil
+}
+
+type skuFixture struct {
+	*catalogFixture
+	skus      *fakeSKURepo
+	inventory *fakeInventory
+	service   ports.IProductSKUService
+	product   *productDomain.Product
+}
+
+func newSKUFixture(t *testing.T) *skuFixture {
+	catalog := newCatalogFixture()
+	f := &skuFixture{
+		catalogFixture: catalog,
+		skus: &fakeSKURepo{
+			options: map[uuid.UUID]*productDomain.ProductOption{},
+			skus:    map[uuid.UUID]*productDomain.ProductSKU{},
+		},
+		inventory: newFakeInventory(),
+	}
+	roles := &fakeRoleService{managers: map[uuid.UUID]bool{catalog.adminID: true}}
+	f.service = NewProductSKUService(f.skus, catalog.products, roles, f.inventory, transactorstest.Transactor{}, ProductSKUServiceConfig{
+		MaxOptions:       3,
+		MaxGeneratedSKUs: 6,
+	})
+	f.product = catalog.createProduct(t, "TEE")
+	return f
+}
+
+func (f *skuFixture) createOption(t *testing.T, name string, values ...string) *productDomain.ProductOption {
+	t.Helper()
+	option, err := f.service.CreateOption(context.Background(), f.sellerID, f.product.ID, productDomain.CreateProductOptionDomain{
+		Name: name, Values: values,
+	})
+	if err != nil {
+		t.Fatalf("CreateOption: %v", err)
+	}
+	return option
+}
+
+func TestGenerateSKUs(t *testing.T) {
+	f := newSKUFixture(t)
+	ctx := context.Background()
+	color := f.createOption(t, "Color", "Red", "Navy Blue")
+	size := f.createOption(t, "Size", "S", "M", "L")
+
+	// Only red first; generating everything afterwards adds the rest.
+	red := color.Values[0].ID
+	skus, err := f.service.GenerateSKUs(ctx, f.sellerID, f.product.ID, productDomain.GenerateProductSKUsDomain{
+		OptionValueIDs: []uuid.UUID{red, size.Values[0].ID, size.Values[2].ID},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSKUs: %v", err)
+	}
+	if len(skus) != 2 || skus[0].Code != "TEE-RED-S" || skus[1].Code != "TEE-RED-L" || skus[0].Price != 59.9 {
+		t.Fatalf("skus = %+v", skus)
+	}
+
+	skus, err = f.service.GenerateSKUs(ctx, f.sellerID, f.product.ID, productDomain.GenerateProductSKUsDomain{})
+	if err != nil {
+		t.Fatalf("GenerateSKUs: %v", err)
+	}
+	if len(skus) != 4 || skus[0].Code != "TEE-RED-M" || skus[1].Code != "TEE-NAVY-BLUE-S" {
+		t.Fatalf("skus = %+v", skus)
+	}
+	if skus, _ := f.service.GenerateSKUs(ctx, f.sellerID, f.product.ID, productDomain.GenerateProductSKUsDomain{}); len(skus) != 0 {
+		t.Fatalf("a second run created %d skus", len(skus))
+	}
+
+	// Every option needs at least one chosen value.
+	_, err = f.service.GenerateSKUs(ctx, f.sellerID, f.product.ID, productDomain.GenerateProductSKUsDomain{
+		OptionValueIDs: []uuid.UUID{red},
+	})
+	if !errors.Is(err, ErrIncompleteCombination) {
+		t.Fatalf("got %v, want ErrIncompleteCombination", err)
+	}
+}
+
+func TestGenerateSKUsLimit(t *testing.T) {
+	f := newSKUFixture(t)
+	f.createOption(t, "Size", "XS", "S", "M", "L", "XL", "XXL", "3XL")
+
+	_, err := f.service.GenerateSKUs(context.Background(), f.sellerID, f.product.ID, productDomain.GenerateProductSKUsDomain{})
+	if !errors.Is(err, ErrTooManySKUs) {
+		t.Fatalf("got %v, want ErrTooManySKUs", err)
+	}
+	if count, _ := f.skus.CountProductSKUs(context.Background(), f.product.ID); count != 0 {
+		t.Fatalf("%d skus were created", count)
+	}
+}
+
+func TestCreateSKU(t *testing.T) {
+	f := newSKUFixture(t)
+	ctx := context.Background()
+	color := f.createOption(t, "Color", "Red", "Blue")
+	size := f.createOption(t, "Size", "S", "M")
+
+	payload := productDomain.UpsertProductSKUDomain{
+		Code: "tee-red-s", Price: 10, OptionValueIDs: []uuid.UUID{color.Values[0].ID},
+	}
+	if _, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, payload); !errors.Is(err, ErrIncompleteCombination) {
+		t.Fatalf("got %v, want ErrIncompleteCombination", err)
+	}
+	payload.OptionValueIDs = []uuid.UUID{color.Values[0].ID, color.Values[1].ID}
+	if _, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, payload); !errors.Is(err, ErrIncompleteCombination) {
+		t.Fatalf("got %v, want ErrIncompleteCombination", err)
+	}
+	payload.OptionValueIDs = []uuid.UUID{size.Values[0].ID, color.Values[0].ID}
+	if _, err := f.service.CreateSKU(ctx, f.otherID, f.product.ID, payload); !errors.Is(err, ErrNotProductOwner) {
+		t.Fatalf("got %v, want ErrNotProductOwner", err)
+	}
+	sku, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, payload)
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	if sku.Code != "TEE-RED-S" || len(sku.OptionValues) != 2 {
+		t.Fatalf("sku = %+v", sku)
+	}
+
+	// Same combination in another order, then a new combination with a used code.
+	payload.Code, payload.OptionValueIDs = "OTHER", []uuid.UUID{color.Values[0].ID, size.Values[0].ID}
+	if _, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, payload); !errors.Is(err, ErrSKUCombinationExists) {
+		t.Fatalf("got %v, want ErrSKUCombinationExists", err)
+	}
+	payload.Code, payload.OptionValueIDs = "Tee-Red-S", []uuid.UUID{color.Values[0].ID, size.Values[1].ID}
+	if _, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, payload); !errors.Is(err, ErrSKUCodeTaken) {
+		t.Fatalf("got %v, want ErrSKUCodeTaken", err)
+	}
+
+	// Keeping its own code on update is not a conflict.
+	payload.Code, payload.Price, payload.Stock = "tee-red-s", 12, 7
+	updated, err := f.service.UpdateSKU(ctx, f.sellerID, f.product.ID, sku.ID, payload)
+	if err != nil || updated.Price != 12 {
+		t.Fatalf("UpdateSKU = %+v, %v", updated, err)
+	}
+	if counts := f.inventory.counts[sku.ID]; len(counts) != 2 || counts[1].Quantity != 7 || *counts[1].ReferenceID != sku.ID {
+		t.Fatalf("stock counts = %+v, want the creation and the edit", counts)
+	}
+
+	// The stock cannot be edited below what checkouts hold.
+	f.inventory.reserved[sku.ID] = 8
+	if _, err := f.service.UpdateSKU(ctx, f.sellerID, f.product.ID, sku.ID, payload); !errors.Is(err, inventoryServices.ErrStockBelowReserved) {
+		t.Fatalf("got %v, want ErrStockBelowReserved", err)
+	}
+}
+
+func TestOptionRules(t *testing.T) {
+	f := newSKUFixture(t)
+	ctx := context.Background()
+	color := f.createOption(t, "Color", "Red", "Blue")
+
+	if _, err := f.service.CreateOption(ctx, f.sellerID, f.product.ID, productDomain.CreateProductOptionDomain{Name: "color", Values: []string{"Green"}}); !errors.Is(err, ErrOptionExists) {
+		t.Fatalf("got %v, want ErrOptionExists", err)
+	}
+	if _, err := f.service.AddOptionValue(ctx, f.sellerID, f.product.ID, color.ID, productDomain.AddProductOptionValueDomain{Value: "red"}); !errors.Is(err, ErrDuplicateOptionValue) {
+		t.Fatalf("got %v, want ErrDuplicateOptionValue", err)
+	}
+
+	sku, err := f.service.CreateSKU(ctx, f.sellerID, f.product.ID, productDomain.UpsertProductSKUDomain{
+		Code: "TEE-RED", OptionValueIDs: []uuid.UUID{color.Values[0].ID},
+	})
+	if err != nil {
+		t.Fatalf("CreateSKU: %v", err)
+	}
+	if _, err := f.service.CreateOption(ctx, f.sellerID, f.product.ID, productDomain.CreateProductOptionDomain{Name: "Size", Values: []string{"S"}}); !errors.Is(err, ErrProductHasSKUs) {
+		t.Fatalf("got %v, want ErrProductHasSKUs", err)
+	}
+	if err := f.service.DeleteOption(ctx, f.sellerID, f.product.ID, color.ID); !errors.Is(err, ErrProductHasSKUs) {
+		t.Fatalf("got %v, want ErrProductHasSKUs", err)
+	}
+	if err := f.service.DeleteOptionValue(ctx, f.sellerID, f.product.ID, color.ID, color.Values[0].ID); !errors.Is(err, ErrOptionValueInUse) {
+		t.Fatalf("got %v, want ErrOptionValueInUse", err)
+	}
+	if err := f.service.DeleteOptionValue(ctx, f.sellerID, f.product.ID, color.ID, color.Values[1].ID); err != nil {
+		t.Fatalf("DeleteOptionValue: %v", err)
+	}
+
+	if err := f.service.DeleteSKU(ctx, f.sellerID, f.product.ID, sku.ID); err != nil {
+		t.Fatalf("DeleteSKU: %v", err)
+	}
+	if err := f.service.DeleteOption(ctx, f.sellerID, f.product.ID, color.ID); err != nil {
+		t.Fatalf("DeleteOption: %v", err)
+	}
+}
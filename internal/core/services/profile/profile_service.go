@@ -0,0 +1,350 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	"github.com/billowdev/go-fiber-e-commerce/internal/core/domain"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/profile"
+	storagePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/storage"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/mergepatch"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidProfilePatch      = errors.New("profile patch must be a JSON object")
+	ErrProfileFieldNotPatchable = errors.New("profile field cannot be patched")
+	ErrInvalidPreferences       = errors.New("invalid preferences")
+	ErrInvalidSocialMediaLinks  = errors.New("invalid social media links")
+	ErrInvalidDateOfBirth       = errors.New("date_of_birth must be a past date formatted as YYYY-MM-DD")
+	ErrInvalidAvatar            = errors.New("avatar must be a base64 data URI of a PNG, JPEG, GIF or WebP image")
+	ErrAvatarTooLarge           = errors.New("avatar is too large")
+)
+
+// avatarContentTypes maps the image types accepted in data URIs to the
+// content type the decoded bytes must sniff as, and the file extension.
+var avatarContentTypes = map[string]struct{ contentType, extension string }{
+	"png":  {"image/png", "png"},
+	"jpeg": {"image/jpeg", "jpg"},
+	"jpg":  {"image/jpeg", "jpg"},
+	"gif":  {"image/gif", "gif"},
+	"webp": {"image/webp", "webp"},
+}
+
+const (
+	profileFieldPreferences      = "preferences"
+	profileFieldSocialMediaLinks = "social_media_links"
+	profileFieldDateOfBirth      = "date_of_birth"
+)
+
+// ProfileServiceConfig holds the tunables of ProfileServiceImpl.
+type ProfileServiceConfig struct {
+	MaxAvatarBytes int
+}
+
+var DefaultProfileServiceConfig = ProfileServiceConfig{
+	MaxAvatarBytes: 2 << 20,
+}
+
+type ProfileServiceImpl struct {
+	profileRepo       ports.IProfileRepository
+	storage           storagePorts.IFileStorage
+	transactor        transactors.IDatabaseTransactor
+	config            ProfileServiceConfig
+	preferencesSchema *jsonschema.Schema
+	socialLinksSchema *jsonschema.Schema
+	now               func() time.Time
+}
+
+func NewProfileService(
+	profileRepo ports.IProfileRepository,
+	storage storagePorts.IFileStorage,
+	transactor transactors.IDatabaseTransactor,
+	config ProfileServiceConfig,
+) ports.IProfileService {
+	return &ProfileServiceImpl{
+		profileRepo:       profileRepo,
+		storage:           storage,
+		transactor:        transactor,
+		config:            config,
+		preferencesSchema: jsonschema.MustCompileString("preferences.json", userDomain.PreferencesSchema),
+		socialLinksSchema: jsonschema.MustCompileString("social_media_links.json", userDomain.SocialMediaLinksSchema),
+		now:               time.Now,
+	}
+}
+
+// GetProfile implements ports.IProfileService.
+func (s *ProfileServiceImpl) GetProfile(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	profile, err := s.profileRepo.GetProfileByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newProfile(userID), nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UpdateProfile implements ports.IProfileService.
+//
+// The patch is applied to a document made of the patchable fields, so
+// {"preferences": {"theme": "dark"}} changes one preference and keeps the
+// others, and {"date_of_birth": null} clears the date. The merged document is
+// validated as a whole before anything is saved.
+func (s *ProfileServiceImpl) UpdateProfile(ctx context.Context, userID uuid.UUID, patch []byte) (*userDomain.UserProfile, error) {
+	var profile *userDomain.UserProfile
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if profile, err = s.profileForUpdate(txCtx, userID); err != nil {
+			return err
+		}
+
+		document, err := mergepatch.ApplyJSON(profileDocument(profile), patch)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidProfilePatch, err)
+		}
+		if err := s.applyDocument(profile, document); err != nil {
+			return err
+		}
+		return s.saveProfile(txCtx, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// UploadAvatar implements ports.IProfileService.
+//
+// The new image is stored before the profile is updated and the previous
+// image is deleted only after the update commits, so a failure never leaves
+// the profile pointing at a missing file.
+func (s *ProfileServiceImpl) UploadAvatar(ctx context.Context, userID uuid.UUID, dataURI string) (*userDomain.UserProfile, error) {
+	data, contentType, extension, err := s.decodeAvatar(dataURI)
+	if err != nil {
+		return nil, err
+	}
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("avatars/%s/%s.%s", userID, hex.EncodeToString(name), extension)
+	url, err := s.storage.Put(ctx, key, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile *userDomain.UserProfile
+	var previousKey string
+	err = s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if profile, err = s.profileForUpdate(txCtx, userID); err != nil {
+			return err
+		}
+		previousKey = profile.ProfilePictureKey
+		profile.ProfilePicture = url
+		profile.ProfilePictureKey = key
+		return s.saveProfile(txCtx, profile)
+	})
+	if err != nil {
+		s.deleteFile(ctx, key)
+		return nil, err
+	}
+	s.deleteFile(ctx, previousKey)
+	return profile, nil
+}
+
+// DeleteAvatar implements ports.IProfileService.
+func (s *ProfileServiceImpl) DeleteAvatar(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	var profile *userDomain.UserProfile
+	var previousKey string
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if profile, err = s.profileForUpdate(txCtx, userID); err != nil {
+			return err
+		}
+		if profile.ProfilePicture == "" && profile.ProfilePictureKey == "" {
+			return nil
+		}
+		previousKey = profile.ProfilePictureKey
+		profile.ProfilePicture = ""
+		profile.ProfilePictureKey = ""
+		return s.saveProfile(txCtx, profile)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.deleteFile(ctx, previousKey)
+	return profile, nil
+}
+
+// profileForUpdate locks the user's profile, or returns a new unsaved one.
+func (s *ProfileServiceImpl) profileForUpdate(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	profile, err := s.profileRepo.GetProfileByUserIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newProfile(userID), nil
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *ProfileServiceImpl) saveProfile(ctx context.Context, profile *userDomain.UserProfile) error {
+	if profile.ID == uuid.Nil {
+		return s.profileRepo.CreateProfile(ctx, profile)
+	}
+	return s.profileRepo.UpdateProfile(ctx, profile)
+}
+
+// applyDocument validates the merged document and copies it onto the profile.
+func (s *ProfileServiceImpl) applyDocument(profile *userDomain.UserProfile, document map[string]interface{}) error {
+	for field := range document {
+		switch field {
+		case profileFieldPreferences, profileFieldSocialMediaLinks, profileFieldDateOfBirth:
+		default:
+			return fmt.Errorf("%w: %s", ErrProfileFieldNotPatchable, field)
+		}
+	}
+
+	preferences, err := validateObject(s.preferencesSchema, document[profileFieldPreferences], ErrInvalidPreferences)
+	if err != nil {
+		return err
+	}
+	links, err := validateObject(s.socialLinksSchema, document[profileFieldSocialMediaLinks], ErrInvalidSocialMediaLinks)
+	if err != nil {
+		return err
+	}
+	dateOfBirth, err := s.parseDateOfBirth(document[profileFieldDateOfBirth])
+	if err != nil {
+		return err
+	}
+
+	profile.Preferences = preferences
+	profile.SocialMediaLinks = links
+	profile.DateOfBirth = dateOfBirth
+	return nil
+}
+
+func (s *ProfileServiceImpl) parseDateOfBirth(value interface{}) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return nil, ErrInvalidDateOfBirth
+	}
+	date, err := time.Parse(userDomain.DateOfBirthLayout, text)
+	if err != nil || date.Year() < 1900 || !date.Before(s.now()) {
+		return nil, ErrInvalidDateOfBirth
+	}
+	return &date, nil
+}
+
+// decodeAvatar checks that the data URI holds an image of the type it
+// declares and returns its bytes, content type and file extension.
+func (s *ProfileServiceImpl) decodeAvatar(dataURI string) ([]byte, string, string, error) {
+	imageType, err := utils.ExtractImageTypeFromBase64(dataURI)
+	if err != nil {
+		return nil, "", "", ErrInvalidAvatar
+	}
+	format, ok := avatarContentTypes[strings.ToLower(imageType)]
+	if !ok {
+		return nil, "", "", ErrInvalidAvatar
+	}
+
+	encoded := dataURI[strings.Index(dataURI, ";base64,")+len(";base64,"):]
+	if base64.StdEncoding.DecodedLen(len(encoded)) > s.config.MaxAvatarBytes+2 {
+		return nil, "", "", fmt.Errorf("%w, the limit is %d KiB", ErrAvatarTooLarge, s.config.MaxAvatarBytes>>10)
+	}
+	data, err := utils.DecodeBase64(encoded)
+	if err != nil {
+		return nil, "", "", ErrInvalidAvatar
+	}
+	if len(data) > s.config.MaxAvatarBytes {
+		return nil, "", "", fmt.Errorf("%w, the limit is %d KiB", ErrAvatarTooLarge, s.config.MaxAvatarBytes>>10)
+	}
+	// Trust the bytes, not the declared type.
+	if http.DetectContentType(data) != format.contentType {
+		return nil, "", "", ErrInvalidAvatar
+	}
+	return data, format.contentType, format.extension, nil
+}
+
+func (s *ProfileServiceImpl) deleteFile(ctx context.Context, key string) {
+	if key == "" {
+		return
+	}
+	if err := s.storage.Delete(ctx, key); err != nil {
+		log.Printf("profile: delete stored file %s: %v", key, err)
+	}
+}
+
+func newProfile(userID uuid.UUID) *userDomain.UserProfile {
+	return &userDomain.UserProfile{
+		UserID:           userID,
+		Preferences:      domain.JSONB{},
+		SocialMediaLinks: domain.JSONB{},
+	}
+}
+
+// profileDocument is the patchable part of the profile as decoded JSON.
+func profileDocument(profile *userDomain.UserProfile) map[string]interface{} {
+	document := map[string]interface{}{}
+	if profile.Preferences != nil {
+		document[profileFieldPreferences] = map[string]interface{}(profile.Preferences)
+	}
+	if profile.SocialMediaLinks != nil {
+		document[profileFieldSocialMediaLinks] = map[string]interface{}(profile.SocialMediaLinks)
+	}
+	if profile.DateOfBirth != nil {
+		document[profileFieldDateOfBirth] = profile.DateOfBirth.Format(userDomain.DateOfBirthLayout)
+	}
+	return document
+}
+
+// validateObject checks value against schema. A missing value stands for an
+// empty object.
+func validateObject(schema *jsonschema.Schema, value interface{}, invalid error) (domain.JSONB, error) {
+	if value == nil {
+		value = map[string]interface{}{}
+	}
+	if err := schema.Validate(value); err != nil {
+		var validationErr *jsonschema.ValidationError
+		if errors.As(err, &validationErr) {
+			return nil, fmt.Errorf("%w: %s", invalid, strings.Join(schemaViolations(validationErr), "; "))
+		}
+		return nil, fmt.Errorf("%w: %v", invalid, err)
+	}
+	return domain.JSONB(value.(map[string]interface{})), nil
+}
+
+// schemaViolations flattens a validation error to its leaf causes, which name
+// the offending value, e.g. "/theme: value must be one of ...".
+func schemaViolations(err *jsonschema.ValidationError) []string {
+	if len(err.Causes) == 0 {
+		location := err.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		return []string{location + ": " + err.Message}
+	}
+	var violations []string
+	for _, cause := range err.Causes {
+		violations = append(violations, schemaViolations(cause)...)
+	}
+	return violations
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/storage"
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// onePixelPNG is a valid 1x1 transparent PNG.
+const onePixelPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAAC0lEQVR4nGNgAAIAAAUAAXpeqz8AAAAASUVORK5CYII="
+
+type fakeProfileRepo struct {
+	profiles map[uuid.UUID]*userDomain.UserProfile
+}
+
+func (f *fakeProfileRepo) GetProfileByUserID(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	if p, ok := f.profiles[userID]; ok {
+		clone := *p
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeProfileRepo) GetProfileByUserIDForUpdate(ctx context.Context, userID uuid.UUID) (*userDomain.UserProfile, error) {
+	return f.GetProfileByUserID(ctx, userID)
+}
+
+func (f *fakeProfileRepo) CreateProfile(ctx context.Context, payload *userDomain.UserProfile) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.profiles[payload.UserID] = &clone
+	return nil
+}
+
+func (f *fakeProfileRepo) UpdateProfile(ctx context.Context, payload *userDomain.UserProfile) error {
+	clone := *payload
+	f.profiles[payload.UserID] = &clone
+	return nil
+}
+
+func newTestProfileService(t *testing.T) (*ProfileServiceImpl, *fakeProfileRepo, string) {
+	dir := t.TempDir()
+	repo := &fakeProfileRepo{profiles: map[uuid.UUID]*userDomain.UserProfile{}}
+	service := NewProfileService(
+		repo,
+		storage.NewLocalStorage(dir, "/uploads"),
+		transactorstest.Transactor{},
+		DefaultProfileServiceConfig,
+	).(*ProfileServiceImpl)
+	service.now = func() time.Time { return time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC) }
+	return service, repo, dir
+}
+
+func TestUpdateProfileAppliesMergePatch(t *testing.T) {
+	service, _, _ := newTestProfileService(t)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	profile, err := service.UpdateProfile(ctx, userID, []byte(`{
+		"preferences": {"theme": "dark", "language": "th", "notifications": {"email": true, "sms": false}},
+		"social_media_links": {"github": "https://github.com/example"},
+		"date_of_birth": "1990-02-03"
+	}`))
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if profile.ID == uuid.Nil || profile.DateOfBirth == nil || profile.DateOfBirth.Format("2006-01-02") != "1990-02-03" {
+		t.Fatalf("profile = %+v", profile)
+	}
+
+	profile, err = service.UpdateProfile(ctx, userID, []byte(`{
+		"preferences": {"theme": "light", "language": null, "notifications": {"sms": true}},
+		"date_of_birth": null
+	}`))
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if profile.Preferences["theme"] != "light" {
+		t.Fatalf("theme = %v, want light", profile.Preferences["theme"])
+	}
+	if _, ok := profile.Preferences["language"]; ok {
+		t.Fatal("null should remove the language preference")
+	}
+	notifications := profile.Preferences["notifications"].(map[string]interface{})
+	if notifications["email"] != true || notifications["sms"] != true {
+		t.Fatalf("notifications = %v, want email kept and sms updated", notifications)
+	}
+	if profile.SocialMediaLinks["github"] != "https://github.com/example" {
+		t.Fatal("untouched social media links should be kept")
+	}
+	if profile.DateOfBirth != nil {
+		t.Fatal("null should clear the date of birth")
+	}
+}
+
+func TestUpdateProfileRejectsInvalidDocuments(t *testing.T) {
+	service, repo, _ := newTestProfileService(t)
+	userID := uuid.New()
+
+	cases := []struct {
+		patch string
+		want  error
+	}{
+		{`["theme"]`, ErrInvalidProfilePatch},
+		{`{"preferences":`, ErrInvalidProfilePatch},
+		{`{"profile_picture": "https://example.com/me.png"}`, ErrProfileFieldNotPatchable},
+		{`{"preferences": {"theme": "neon"}}`, ErrInvalidPreferences},
+		{`{"preferences": {"favourite_colour": "blue"}}`, ErrInvalidPreferences},
+		{`{"preferences": "dark"}`, ErrInvalidPreferences},
+		{`{"social_media_links": {"github": "javascript:alert(1)"}}`, ErrInvalidSocialMediaLinks},
+		{`{"date_of_birth": "2030-01-01"}`, ErrInvalidDateOfBirth},
+		{`{"date_of_birth": "03/02/1990"}`, ErrInvalidDateOfBirth},
+	}
+	for _, tc := range cases {
+		if _, err := service.UpdateProfile(context.Background(), userID, []byte(tc.patch)); !errors.Is(err, tc.want) {
+			t.Errorf("patch %s: got %v, want %v", tc.patch, err, tc.want)
+		}
+	}
+	if len(repo.profiles) != 0 {
+		t.Fatal("invalid patches must not save a profile")
+	}
+}
+
+func TestUploadAvatarReplacesPreviousFile(t *testing.T) {
+	service, _, dir := newTestProfileService(t)
+	userID := uuid.New()
+	ctx := context.Background()
+
+	first, err := service.UploadAvatar(ctx, userID, "data:image/png;base64,"+onePixelPNG)
+	if err != nil {
+		t.Fatalf("UploadAvatar: %v", err)
+	}
+	firstKey := first.ProfilePictureKey
+	if !strings.HasPrefix(first.ProfilePicture, "/uploads/avatars/"+userID.String()+"/") || !strings.HasSuffix(firstKey, ".png") {
+		t.Fatalf("profile picture = %q, key = %q", first.ProfilePicture, firstKey)
+	}
+	if _, err := os.Stat(filepath.Join(dir, firstKey)); err != nil {
+		t.Fatalf("uploaded file: %v", err)
+	}
+
+	second, err := service.UploadAvatar(ctx, userID, "data:image/png;base64,"+onePixelPNG)
+	if err != nil {
+		t.Fatalf("UploadAvatar: %v", err)
+	}
+	if second.ProfilePictureKey == firstKey {
+		t.Fatal("each upload should get a new key")
+	}
+	if _, err := os.Stat(filepath.Join(dir, firstKey)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("previous avatar should be deleted, stat: %v", err)
+	}
+
+	removed, err := service.DeleteAvatar(ctx, userID)
+	if err != nil {
+		t.Fatalf("DeleteAvatar: %v", err)
+	}
+	if removed.ProfilePicture != "" {
+		t.Fatal("profile picture should be cleared")
+	}
+	if _, err := os.Stat(filepath.Join(dir, second.ProfilePictureKey)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("avatar should be deleted, stat: %v", err)
+	}
+}
+
+func TestUploadAvatarRejectsInvalidImages(t *testing.T) {
+	service, _, _ := newTestProfileService(t)
+	service.config.MaxAvatarBytes = 128
+
+	cases := []struct {
+		dataURI string
+		want    error
+	}{
+		{"https://example.com/me.png", ErrInvalidAvatar},
+		{"data:image/svg+xml;base64,PHN2Zy8+", ErrInvalidAvatar},
+		{"data:image/jpeg;base64," + onePixelPNG, ErrInvalidAvatar},
+		{"data:image/png;base64,not base64!", ErrInvalidAvatar},
+		{"data:image/png;base64," + strings.Repeat("A", 400), ErrAvatarTooLarge},
+	}
+	for _, tc := range cases {
+		if _, err := service.UploadAvatar(context.Background(), uuid.New(), tc.dataURI); !errors.Is(err, tc.want) {
+			t.Errorf("UploadAvatar(%.40s): got %v, want %v", tc.dataURI, err, tc.want)
+		}
+	}
+}
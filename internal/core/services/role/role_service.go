@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrRoleAlreadyExists        = errors.New("role name is already in use")
+	ErrRoleInUse                = errors.New("role is still assigned to users")
+	ErrPermissionNotFound       = errors.New("permission not found")
+	ErrPermissionAlreadyGranted = errors.New("permission is already granted to the role")
+)
+
+// DefaultPermissionCacheTTL bounds how long a role's permissions are served
+// from memory. Changes made through this service invalidate the cache at
+// once; the TTL only matters for changes made by other instances.
+const DefaultPermissionCacheTTL = 5 * time.Minute
+
+type permissionCacheEntry struct {
+	permissions []userDomain.UserPermission
+	expiresAt   time.Time
+}
+
+type RoleServiceImpl struct {
+	roleRepo   ports.IRoleRepository
+	userRepo   userPorts.IUserRepository
+	transactor transactors.IDatabaseTransactor
+	cacheTTL   time.Duration
+
+	mu    sync.RWMutex
+	cache map[uuid.UUID]permissionCacheEntry
+	// generations counts the invalidations of each role, so that a list read
+	// before an invalidation is not cached after it.
+	generations map[uuid.UUID]uint64
+}
+
+func NewRoleService(
+	roleRepo ports.IRoleRepository,
+	userRepo userPorts.IUserRepository,
+	transactor transactors.IDatabaseTransactor,
+	cacheTTL time.Duration,
+) ports.IRoleService {
+	return &RoleServiceImpl{
+		roleRepo:    roleRepo,
+		userRepo:    userRepo,
+		transactor:  transactor,
+		cacheTTL:    cacheTTL,
+		cache:       make(map[uuid.UUID]permissionCacheEntry),
+		generations: make(map[uuid.UUID]uint64),
+	}
+}
+
+// ListRoles implements ports.IRoleService.
+func (s *RoleServiceImpl) ListRoles(ctx context.Context) ([]userDomain.UserRole, error) {
+	return s.roleRepo.ListRoles(ctx)
+}
+
+// GetRole implements ports.IRoleService.
+func (s *RoleServiceImpl) GetRole(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error) {
+	role, err := s.roleRepo.GetRoleByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+// CreateRole implements ports.IRoleService.
+func (s *RoleServiceImpl) CreateRole(ctx context.Context, payload userDomain.UpsertRoleDomain) (*userDomain.UserRole, error) {
+	role := &userDomain.UserRole{
+		RoleName:    normalizeName(payload.RoleName),
+		Description: payload.Description,
+	}
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.ensureRoleNameAvailable(txCtx, role.RoleName, uuid.Nil); err != nil {
+			return err
+		}
+		return s.roleRepo.CreateRole(txCtx, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// UpdateRole implements ports.IRoleService.
+func (s *RoleServiceImpl) UpdateRole(ctx context.Context, id uuid.UUID, payload userDomain.UpsertRoleDomain) (*userDomain.UserRole, error) {
+	var role *userDomain.UserRole
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if role, err = s.GetRole(txCtx, id); err != nil {
+			return err
+		}
+		role.RoleName = normalizeName(payload.RoleName)
+		role.Description = payload.Description
+		if err := s.ensureRoleNameAvailable(txCtx, role.RoleName, id); err != nil {
+			return err
+		}
+		return s.roleRepo.UpdateRole(txCtx, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+// DeleteRole implements ports.IRoleService.
+func (s *RoleServiceImpl) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.GetRole(txCtx, id); err != nil {
+			return err
+		}
+		count, err := s.roleRepo.CountUsersWithRole(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrRoleInUse
+		}
+		return s.roleRepo.DeleteRole(txCtx, id)
+	})
+	if err != nil {
+		return err
+	}
+	s.InvalidateRole(id)
+	return nil
+}
+
+// ListRolePermissions implements ports.IRoleService.
+func (s *RoleServiceImpl) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error) {
+	if _, err := s.GetRole(ctx, roleID); err != nil {
+		return nil, err
+	}
+	return s.roleRepo.ListRolePermissions(ctx, roleID)
+}
+
+// GrantPermission implements ports.IRoleService.
+func (s *RoleServiceImpl) GrantPermission(ctx context.Context, roleID uuid.UUID, payload userDomain.GrantPermissionDomain) (*userDomain.UserPermission, error) {
+	permission := &userDomain.UserPermission{
+		RoleID:         roleID,
+		Permission:     normalizeName(payload.Permission),
+		PermissionType: payload.PermissionType,
+		EffectiveDate:  time.Now(),
+		Description:    payload.Description,
+	}
+	if payload.EffectiveDate != nil {
+		permission.EffectiveDate = *payload.EffectiveDate
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		if _, err := s.GetRole(txCtx, roleID); err != nil {
+			return err
+		}
+		existing, err := s.roleRepo.ListRolePermissions(txCtx, roleID)
+		if err != nil {
+			return err
+		}
+		for _, p := range existing {
+			if p.Permission == permission.Permission {
+				return ErrPermissionAlreadyGranted
+			}
+		}
+		return s.roleRepo.CreatePermission(txCtx, permission)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.InvalidateRole(roleID)
+	return permission, nil
+}
+
+// RevokePermission implements ports.IRoleService.
+func (s *RoleServiceImpl) RevokePermission(ctx context.Context, roleID, permissionID uuid.UUID) error {
+	permission, err := s.roleRepo.GetPermissionByID(ctx, permissionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPermissionNotFound
+		}
+		return err
+	}
+	if permission.RoleID != roleID {
+		return ErrPermissionNotFound
+	}
+	if err := s.roleRepo.DeletePermission(ctx, permissionID); err != nil {
+		return err
+	}
+	s.InvalidateRole(roleID)
+	return nil
+}
+
+// HasPermission implements ports.IRoleService.
+//
+// The user's role is read on every call so that role reassignments apply to
+// existing sessions immediately; only the role's permission list is cached.
+func (s *RoleServiceImpl) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if user.RoleID == nil {
+		return false, nil
+	}
+
+	permissions, err := s.rolePermissions(ctx, *user.RoleID)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, p := range permissions {
+		if p.Permission == permission && p.IsEffective(now) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// InvalidateRole implements ports.IRoleService.
+func (s *RoleServiceImpl) InvalidateRole(roleID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, roleID)
+	s.generations[roleID]++
+}
+
+func (s *RoleServiceImpl) rolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error) {
+	s.mu.RLock()
+	entry, ok := s.cache[roleID]
+	generation := s.generations[roleID]
+	s.mu.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.permissions, nil
+	}
+
+	permissions, err := s.roleRepo.ListRolePermissions(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// An invalidation during the read may have removed a permission the list
+	// still holds; it is then used for this check only.
+	s.mu.Lock()
+	if s.generations[roleID] == generation {
+		s.cache[roleID] = permissionCacheEntry{permissions: permissions, expiresAt: time.Now().Add(s.cacheTTL)}
+	}
+	s.mu.Unlock()
+	return permissions, nil
+}
+
+func (s *RoleServiceImpl) ensureRoleNameAvailable(ctx context.Context, name string, currentID uuid.UUID) error {
+	existing, err := s.roleRepo.GetRoleByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != currentID {
+		return ErrRoleAlreadyExists
+	}
+	return nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
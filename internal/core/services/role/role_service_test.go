@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeRoleRepo struct {
+	roles       map[uuid.UUID]*userDomain.UserRole
+	permissions map[uuid.UUID]*userDomain.UserPermission
+	listCalls   int
+	// afterList, when set, runs once ListRolePermissions has read the
+	// permissions and before it returns them.
+	afterList func()
+}
+
+func newFakeRoleRepo() *fakeRoleRepo {
+	return &fakeRoleRepo{
+		roles:       map[uuid.UUID]*userDomain.UserRole{},
+		permissions: map[uuid.UUID]*userDomain.UserPermission{},
+	}
+}
+
+func (f *fakeRoleRepo) ListRoles(ctx context.Context) ([]userDomain.UserRole, error) {
+	var roles []userDomain.UserRole
+	for _, r := range f.roles {
+		roles = append(roles, *r)
+	}
+	return roles, nil
+}
+
+func (f *fakeRoleRepo) GetRoleByID(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error) {
+	if r, ok := f.roles[id]; ok {
+		clone := *r
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) GetRoleByName(ctx context.Context, name string) (*userDomain.UserRole, error) {
+	for _, r := range f.roles {
+		if r.RoleName == name {
+			clone := *r
+			return &clone, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) CreateRole(ctx context.Context, payload *userDomain.UserRole) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.roles[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeRoleRepo) UpdateRole(ctx context.Context, payload *userDomain.UserRole) error {
+	clone := *payload
+	f.roles[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeRoleRepo) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	delete(f.roles, id)
+	return nil
+}
+
+func (f *fakeRoleRepo) CountUsersWithRole(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeRoleRepo) ListRolePermissions(ctx context.Context, roleID uuid.UUID) ([]userDomain.UserPermission, error) {
+	f.listCalls++
+	var permissions []userDomain.UserPermission
+	for _, p := range f.permissions {
+		if p.RoleID == roleID {
+			permissions = append(permissions, *p)
+		}
+	}
+	if f.afterList != nil {
+		f.afterList()
+	}
+	return permissions, nil
+}
+
+func (f *fakeRoleRepo) GetPermissionByID(ctx context.Context, id uuid.UUID) (*userDomain.UserPermission, error) {
+	if p, ok := f.permissions[id]; ok {
+		clone := *p
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeRoleRepo) CreatePermission(ctx context.Context, payload *userDomain.UserPermission) error {
+	payload.ID = uuid.New()
+	clone := *payload
+	f.permissions[payload.ID] = &clone
+	return nil
+}
+
+func (f *fakeRoleRepo) DeletePermission(ctx context.Context, id uuid.UUID) error {
+	delete(f.permissions, id)
+	return nil
+}
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if u, ok := f.users[id]; ok {
+		clone := *u
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestHasPermission(t *testing.T) {
+	ctx := context.Background()
+	roleRepo := newFakeRoleRepo()
+	userRepo := &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}}
+	svc := NewRoleService(roleRepo, userRepo, transactorstest.Transactor{}, time.Hour)
+
+	role, err := svc.CreateRole(ctx, userDomain.UpsertRoleDomain{RoleName: "seller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role.RoleName != "SELLER" {
+		t.Errorf("expected role name to be normalised, got %q", role.RoleName)
+	}
+
+	userID := uuid.New()
+	userRepo.users[userID] = &userDomain.User{RoleID: &role.ID}
+
+	allowed, err := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Fatal("expected permission to be denied before it is granted")
+	}
+
+	// Granting invalidates the cached (empty) permission list.
+	grant, err := svc.GrantPermission(ctx, role.ID, userDomain.GrantPermissionDomain{
+		Permission:     userDomain.PERMISSION_CREATE_PRODUCT,
+		PermissionType: "Write",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed, _ := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT); !allowed {
+		t.Fatal("expected permission to be allowed after grant")
+	}
+
+	calls := roleRepo.listCalls
+	if _, err := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT); err != nil {
+		t.Fatal(err)
+	}
+	if roleRepo.listCalls != calls {
+		t.Error("expected permissions to be served from the cache")
+	}
+
+	if _, err := svc.GrantPermission(ctx, role.ID, userDomain.GrantPermissionDomain{
+		Permission:     userDomain.PERMISSION_CREATE_PRODUCT,
+		PermissionType: "Write",
+	}); !errors.Is(err, ErrPermissionAlreadyGranted) {
+		t.Errorf("expected ErrPermissionAlreadyGranted, got %v", err)
+	}
+
+	if err := svc.RevokePermission(ctx, role.ID, grant.ID); err != nil {
+		t.Fatal(err)
+	}
+	if allowed, _ := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT); allowed {
+		t.Fatal("expected permission to be denied after revoke")
+	}
+}
+
+func TestHasPermissionHonoursEffectiveDate(t *testing.T) {
+	ctx := context.Background()
+	roleRepo := newFakeRoleRepo()
+	userRepo := &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}}
+	svc := NewRoleService(roleRepo, userRepo, transactorstest.Transactor{}, time.Hour)
+
+	role, err := svc.CreateRole(ctx, userDomain.UpsertRoleDomain{RoleName: "SELLER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	userRepo.users[userID] = &userDomain.User{RoleID: &role.ID}
+
+	tomorrow := time.Now().Add(24 * time.Hour)
+	if _, err := svc.GrantPermission(ctx, role.ID, userDomain.GrantPermissionDomain{
+		Permission:     userDomain.PERMISSION_VIEW_ORDERS,
+		PermissionType: "Read",
+		EffectiveDate:  &tomorrow,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if allowed, _ := svc.HasPermission(ctx, userID, userDomain.PERMISSION_VIEW_ORDERS); allowed {
+		t.Error("expected a permission that is not yet effective to be denied")
+	}
+}
+
+// TestRevokeDuringCacheFill revokes a permission while HasPermission is
+// reading the role's permissions, and checks that the list read before the
+// revoke is not cached.
+func TestRevokeDuringCacheFill(t *testing.T) {
+	ctx := context.Background()
+	roleRepo := newFakeRoleRepo()
+	userRepo := &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}}
+	svc := NewRoleService(roleRepo, userRepo, transactorstest.Transactor{}, time.Hour)
+
+	role, err := svc.CreateRole(ctx, userDomain.UpsertRoleDomain{RoleName: "SELLER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	userID := uuid.New()
+	userRepo.users[userID] = &userDomain.User{RoleID: &role.ID}
+	grant, err := svc.GrantPermission(ctx, role.ID, userDomain.GrantPermissionDomain{
+		Permission:     userDomain.PERMISSION_CREATE_PRODUCT,
+		PermissionType: "Write",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roleRepo.afterList = func() {
+		roleRepo.afterList = nil
+		if err := svc.RevokePermission(ctx, role.ID, grant.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT); err != nil {
+		t.Fatal(err)
+	}
+	if allowed, _ := svc.HasPermission(ctx, userID, userDomain.PERMISSION_CREATE_PRODUCT); allowed {
+		t.Fatal("expected the revoke to win over the fill it interleaved with")
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	authPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	ports "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	roleServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/role"
+	"github.com/billowdev/go-fiber-e-commerce/pkg/helpers/pagination"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrUserErased         = errors.New("the personal data of this user has been erased")
+	ErrCannotChangeSelf   = errors.New("administrators cannot change their own status or role")
+	ErrStatusUnchanged    = errors.New("the user already has this status")
+	ErrRoleUnchanged      = errors.New("the user already has this role")
+	ErrServiceAccountRole = errors.New("the service account role is managed through the service account endpoints")
+	ErrServiceAccount     = errors.New("service accounts are managed through the service account endpoints")
+)
+
+// Actions recorded in the change log.
+const (
+	auditEntityUser     = "User"
+	auditFieldStatus    = "status"
+	auditFieldRole      = "role_id"
+	auditFieldSessions  = "sessions"
+	actionStatusChange  = "STATUS_CHANGE"
+	actionRoleChange    = "ROLE_CHANGE"
+	actionForceLogout   = "FORCE_LOGOUT"
+	sessionsRevokedNote = "revoked"
+)
+
+type UserAdminServiceImpl struct {
+	userRepo         ports.IUserRepository
+	roleService      rolePorts.IRoleService
+	refreshTokenRepo authPorts.IRefreshTokenRepository
+	changeLogRepo    auditPorts.IChangeLogRepository
+	transactor       transactors.IDatabaseTransactor
+	now              func() time.Time
+}
+
+func NewUserAdminService(
+	userRepo ports.IUserRepository,
+	roleService rolePorts.IRoleService,
+	refreshTokenRepo authPorts.IRefreshTokenRepository,
+	changeLogRepo auditPorts.IChangeLogRepository,
+	transactor transactors.IDatabaseTransactor,
+) ports.IUserAdminService {
+	return &UserAdminServiceImpl{
+		userRepo:         userRepo,
+		roleService:      roleService,
+		refreshTokenRepo: refreshTokenRepo,
+		changeLogRepo:    changeLogRepo,
+		transactor:       transactor,
+		now:              time.Now,
+	}
+}
+
+// ListUsers implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) ListUsers(ctx context.Context) (pagination.Pagination[[]userDomain.User], error) {
+	return s.userRepo.ListUsers(ctx)
+}
+
+// GetUser implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) GetUser(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ChangeStatus implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) ChangeStatus(ctx context.Context, id, actorID uuid.UUID, payload userDomain.ChangeUserStatusDomain) (*userDomain.User, error) {
+	var user *userDomain.User
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if user, err = s.lockManagedUser(txCtx, id, actorID); err != nil {
+			return err
+		}
+		if user.Status == payload.Status {
+			return ErrStatusUnchanged
+		}
+
+		old := user.Status
+		user.Status = payload.Status
+		if err := s.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		if payload.Status != userDomain.USER_STATUS_ACTIVE {
+			if err := s.refreshTokenRepo.RevokeUserRefreshTokens(txCtx, user.ID); err != nil {
+				return err
+			}
+		}
+		return s.audit(txCtx, user.ID, actorID, auditFieldStatus, string(old), string(payload.Status), actionStatusChange, payload.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// AssignRole implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) AssignRole(ctx context.Context, id, actorID uuid.UUID, payload userDomain.AssignUserRoleDomain) (*userDomain.User, error) {
+	var user *userDomain.User
+	err := s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		role, err := s.roleService.GetRole(txCtx, payload.RoleID)
+		if err != nil {
+			return err
+		}
+		if role.RoleName == userDomain.ROLE_SERVICE_ACCOUNT {
+			return ErrServiceAccountRole
+		}
+		if user, err = s.lockManagedUser(txCtx, id, actorID); err != nil {
+			return err
+		}
+		if user.RoleID != nil && *user.RoleID == role.ID {
+			return ErrRoleUnchanged
+		}
+
+		old := ""
+		if user.RoleID != nil {
+			old = user.RoleID.String()
+		}
+		user.RoleID = &role.ID
+		user.Role = nil
+		if err := s.userRepo.UpdateUser(txCtx, user); err != nil {
+			return err
+		}
+		return s.audit(txCtx, user.ID, actorID, auditFieldRole, old, role.ID.String(), actionRoleChange, payload.Reason)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// ForceLogout implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) ForceLogout(ctx context.Context, id, actorID uuid.UUID, payload userDomain.ForceLogoutDomain) error {
+	return s.transactor.WithinTransaction(ctx, func(txCtx context.Context) error {
+		user, err := s.lockUser(txCtx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.refreshTokenRepo.RevokeUserRefreshTokens(txCtx, user.ID); err != nil {
+			return err
+		}
+		return s.audit(txCtx, user.ID, actorID, auditFieldSessions, "", sessionsRevokedNote, actionForceLogout, payload.Reason)
+	})
+}
+
+// ListUserHistory implements ports.IUserAdminService.
+func (s *UserAdminServiceImpl) ListUserHistory(ctx context.Context, id uuid.UUID) (pagination.Pagination[[]loggingDomain.ChangeLogs], error) {
+	if _, err := s.GetUser(ctx, id); err != nil {
+		return pagination.Pagination[[]loggingDomain.ChangeLogs]{}, err
+	}
+	return s.changeLogRepo.ListEntityChangeLogs(ctx, auditEntityUser, id)
+}
+
+// lockUser loads the user under a row lock.
+func (s *UserAdminServiceImpl) lockUser(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	user, err := s.userRepo.GetUserByIDForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// lockManagedUser loads a user whose status or role the actor may change:
+// not the actor, not an erased account and not a service account.
+func (s *UserAdminServiceImpl) lockManagedUser(ctx context.Context, id, actorID uuid.UUID) (*userDomain.User, error) {
+	if id == actorID {
+		return nil, ErrCannotChangeSelf
+	}
+	user, err := s.lockUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == userDomain.USER_STATUS_ERASED {
+		return nil, ErrUserErased
+	}
+	if user.RoleID != nil {
+		role, err := s.roleService.GetRole(ctx, *user.RoleID)
+		if err != nil && !errors.Is(err, roleServices.ErrRoleNotFound) {
+			return nil, err
+		}
+		if role != nil && role.RoleName == userDomain.ROLE_SERVICE_ACCOUNT {
+			return nil, ErrServiceAccount
+		}
+	}
+	return user, nil
+}
+
+func (s *UserAdminServiceImpl) audit(ctx context.Context, userID, actorID uuid.UUID, field, oldValue, newValue, action, reason string) error {
+	entry := loggingDomain.NewChangeLog(auditEntityUser, userID, field, oldValue, newValue, action, actorID, reason)
+	entry.Timestamp = s.now()
+	return s.changeLogRepo.CreateChangeLog(ctx, entry)
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/billowdev/go-fiber-e-commerce/internal/adapters/transactors/transactorstest"
+	userDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/core/user"
+	loggingDomain "github.com/billowdev/go-fiber-e-commerce/internal/core/domain/logging"
+	auditPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/audit"
+	authPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/auth"
+	rolePorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/role"
+	userPorts "github.com/billowdev/go-fiber-e-commerce/internal/core/ports/user"
+	roleServices "github.com/billowdev/go-fiber-e-commerce/internal/core/services/role"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeUserRepo struct {
+	userPorts.IUserRepository
+	users map[uuid.UUID]*userDomain.User
+}
+
+func (f *fakeUserRepo) GetUserByID(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	if user, ok := f.users[id]; ok {
+		clone := *user
+		return &clone, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUserRepo) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (*userDomain.User, error) {
+	return f.GetUserByID(ctx, id)
+}
+
+func (f *fakeUserRepo) UpdateUser(ctx context.Context, payload *userDomain.User) error {
+	clone := *payload
+	f.users[payload.ID] = &clone
+	return nil
+}
+
+type fakeRoleService struct {
+	rolePorts.IRoleService
+	roles map[uuid.UUID]*userDomain.UserRole
+}
+
+func (f *fakeRoleService) GetRole(ctx context.Context, id uuid.UUID) (*userDomain.UserRole, error) {
+	if role, ok := f.roles[id]; ok {
+		return role, nil
+	}
+	return nil, roleServices.ErrRoleNotFound
+}
+
+type fakeRefreshTokenRepo struct {
+	authPorts.IRefreshTokenRepository
+	revoked []uuid.UUID
+}
+
+func (f *fakeRefreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	f.revoked = append(f.revoked, userID)
+	return nil
+}
+
+type fakeChangeLogRepo struct {
+	auditPorts.IChangeLogRepository
+	entries []loggingDomain.ChangeLogs
+}
+
+func (f *fakeChangeLogRepo) CreateChangeLog(ctx context.Context, payload *loggingDomain.ChangeLogs) error {
+	f.entries = append(f.entries, *payload)
+	return nil
+}
+
+type userAdminFixture struct {
+	service  *UserAdminServiceImpl
+	users    *fakeUserRepo
+	tokens   *fakeRefreshTokenRepo
+	logs     *fakeChangeLogRepo
+	adminID  uuid.UUID
+	userID   uuid.UUID
+	sellerID uuid.UUID
+	robotID  uuid.UUID
+}
+
+func newUserAdminFixture() *userAdminFixture {
+	customer := &userDomain.UserRole{RoleName: userDomain.ROLE_CUSTOMER}
+	seller := &userDomain.UserRole{RoleName: userDomain.ROLE_SELLER}
+	robot := &userDomain.UserRole{RoleName: userDomain.ROLE_SERVICE_ACCOUNT}
+	for _, role := range []*userDomain.UserRole{customer, seller, robot} {
+		role.ID = uuid.New()
+	}
+
+	f := &userAdminFixture{
+		users:    &fakeUserRepo{users: map[uuid.UUID]*userDomain.User{}},
+		tokens:   &fakeRefreshTokenRepo{},
+		logs:     &fakeChangeLogRepo{},
+		adminID:  uuid.New(),
+		userID:   uuid.New(),
+		sellerID: seller.ID,
+		robotID:  robot.ID,
+	}
+	user := &userDomain.User{Username: "somchai", Password: "hash", Status: userDomain.USER_STATUS_ACTIVE, RoleID: &customer.ID}
+	user.ID = f.userID
+	f.users.users[f.userID] = user
+
+	roles := &fakeRoleService{roles: map[uuid.UUID]*userDomain.UserRole{customer.ID: customer, seller.ID: seller, robot.ID: robot}}
+	f.service = NewUserAdminService(f.users, roles, f.tokens, f.logs, transactorstest.Transactor{}).(*UserAdminServiceImpl)
+	return f
+}
+
+func TestChangeStatus(t *testing.T) {
+	f := newUserAdminFixture()
+	ctx := context.Background()
+
+	user, err := f.service.ChangeStatus(ctx, f.userID, f.adminID, userDomain.ChangeUserStatusDomain{Status: userDomain.USER_STATUS_BANNED, Reason: "fraudulent chargebacks"})
+	if err != nil {
+		t.Fatalf("ChangeStatus: %v", err)
+	}
+	if user.Status != userDomain.USER_STATUS_BANNED || user.Password != "hash" {
+		t.Fatalf("user = %+v", user)
+	}
+	if len(f.tokens.revoked) != 1 || f.tokens.revoked[0] != f.userID {
+		t.Fatalf("banning should revoke the sessions, revoked = %v", f.tokens.revoked)
+	}
+	entry := f.logs.entries[0]
+	if entry.Action != actionStatusChange || entry.OldValue != "active" || entry.NewValue != "banned" ||
+		entry.UserID != f.adminID || entry.EntityID != f.userID || entry.Description != "fraudulent chargebacks" {
+		t.Fatalf("audit entry = %+v", entry)
+	}
+
+	if _, err := f.service.ChangeStatus(ctx, f.userID, f.adminID, userDomain.ChangeUserStatusDomain{Status: userDomain.USER_STATUS_BANNED, Reason: "again"}); !errors.Is(err, ErrStatusUnchanged) {
+		t.Fatalf("got %v, want ErrStatusUnchanged", err)
+	}
+	if _, err := f.service.ChangeStatus(ctx, f.userID, f.adminID, userDomain.ChangeUserStatusDomain{Status: userDomain.USER_STATUS_ACTIVE, Reason: "appeal accepted"}); err != nil {
+		t.Fatalf("reactivate: %v", err)
+	}
+	if len(f.tokens.revoked) != 1 {
+		t.Fatal("reactivating should not revoke sessions")
+	}
+	if _, err := f.service.ChangeStatus(ctx, f.adminID, f.adminID, userDomain.ChangeUserStatusDomain{Status: userDomain.USER_STATUS_INACTIVE, Reason: "oops"}); !errors.Is(err, ErrCannotChangeSelf) {
+		t.Fatalf("got %v, want ErrCannotChangeSelf", err)
+	}
+}
+
+func TestChangeStatusRefusesErasedUsers(t *testing.T) {
+	f := newUserAdminFixture()
+	f.users.users[f.userID].Status = userDomain.USER_STATUS_ERASED
+
+	_, err := f.service.ChangeStatus(context.Background(), f.userID, f.adminID, userDomain.ChangeUserStatusDomain{Status: userDomain.USER_STATUS_ACTIVE, Reason: "restore"})
+	if !errors.Is(err, ErrUserErased) {
+		t.Fatalf("got %v, want ErrUserErased", err)
+	}
+	if len(f.logs.entries) != 0 {
+		t.Fatal("nothing should be logged")
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	f := newUserAdminFixture()
+	ctx := context.Background()
+	oldRole := f.users.users[f.userID].RoleID.String()
+
+	user, err := f.service.AssignRole(ctx, f.userID, f.adminID, userDomain.AssignUserRoleDomain{RoleID: f.sellerID, Reason: "opened a shop"})
+	if err != nil {
+		t.Fatalf("AssignRole: %v", err)
+	}
+	if *user.RoleID != f.sellerID || *f.users.users[f.userID].RoleID != f.sellerID {
+		t.Fatalf("role was not changed: %+v", user)
+	}
+	entry := f.logs.entries[0]
+	if entry.Action != actionRoleChange || entry.OldValue != oldRole || entry.NewValue != f.sellerID.String() {
+		t.Fatalf("audit entry = %+v", entry)
+	}
+
+	if _, err := f.service.AssignRole(ctx, f.userID, f.adminID, userDomain.AssignUserRoleDomain{RoleID: f.sellerID, Reason: "again"}); !errors.Is(err, ErrRoleUnchanged) {
+		t.Fatalf("got %v, want ErrRoleUnchanged", err)
+	}
+	if _, err := f.service.AssignRole(ctx, f.userID, f.adminID, userDomain.AssignUserRoleDomain{RoleID: f.robotID, Reason: "automation"}); !errors.Is(err, ErrServiceAccountRole) {
+		t.Fatalf("got %v, want ErrServiceAccountRole", err)
+	}
+	if _, err := f.service.AssignRole(ctx, f.userID, f.adminID, userDomain.AssignUserRoleDomain{RoleID: uuid.New(), Reason: "typo"}); !errors.Is(err, roleServices.ErrRoleNotFound) {
+		t.Fatalf("got %v, want ErrRoleNotFound", err)
+	}
+}
+
+func TestForceLogout(t *testing.T) {
+	f := newUserAdminFixture()
+	ctx := context.Background()
+
+	if err := f.service.ForceLogout(ctx, f.userID, f.adminID, userDomain.ForceLogoutDomain{Reason: "lost phone"}); err != nil {
+		t.Fatalf("ForceLogout: %v", err)
+	}
+	if len(f.tokens.revoked) != 1 || len(f.logs.entries) != 1 || f.logs.entries[0].Action != actionForceLogout {
+		t.Fatalf("revoked = %v, audit = %+v", f.tokens.revoked, f.logs.entries)
+	}
+	if err := f.service.ForceLogout(ctx, uuid.New(), f.adminID, userDomain.ForceLogoutDomain{Reason: "lost phone"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("got %v, want ErrUserNotFound", err)
+	}
+}
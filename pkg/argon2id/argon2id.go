@@ -6,7 +6,6 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"runtime"
 	"strings"
 
 	"golang.org/x/crypto/argon2"
@@ -37,10 +36,13 @@ var (
 // The default parameters should generally be used for development/testing purposes
 // only. Custom parameters should be set for production applications depending on
 // available memory/CPU resources and business requirements.
+//
+// Parallelism is fixed rather than taken from the CPU count so that every
+// machine produces hashes with the same parameters.
 var DefaultParams = &Params{
 	Memory:      64 * 1024,
 	Iterations:  1,
-	Parallelism: uint8(runtime.NumCPU()),
+	Parallelism: 2,
 	SaltLength:  16,
 	KeyLength:   32,
 }
@@ -75,6 +77,16 @@ type Params struct {
 	KeyLength uint32
 }
 
+// IsWeakerThan reports whether hashes made with p are cheaper to attack than
+// hashes made with target, meaning they should be recomputed with target.
+// Parallelism only spreads the same work over more lanes, so it is ignored.
+func (p *Params) IsWeakerThan(target *Params) bool {
+	return p.Memory < target.Memory ||
+		p.Iterations < target.Iterations ||
+		p.SaltLength < target.SaltLength ||
+		p.KeyLength < target.KeyLength
+}
+
 // CreateHash returns a Argon2id hash of a plain-text password using the
 // provided algorithm parameters. The returned hash follows the format used by
 // the Argon2 reference C implementation and contains the base64-encoded Argon2id d
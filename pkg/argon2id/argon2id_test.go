@@ -116,4 +116,27 @@ func TestVariant(t *testing.T) {
 	if err != ErrIncompatibleVariant {
 		t.Fatalf("expected error %s", ErrIncompatibleVariant)
 	}
-}
\ No newline at end of file
+}
+
+func TestIsWeakerThan(t *testing.T) {
+	target := &Params{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 16, KeyLength: 32}
+
+	_, params, err := CheckHash("bug", "$argon2id$v=19$m=65536,t=1,p=2$UDk0zEuIzbt0x3bwkf8Bgw$ihSfHWUJpTgDvNWiojrgcN4E0pJdUVmqCEdRZesx9tE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !params.IsWeakerThan(target) {
+		t.Fatal("t=1 should be weaker than t=3")
+	}
+
+	same := *target
+	same.Parallelism = 1
+	if same.IsWeakerThan(target) {
+		t.Fatal("a different parallelism alone should not require a rehash")
+	}
+	stronger := *target
+	stronger.Memory *= 2
+	if stronger.IsWeakerThan(target) {
+		t.Fatal("stronger params should not require a rehash")
+	}
+}
@@ -1,8 +1,11 @@
 package configs
 
 import (
+	"log"
 	"strconv"
 
+	"github.com/billowdev/go-fiber-e-commerce/pkg/argon2id"
+
 	"github.com/spf13/viper"
 )
 
@@ -26,6 +29,52 @@ var (
 	DB_SCHEMA        string
 
 	SERVER_HTTP_PORT string
+
+	JWT_SECRET        string
+	ACCESS_TOKEN_EXP  int // minutes
+	REFRESH_TOKEN_EXP int // minutes
+
+	IMPERSONATION_TOKEN_EXP int // minutes an impersonation token stays valid
+
+	ENCRYPTION_KEY string // Encrypts secrets stored in the database, e.g. TOTP seeds
+
+	OTP_SENDER        string // "log" (default) writes codes to the log, "live" delivers them by SMTP and SMS
+	SMTP_HOST         string
+	SMTP_PORT         string
+	SMTP_USERNAME     string
+	SMTP_PASSWORD     string
+	SMTP_FROM         string
+	SMS_GATEWAY_URL   string
+	SMS_GATEWAY_TOKEN string
+
+	LOCKOUT_STORE   string // "postgres" (default) shares login failure counters between instances, "memory" keeps them per process
+	PROXY_HEADER    string // Header carrying the client IP when running behind a proxy, e.g. X-Forwarded-For
+	TRUSTED_PROXIES string // Comma separated proxy IPs or CIDRs allowed to set PROXY_HEADER
+
+	ARGON2_MEMORY      int // KiB used per password hash
+	ARGON2_ITERATIONS  int
+	ARGON2_PARALLELISM int
+
+	STORAGE_DRIVER     string // "local" (default) keeps uploads on disk, "s3" in an S3-compatible bucket
+	STORAGE_LOCAL_DIR  string // Directory uploaded files are written to
+	STORAGE_PUBLIC_URL string // URL prefix of uploaded files; a path such as /uploads is served by the API itself
+
+	S3_ENDPOINT          string // e.g. https://s3.ap-southeast-1.amazonaws.com, or http://localhost:9000 for MinIO
+	S3_REGION            string
+	S3_BUCKET            string
+	S3_ACCESS_KEY_ID     string
+	S3_SECRET_ACCESS_KEY string
+	S3_PUBLIC_URL        string // URL prefix objects are fetched from, e.g. a CDN; defaults to the bucket URL
+	S3_PRIVATE_BUCKET    string // Bucket without public access for files only the API hands out, such as data exports
+
+	PRIVATE_STORAGE_LOCAL_DIR string // Directory files only the API hands out are written to with the local driver; it must not be served publicly
+
+	HTTP_BODY_LIMIT_MB int // Largest request body accepted, uploads included
+
+	PRIVACY_EXPORT_TTL_HOURS int // How long an export archive can be downloaded before it is deleted
+
+	INVENTORY_RESERVATION_TTL_MINUTES int    // How long checkout holds the stock of an unpaid order before releasing it
+	INVENTORY_ALLOCATION_STRATEGY     string // How checkout picks the warehouses an order ships from: priority, nearest or fewest_shipments
 )
 
 func init() {
@@ -58,4 +107,121 @@ func init() {
 		DB_RUN_SEEDER = false
 	}
 
+	JWT_SECRET = viper.GetString("JWT_SECRET")
+
+	ACCESS_TOKEN_EXP, err = strconv.Atoi(viper.GetString("ACCESS_TOKEN_EXP"))
+	if err != nil || ACCESS_TOKEN_EXP <= 0 {
+		ACCESS_TOKEN_EXP = 15
+	}
+
+	REFRESH_TOKEN_EXP, err = strconv.Atoi(viper.GetString("REFRESH_TOKEN_EXP"))
+	if err != nil || REFRESH_TOKEN_EXP <= 0 {
+		REFRESH_TOKEN_EXP = 43200
+	}
+
+	IMPERSONATION_TOKEN_EXP, err = strconv.Atoi(viper.GetString("IMPERSONATION_TOKEN_EXP"))
+	if err != nil || IMPERSONATION_TOKEN_EXP <= 0 {
+		IMPERSONATION_TOKEN_EXP = 15
+	}
+
+	ENCRYPTION_KEY = viper.GetString("ENCRYPTION_KEY")
+
+	OTP_SENDER = viper.GetString("OTP_SENDER")
+	if OTP_SENDER == "" {
+		OTP_SENDER = "log"
+	}
+	SMTP_HOST = viper.GetString("SMTP_HOST")
+	SMTP_PORT = viper.GetString("SMTP_PORT")
+	if SMTP_PORT == "" {
+		SMTP_PORT = "587"
+	}
+	SMTP_USERNAME = viper.GetString("SMTP_USERNAME")
+	SMTP_PASSWORD = viper.GetString("SMTP_PASSWORD")
+	SMTP_FROM = viper.GetString("SMTP_FROM")
+	SMS_GATEWAY_URL = viper.GetString("SMS_GATEWAY_URL")
+	SMS_GATEWAY_TOKEN = viper.GetString("SMS_GATEWAY_TOKEN")
+
+	LOCKOUT_STORE = viper.GetString("LOCKOUT_STORE")
+	if LOCKOUT_STORE == "" {
+		LOCKOUT_STORE = "postgres"
+	}
+	PROXY_HEADER = viper.GetString("PROXY_HEADER")
+	TRUSTED_PROXIES = viper.GetString("TRUSTED_PROXIES")
+
+	// Defaults follow the second recommended option of RFC 9106.
+	ARGON2_MEMORY, err = strconv.Atoi(viper.GetString("ARGON2_MEMORY"))
+	if err != nil || ARGON2_MEMORY <= 0 {
+		ARGON2_MEMORY = 64 * 1024
+	}
+	ARGON2_ITERATIONS, err = strconv.Atoi(viper.GetString("ARGON2_ITERATIONS"))
+	if err != nil || ARGON2_ITERATIONS <= 0 {
+		ARGON2_ITERATIONS = 3
+	}
+	ARGON2_PARALLELISM, err = strconv.Atoi(viper.GetString("ARGON2_PARALLELISM"))
+	if err != nil || ARGON2_PARALLELISM <= 0 || ARGON2_PARALLELISM > 255 {
+		ARGON2_PARALLELISM = 4
+	}
+	if ARGON2_MEMORY < 8*ARGON2_PARALLELISM {
+		log.Printf("ARGON2_MEMORY must be at least 8 KiB per lane, using %d", 8*ARGON2_PARALLELISM)
+		ARGON2_MEMORY = 8 * ARGON2_PARALLELISM
+	}
+
+	STORAGE_DRIVER = viper.GetString("STORAGE_DRIVER")
+	if STORAGE_DRIVER == "" {
+		STORAGE_DRIVER = "local"
+	}
+	STORAGE_LOCAL_DIR = viper.GetString("STORAGE_LOCAL_DIR")
+	if STORAGE_LOCAL_DIR == "" {
+		STORAGE_LOCAL_DIR = "./uploads"
+	}
+	STORAGE_PUBLIC_URL = viper.GetString("STORAGE_PUBLIC_URL")
+	if STORAGE_PUBLIC_URL == "" {
+		STORAGE_PUBLIC_URL = "/uploads"
+	}
+	S3_ENDPOINT = viper.GetString("S3_ENDPOINT")
+	S3_REGION = viper.GetString("S3_REGION")
+	if S3_REGION == "" {
+		S3_REGION = "us-east-1"
+	}
+	S3_BUCKET = viper.GetString("S3_BUCKET")
+	S3_ACCESS_KEY_ID = viper.GetString("S3_ACCESS_KEY_ID")
+	S3_SECRET_ACCESS_KEY = viper.GetString("S3_SECRET_ACCESS_KEY")
+	S3_PUBLIC_URL = viper.GetString("S3_PUBLIC_URL")
+	S3_PRIVATE_BUCKET = viper.GetString("S3_PRIVATE_BUCKET")
+	PRIVATE_STORAGE_LOCAL_DIR = viper.GetString("PRIVATE_STORAGE_LOCAL_DIR")
+	if PRIVATE_STORAGE_LOCAL_DIR == "" {
+		PRIVATE_STORAGE_LOCAL_DIR = "./private"
+	}
+
+	HTTP_BODY_LIMIT_MB, err = strconv.Atoi(viper.GetString("HTTP_BODY_LIMIT_MB"))
+	if err != nil || HTTP_BODY_LIMIT_MB <= 0 {
+		HTTP_BODY_LIMIT_MB = 16
+	}
+
+	PRIVACY_EXPORT_TTL_HOURS, err = strconv.Atoi(viper.GetString("PRIVACY_EXPORT_TTL_HOURS"))
+	if err != nil || PRIVACY_EXPORT_TTL_HOURS <= 0 {
+		PRIVACY_EXPORT_TTL_HOURS = 7 * 24
+	}
+
+	INVENTORY_RESERVATION_TTL_MINUTES, err = strconv.Atoi(viper.GetString("INVENTORY_RESERVATION_TTL_MINUTES"))
+	if err != nil || INVENTORY_RESERVATION_TTL_MINUTES <= 0 {
+		INVENTORY_RESERVATION_TTL_MINUTES = 15
+	}
+	INVENTORY_ALLOCATION_STRATEGY = viper.GetString("INVENTORY_ALLOCATION_STRATEGY")
+	if INVENTORY_ALLOCATION_STRATEGY == "" {
+		INVENTORY_ALLOCATION_STRATEGY = "priority"
+	}
+
+}
+
+// PasswordHashParams returns the argon2id parameters for new password hashes.
+// Existing hashes made with weaker parameters are upgraded on login.
+func PasswordHashParams() *argon2id.Params {
+	return &argon2id.Params{
+		Memory:      uint32(ARGON2_MEMORY),
+		Iterations:  uint32(ARGON2_ITERATIONS),
+		Parallelism: uint8(ARGON2_PARALLELISM),
+		SaltLength:  argon2id.DefaultParams.SaltLength,
+		KeyLength:   argon2id.DefaultParams.KeyLength,
+	}
 }
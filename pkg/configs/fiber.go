@@ -26,10 +26,22 @@ func NewFiberHttpServiceParams() *FiberHttpServiceParams {
 }
 
 func NewFiberHTTPService(params *FiberHttpServiceParams) *fiber.App {
-	app := fiber.New(fiber.Config{
+	config := fiber.Config{
 		Prefork:   false,
-		BodyLimit: 200 * 1024 * 1024,
-	})
+		BodyLimit: HTTP_BODY_LIMIT_MB << 20,
+	}
+	// c.IP() feeds the login lockout, so only honour the proxy header when it
+	// comes from a known proxy.
+	if PROXY_HEADER != "" {
+		config.ProxyHeader = PROXY_HEADER
+		config.EnableTrustedProxyCheck = true
+		for _, proxy := range strings.Split(TRUSTED_PROXIES, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				config.TrustedProxies = append(config.TrustedProxies, proxy)
+			}
+		}
+	}
+	app := fiber.New(config)
 	app.Use(logger.New())
 	// storageservice.NewS3Client(configs.S3REGION)
 	// app.Use(requestid.New())
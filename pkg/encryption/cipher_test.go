@@ -0,0 +1,33 @@
+package encryption
+
+import "testing"
+
+func TestEncryptDecrypt(t *testing.T) {
+	c := NewCipher("test-key")
+
+	encrypted, err := c.Encrypt("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted == "JBSWY3DPEHPK3PXP" || !IsEncrypted(encrypted) {
+		t.Fatalf("expected an encrypted value, got %q", encrypted)
+	}
+
+	decrypted, err := c.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decrypted != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("expected the original plaintext, got %q", decrypted)
+	}
+
+	if _, err := NewCipher("other-key").Decrypt(encrypted); err != ErrInvalidCiphertext {
+		t.Errorf("expected ErrInvalidCiphertext with the wrong key, got %v", err)
+	}
+}
+
+func TestMissingKey(t *testing.T) {
+	if _, err := NewCipher("").Encrypt("secret"); err != ErrMissingKey {
+		t.Errorf("expected ErrMissingKey, got %v", err)
+	}
+}
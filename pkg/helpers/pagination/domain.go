@@ -116,4 +116,5 @@ type APIV2PaginationResponse struct {
 	StatusMessage string      `json:"status_message"`
 	Data          interface{} `json:"data"`
 	Pagination    interface{} `json:"pagination"`
+	Facets        interface{} `json:"facets,omitempty"`
 }
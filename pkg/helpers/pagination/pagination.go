@@ -107,8 +107,29 @@ func NewPagErrorResponse(message string, err error) APIV2PaginationResponse {
 //	    // Handle error
 //	}
 func NewPaginationResponse[T any](c *fiber.Ctx, message string, data Pagination[[]T]) error {
+	return c.Status(200).JSON(newPaginationResponse(message, data))
+}
+
+// NewFacetedPaginationResponse sends a paginated response like
+// NewPaginationResponse, with the facets of the listing next to the page.
+//
+// Parameters:
+// - c *fiber.Ctx: The Fiber context used to construct and send the HTTP response.
+// - message string: A message to include in the response status message.
+// - data Pagination[[]T]: A Pagination object containing the paginated data and pagination info.
+// - facets interface{}: The facet buckets of the listing, sent as "facets".
+//
+// Returns:
+// - error: An error if there was an issue sending the response, otherwise nil.
+func NewFacetedPaginationResponse[T any](c *fiber.Ctx, message string, data Pagination[[]T], facets interface{}) error {
+	response := newPaginationResponse(message, data)
+	response.Facets = facets
+	return c.Status(200).JSON(response)
+}
+
+func newPaginationResponse[T any](message string, data Pagination[[]T]) APIV2PaginationResponse {
 	if data.Rows == nil || reflect.ValueOf(data.Rows).Len() == 0 {
-		return c.Status(200).JSON(APIV2PaginationResponse{
+		return APIV2PaginationResponse{
 			StatusCode:    configs.API_SUCCESS_CODE,
 			StatusMessage: "The process of pagination was success",
 			Data:          make([]interface{}, 0),
@@ -120,15 +141,14 @@ func NewPaginationResponse[T any](c *fiber.Ctx, message string, data Pagination[
 				TotalPages: 0,
 				Rows:       make([]interface{}, 0),
 			}),
-		})
+		}
 	}
-	response := APIV2PaginationResponse{
+	return APIV2PaginationResponse{
 		StatusCode:    configs.API_SUCCESS_CODE,
 		StatusMessage: message,
 		Data:          data.Rows,
 		Pagination:    GetPaginationInfo(data),
 	}
-	return c.Status(200).JSON(response)
 }
 
 // GetPaginationInfo extracts pagination details from a Pagination[T] payload.
@@ -296,13 +316,28 @@ func Paginate[FT any, T any](p PaginationParams[FT], query *gorm.DB) (Pagination
 	if err := query.Model(value).Count(&totalRows).Error; err != nil {
 		return Pagination[T]{}, err
 	}
-	p.TotalRows = totalRows
-	p.TotalPages = int(math.Ceil(float64(totalRows) / float64(p.GetLimit())))
-
 	if err := query.Offset(p.GetOffset()).Limit(p.GetLimit()).Order(p.GetSort()).Find(&value).Error; err != nil {
 		return Pagination[T]{}, err
 
 	}
+	return NewPage(p, totalRows, value), nil
+}
+
+// NewPage wraps a page of rows fetched without Paginate, for example by a raw
+// query, in a Pagination with the same totals and links Paginate builds.
+//
+// Parameters:
+// - p: PaginationParams[FT] - The parameters the page was fetched with.
+// - totalRows: int64 - The number of rows across all pages.
+// - rows: T - The rows of the current page.
+//
+// Example:
+//
+//	page := NewPage(params, total, rows)
+func NewPage[FT any, T any](p PaginationParams[FT], totalRows int64, rows T) Pagination[T] {
+	p.TotalRows = totalRows
+	p.TotalPages = int(math.Ceil(float64(totalRows) / float64(p.GetLimit())))
+
 	var nextLink, prevLink string
 	if p.Page < p.TotalPages {
 		nextLink = fmt.Sprintf("%s?page=%d&page_size=%d", p.BaseURL, p.Page+1, p.Limit)
@@ -319,8 +354,8 @@ func Paginate[FT any, T any](p PaginationParams[FT], query *gorm.DB) (Pagination
 		Page:       p.GetPage(),
 		PageSize:   p.GetLimit(),
 		TotalPages: p.TotalPages,
-		Rows:       value,
-	}, nil
+		Rows:       rows,
+	}
 }
 
 // PaginateBatchProcessing performs pagination by processing records in batches based on the provided query.
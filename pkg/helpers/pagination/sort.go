@@ -0,0 +1,45 @@
+package pagination
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RestrictSort replaces p.Sort with a safe ORDER BY clause. Sort and order
+// come straight from the query string, so only the listed columns are
+// accepted; anything else falls back to defaultOrderBy.
+//
+// Both "sort=email&order=asc" and "sort=email asc" are understood, and the
+// direction defaults to ascending.
+//
+// Example:
+//
+//	params.RestrictSort([]string{"created_at", "email"}, "created_at DESC")
+func (p *PaginationParams[FilterType]) RestrictSort(allowed []string, defaultOrderBy string) {
+	field, order, _ := strings.Cut(strings.TrimSpace(p.Sort), " ")
+	if p.Order != "" {
+		order = p.Order
+	}
+	order = strings.ToUpper(strings.TrimSpace(order))
+	if order == "" {
+		order = "ASC"
+	}
+
+	p.Sort = defaultOrderBy
+	if order != "ASC" && order != "DESC" {
+		return
+	}
+	for _, column := range allowed {
+		if strings.EqualFold(field, column) {
+			p.Sort = fmt.Sprintf("%s %s", column, order)
+			return
+		}
+	}
+}
+
+// LimitPageSize caps the page size requested by the client.
+func (p *PaginationParams[FilterType]) LimitPageSize(max int) {
+	if p.Limit <= 0 || p.Limit > max {
+		p.Limit = max
+	}
+}
@@ -0,0 +1,25 @@
+package pagination
+
+import "testing"
+
+func TestRestrictSort(t *testing.T) {
+	allowed := []string{"created_at", "email"}
+	cases := []struct {
+		sort, order, want string
+	}{
+		{"created_at desc", "", "created_at DESC"},
+		{"email", "asc", "email ASC"},
+		{"EMAIL", "", "email ASC"},
+		{"email", "sideways", "id DESC"},
+		{"password", "asc", "id DESC"},
+		{"email; DROP TABLE users", "", "id DESC"},
+		{"", "", "id DESC"},
+	}
+	for _, tc := range cases {
+		p := PaginationParams[struct{}]{Sort: tc.sort, Order: tc.order}
+		p.RestrictSort(allowed, "id DESC")
+		if p.Sort != tc.want {
+			t.Errorf("RestrictSort(%q, %q) = %q, want %q", tc.sort, tc.order, p.Sort, tc.want)
+		}
+	}
+}
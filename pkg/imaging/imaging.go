@@ -0,0 +1,140 @@
+// Package imaging decodes uploaded images and re-encodes them without their
+// metadata, optionally scaled down.
+//
+// Decoding and re-encoding is what strips EXIF: only pixels survive, so GPS
+// coordinates, camera serials and embedded thumbnails are dropped. The EXIF
+// orientation of JPEGs is applied to the pixels first so photos taken in
+// portrait stay upright.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("imaging: not a PNG, JPEG, GIF or WebP image")
+	ErrTooManyPixels     = errors.New("imaging: image dimensions are too large")
+)
+
+// Format is an image encoding.
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+	GIF  Format = "gif"
+	WebP Format = "webp"
+)
+
+var contentTypes = map[Format]string{
+	JPEG: "image/jpeg",
+	PNG:  "image/png",
+	GIF:  "image/gif",
+	WebP: "image/webp",
+}
+
+var extensions = map[Format]string{
+	JPEG: "jpg",
+	PNG:  "png",
+	GIF:  "gif",
+	WebP: "webp",
+}
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// Extension returns the usual file extension of the format, without a dot.
+func (f Format) Extension() string {
+	return extensions[f]
+}
+
+// Sniff detects the format from the leading bytes, ignoring whatever type
+// the client declared.
+func Sniff(data []byte) (Format, error) {
+	contentType := http.DetectContentType(data)
+	for format, known := range contentTypes {
+		if contentType == known {
+			return format, nil
+		}
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Decode sniffs and decodes data. Images with more than maxPixels pixels are
+// refused from their header, before any pixel memory is allocated.
+func Decode(data []byte, maxPixels int) (image.Image, Format, error) {
+	format, err := Sniff(data)
+	if err != nil {
+		return nil, "", err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxPixels/config.Height {
+		return nil, "", ErrTooManyPixels
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ErrUnsupportedFormat
+	}
+	if format == JPEG {
+		img = Orient(img, jpegOrientation(data))
+	}
+	return img, format, nil
+}
+
+// Fit scales img down so neither side exceeds maxSide, keeping the aspect
+// ratio. Smaller images are returned unchanged.
+func Fit(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+// Encode writes img in the given format. quality only applies to JPEG; WebP
+// is encoded losslessly.
+func Encode(img image.Image, format Format, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case JPEG:
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case PNG:
+		err = png.Encode(&buf, img)
+	case GIF:
+		err = gif.Encode(&buf, img, nil)
+	case WebP:
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		err = ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,128 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+// testJPEG encodes a w×h JPEG whose left half is red and, when orientation
+// is set, an EXIF segment carrying it.
+func testJPEG(t *testing.T, w, h, orientation int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.White)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if orientation == 0 {
+		return data
+	}
+
+	// Little endian TIFF with a single IFD0 entry: Orientation, SHORT, 1.
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], uint16(orientation))
+	tiff = append(append(tiff, entry...), 0, 0, 0, 0)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(out[4:], uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestSniff(t *testing.T) {
+	if format, err := Sniff(testJPEG(t, 2, 2, 0)); err != nil || format != JPEG {
+		t.Fatalf("Sniff = %q, %v", format, err)
+	}
+	for _, data := range [][]byte{[]byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), []byte("%PDF-1.7"), nil} {
+		if _, err := Sniff(data); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Errorf("Sniff(%q) = %v, want ErrUnsupportedFormat", data, err)
+		}
+	}
+}
+
+func TestDecodeAppliesOrientation(t *testing.T) {
+	img, format, err := Decode(testJPEG(t, 32, 16, 6), 1<<20)
+	if err != nil || format != JPEG {
+		t.Fatalf("Decode: %q, %v", format, err)
+	}
+	// Rotated clockwise: 16×32, and the red left half becomes the top half.
+	if b := img.Bounds(); b.Dx() != 16 || b.Dy() != 32 {
+		t.Fatalf("bounds = %v", b)
+	}
+	if _, g, _, _ := img.At(8, 4).RGBA(); g>>8 > 60 {
+		t.Fatalf("top pixel is not red: %v", img.At(8, 4))
+	}
+	if _, g, _, _ := img.At(8, 28).RGBA(); g>>8 < 200 {
+		t.Fatalf("bottom pixel is not white: %v", img.At(8, 28))
+	}
+
+	out, err := Encode(img, JPEG, 90)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(out, []byte("Exif")) || jpegOrientation(out) != 1 {
+		t.Fatal("re-encoded image still carries EXIF")
+	}
+}
+
+func TestDecodeRefusesLargeImages(t *testing.T) {
+	if _, _, err := Decode(testJPEG(t, 64, 64, 0), 64*63); !errors.Is(err, ErrTooManyPixels) {
+		t.Fatalf("got %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestFitAndWebP(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 300, 120))
+	fitted := Fit(img, 100)
+	if b := fitted.Bounds(); b.Dx() != 100 || b.Dy() != 40 {
+		t.Fatalf("bounds = %v", b)
+	}
+	if Fit(fitted, 200) != fitted {
+		t.Fatal("Fit enlarged a small image")
+	}
+
+	data, err := Encode(fitted, WebP, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, format, err := Decode(data, 1<<20)
+	if err != nil || format != WebP || decoded.Bounds().Dx() != 100 {
+		t.Fatalf("WebP round trip: %q, %v", format, err)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	// Where the top-left pixel ends up for each orientation.
+	want := map[int]image.Point{
+		1: {0, 0}, 2: {2, 0}, 3: {2, 1}, 4: {0, 1},
+		5: {0, 0}, 6: {1, 0}, 7: {1, 2}, 8: {0, 2},
+	}
+	for orientation, at := range want {
+		out := Orient(img, orientation)
+		if r, _, _, _ := out.At(at.X, at.Y).RGBA(); r>>8 != 255 {
+			t.Errorf("orientation %d: pixel not at %v", orientation, at)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Package mergepatch implements JSON Merge Patch (RFC 7396).
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrNotObject is returned by ApplyJSON when the patch is not a JSON object.
+var ErrNotObject = errors.New("mergepatch: patch must be a JSON object")
+
+// Apply merges patch into target and returns the result, following the
+// MergePatch pseudocode of RFC 7396 section 2: object members set to null are
+// removed, other members are merged recursively, and any value that is not an
+// object replaces the target as a whole. Values are those produced by
+// encoding/json. target is not modified.
+func Apply(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	result := map[string]interface{}{}
+	if targetObject, ok := target.(map[string]interface{}); ok {
+		for name, value := range targetObject {
+			result[name] = value
+		}
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(result, name)
+			continue
+		}
+		result[name] = Apply(result[name], value)
+	}
+	return result
+}
+
+// ApplyJSON decodes an object patch and merges it into target.
+func ApplyJSON(target map[string]interface{}, patch []byte) (map[string]interface{}, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(patch, &decoded); err != nil {
+		return nil, err
+	}
+	if _, ok := decoded.(map[string]interface{}); !ok {
+		return nil, ErrNotObject
+	}
+	return Apply(target, decoded).(map[string]interface{}), nil
+}
@@ -0,0 +1,65 @@
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+// Test cases from RFC 7396 appendix A.
+func TestApplyRFC7396Examples(t *testing.T) {
+	cases := []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tc := range cases {
+		got := Apply(decode(t, tc.target), decode(t, tc.patch))
+		if want := decode(t, tc.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("Apply(%s, %s) = %v, want %s", tc.target, tc.patch, got, tc.want)
+		}
+	}
+}
+
+func TestApplyDoesNotModifyTarget(t *testing.T) {
+	target := map[string]interface{}{"a": map[string]interface{}{"b": "c"}}
+	Apply(target, decode(t, `{"a":{"b":null}}`))
+	if target["a"].(map[string]interface{})["b"] != "c" {
+		t.Fatal("target was modified")
+	}
+}
+
+func TestApplyJSONRequiresObject(t *testing.T) {
+	if _, err := ApplyJSON(nil, []byte(`["a"]`)); !errors.Is(err, ErrNotObject) {
+		t.Fatalf("got %v, want ErrNotObject", err)
+	}
+	got, err := ApplyJSON(map[string]interface{}{"a": "b"}, []byte(`{"c":"d"}`))
+	if err != nil {
+		t.Fatalf("ApplyJSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, map[string]interface{}{"a": "b", "c": "d"}) {
+		t.Fatalf("got %v", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Package postal validates postal addresses against per-country rules: the
+// format of the postal code and whether a region (state, province,
+// prefecture) is part of the address.
+package postal
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrUnknownCountry     = errors.New("country code is not a valid ISO 3166-1 alpha-2 code")
+	ErrPostalCodeRequired = errors.New("postal code is required for this country")
+	ErrInvalidPostalCode  = errors.New("postal code is not valid for this country")
+	ErrRegionRequired     = errors.New("region is required for this country")
+	ErrInvalidRegion      = errors.New("region is not valid for this country")
+	ErrInvalidPhoneNumber = errors.New("phone number is not valid")
+)
+
+// countryRule describes how addresses are written in one country.
+type countryRule struct {
+	PostalCode     *regexp.Regexp // nil when the country does not use postal codes
+	RegionRequired bool
+	Regions        map[string]bool // accepted region codes, nil to accept any text
+}
+
+// isoCountries lists every ISO 3166-1 alpha-2 code.
+var isoCountries = codes(`
+AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL
+BM BN BO BQ BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV
+CW CX CY CZ DE DJ DK DM DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD
+GE GF GG GH GI GL GM GN GP GQ GR GS GT GU GW GY HK HM HN HR HT HU ID IE IL IM
+IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN KP KR KW KY KZ LA LB LC LI LK
+LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ MR MS MT MU MV MW
+MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM PN PR
+PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS
+ST SV SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US
+UY UZ VA VC VE VG VI VN VU WF WS YE YT ZA ZM ZW`)
+
+var usStates = codes(`AL AK AZ AR CA CO CT DE DC FL GA HI ID IL IN IA KS KY LA ME MD MA MI MN MS MO MT NE NV
+NH NJ NM NY NC ND OH OK OR PA RI SC SD TN TX UT VT VA WA WV WI WY AS GU MP PR VI UM AA AE AP`)
+
+var caProvinces = codes(`AB BC MB NB NL NS NT NU ON PE QC SK YT`)
+
+var auStates = codes(`ACT NSW NT QLD SA TAS VIC WA`)
+
+// rules holds the countries with a known postal code format. Countries
+// missing here only need a valid country code; their postal code, if any, is
+// checked for plausible characters.
+var rules = map[string]countryRule{
+	"AU": {PostalCode: regexp.MustCompile(`^\d{4}$`), RegionRequired: true, Regions: auStates},
+	"BR": {PostalCode: regexp.MustCompile(`^\d{5}-?\d{3}$`), RegionRequired: true},
+	"CA": {PostalCode: regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] ?\d[ABCEGHJ-NPRSTV-Z]\d$`), RegionRequired: true, Regions: caProvinces},
+	"CN": {PostalCode: regexp.MustCompile(`^\d{6}$`), RegionRequired: true},
+	"DE": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"ES": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"GB": {PostalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"HK": {},
+	"ID": {PostalCode: regexp.MustCompile(`^\d{5}$`), RegionRequired: true},
+	"IE": {PostalCode: regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`)},
+	"IN": {PostalCode: regexp.MustCompile(`^\d{6}$`), RegionRequired: true},
+	"IT": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"JP": {PostalCode: regexp.MustCompile(`^\d{3}-?\d{4}$`), RegionRequired: true},
+	"KH": {PostalCode: regexp.MustCompile(`^\d{5,6}$`)},
+	"KR": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"LA": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"MM": {PostalCode: regexp.MustCompile(`^\d{5}$`)},
+	"MY": {PostalCode: regexp.MustCompile(`^\d{5}$`), RegionRequired: true},
+	"NL": {PostalCode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"PH": {PostalCode: regexp.MustCompile(`^\d{4}$`)},
+	"SG": {PostalCode: regexp.MustCompile(`^\d{6}$`)},
+	"TH": {PostalCode: regexp.MustCompile(`^\d{5}$`), RegionRequired: true},
+	"TW": {PostalCode: regexp.MustCompile(`^\d{3}(\d{2,3})?$`)},
+	"US": {PostalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`), RegionRequired: true, Regions: usStates},
+	"VN": {PostalCode: regexp.MustCompile(`^\d{6}$`)},
+}
+
+// noPostalCode lists countries that do not use postal codes at all.
+var noPostalCode = codes(`AE AG AO AW BF BI BJ BO BS BW BZ CD CF CG CI CK CM DJ DM ER FJ GA GD GH GM GQ GY HK
+HM JM KE KI KM KN KP LC ML MO MR MW NA NR NU QA RW SB SC SL SR SS ST SX SY TD TF TG TK TL TO TT TV UG VU YE ZW`)
+
+var (
+	genericPostalCode  = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 -]{1,9}$`)
+	phoneNumberPattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,24}$`)
+)
+
+func codes(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, code := range strings.Fields(list) {
+		set[code] = true
+	}
+	return set
+}
+
+// Address is the subset of an address the rules look at.
+type Address struct {
+	CountryCode string
+	Region      string
+	PostalCode  string
+	Phone       string
+}
+
+// Normalize trims the fields and upper cases the codes, the form Validate
+// expects and addresses are stored in.
+func Normalize(a Address) Address {
+	a.CountryCode = strings.ToUpper(strings.TrimSpace(a.CountryCode))
+	a.PostalCode = strings.ToUpper(strings.Join(strings.Fields(a.PostalCode), " "))
+	a.Region = strings.TrimSpace(a.Region)
+	a.Phone = strings.TrimSpace(a.Phone)
+	if rule, ok := rules[a.CountryCode]; ok && rule.Regions != nil {
+		a.Region = strings.ToUpper(a.Region)
+	}
+	return a
+}
+
+// IsCountry reports whether code is an ISO 3166-1 alpha-2 code.
+func IsCountry(code string) bool {
+	return isoCountries[strings.ToUpper(code)]
+}
+
+// Validate checks a normalised address against the rules of its country.
+func Validate(a Address) error {
+	if !isoCountries[a.CountryCode] {
+		return ErrUnknownCountry
+	}
+	if a.Phone != "" && !phoneNumberPattern.MatchString(a.Phone) {
+		return ErrInvalidPhoneNumber
+	}
+
+	rule, known := rules[a.CountryCode]
+	switch {
+	case noPostalCode[a.CountryCode] && rule.PostalCode == nil:
+		// Any value is tolerated, some customers still fill in a local code.
+	case rule.PostalCode != nil:
+		if a.PostalCode == "" {
+			return ErrPostalCodeRequired
+		}
+		if !rule.PostalCode.MatchString(a.PostalCode) {
+			return ErrInvalidPostalCode
+		}
+	case a.PostalCode != "" && !genericPostalCode.MatchString(a.PostalCode):
+		return ErrInvalidPostalCode
+	}
+
+	if !known {
+		return nil
+	}
+	if rule.RegionRequired && a.Region == "" {
+		return ErrRegionRequired
+	}
+	if a.Region != "" && rule.Regions != nil && !rule.Regions[a.Region] {
+		return ErrInvalidRegion
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package postal
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		name    string
+		address Address
+		want    error
+	}{
+		{"thai address", Address{CountryCode: "th", Region: "Bangkok", PostalCode: "10110", Phone: "+66 81 234 5678"}, nil},
+		{"thai postal code", Address{CountryCode: "TH", Region: "Bangkok", PostalCode: "1011"}, ErrInvalidPostalCode},
+		{"thai region", Address{CountryCode: "TH", PostalCode: "10110"}, ErrRegionRequired},
+		{"us zip+4", Address{CountryCode: "US", Region: "ca", PostalCode: "94105-1234"}, nil},
+		{"us state", Address{CountryCode: "US", Region: "California", PostalCode: "94105"}, ErrInvalidRegion},
+		{"us missing zip", Address{CountryCode: "US", Region: "NY"}, ErrPostalCodeRequired},
+		{"uk postcode", Address{CountryCode: "GB", PostalCode: "sw1a  1aa"}, nil},
+		{"uk bad postcode", Address{CountryCode: "GB", PostalCode: "12345"}, ErrInvalidPostalCode},
+		{"canada", Address{CountryCode: "CA", Region: "ON", PostalCode: "k1a 0b1"}, nil},
+		{"japan", Address{CountryCode: "JP", Region: "Tokyo", PostalCode: "100-0001"}, nil},
+		{"no postal codes", Address{CountryCode: "AE"}, nil},
+		{"unlisted country", Address{CountryCode: "NO", PostalCode: "0150"}, nil},
+		{"unlisted country bad code", Address{CountryCode: "NO", PostalCode: "#01"}, ErrInvalidPostalCode},
+		{"unknown country", Address{CountryCode: "XX"}, ErrUnknownCountry},
+		{"bad phone", Address{CountryCode: "DE", PostalCode: "10115", Phone: "call me"}, ErrInvalidPhoneNumber},
+	}
+	for _, tc := range cases {
+		if err := Validate(Normalize(tc.address)); !errors.Is(err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	got := Normalize(Address{CountryCode: " gb ", PostalCode: " sw1a   1aa ", Region: " London "})
+	if got.CountryCode != "GB" || got.PostalCode != "SW1A 1AA" || got.Region != "London" {
+		t.Fatalf("Normalize = %+v", got)
+	}
+	if got := Normalize(Address{CountryCode: "us", Region: "ny"}); got.Region != "NY" {
+		t.Fatalf("region codes should be upper cased, got %q", got.Region)
+	}
+}
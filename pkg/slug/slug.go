@@ -0,0 +1,32 @@
+// Package slug turns display names into URL path segments.
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Make lowercases s and joins its runs of letters and digits with single
+// hyphens. Letters of any script are kept, so Thai names stay readable;
+// combining marks are kept with the letter they belong to.
+func Make(s string) string {
+	var b strings.Builder
+	pendingHyphen := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) {
+			if pendingHyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingHyphen = false
+			b.WriteRune(r)
+			continue
+		}
+		pendingHyphen = true
+	}
+	return b.String()
+}
+
+// Valid reports whether s is already in the form produced by Make.
+func Valid(s string) bool {
+	return s != "" && Make(s) == s
+}
@@ -0,0 +1,32 @@
+package slug
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	cases := map[string]string{
+		"Electronics":            "electronics",
+		"  TVs & Home Theater  ": "tvs-home-theater",
+		"Men's Shoes -- Running": "men-s-shoes-running",
+		"เสื้อผ้า ผู้หญิง":       "เสื้อผ้า-ผู้หญิง",
+		"4K/UHD": "4k-uhd",
+		"!!!":    "",
+	}
+	for in, want := range cases {
+		if got := Make(in); got != want {
+			t.Errorf("Make(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range []string{"electronics", "tvs-home-theater", "เสื้อผ้า"} {
+		if !Valid(s) {
+			t.Errorf("Valid(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", "Electronics", "-shoes", "shoes-", "a--b", "a b"} {
+		if Valid(s) {
+			t.Errorf("Valid(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrMissingSecret is returned when a JWTManager is used without a signing secret.
+	ErrMissingSecret = errors.New("tokens: signing secret is not configured")
+
+	// ErrInvalidToken is returned when a token cannot be parsed, has a bad
+	// signature, is expired or is not an access token.
+	ErrInvalidToken = errors.New("tokens: invalid or expired token")
+)
+
+const (
+	// TokenTypeAccess marks a JWT that grants access to the API.
+	TokenTypeAccess = "access"
+	// TokenTypeTwoFactor marks a short-lived JWT proving that the password step
+	// of a login succeeded and a second factor is still required.
+	TokenTypeTwoFactor = "2fa"
+)
+
+// AccessTokenClaims are the claims carried by an access token. The subject
+// (`sub`) is the user ID.
+type AccessTokenClaims struct {
+	jwt.RegisteredClaims
+	Role      string      `json:"role,omitempty"`
+	TokenType string      `json:"typ"`
+	Actor     *ActorClaim `json:"act,omitempty"` // Set when someone else acts as the subject
+}
+
+// ActorClaim names the party acting on behalf of the subject, as the "act"
+// claim of RFC 8693.
+type ActorClaim struct {
+	Subject string `json:"sub"`
+}
+
+// IsImpersonation reports whether the token was issued to an actor other
+// than the subject.
+func (c *AccessTokenClaims) IsImpersonation() bool {
+	return c.Actor != nil && c.Actor.Subject != ""
+}
+
+// JWTManager signs and verifies HMAC-SHA256 access tokens.
+type JWTManager struct {
+	secret    []byte
+	issuer    string
+	accessTTL time.Duration
+}
+
+// NewJWTManager creates a JWTManager using the given secret and access token lifetime.
+//
+// Example usage:
+//
+//	manager := NewJWTManager(configs.JWT_SECRET, configs.APP_NAME, 15*time.Minute)
+//	token, expiresAt, err := manager.GenerateAccessToken(userID, "user")
+func NewJWTManager(secret, issuer string, accessTTL time.Duration) *JWTManager {
+	return &JWTManager{
+		secret:    []byte(secret),
+		issuer:    issuer,
+		accessTTL: accessTTL,
+	}
+}
+
+// AccessTTL returns how long issued access tokens stay valid.
+func (m *JWTManager) AccessTTL() time.Duration {
+	return m.accessTTL
+}
+
+// GenerateAccessToken returns a signed access token for the subject together
+// with its expiry time.
+func (m *JWTManager) GenerateAccessToken(subject, role string) (string, time.Time, error) {
+	return m.sign(AccessTokenClaims{Role: role, TokenType: TokenTypeAccess}, subject, m.accessTTL)
+}
+
+// GenerateImpersonationToken returns an access token for the subject that
+// names actor as the party really making the requests. The session ID is
+// carried as the token ID (`jti`) so the session can be ended early.
+func (m *JWTManager) GenerateImpersonationToken(subject, role, actor, sessionID string, ttl time.Duration) (string, time.Time, error) {
+	claims := AccessTokenClaims{Role: role, TokenType: TokenTypeAccess, Actor: &ActorClaim{Subject: actor}}
+	claims.ID = sessionID
+	return m.sign(claims, subject, ttl)
+}
+
+// GenerateTwoFactorToken returns a challenge token for the subject that is only
+// accepted by VerifyTwoFactorToken, never as an access token. Every challenge
+// gets its own token ID (`jti`), so wrong codes can be counted per challenge.
+func (m *JWTManager) GenerateTwoFactorToken(subject string, ttl time.Duration) (string, time.Time, error) {
+	id, err := NewOpaqueToken(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claims := AccessTokenClaims{TokenType: TokenTypeTwoFactor}
+	claims.ID = id
+	return m.sign(claims, subject, ttl)
+}
+
+func (m *JWTManager) sign(claims AccessTokenClaims, subject string, ttl time.Duration) (string, time.Time, error) {
+	if len(m.secret) == 0 {
+		return "", time.Time{}, ErrMissingSecret
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        claims.ID,
+		Subject:   subject,
+		Issuer:    m.issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// VerifyAccessToken parses and validates an access token and returns its claims.
+func (m *JWTManager) VerifyAccessToken(token string) (*AccessTokenClaims, error) {
+	claims, err := m.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeAccess {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// VerifyTwoFactorToken parses and validates a two-factor challenge token.
+func (m *JWTManager) VerifyTwoFactorToken(token string) (*AccessTokenClaims, error) {
+	claims, err := m.verify(token)
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != TokenTypeTwoFactor {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+func (m *JWTManager) verify(token string) (*AccessTokenClaims, error) {
+	if len(m.secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	claims := &AccessTokenClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(m.issuer))
+	if err != nil || !parsed.Valid || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// NewOpaqueToken returns a URL-safe random token with n bytes of entropy.
+func NewOpaqueToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("tokens: generate random token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 digest of a high-entropy token.
+// It is meant for opaque random tokens that are looked up by their hash, not
+// for passwords, which must go through argon2id.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
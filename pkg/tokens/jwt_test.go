@@ -0,0 +1,124 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateAndVerifyAccessToken(t *testing.T) {
+	manager := NewJWTManager("secret", "test", time.Minute)
+
+	token, expiresAt, err := manager.GenerateAccessToken("user-1", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expiresAt) > time.Minute {
+		t.Errorf("unexpected expiry %v", expiresAt)
+	}
+
+	claims, err := manager.VerifyAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user-1" || claims.Role != "admin" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+}
+
+func TestVerifyAccessTokenRejectsInvalidTokens(t *testing.T) {
+	manager := NewJWTManager("secret", "test", time.Minute)
+	token, _, err := manager.GenerateAccessToken("user-1", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewJWTManager("other-secret", "test", time.Minute).VerifyAccessToken(token); err == nil {
+		t.Error("expected token signed with another secret to be rejected")
+	}
+	if _, err := NewJWTManager("secret", "other-issuer", time.Minute).VerifyAccessToken(token); err == nil {
+		t.Error("expected token from another issuer to be rejected")
+	}
+
+	expired, _, err := NewJWTManager("secret", "test", -time.Minute).GenerateAccessToken("user-1", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.VerifyAccessToken(expired); err == nil {
+		t.Error("expected expired token to be rejected")
+	}
+
+	if _, _, err := NewJWTManager("", "test", time.Minute).GenerateAccessToken("user-1", "admin"); err != ErrMissingSecret {
+		t.Errorf("expected ErrMissingSecret, got %v", err)
+	}
+}
+
+func TestTwoFactorTokenIsNotAnAccessToken(t *testing.T) {
+	manager := NewJWTManager("secret", "test", time.Minute)
+	challenge, _, err := manager.GenerateTwoFactorToken("user-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.VerifyAccessToken(challenge); err != ErrInvalidToken {
+		t.Errorf("expected a challenge token to be rejected as access token, got %v", err)
+	}
+	if claims, err := manager.VerifyTwoFactorToken(challenge); err != nil || claims.Subject != "user-1" {
+		t.Errorf("expected the challenge token to verify, got %v", err)
+	}
+
+	access, _, err := manager.GenerateAccessToken("user-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.VerifyTwoFactorToken(access); err != ErrInvalidToken {
+		t.Errorf("expected an access token to be rejected as challenge token, got %v", err)
+	}
+}
+
+func TestNewOpaqueTokenIsUnique(t *testing.T) {
+	a, err := NewOpaqueToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewOpaqueToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("expected unique tokens")
+	}
+	if HashToken(a) == a || HashToken(a) != HashToken(a) {
+		t.Error("expected a stable digest")
+	}
+}
+
+// RFC 7636 appendix B.
+func TestPKCEChallenge(t *testing.T) {
+	got := PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if want := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"; got != want {
+		t.Errorf("PKCEChallenge = %s, want %s", got, want)
+	}
+}
+
+func TestImpersonationToken(t *testing.T) {
+	manager := NewJWTManager("secret", "test", time.Hour)
+	token, expiresAt, err := manager.GenerateImpersonationToken("user-1", "customer", "admin-1", "session-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Until(expiresAt) > time.Minute {
+		t.Errorf("the impersonation ttl should apply, expires %v", expiresAt)
+	}
+
+	claims, err := manager.VerifyAccessToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.IsImpersonation() || claims.Subject != "user-1" || claims.Actor.Subject != "admin-1" || claims.ID != "session-1" {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	plain, _, _ := manager.GenerateAccessToken("user-1", "customer")
+	if claims, _ := manager.VerifyAccessToken(plain); claims.IsImpersonation() {
+		t.Error("a regular access token is not an impersonation")
+	}
+}
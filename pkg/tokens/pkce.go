@@ -0,0 +1,16 @@
+package tokens
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// PKCEChallengeMethod is the only code challenge method we send.
+const PKCEChallengeMethod = "S256"
+
+// PKCEChallenge derives the S256 code challenge of RFC 7636 from a verifier.
+// Verifiers from NewOpaqueToken(32) satisfy the RFC's length and alphabet rules.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
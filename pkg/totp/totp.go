@@ -0,0 +1,127 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// ErrInvalidSecret is returned when a secret is not valid unpadded base32.
+var ErrInvalidSecret = errors.New("totp: secret is not valid base32")
+
+const (
+	// Digits is the length of generated codes.
+	Digits = 6
+	// Period is the time step in seconds.
+	Period = 30
+	// Skew is the number of time steps accepted before and after the current
+	// one to tolerate clock drift between the server and the authenticator.
+	Skew = 1
+
+	secretBytes = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random 160-bit secret encoded as unpadded base32,
+// the format authenticator apps expect.
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("totp: generate secret: %w", err)
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// Code returns the code for the given secret at time t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, counterAt(t)), nil
+}
+
+// Validate reports whether code is valid for the secret at time t, allowing
+// Skew steps of drift. On success it also returns the time step that matched so
+// callers can reject a code that was already used (RFC 6238 section 5.2).
+func Validate(secret, code string, t time.Time) (bool, int64, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return false, 0, err
+	}
+	code = strings.TrimSpace(code)
+	if len(code) != Digits {
+		return false, 0, nil
+	}
+
+	counter := counterAt(t)
+	for step := counter - Skew; step <= counter+Skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return true, int64(step), nil
+		}
+	}
+	return false, 0, nil
+}
+
+// URI returns the otpauth:// key URI understood by authenticator apps.
+//
+// Example usage:
+//
+//	uri := URI(secret, configs.APP_NAME, user.Email)
+func URI(secret, issuer, account string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(Digits))
+	query.Set("period", fmt.Sprint(Period))
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// QRCodePNG renders the key URI as a PNG QR code of size x size pixels.
+func QRCodePNG(uri string, size int) ([]byte, error) {
+	return qrcode.Encode(uri, qrcode.Medium, size)
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+func counterAt(t time.Time) uint64 {
+	return uint64(t.Unix()) / Period
+}
+
+// hotp implements RFC 4226 with HMAC-SHA1 and dynamic truncation.
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
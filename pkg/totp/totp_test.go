@@ -0,0 +1,66 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 appendix B test vectors for HMAC-SHA1, truncated to six digits.
+func TestCodeMatchesRFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(secret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAllowsSkew(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	previous, _ := Code(secret, now.Add(-Period*time.Second))
+	if ok, _, _ := Validate(secret, previous, now); !ok {
+		t.Error("expected the previous step to be accepted")
+	}
+
+	stale, _ := Code(secret, now.Add(-3*Period*time.Second))
+	if ok, _, _ := Validate(secret, stale, now); ok {
+		t.Error("expected a code three steps old to be rejected")
+	}
+
+	if _, _, err := Validate("not base32!", "123456", now); err != ErrInvalidSecret {
+		t.Errorf("expected ErrInvalidSecret, got %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("JBSWY3DPEHPK3PXP", "Shop", "john@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Shop:john@example.com?") {
+		t.Errorf("unexpected uri %s", uri)
+	}
+	if !strings.Contains(uri, "secret=JBSWY3DPEHPK3PXP") || !strings.Contains(uri, "issuer=Shop") {
+		t.Errorf("uri is missing secret or issuer: %s", uri)
+	}
+}
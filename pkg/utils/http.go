@@ -6,9 +6,27 @@ import (
 )
 
 type APIResponse struct {
-	StatusCode    string      `json:"status_code"`
-	StatusMessage string      `json:"status_message"`
-	Data          interface{} `json:"data"`
+	StatusCode    string               `json:"status_code"`
+	StatusMessage string               `json:"status_message"`
+	Data          interface{}          `json:"data"`
+	Impersonation *ImpersonationBanner `json:"impersonation,omitempty"`
+}
+
+// ImpersonationBanner tells clients that the request was made by a staff
+// member acting as the user, so they can show a banner.
+type ImpersonationBanner struct {
+	Active  bool   `json:"active"`
+	ActorID string `json:"actor_id"`
+}
+
+// impersonationBanner returns the banner for requests made with an
+// impersonation token, nil otherwise.
+func impersonationBanner(c *fiber.Ctx) *ImpersonationBanner {
+	actor := GetLocalAsString(c, "act")
+	if actor == "" {
+		return nil
+	}
+	return &ImpersonationBanner{Active: true, ActorID: actor}
 }
 
 func NewSuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
@@ -19,6 +37,7 @@ func NewSuccessResponse(c *fiber.Ctx, message string, data interface{}) error {
 		StatusCode:    configs.API_SUCCESS_CODE,
 		StatusMessage: message,
 		Data:          data,
+		Impersonation: impersonationBanner(c),
 	}
 	return c.Status(200).JSON(response)
 }
@@ -31,6 +50,22 @@ func NewErrorResponse(c *fiber.Ctx, message string, data interface{}) error {
 		StatusCode:    configs.API_ERROR_CODE,
 		StatusMessage: message,
 		Data:          data,
+		Impersonation: impersonationBanner(c),
 	}
 	return c.Status(200).JSON(response)
 }
+
+// NewErrorResponseWithStatus is like NewErrorResponse but lets the caller pick
+// the HTTP status, e.g. 401 from authentication middleware.
+func NewErrorResponseWithStatus(c *fiber.Ctx, status int, message string, data interface{}) error {
+	if message == "" {
+		message = "The process was failed"
+	}
+	response := APIResponse{
+		StatusCode:    configs.API_ERROR_CODE,
+		StatusMessage: message,
+		Data:          data,
+		Impersonation: impersonationBanner(c),
+	}
+	return c.Status(status).JSON(response)
+}
@@ -453,6 +453,25 @@ func ParseSubjectUUID(c *fiber.Ctx) (uuid.UUID, error) {
 	return createdBy, nil
 }
 
+// ParseActorUUID returns the staff member impersonating the subject of the
+// request. It fails for requests that are not impersonated.
+func ParseActorUUID(c *fiber.Ctx) (uuid.UUID, error) {
+	actor, err := uuid.Parse(GetLocalAsString(c, "act"))
+	if err != nil {
+		return uuid.UUID{}, errors.New("the request is not made by an impersonator")
+	}
+	return actor, nil
+}
+
+// ParseImpersonationUUID returns the impersonation session of the request.
+func ParseImpersonationUUID(c *fiber.Ctx) (uuid.UUID, error) {
+	session, err := uuid.Parse(GetLocalAsString(c, "jti"))
+	if err != nil {
+		return uuid.UUID{}, errors.New("the request is not made by an impersonator")
+	}
+	return session, nil
+}
+
 func ToLowerCase(text string) string {
 	return strings.ToLower(text)
 }
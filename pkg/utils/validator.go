@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = validator.New()
+
+// ValidateStruct validates a struct using its `validate` tags and returns a
+// single error describing every failed field, or nil when the struct is valid.
+//
+// Example usage:
+//
+//	if err := ValidateStruct(payload); err != nil {
+//	    return NewErrorResponse(c, err.Error(), nil)
+//	}
+func ValidateStruct(s interface{}) error {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	messages := make([]string, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		messages = append(messages, fmt.Sprintf("%s failed on the '%s' rule", convertPascalToSnakeCase(fe.Field()), fe.Tag()))
+	}
+	return fmt.Errorf("validation error: %s", strings.Join(messages, ", "))
+}